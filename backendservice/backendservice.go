@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/internal/runtimestate"
 	libdb "github.com/contenox/runtime/libdbexec"
 	"github.com/contenox/runtime/runtimetypes"
+	"github.com/google/uuid"
 )
 
 var ErrInvalidBackend = errors.New("invalid backend data")
@@ -18,14 +22,54 @@ type Service interface {
 	Update(ctx context.Context, backend *runtimetypes.Backend) error
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, createdAtCursor *time.Time, limit int) ([]*runtimetypes.Backend, error)
+	// SyncModels reconciles the models a backend reports with the model store and the backend's pools.
+	SyncModels(ctx context.Context, id string) (*SyncResult, error)
+	// SyncAll runs SyncModels for every backend.
+	SyncAll(ctx context.Context) error
+}
+
+// SyncResult reports what SyncModels changed.
+type SyncResult struct {
+	BackendID string `json:"backendId" example:"b7d9e1a3-8f0c-4a7d-9b1e-2f3a4b5c6d7e"`
+	// Discovered lists the models the backend reported.
+	Discovered []string `json:"discovered" example:"[\"mistral:instruct\", \"nomic-embed-text:latest\"]"`
+	// Added lists models that were newly created in the model store.
+	Added []string `json:"added" example:"[\"nomic-embed-text:latest\"]"`
+	// Assigned lists models that were newly assigned to the backend's pools.
+	Assigned []string `json:"assigned" example:"[\"nomic-embed-text:latest\"]"`
+	// Missing lists models assigned to the backend's pools that the backend does not report.
+	Missing []string `json:"missing" example:"[\"llama2:7b\"]"`
 }
 
 type service struct {
 	dbInstance libdb.DBManager
+	discover   ModelDiscovery
+	keys       *runtimestate.BackendKeys
 }
 
-func New(db libdb.DBManager) Service {
-	return &service{dbInstance: db}
+// Option configures the backend service.
+type Option func(*service)
+
+// WithModelDiscovery replaces how SyncModels queries backends for their models.
+func WithModelDiscovery(discover ModelDiscovery) Option {
+	return func(s *service) {
+		s.discover = discover
+	}
+}
+
+// WithBackendKeys enables per-backend API keys, encrypted with keys before they are stored.
+func WithBackendKeys(keys *runtimestate.BackendKeys) Option {
+	return func(s *service) {
+		s.keys = keys
+	}
+}
+
+func New(db libdb.DBManager, opts ...Option) Service {
+	s := &service{dbInstance: db, discover: DiscoverModels}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *service) Create(ctx context.Context, backend *runtimetypes.Backend) error {
@@ -44,20 +88,78 @@ func (s *service) Create(ctx context.Context, backend *runtimetypes.Backend) err
 		return err
 	}
 
-	return storeInstance.CreateBackend(ctx, backend)
+	backend.EncryptedAPIKey = ""
+	if backend.APIKey != "" {
+		if backend.ID == "" {
+			backend.ID = uuid.New().String()
+		}
+		if backend.EncryptedAPIKey, err = s.sealAPIKey(backend); err != nil {
+			return err
+		}
+	}
+	if err := storeInstance.CreateBackend(ctx, backend); err != nil {
+		return err
+	}
+	backend.Redact()
+	return nil
 }
 
 func (s *service) Get(ctx context.Context, id string) (*runtimetypes.Backend, error) {
 	tx := s.dbInstance.WithoutTransaction()
-	return runtimetypes.New(tx).GetBackend(ctx, id)
+	backend, err := runtimetypes.New(tx).GetBackend(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	backend.Redact()
+	return backend, nil
 }
 
+// Update replaces the backend configuration. A non-empty APIKey rotates the
+// stored key; without one the current key is kept.
 func (s *service) Update(ctx context.Context, backend *runtimetypes.Backend) error {
 	if err := validate(backend); err != nil {
 		return err
 	}
-	tx := s.dbInstance.WithoutTransaction()
-	return runtimetypes.New(tx).UpdateBackend(ctx, backend)
+	var sealed string
+	if backend.APIKey != "" {
+		var err error
+		if sealed, err = s.sealAPIKey(backend); err != nil {
+			return err
+		}
+	}
+
+	tx, commit, release, err := s.dbInstance.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	storeInstance := runtimetypes.New(tx)
+	if err := storeInstance.UpdateBackend(ctx, backend); err != nil {
+		return err
+	}
+	if sealed != "" {
+		if err := storeInstance.SetBackendAPIKey(ctx, backend.ID, sealed); err != nil {
+			return err
+		}
+	}
+	current, err := storeInstance.GetBackend(ctx, backend.ID)
+	if err != nil {
+		return err
+	}
+	if err := commit(ctx); err != nil {
+		return err
+	}
+	*backend = *current
+	backend.Redact()
+	return nil
+}
+
+func (s *service) sealAPIKey(backend *runtimetypes.Backend) (string, error) {
+	sealed, err := s.keys.Seal(backend.ID, backend.APIKey)
+	if errors.Is(err, runtimestate.ErrBackendKeysDisabled) {
+		return "", fmt.Errorf("%w: %w: %w", ErrInvalidBackend, err, apiframework.ErrBadRequest)
+	}
+	return sealed, err
 }
 
 func (s *service) Delete(ctx context.Context, id string) error {
@@ -67,7 +169,111 @@ func (s *service) Delete(ctx context.Context, id string) error {
 
 func (s *service) List(ctx context.Context, createdAtCursor *time.Time, limit int) ([]*runtimetypes.Backend, error) {
 	tx := s.dbInstance.WithoutTransaction()
-	return runtimetypes.New(tx).ListBackends(ctx, createdAtCursor, limit)
+	backends, err := runtimetypes.New(tx).ListBackends(ctx, createdAtCursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, backend := range backends {
+		backend.Redact()
+	}
+	return backends, nil
+}
+
+// SyncModels adds models the backend hosts but the store doesn't know yet, and assigns
+// models to the backend's pools when none of them contains the model already.
+// Existing models and assignments are never modified or removed, so running it
+// repeatedly converges to the same state.
+func (s *service) SyncModels(ctx context.Context, id string) (*SyncResult, error) {
+	backend, err := runtimetypes.New(s.dbInstance.WithoutTransaction()).GetBackend(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	discovered, err := s.discover(ctx, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover models of backend %s: %w", id, err)
+	}
+
+	tx, commit, release, err := s.dbInstance.WithTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	storeInstance := runtimetypes.New(tx)
+
+	pools, err := storeInstance.ListPoolsForBackend(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pooled := make(map[string]struct{})
+	for _, pool := range pools {
+		models, err := storeInstance.ListModelsForPool(ctx, pool.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range models {
+			pooled[m.Model] = struct{}{}
+		}
+	}
+
+	result := &SyncResult{BackendID: id, Discovered: []string{}, Added: []string{}, Assigned: []string{}, Missing: []string{}}
+	reported := make(map[string]struct{}, len(discovered))
+	for _, found := range discovered {
+		reported[found.Model] = struct{}{}
+		result.Discovered = append(result.Discovered, found.Model)
+
+		model, err := storeInstance.GetModelByName(ctx, found.Model)
+		if errors.Is(err, libdb.ErrNotFound) {
+			model = &runtimetypes.Model{
+				Model:         found.Model,
+				ContextLength: found.ContextLength,
+				CanChat:       found.CanChat,
+				CanEmbed:      found.CanEmbed,
+				CanPrompt:     found.CanPrompt,
+				CanStream:     found.CanStream,
+			}
+			if err := storeInstance.AppendModel(ctx, model); err != nil {
+				return nil, fmt.Errorf("failed to add model %s: %w", found.Model, err)
+			}
+			result.Added = append(result.Added, found.Model)
+		} else if err != nil {
+			return nil, err
+		}
+
+		if _, ok := pooled[found.Model]; ok || len(pools) == 0 {
+			continue
+		}
+		for _, pool := range pools {
+			if err := storeInstance.AssignModelToPool(ctx, pool.ID, model.ID); err != nil {
+				return nil, fmt.Errorf("failed to assign model %s to pool %s: %w", found.Model, pool.Name, err)
+			}
+		}
+		result.Assigned = append(result.Assigned, found.Model)
+	}
+	for name := range pooled {
+		if _, ok := reported[name]; !ok {
+			result.Missing = append(result.Missing, name)
+		}
+	}
+	sort.Strings(result.Missing)
+
+	if err := commit(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *service) SyncAll(ctx context.Context) error {
+	backends, err := runtimetypes.New(s.dbInstance.WithoutTransaction()).ListAllBackends(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, backend := range backends {
+		if _, err := s.SyncModels(ctx, backend.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func validate(backend *runtimetypes.Backend) error {
@@ -77,8 +283,13 @@ func validate(backend *runtimetypes.Backend) error {
 	if backend.BaseURL == "" {
 		return fmt.Errorf("%w: baseURL is required", ErrInvalidBackend)
 	}
-	if backend.Type != "ollama" && backend.Type != "vllm" {
-		return fmt.Errorf("%w: Type is required to be ollama or vllm", ErrInvalidBackend)
+	if backend.Type != "ollama" && backend.Type != "vllm" && backend.Type != "openai" {
+		return fmt.Errorf("%w: Type is required to be ollama, vllm or openai", ErrInvalidBackend)
+	}
+	if backend.Timeout != "" {
+		if d, err := time.ParseDuration(backend.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("%w: timeout must be a positive duration such as \"90s\"", ErrInvalidBackend)
+		}
 	}
 
 	return nil
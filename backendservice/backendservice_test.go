@@ -0,0 +1,16 @@
+package backendservice
+
+import (
+	"testing"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/runtimetypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_SealAPIKey_RequiresEncryptionKey(t *testing.T) {
+	s := &service{}
+	_, err := s.sealAPIKey(&runtimetypes.Backend{ID: "b1", APIKey: "sk-secret-key"})
+	require.ErrorIs(t, err, ErrInvalidBackend)
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+}
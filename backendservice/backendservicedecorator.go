@@ -117,6 +117,45 @@ func (d *activityTrackerDecorator) List(ctx context.Context, createdAtCursor *ti
 	return backends, err
 }
 
+func (d *activityTrackerDecorator) SyncModels(ctx context.Context, id string) (*SyncResult, error) {
+	reportErrFn, reportChangeFn, endFn := d.tracker.Start(
+		ctx,
+		"sync",
+		"backend",
+		"backendID", id,
+	)
+	defer endFn()
+
+	result, err := d.service.SyncModels(ctx, id)
+	if err != nil {
+		reportErrFn(err)
+	} else if len(result.Added) > 0 || len(result.Assigned) > 0 {
+		reportChangeFn(id, map[string]interface{}{
+			"added":    result.Added,
+			"assigned": result.Assigned,
+			"missing":  result.Missing,
+		})
+	}
+
+	return result, err
+}
+
+func (d *activityTrackerDecorator) SyncAll(ctx context.Context) error {
+	reportErrFn, _, endFn := d.tracker.Start(
+		ctx,
+		"sync",
+		"backends",
+	)
+	defer endFn()
+
+	err := d.service.SyncAll(ctx)
+	if err != nil {
+		reportErrFn(err)
+	}
+
+	return err
+}
+
 func WithActivityTracker(service Service, tracker libtracker.ActivityTracker) Service {
 	return &activityTrackerDecorator{
 		service: service,
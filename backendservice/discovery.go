@@ -0,0 +1,120 @@
+package backendservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/contenox/runtime/runtimetypes"
+	"github.com/ollama/ollama/api"
+	"github.com/ollama/ollama/types/model"
+)
+
+// defaultDiscoveredContextLength is used when a backend does not report a model's context window.
+const defaultDiscoveredContextLength = 4096
+
+// ModelDiscovery lists the models a backend currently hosts, with their capabilities.
+type ModelDiscovery func(ctx context.Context, backend *runtimetypes.Backend) ([]*runtimetypes.Model, error)
+
+// DiscoverModels queries a backend for its models using the API of its type.
+func DiscoverModels(ctx context.Context, backend *runtimetypes.Backend) ([]*runtimetypes.Model, error) {
+	switch backend.Type {
+	case "ollama":
+		return discoverOllamaModels(ctx, backend)
+	case "vllm":
+		return discoverVLLMModels(ctx, backend)
+	default:
+		return nil, fmt.Errorf("%w: model discovery is not supported for backend type %q", ErrInvalidBackend, backend.Type)
+	}
+}
+
+func discoverOllamaModels(ctx context.Context, backend *runtimetypes.Backend) ([]*runtimetypes.Model, error) {
+	baseURL, err := url.Parse(backend.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backend URL: %w", err)
+	}
+	client := api.NewClient(baseURL, http.DefaultClient)
+	list, err := client.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+
+	models := make([]*runtimetypes.Model, 0, len(list.Models))
+	for _, m := range list.Models {
+		discovered := &runtimetypes.Model{
+			Model:         m.Model,
+			ContextLength: defaultDiscoveredContextLength,
+			CanChat:       true,
+			CanPrompt:     true,
+			CanStream:     true,
+		}
+		show, err := client.Show(ctx, &api.ShowRequest{Model: m.Model})
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect model %s: %w", m.Model, err)
+		}
+		if len(show.Capabilities) > 0 {
+			completion := slices.Contains(show.Capabilities, model.CapabilityCompletion)
+			discovered.CanChat = completion
+			discovered.CanPrompt = completion
+			discovered.CanStream = completion
+			discovered.CanEmbed = slices.Contains(show.Capabilities, model.CapabilityEmbedding)
+		}
+		for key, value := range show.ModelInfo {
+			if !strings.HasSuffix(key, ".context_length") {
+				continue
+			}
+			if n, ok := value.(float64); ok && n > 0 {
+				discovered.ContextLength = int(n)
+			}
+		}
+		models = append(models, discovered)
+	}
+	return models, nil
+}
+
+func discoverVLLMModels(ctx context.Context, backend *runtimetypes.Backend) ([]*runtimetypes.Model, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(backend.BaseURL, "/")+"/v1/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list models: unexpected status %d", resp.StatusCode)
+	}
+
+	var modelResp struct {
+		Data []struct {
+			ID          string `json:"id"`
+			MaxModelLen int    `json:"max_model_len"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&modelResp); err != nil {
+		return nil, fmt.Errorf("failed to decode models: %w", err)
+	}
+
+	models := make([]*runtimetypes.Model, 0, len(modelResp.Data))
+	for _, m := range modelResp.Data {
+		contextLength := m.MaxModelLen
+		if contextLength <= 0 {
+			contextLength = defaultDiscoveredContextLength
+		}
+		models = append(models, &runtimetypes.Model{
+			Model:         m.ID,
+			ContextLength: contextLength,
+			CanChat:       true,
+			CanPrompt:     true,
+			CanStream:     true,
+		})
+	}
+	return models, nil
+}
@@ -12,21 +12,27 @@ import (
 
 type Service interface {
 	OpenAIChatCompletions(ctx context.Context, taskChainID string, req taskengine.OpenAIChatRequest) (*taskengine.OpenAIChatResponse, []taskengine.CapturedStateUnit, error)
+	// CountTokens returns the prompt tokens the chain's chat model task would consume for req
+	// without invoking a model.
+	CountTokens(ctx context.Context, taskChainID string, req taskengine.OpenAIChatRequest) (*taskengine.ChatTokenCount, error)
 }
 
 type service struct {
 	dbInstance   libdbexec.DBManager
 	chainService taskchainservice.Service
 	env          execservice.TasksEnvService
+	tokenizer    taskengine.TokenCounter
 }
 
 func New(
 	env execservice.TasksEnvService,
 	chainService taskchainservice.Service,
+	tokenizer taskengine.TokenCounter,
 ) Service {
 	return &service{
 		chainService: chainService,
 		env:          env,
+		tokenizer:    tokenizer,
 	}
 }
 
@@ -36,6 +42,10 @@ func (s *service) OpenAIChatCompletions(ctx context.Context, taskChainID string,
 		return nil, nil, fmt.Errorf("failed to load task chain '%s': %w", taskChainID, err)
 	}
 
+	if err := req.NormalizeRoles(); err != nil {
+		return nil, nil, err
+	}
+
 	result, _, stackTrace, err := s.env.Execute(ctx, chain, req, taskengine.DataTypeOpenAIChat)
 	if err != nil {
 		return nil, stackTrace, fmt.Errorf("chain execution failed: %w", err)
@@ -52,3 +62,25 @@ func (s *service) OpenAIChatCompletions(ctx context.Context, taskChainID string,
 
 	return &res, stackTrace, nil
 }
+
+func (s *service) CountTokens(ctx context.Context, taskChainID string, req taskengine.OpenAIChatRequest) (*taskengine.ChatTokenCount, error) {
+	chain, err := s.chainService.Get(ctx, taskChainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task chain '%s': %w", taskChainID, err)
+	}
+
+	// Count for the first task that executes a chat model; it receives the request.
+	var chatTask *taskengine.TaskDefinition
+	for i := range chain.Tasks {
+		if h := chain.Tasks[i].Handler; h == taskengine.HandleModelExecution || h == taskengine.HandleToolCalling {
+			chatTask = &chain.Tasks[i]
+			break
+		}
+	}
+
+	count, err := taskengine.CountChatTokens(ctx, s.tokenizer, chatTask, req)
+	if err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
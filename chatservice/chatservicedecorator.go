@@ -38,6 +38,27 @@ func (d *activityTrackerDecorator) OpenAIChatCompletions(ctx context.Context, ch
 	return resp, traces, nil
 }
 
+// CountTokens implements Service.
+func (d *activityTrackerDecorator) CountTokens(ctx context.Context, chainID string, req taskengine.OpenAIChatRequest) (*taskengine.ChatTokenCount, error) {
+	reportErr, _, endFn := d.tracker.Start(
+		ctx,
+		"count_tokens",
+		"chat",
+		"chain_id", chainID,
+		"model", req.Model,
+		"message_count", len(req.Messages),
+	)
+	defer endFn()
+
+	count, err := d.service.CountTokens(ctx, chainID, req)
+	if err != nil {
+		reportErr(fmt.Errorf("token count failed: %w", err))
+		return nil, err
+	}
+
+	return count, nil
+}
+
 // WithActivityTracker creates a new decorated service that tracks activity
 func WithActivityTracker(service Service, tracker libtracker.ActivityTracker) Service {
 	return &activityTrackerDecorator{
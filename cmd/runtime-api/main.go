@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"log/slog"
@@ -16,11 +18,14 @@ import (
 	"github.com/contenox/runtime/internal/serverapi"
 	libbus "github.com/contenox/runtime/libbus"
 	libdb "github.com/contenox/runtime/libdbexec"
+	libkv "github.com/contenox/runtime/libkvstore"
 	libroutine "github.com/contenox/runtime/libroutine"
 	"github.com/contenox/runtime/libtracker"
 	"github.com/contenox/runtime/runtimetypes"
 	"github.com/contenox/runtime/taskengine"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
@@ -101,7 +106,19 @@ func main() {
 	if err != nil {
 		log.Fatalf("%s initializing OpenSearch failed: %v", nodeInstanceID, err)
 	}
-	state, err := runtimestate.New(ctx, dbInstance, ps, runtimestate.WithPools())
+	backendTimeout, err := config.DefaultBackendTimeout()
+	if err != nil {
+		log.Fatalf("%s parsing backend timeout failed: %v", nodeInstanceID, err)
+	}
+	backendKeys, err := config.BackendKeys()
+	if err != nil {
+		log.Fatalf("%s parsing backend key secret failed: %v", nodeInstanceID, err)
+	}
+	state, err := runtimestate.New(ctx, dbInstance, ps,
+		runtimestate.WithPools(),
+		runtimestate.WithDefaultBackendTimeout(backendTimeout),
+		runtimestate.WithBackendKeys(backendKeys),
+	)
 	// state, err := runtimestate.New(ctx, dbInstance, ps)
 	if err != nil {
 		log.Fatalf("%s initializing runtime state failed: %v", nodeInstanceID, err)
@@ -145,12 +162,27 @@ func main() {
 		log.Fatalf("%s initializing vector store failed: %v", nodeInstanceID, err)
 	}
 
-	// tracker := taskengine.NewKVActivityTracker(kvManager)
 	stdOuttracker := libtracker.NewLogActivityTracker(slog.Default())
 	serveropsChainedTracker := libtracker.ChainedTracker{
-		// tracker,
 		stdOuttracker,
 	}
+	var activity *taskengine.KVActivitySink
+	var activityKV libkv.KVManager
+	if config.ActivityStoreAddr != "" {
+		activityKV, err = libkv.NewManager(libkv.Config{
+			Addr:     config.ActivityStoreAddr,
+			Password: config.ActivityStorePassword,
+		}, 0)
+		if err != nil {
+			log.Fatalf("%s initializing activity store failed: %v", nodeInstanceID, err)
+		}
+		cleanups = append(cleanups, activityKV.Close)
+		activity = taskengine.NewKVActivityTracker(activityKV)
+		if err := activity.LoadAlertRules(ctx); err != nil {
+			log.Fatalf("%s loading alert rules failed: %v", nodeInstanceID, err)
+		}
+		serveropsChainedTracker = append(serveropsChainedTracker, activity)
+	}
 	repo, err := llmrepo.NewModelManager(state, tokenizerSvc, llmrepo.ModelManagerConfig{
 		DefaultPromptModel: llmrepo.ModelConfig{
 			Name:     config.TaskModel,
@@ -168,19 +200,53 @@ func main() {
 	if err != nil {
 		log.Fatalf("%s initializing llm repo failed: %v", nodeInstanceID, err)
 	}
+	localHooks := map[string]taskengine.HookRepo{
+		hooks.CommandRouterHookName: hooks.NewCommandRouter(),
+	}
+	if config.SQLHookDatabaseURL != "" {
+		sqlHook, sqlCleanup, err := initSQLQueryHook(ctx, config)
+		if err != nil {
+			log.Fatalf("%s initializing sql query hook failed: %v", nodeInstanceID, err)
+		}
+		cleanups = append(cleanups, sqlCleanup)
+		localHooks[hooks.SQLQueryHookName] = sqlHook
+	}
 	// Create persistent hook repo
-	hookRepo := hooks.NewPersistentRepo(map[string]taskengine.HookRepo{}, dbInstance, http.DefaultClient)
+	var hookRepo taskengine.HookRepo = hooks.NewPersistentRepo(localHooks, dbInstance, http.DefaultClient)
+	var envOpts []taskengine.EnvOption
+	if config.MetricsEnabled() {
+		metrics, err := taskengine.NewPrometheusMetrics(prometheus.DefaultRegisterer)
+		if err != nil {
+			log.Fatalf("%s initializing metrics failed: %v", nodeInstanceID, err)
+		}
+		hookRepo = hooks.WithMetrics(hookRepo, metrics)
+		envOpts = append(envOpts, taskengine.WithMetrics(metrics))
+	}
 	exec, err := taskengine.NewExec(ctx, repo, hookRepo, serveropsChainedTracker)
 	if err != nil {
 		log.Fatalf("%s initializing task engine engine failed: %v", nodeInstanceID, err)
 	}
-	environmentExec, err := taskengine.NewEnv(ctx, serveropsChainedTracker, exec, taskengine.NewSimpleInspector())
+	maxSteps, err := config.ChainStepLimit()
+	if err != nil {
+		log.Fatalf("%s initializing task engine failed: %v", nodeInstanceID, err)
+	}
+	envOpts = append(envOpts, taskengine.WithMaxSteps(maxSteps))
+	inFlight := taskengine.NewInFlightRegistry()
+	envOpts = append(envOpts, taskengine.WithInFlight(inFlight))
+	checkpointTTL, err := config.CheckpointTTL()
+	if err != nil {
+		log.Fatalf("%s initializing task engine failed: %v", nodeInstanceID, err)
+	}
+	if checkpointTTL > 0 {
+		envOpts = append(envOpts, taskengine.WithCheckpoints(taskengine.NewKVCheckpointStore(activityKV, checkpointTTL)))
+	}
+	environmentExec, err := taskengine.NewEnv(ctx, serveropsChainedTracker, exec, taskengine.NewSimpleInspector(), envOpts...)
 	if err != nil {
 		log.Fatalf("%s initializing task engine failed: %v", nodeInstanceID, err)
 	}
 	cleanups = append(cleanups, cleanup)
 
-	apiHandler, cleanup, err := serverapi.New(ctx, nodeInstanceID, Tenancy, config, dbInstance, ps, repo, environmentExec, state, hookRepo)
+	apiHandler, cleanup, err := serverapi.New(ctx, nodeInstanceID, Tenancy, config, dbInstance, ps, repo, environmentExec, state, hookRepo, inFlight, activity)
 	cleanups = append(cleanups, cleanup)
 	if err != nil {
 		log.Fatalf("%s initializing API handler failed: %v", nodeInstanceID, err)
@@ -188,9 +254,43 @@ func main() {
 
 	mux := http.NewServeMux()
 	mux.Handle("/", apiHandler)
+	if config.MetricsEnabled() {
+		mux.Handle("GET /metrics", promhttp.Handler())
+	}
 	port := config.Port
 	log.Printf("%s %s starting server on :%s", Tenancy, nodeInstanceID, port)
 	if err := http.ListenAndServe(config.Addr+":"+port, mux); err != nil {
 		log.Fatalf("%s server failed: %v", nodeInstanceID, err)
 	}
 }
+
+func initSQLQueryHook(ctx context.Context, cfg *serverapi.Config) (taskengine.HookRepo, func() error, error) {
+	queries := map[string]hooks.SQLQuery{}
+	if cfg.SQLHookQueries != "" {
+		if err := json.Unmarshal([]byte(cfg.SQLHookQueries), &queries); err != nil {
+			return nil, nil, fmt.Errorf("invalid sql hook queries: %w", err)
+		}
+	}
+	maxRows, err := cfg.SQLHookRowLimit()
+	if err != nil {
+		return nil, nil, err
+	}
+	timeout, err := cfg.SQLHookQueryTimeout()
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := sql.Open("postgres", cfg.SQLHookDatabaseURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open sql hook database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("sql hook database connection failed: %w", err)
+	}
+	hook, err := hooks.NewSQLQueryHook(db, queries, maxRows, timeout)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return hook, db.Close, nil
+}
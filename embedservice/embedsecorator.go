@@ -53,6 +53,25 @@ func (d *activityTrackerDecorator) Embed(ctx context.Context, text string) ([]fl
 	return vector, nil
 }
 
+func (d *activityTrackerDecorator) EmbedBatch(ctx context.Context, modelName string, texts []string) (*BatchResult, error) {
+	reportErr, _, endFn := d.tracker.Start(
+		ctx,
+		"embed_batch",
+		"embedding",
+		"model_name", modelName,
+		"inputs", len(texts),
+	)
+	defer endFn()
+
+	result, err := d.service.EmbedBatch(ctx, modelName, texts)
+	if err != nil {
+		reportErr(fmt.Errorf("batch embedding failed: %w", err))
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func WithActivityTracker(service Service, tracker libtracker.ActivityTracker) Service {
 	return &activityTrackerDecorator{
 		service: service,
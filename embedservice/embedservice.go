@@ -2,28 +2,65 @@ package embedservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/contenox/runtime/internal/apiframework"
 	"github.com/contenox/runtime/internal/llmrepo"
+	"github.com/contenox/runtime/internal/llmresolver"
 )
 
 type Service interface {
 	Embed(ctx context.Context, text string) ([]float64, error)
+	// EmbedBatch embeds every text with the given model and returns the vectors in input order.
+	// An empty modelName selects the default embedding model.
+	EmbedBatch(ctx context.Context, modelName string, texts []string) (*BatchResult, error)
 	DefaultModelName(ctx context.Context) (string, error)
 }
 
+// BatchResult holds the vectors produced by EmbedBatch.
+// Vectors[i] is the embedding of the i-th input text.
+type BatchResult struct {
+	ModelName    string
+	Vectors      [][]float64
+	PromptTokens int
+}
+
+// DefaultMaxBatchSize is the number of inputs EmbedBatch accepts per call
+// unless configured otherwise.
+const DefaultMaxBatchSize = 2048
+
 type service struct {
 	repo          llmrepo.ModelRepo
 	modelName     string
 	modelProvider string
+	maxBatchSize  int
+}
+
+// Option configures the embed service.
+type Option func(*service)
+
+// WithMaxBatchSize sets how many inputs EmbedBatch accepts per call.
+// Values below one keep DefaultMaxBatchSize.
+func WithMaxBatchSize(n int) Option {
+	return func(s *service) {
+		if n > 0 {
+			s.maxBatchSize = n
+		}
+	}
 }
 
-func New(repo llmrepo.ModelRepo, modelName string, modelProvider string) Service {
-	return &service{
+func New(repo llmrepo.ModelRepo, modelName string, modelProvider string, opts ...Option) Service {
+	s := &service{
 		repo:          repo,
 		modelName:     modelName,
 		modelProvider: modelProvider,
+		maxBatchSize:  DefaultMaxBatchSize,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // Embed implements Service.
@@ -38,6 +75,47 @@ func (s *service) Embed(ctx context.Context, text string) ([]float64, error) {
 	return vectorData, nil
 }
 
+// EmbedBatch implements Service.
+//
+// Requests are resolved with the configured embedding provider, so only models
+// served by the embedding pool can be used.
+func (s *service) EmbedBatch(ctx context.Context, modelName string, texts []string) (*BatchResult, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("%w: input must not be empty", apiframework.ErrBadRequest)
+	}
+	if len(texts) > s.maxBatchSize {
+		return nil, fmt.Errorf("%w: input has %d items, at most %d are allowed", apiframework.ErrBadRequest, len(texts), s.maxBatchSize)
+	}
+	if modelName == "" {
+		modelName = s.modelName
+	}
+	result := &BatchResult{
+		ModelName: modelName,
+		Vectors:   make([][]float64, len(texts)),
+	}
+	for i, text := range texts {
+		if text == "" {
+			return nil, fmt.Errorf("%w: input %d is empty", apiframework.ErrBadRequest, i)
+		}
+		vector, _, err := s.repo.Embed(ctx, llmrepo.EmbedRequest{
+			ModelName:    modelName,
+			ProviderType: s.modelProvider,
+		}, text)
+		if errors.Is(err, llmresolver.ErrNoSatisfactoryModel) || errors.Is(err, llmresolver.ErrNoAvailableModels) {
+			return nil, fmt.Errorf("%w: model %q is not an available embedding model", apiframework.ErrUnprocessableEntity, modelName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("embedding input %d failed: %w", i, err)
+		}
+		result.Vectors[i] = vector
+		// Token usage is informational; a missing tokenizer must not fail the request.
+		if count, err := s.repo.CountTokens(ctx, modelName, text); err == nil {
+			result.PromptTokens += count
+		}
+	}
+	return result, nil
+}
+
 // DefaultModelName implements Service.
 func (s *service) DefaultModelName(ctx context.Context) (string, error) {
 	return s.modelName, nil
@@ -0,0 +1,81 @@
+package embedservice_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/contenox/runtime/embedservice"
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/internal/llmrepo"
+	"github.com/contenox/runtime/internal/llmresolver"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo embeds text as its length and only knows the models listed in embedModels.
+type fakeRepo struct {
+	llmrepo.ModelRepo
+	embedModels map[string]bool
+	requests    []llmrepo.EmbedRequest
+}
+
+func (f *fakeRepo) Embed(ctx context.Context, req llmrepo.EmbedRequest, prompt string) ([]float64, llmrepo.Meta, error) {
+	f.requests = append(f.requests, req)
+	if !f.embedModels[req.ModelName] {
+		return nil, llmrepo.Meta{}, fmt.Errorf("failed to filter candidates: %w", llmresolver.ErrNoSatisfactoryModel)
+	}
+	return []float64{float64(len(prompt))}, llmrepo.Meta{ModelName: req.ModelName}, nil
+}
+
+func (f *fakeRepo) CountTokens(ctx context.Context, modelName string, prompt string) (int, error) {
+	return 0, errors.New("tokenizer not initialized")
+}
+
+func newTestService() (embedservice.Service, *fakeRepo) {
+	repo := &fakeRepo{embedModels: map[string]bool{"nomic-embed-text": true}}
+	return embedservice.New(repo, "nomic-embed-text", "ollama"), repo
+}
+
+func TestUnit_EmbedBatch_SingleInputUsesDefaultModel(t *testing.T) {
+	svc, repo := newTestService()
+
+	result, err := svc.EmbedBatch(context.Background(), "", []string{"hello"})
+	require.NoError(t, err)
+	require.Equal(t, "nomic-embed-text", result.ModelName)
+	require.Equal(t, [][]float64{{5}}, result.Vectors)
+	require.Equal(t, 0, result.PromptTokens, "a missing tokenizer must not fail the request")
+	require.Equal(t, []llmrepo.EmbedRequest{{ModelName: "nomic-embed-text", ProviderType: "ollama"}}, repo.requests)
+}
+
+func TestUnit_EmbedBatch_PreservesInputOrder(t *testing.T) {
+	svc, _ := newTestService()
+
+	result, err := svc.EmbedBatch(context.Background(), "nomic-embed-text", []string{"a", "abc", "ab"})
+	require.NoError(t, err)
+	require.Equal(t, [][]float64{{1}, {3}, {2}}, result.Vectors)
+}
+
+func TestUnit_EmbedBatch_RejectsNonEmbeddingModel(t *testing.T) {
+	svc, _ := newTestService()
+
+	_, err := svc.EmbedBatch(context.Background(), "llama3", []string{"hello"})
+	require.ErrorIs(t, err, apiframework.ErrUnprocessableEntity)
+	require.ErrorContains(t, err, `model "llama3" is not an available embedding model`)
+
+	_, err = svc.EmbedBatch(context.Background(), "", nil)
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+}
+
+func TestUnit_EmbedBatch_RejectsOversizedBatch(t *testing.T) {
+	repo := &fakeRepo{embedModels: map[string]bool{"nomic-embed-text": true}}
+	svc := embedservice.New(repo, "nomic-embed-text", "ollama", embedservice.WithMaxBatchSize(2))
+
+	_, err := svc.EmbedBatch(context.Background(), "", []string{"a", "b"})
+	require.NoError(t, err)
+
+	_, err = svc.EmbedBatch(context.Background(), "", []string{"a", "b", "c"})
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	require.ErrorContains(t, err, "input has 3 items, at most 2 are allowed")
+	require.Len(t, repo.requests, 2, "oversized batches never reach the backend")
+}
@@ -0,0 +1,124 @@
+package execservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/taskengine"
+)
+
+const (
+	// MaxBatchSize is the maximum number of inputs accepted by ExecuteBatch.
+	MaxBatchSize = 1000
+	// MaxBatchConcurrency caps the number of inputs executed in parallel.
+	MaxBatchConcurrency = 16
+	// DefaultBatchConcurrency is used when no concurrency is requested.
+	DefaultBatchConcurrency = 4
+	// DefaultBatchTimeout bounds the runtime of a whole batch.
+	DefaultBatchTimeout = 10 * time.Minute
+)
+
+// Batch item statuses.
+const (
+	BatchStatusOK    = "ok"
+	BatchStatusError = "error"
+)
+
+// BatchConfig controls how ExecuteBatch runs the inputs.
+// Zero values fall back to DefaultBatchConcurrency and DefaultBatchTimeout.
+type BatchConfig struct {
+	Concurrency int
+	Timeout     time.Duration
+}
+
+// BatchItemResult is the outcome of executing the chain for a single input.
+type BatchItemResult struct {
+	Index      int                            `json:"index" example:"0"`
+	Status     string                         `json:"status" example:"ok"`
+	Output     any                            `json:"output,omitempty" example:"Paris" openapi_include_type:"object"`
+	OutputType string                         `json:"outputType,omitempty" example:"string"`
+	State      []taskengine.CapturedStateUnit `json:"state,omitempty" openapi_include_type:"taskengine.CapturedStateUnit"`
+	Error      string                         `json:"error,omitempty" example:"task classify failed after 0 retries: timeout"`
+	// CheckpointID is set on failed items that can be continued via /tasks/{id}/resume.
+	CheckpointID string `json:"checkpointId,omitempty" example:"3f2b8c1e-6a4d-4f0e-9b7a-2d5c8e1f4a60"`
+}
+
+// ExecuteBatch runs chain once per input using a bounded worker pool.
+// Results are returned in input order. A failing input does not abort the batch;
+// its result carries the error instead. Inputs not started before the batch
+// timeout expires are reported as failed.
+func ExecuteBatch(ctx context.Context, service TasksEnvService, chain *taskengine.TaskChainDefinition, inputs []any, inputType taskengine.DataType, cfg BatchConfig) ([]BatchItemResult, error) {
+	if chain == nil {
+		return nil, fmt.Errorf("chain is required: %w", apiframework.ErrBadRequest)
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("batch has no inputs: %w", apiframework.ErrBadRequest)
+	}
+	if len(inputs) > MaxBatchSize {
+		return nil, fmt.Errorf("batch size %d exceeds the maximum of %d: %w", len(inputs), MaxBatchSize, apiframework.ErrBadRequest)
+	}
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	concurrency = min(concurrency, MaxBatchConcurrency, len(inputs))
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultBatchTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results := make([]BatchItemResult, len(inputs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = executeBatchItem(ctx, service, chain, i, inputs[i], inputType)
+			}
+		}()
+	}
+
+feed:
+	for i := range inputs {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			for j := i; j < len(inputs); j++ {
+				results[j] = BatchItemResult{Index: j, Status: BatchStatusError, Error: ctx.Err().Error()}
+			}
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results, nil
+}
+
+func executeBatchItem(ctx context.Context, service TasksEnvService, chain *taskengine.TaskChainDefinition, index int, input any, inputType taskengine.DataType) BatchItemResult {
+	output, outputType, state, err := service.Execute(ctx, chain, input, inputType)
+	if err != nil {
+		result := BatchItemResult{Index: index, Status: BatchStatusError, State: state, Error: err.Error()}
+		var resumable *taskengine.ResumableError
+		if errors.As(err, &resumable) {
+			result.CheckpointID = resumable.CheckpointID
+		}
+		return result
+	}
+	return BatchItemResult{
+		Index:      index,
+		Status:     BatchStatusOK,
+		Output:     output,
+		OutputType: outputType.String(),
+		State:      state,
+	}
+}
@@ -0,0 +1,112 @@
+package execservice_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/execservice"
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// inputEchoExecutor returns its input, failing for inputs listed in failOn.
+// Earlier inputs take longer so that completion order differs from input order.
+type inputEchoExecutor struct {
+	failOn map[string]bool
+}
+
+func (e *inputEchoExecutor) TaskExec(ctx context.Context, _ time.Time, _ int, _ *taskengine.TaskDefinition, input any, _ taskengine.DataType) (any, taskengine.DataType, string, error) {
+	s := fmt.Sprintf("%v", input)
+	var n int
+	_, _ = fmt.Sscanf(s, "in-%d", &n)
+	time.Sleep(time.Duration(10-n) * time.Millisecond)
+	if e.failOn[s] {
+		return nil, taskengine.DataTypeAny, "", errors.New("rejected " + s)
+	}
+	return "out-" + s, taskengine.DataTypeString, "ok", nil
+}
+
+func newBatchService(t *testing.T, failOn ...string) execservice.TasksEnvService {
+	t.Helper()
+	fails := map[string]bool{}
+	for _, f := range failOn {
+		fails[f] = true
+	}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, &inputEchoExecutor{failOn: fails}, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+	return execservice.NewTasksEnv(t.Context(), env, nil)
+}
+
+func batchChain() *taskengine.TaskChainDefinition {
+	return &taskengine.TaskChainDefinition{
+		ID: "echo",
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:      "echo",
+				Handler: taskengine.HandleRawString,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestUnit_ExecuteBatch_MixedResultsInOrder(t *testing.T) {
+	service := newBatchService(t, "in-2", "in-5")
+
+	inputs := make([]any, 8)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("in-%d", i)
+	}
+
+	results, err := execservice.ExecuteBatch(context.Background(), service, batchChain(), inputs, taskengine.DataTypeString, execservice.BatchConfig{Concurrency: 3})
+	require.NoError(t, err)
+	require.Len(t, results, len(inputs))
+
+	for i, res := range results {
+		require.Equal(t, i, res.Index)
+		if i == 2 || i == 5 {
+			require.Equal(t, execservice.BatchStatusError, res.Status)
+			require.Contains(t, res.Error, fmt.Sprintf("rejected in-%d", i))
+			require.Nil(t, res.Output)
+			continue
+		}
+		require.Equal(t, execservice.BatchStatusOK, res.Status)
+		require.Equal(t, fmt.Sprintf("out-in-%d", i), res.Output)
+		require.Equal(t, "string", res.OutputType)
+		require.Empty(t, res.Error)
+	}
+}
+
+func TestUnit_ExecuteBatch_Limits(t *testing.T) {
+	service := newBatchService(t)
+
+	_, err := execservice.ExecuteBatch(context.Background(), service, batchChain(), nil, taskengine.DataTypeString, execservice.BatchConfig{})
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+
+	tooMany := make([]any, execservice.MaxBatchSize+1)
+	_, err = execservice.ExecuteBatch(context.Background(), service, batchChain(), tooMany, taskengine.DataTypeString, execservice.BatchConfig{})
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+}
+
+func TestUnit_ExecuteBatch_TimeoutFailsRemainingInputs(t *testing.T) {
+	service := newBatchService(t)
+
+	inputs := []any{"in-0", "in-0", "in-0", "in-0"}
+	results, err := execservice.ExecuteBatch(context.Background(), service, batchChain(), inputs, taskengine.DataTypeString, execservice.BatchConfig{
+		Concurrency: 1,
+		Timeout:     25 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, len(inputs))
+	require.Equal(t, execservice.BatchStatusOK, results[0].Status)
+	require.Equal(t, execservice.BatchStatusError, results[len(results)-1].Status)
+}
@@ -49,10 +49,11 @@ func (s *execService) Execute(ctx context.Context, request *TaskRequest) (*Simpl
 	if request.ModelProvider != "" {
 		providerNames = append(providerNames, request.ModelProvider)
 	}
+	temperature := float32(0.1)
 	response, _, err := s.modelRepo.PromptExecute(ctx, llmrepo.Request{
 		ModelNames:    modelNames,
 		ProviderTypes: providerNames,
-	}, "You are a task processing engine talking to other machines. Return the direct answer without explanation to the given task.", 0.1, request.Prompt)
+	}, "You are a task processing engine talking to other machines. Return the direct answer without explanation to the given task.", &temperature, request.Prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute prompt: %w", err)
 	}
@@ -24,10 +24,15 @@ func (d *activityTrackerDecorator) Execute(ctx context.Context, request *TaskReq
 	if err != nil {
 		reportErrFn(err)
 	} else if response != nil {
-		reportChangeFn(response.ID, map[string]interface{}{
+		change := map[string]interface{}{
 			"prompt":   request.Prompt,
 			"response": response.Response,
-		})
+		}
+		if !libtracker.CaptureContentEnabled(ctx) {
+			delete(change, "prompt")
+			delete(change, "response")
+		}
+		reportChangeFn(response.ID, change)
 	}
 
 	return response, err
@@ -0,0 +1,45 @@
+package execservice_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/contenox/runtime/execservice"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/stretchr/testify/require"
+)
+
+type echoService struct{}
+
+func (echoService) Execute(_ context.Context, request *execservice.TaskRequest) (*execservice.SimpleExecutionResponse, error) {
+	return &execservice.SimpleExecutionResponse{ID: "r1", Response: "echo: " + request.Prompt}, nil
+}
+
+// changeTracker keeps the last value reported through reportChange.
+type changeTracker struct {
+	change any
+}
+
+func (c *changeTracker) Start(context.Context, string, string, ...any) (func(error), func(string, any), func()) {
+	return func(error) {}, func(_ string, data any) { c.change = data }, func() {}
+}
+
+func TestUnit_ExecDecorator_RespectsCaptureContent(t *testing.T) {
+	for name, tc := range map[string]struct {
+		capture bool
+		want    map[string]interface{}
+	}{
+		"captured": {capture: true, want: map[string]interface{}{"prompt": "secret", "response": "echo: secret"}},
+		"redacted": {capture: false, want: map[string]interface{}{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tracker := &changeTracker{}
+			service := execservice.WithActivityTracker(echoService{}, tracker)
+			ctx := libtracker.WithCaptureContent(t.Context(), tc.capture)
+
+			_, err := service.Execute(ctx, &execservice.TaskRequest{Prompt: "secret"})
+			require.NoError(t, err)
+			require.Equal(t, tc.want, tracker.change)
+		})
+	}
+}
@@ -2,21 +2,27 @@ package execservice
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/contenox/runtime/internal/apiframework"
 	"github.com/contenox/runtime/taskengine"
 )
 
 type TasksEnvService interface {
 	Execute(ctx context.Context, chain *taskengine.TaskChainDefinition, input any, inputType taskengine.DataType) (any, taskengine.DataType, []taskengine.CapturedStateUnit, error)
+	// Resume continues a failed execution from its checkpoint, see taskengine.ResumableError.
+	Resume(ctx context.Context, checkpointID string) (any, taskengine.DataType, []taskengine.CapturedStateUnit, error)
+	// Validate checks the chain structure and the args of its hook tasks without executing anything.
+	Validate(ctx context.Context, chain *taskengine.TaskChainDefinition) error
 	taskengine.HookRegistry
 }
 
 type tasksEnvService struct {
 	environmentExec taskengine.EnvExecutor
-	hookRegistry    taskengine.HookRegistry
+	hookRegistry    taskengine.HookRepo
 }
 
-func NewTasksEnv(ctx context.Context, environmentExec taskengine.EnvExecutor, hookRegistry taskengine.HookRegistry) TasksEnvService {
+func NewTasksEnv(ctx context.Context, environmentExec taskengine.EnvExecutor, hookRegistry taskengine.HookRepo) TasksEnvService {
 	return &tasksEnvService{
 		environmentExec: environmentExec,
 		hookRegistry:    hookRegistry,
@@ -24,9 +30,32 @@ func NewTasksEnv(ctx context.Context, environmentExec taskengine.EnvExecutor, ho
 }
 
 func (s *tasksEnvService) Execute(ctx context.Context, chain *taskengine.TaskChainDefinition, input any, inputType taskengine.DataType) (any, taskengine.DataType, []taskengine.CapturedStateUnit, error) {
+	if s.hookRegistry != nil {
+		if err := s.Validate(ctx, chain); err != nil {
+			return nil, taskengine.DataTypeAny, nil, err
+		}
+	}
 	return s.environmentExec.ExecEnv(ctx, chain, input, inputType)
 }
 
+func (s *tasksEnvService) Resume(ctx context.Context, checkpointID string) (any, taskengine.DataType, []taskengine.CapturedStateUnit, error) {
+	if checkpointID == "" {
+		return nil, taskengine.DataTypeAny, nil, fmt.Errorf("checkpoint ID is required %w", apiframework.ErrBadRequest)
+	}
+	return s.environmentExec.ResumeChain(ctx, checkpointID)
+}
+
+func (s *tasksEnvService) Validate(ctx context.Context, chain *taskengine.TaskChainDefinition) error {
+	if chain == nil {
+		return fmt.Errorf("chain is required %w", apiframework.ErrBadRequest)
+	}
+	return taskengine.ValidateChainHooks(ctx, s.hookRegistry, chain)
+}
+
 func (s *tasksEnvService) Supports(ctx context.Context) ([]string, error) {
 	return s.hookRegistry.Supports(ctx)
 }
+
+func (s *tasksEnvService) Schemas(ctx context.Context) ([]taskengine.HookSchema, error) {
+	return s.hookRegistry.Schemas(ctx)
+}
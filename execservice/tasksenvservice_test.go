@@ -0,0 +1,18 @@
+package execservice_test
+
+import (
+	"testing"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_TasksEnvService_Resume(t *testing.T) {
+	service := newBatchService(t)
+
+	_, _, _, err := service.Resume(t.Context(), "")
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+
+	_, _, _, err = service.Resume(t.Context(), "req-1")
+	require.ErrorIs(t, err, apiframework.ErrBadRequest, "checkpoints are not enabled")
+}
@@ -31,22 +31,91 @@ func (d *activityTrackerTaskEnvDecorator) Execute(ctx context.Context, chain *ta
 	if err != nil {
 		reportErrFn(err)
 	} else {
-		reportChangeFn(chainID, map[string]any{
+		change := map[string]any{
 			"input":      input,
 			"result":     result,
 			"chainID":    chainID,
 			"stacktrace": stacktrace,
 			"outputType": outputType.String(),
-		})
+		}
+		if !libtracker.CaptureContentEnabled(ctx) {
+			delete(change, "input")
+			delete(change, "result")
+			change["stacktrace"] = redactStackTrace(stacktrace)
+		}
+		reportChangeFn(chainID, change)
 	}
 
 	return result, outputType, stacktrace, err
 }
 
+// redactStackTrace returns a copy of the captured state without task inputs and outputs.
+func redactStackTrace(stacktrace []taskengine.CapturedStateUnit) []taskengine.CapturedStateUnit {
+	redacted := make([]taskengine.CapturedStateUnit, len(stacktrace))
+	for i, unit := range stacktrace {
+		unit.Input = ""
+		unit.Output = ""
+		redacted[i] = unit
+	}
+	return redacted
+}
+
+func (d *activityTrackerTaskEnvDecorator) Resume(ctx context.Context, checkpointID string) (any, taskengine.DataType, []taskengine.CapturedStateUnit, error) {
+	reportErrFn, reportChangeFn, endFn := d.tracker.Start(
+		ctx,
+		"resume",
+		"task-chain",
+		"checkpointID", checkpointID,
+	)
+	defer endFn()
+
+	result, outputType, stacktrace, err := d.service.Resume(ctx, checkpointID)
+	if err != nil {
+		reportErrFn(err)
+	} else {
+		change := map[string]any{
+			"result":     result,
+			"stacktrace": stacktrace,
+			"outputType": outputType.String(),
+		}
+		if !libtracker.CaptureContentEnabled(ctx) {
+			delete(change, "result")
+			change["stacktrace"] = redactStackTrace(stacktrace)
+		}
+		reportChangeFn(checkpointID, change)
+	}
+
+	return result, outputType, stacktrace, err
+}
+
+func (d *activityTrackerTaskEnvDecorator) Validate(ctx context.Context, chain *taskengine.TaskChainDefinition) error {
+	var chainID string
+	if chain != nil {
+		chainID = chain.ID
+	}
+	reportErrFn, _, endFn := d.tracker.Start(
+		ctx,
+		"validate",
+		"task-chain",
+		"chainID", chainID,
+	)
+	defer endFn()
+
+	err := d.service.Validate(ctx, chain)
+	if err != nil {
+		reportErrFn(err)
+	}
+	return err
+}
+
 func (d *activityTrackerTaskEnvDecorator) Supports(ctx context.Context) ([]string, error) {
 	return d.service.Supports(ctx)
 }
 
+func (d *activityTrackerTaskEnvDecorator) Schemas(ctx context.Context) ([]taskengine.HookSchema, error) {
+	return d.service.Schemas(ctx)
+}
+
 func EnvWithActivityTracker(service TasksEnvService, tracker libtracker.ActivityTracker) TasksEnvService {
 	return &activityTrackerTaskEnvDecorator{
 		service: service,
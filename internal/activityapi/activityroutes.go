@@ -0,0 +1,158 @@
+package activityapi
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	serverops "github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/taskengine"
+)
+
+// ActivityStore queries and streams recorded activity events, e.g. the KV activity sink.
+type ActivityStore interface {
+	GetActivityLogs(ctx context.Context, limit int, filter taskengine.ActivityFilter) ([]taskengine.TrackedEvent, error)
+	GetRecentRequestIDs(ctx context.Context, limit int, filter taskengine.ActivityFilter) ([]taskengine.TrackedRequest, error)
+	ExportActivityLogs(ctx context.Context, w io.Writer, filter taskengine.ActivityFilter) (int, error)
+}
+
+func AddActivityRoutes(mux *http.ServeMux, store ActivityStore) {
+	h := &activityManager{store: store}
+	mux.HandleFunc("GET /activity/logs", h.logs)
+	mux.HandleFunc("GET /activity/requests", h.requests)
+	mux.HandleFunc("GET /activity/export", h.export)
+}
+
+type activityManager struct {
+	store ActivityStore
+}
+
+// Lists recent activity events, newest first.
+//
+// Events can be narrowed to an operation and to a range of start times.
+func (h *activityManager) logs(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseActivityFilter(
+		serverops.GetQueryParam(r, "from", "", "Optional RFC3339 lower bound of the event start time."),
+		serverops.GetQueryParam(r, "to", "", "Optional RFC3339 upper bound of the event start time."),
+		serverops.GetQueryParam(r, "operation", "", "Only list events of this operation."),
+	)
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+	limit, err := parseLimit(serverops.GetQueryParam(r, "limit", "100", "The maximum number of events to return."))
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+	events, err := h.store.GetActivityLogs(r.Context(), limit, filter)
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+	_ = serverops.Encode(w, r, http.StatusOK, events) // @response []taskengine.TrackedEvent
+}
+
+// Lists the IDs of recently tracked requests.
+//
+// With a filter, only requests with matching events are listed, newest first.
+func (h *activityManager) requests(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseActivityFilter(
+		serverops.GetQueryParam(r, "from", "", "Optional RFC3339 lower bound of the event start time."),
+		serverops.GetQueryParam(r, "to", "", "Optional RFC3339 upper bound of the event start time."),
+		serverops.GetQueryParam(r, "operation", "", "Only list requests with events of this operation."),
+	)
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+	limit, err := parseLimit(serverops.GetQueryParam(r, "limit", "100", "The maximum number of requests to return."))
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+	requests, err := h.store.GetRecentRequestIDs(r.Context(), limit, filter)
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+	_ = serverops.Encode(w, r, http.StatusOK, requests) // @response []taskengine.TrackedRequest
+}
+
+// Exports activity events as newline-delimited JSON (JSON Lines).
+//
+// Each line is one event, oldest first, with a stable schema:
+// timestamp, eventType ("<subject>.<operation>"), subject, operation, requestId,
+// entityId, durationMs, error and metadata.
+// The export is streamed and gzip-compressed when the client sends "Accept-Encoding: gzip".
+// Example line:
+// {"timestamp":"2025-01-01T12:00:00Z","eventType":"backend.create","subject":"backend","operation":"create","requestId":"r1","durationMs":3.2}
+func (h *activityManager) export(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseActivityFilter(
+		serverops.GetQueryParam(r, "from", "", "Optional RFC3339 lower bound of the event start time."),
+		serverops.GetQueryParam(r, "to", "", "Optional RFC3339 upper bound of the event start time."),
+		serverops.GetQueryParam(r, "operation", "", "Only export events of this operation."),
+	)
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	var out io.Writer = w
+	if acceptsGzip(r) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		out = gz
+	}
+	w.WriteHeader(http.StatusOK)
+
+	// The status is sent already, failures can only end the stream early.
+	if _, err := h.store.ExportActivityLogs(r.Context(), out, filter); err != nil {
+		log.Printf("activity export aborted: %v", err)
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(enc), ";")
+		if strings.EqualFold(name, "gzip") {
+			return true
+		}
+	}
+	return false
+}
+
+func parseActivityFilter(from, to, operation string) (taskengine.ActivityFilter, error) {
+	filter := taskengine.ActivityFilter{Operation: operation}
+	for name, bound := range map[string]struct {
+		raw string
+		dst *time.Time
+	}{"from": {from, &filter.From}, "to": {to, &filter.To}} {
+		if bound.raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, bound.raw)
+		if err != nil {
+			return filter, fmt.Errorf("%w: invalid %s, expected RFC3339", serverops.ErrUnprocessableEntity, name)
+		}
+		*bound.dst = t
+	}
+	return filter, nil
+}
+
+func parseLimit(raw string) (int, error) {
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("%w: invalid limit, expected a positive integer", serverops.ErrUnprocessableEntity)
+	}
+	return limit, nil
+}
@@ -0,0 +1,98 @@
+package activityapi_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/activityapi"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	filter taskengine.ActivityFilter
+	limit  int
+}
+
+func (f *fakeStore) GetActivityLogs(_ context.Context, limit int, filter taskengine.ActivityFilter) ([]taskengine.TrackedEvent, error) {
+	f.limit, f.filter = limit, filter
+	return []taskengine.TrackedEvent{{ID: "e1", Operation: filter.Operation}}, nil
+}
+
+func (f *fakeStore) GetRecentRequestIDs(_ context.Context, limit int, filter taskengine.ActivityFilter) ([]taskengine.TrackedRequest, error) {
+	f.limit, f.filter = limit, filter
+	return []taskengine.TrackedRequest{{ID: "req-1"}}, nil
+}
+
+func (f *fakeStore) ExportActivityLogs(_ context.Context, w io.Writer, filter taskengine.ActivityFilter) (int, error) {
+	f.filter = filter
+	_, err := fmt.Fprintln(w, `{"eventType":"backend.create"}`)
+	return 1, err
+}
+
+func TestUnit_ExportActivity(t *testing.T) {
+	exporter := &fakeStore{}
+	mux := http.NewServeMux()
+	activityapi.AddActivityRoutes(mux, exporter)
+
+	req := httptest.NewRequest(http.MethodGet, "/activity/export?from=2025-01-01T00:00:00Z&to=2025-01-02T00:00:00Z", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	require.Equal(t, "{\"eventType\":\"backend.create\"}\n", rec.Body.String())
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), exporter.filter.From)
+	require.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), exporter.filter.To)
+
+	req = httptest.NewRequest(http.MethodGet, "/activity/export", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip;q=0.8")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	gz, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, "{\"eventType\":\"backend.create\"}\n", string(body))
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/activity/export?from=yesterday", nil))
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestUnit_ListActivity_Filters(t *testing.T) {
+	store := &fakeStore{}
+	mux := http.NewServeMux()
+	activityapi.AddActivityRoutes(mux, store)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/activity/logs?operation=chat&from=2025-01-01T00:00:00Z&limit=5", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var events []taskengine.TrackedEvent
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	require.Len(t, events, 1)
+	require.Equal(t, 5, store.limit)
+	require.Equal(t, "chat", store.filter.Operation)
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), store.filter.From)
+	require.True(t, store.filter.To.IsZero())
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/activity/requests?to=2025-01-02T00:00:00Z", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[{"id":"req-1"}]`, rec.Body.String())
+	require.Equal(t, 100, store.limit)
+	require.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), store.filter.To)
+
+	for _, target := range []string{"/activity/logs?to=tomorrow", "/activity/requests?limit=none", "/activity/logs?limit=0"} {
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code, target)
+	}
+}
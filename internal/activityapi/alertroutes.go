@@ -0,0 +1,40 @@
+package activityapi
+
+import (
+	"context"
+	"net/http"
+
+	serverops "github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/taskengine"
+)
+
+// AlertStore lists the alerts raised by the activity alert rules, e.g. the KV activity sink.
+type AlertStore interface {
+	FetchAlerts(ctx context.Context, limit int) ([]taskengine.Alert, error)
+}
+
+func AddAlertRoutes(mux *http.ServeMux, store AlertStore) {
+	h := &alertManager{store: store}
+	mux.HandleFunc("GET /activity/alerts", h.list)
+}
+
+type alertManager struct {
+	store AlertStore
+}
+
+// Lists the alerts raised by the configured alert rules, newest first.
+//
+// Rules are read from the activity store at startup; see taskengine.AlertRule.
+func (h *alertManager) list(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(serverops.GetQueryParam(r, "limit", "100", "The maximum number of alerts to return."))
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+	alerts, err := h.store.FetchAlerts(r.Context(), limit)
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+	_ = serverops.Encode(w, r, http.StatusOK, alerts) // @response []taskengine.Alert
+}
@@ -0,0 +1,37 @@
+package activityapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/contenox/runtime/internal/activityapi"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAlerts struct {
+	limit int
+}
+
+func (f *fakeAlerts) FetchAlerts(_ context.Context, limit int) ([]taskengine.Alert, error) {
+	f.limit = limit
+	return []taskengine.Alert{{ID: "a1", RuleID: "slow-tasks", Severity: taskengine.AlertSeverityWarning}}, nil
+}
+
+func TestUnit_ListAlerts(t *testing.T) {
+	store := &fakeAlerts{}
+	mux := http.NewServeMux()
+	activityapi.AddAlertRoutes(mux, store)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/activity/alerts?limit=10", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, 10, store.limit)
+	require.Contains(t, rec.Body.String(), `"ruleId":"slow-tasks"`)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/activity/alerts?limit=x", nil))
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
@@ -0,0 +1,46 @@
+package activityapi
+
+import (
+	"net/http"
+
+	serverops "github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/taskengine"
+)
+
+// InFlightManager lists and cancels running chain executions, e.g. the
+// taskengine.InFlightRegistry.
+type InFlightManager interface {
+	List() []taskengine.InFlightExecution
+	Cancel(requestID string) error
+}
+
+func AddInFlightRoutes(mux *http.ServeMux, manager InFlightManager) {
+	h := &inFlightManager{manager: manager}
+	mux.HandleFunc("GET /activity/in-flight", h.list)
+	mux.HandleFunc("POST /activity/requests/{id}/cancel", h.cancel)
+}
+
+type inFlightManager struct {
+	manager InFlightManager
+}
+
+// Lists the chain executions currently running, oldest first.
+//
+// Each entry names the request, the chain, when the execution started and the task it is running.
+func (h *inFlightManager) list(w http.ResponseWriter, r *http.Request) {
+	_ = serverops.Encode(w, r, http.StatusOK, h.manager.List()) // @response []taskengine.InFlightExecution
+}
+
+// Cancels the running executions of a request.
+//
+// The execution's context is cancelled, which aborts model and hook calls in progress;
+// the execution then fails with "execution cancelled by operator".
+// Fails with 404 Not Found if no execution of the request is running.
+func (h *inFlightManager) cancel(w http.ResponseWriter, r *http.Request) {
+	id := serverops.GetPathParam(r, "id", "The request ID of the execution to cancel.")
+	if err := h.manager.Cancel(id); err != nil {
+		_ = serverops.Error(w, r, err, serverops.UpdateOperation)
+		return
+	}
+	_ = serverops.Encode(w, r, http.StatusOK, "cancelled") // @response string
+}
@@ -0,0 +1,56 @@
+package activityapi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/activityapi"
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeInFlight struct {
+	executions []taskengine.InFlightExecution
+	cancelled  []string
+}
+
+func (f *fakeInFlight) List() []taskengine.InFlightExecution { return f.executions }
+
+func (f *fakeInFlight) Cancel(requestID string) error {
+	for _, e := range f.executions {
+		if e.RequestID == requestID {
+			f.cancelled = append(f.cancelled, requestID)
+			return nil
+		}
+	}
+	return fmt.Errorf("no execution in flight for request %s %w", requestID, apiframework.ErrNotFound)
+}
+
+func TestUnit_InFlightRoutes(t *testing.T) {
+	manager := &fakeInFlight{executions: []taskengine.InFlightExecution{{
+		RequestID: "req-1", ChainID: "chat", StartedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), CurrentTask: "generate",
+	}}}
+	mux := http.NewServeMux()
+	activityapi.AddInFlightRoutes(mux, manager)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/activity/in-flight", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listed []taskengine.InFlightExecution
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Equal(t, manager.executions, listed)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/activity/requests/req-1/cancel", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, []string{"req-1"}, manager.cancelled)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/activity/requests/req-9/cancel", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,38 @@
+package apiframework
+
+import (
+	"context"
+	"net/http"
+)
+
+// DefaultMaxBodyBytes is the request body limit applied by Decode when none is configured.
+const DefaultMaxBodyBytes int64 = 10 << 20
+
+// DefaultMaxBatchBodyBytes is the default body limit of routes that accept batches,
+// e.g. embedding or task chain batches.
+const DefaultMaxBatchBodyBytes int64 = 50 << 20
+
+type maxBodyBytesKey struct{}
+
+// MaxBodySizeMiddleware sets the server-wide request body limit enforced by Decode.
+// Bodies exceeding it are rejected with 413 Request Entity Too Large.
+func MaxBodySizeMiddleware(limit int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), maxBodyBytesKey{}, limit)))
+	})
+}
+
+// WithMaxBodySize overrides the request body limit for a single route.
+func WithMaxBodySize(limit int64, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(w, r.WithContext(context.WithValue(r.Context(), maxBodyBytesKey{}, limit)))
+	}
+}
+
+// maxBodyBytes returns the body limit for the request.
+func maxBodyBytes(r *http.Request) int64 {
+	if limit, ok := r.Context().Value(maxBodyBytesKey{}).(int64); ok && limit > 0 {
+		return limit
+	}
+	return DefaultMaxBodyBytes
+}
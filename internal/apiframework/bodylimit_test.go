@@ -0,0 +1,59 @@
+package apiframework_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/stretchr/testify/require"
+)
+
+type payload struct {
+	Text string `json:"text"`
+}
+
+func decodeHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := apiframework.Decode[payload](r)
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.CreateOperation)
+		return
+	}
+	_ = apiframework.Encode(w, r, http.StatusOK, p)
+}
+
+// body returns a JSON payload of exactly size bytes.
+func body(size int) string {
+	const envelope = `{"text":""}`
+	return `{"text":"` + strings.Repeat("a", size-len(envelope)) + `"}`
+}
+
+func post(handler http.Handler, payload string) int {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestUnit_Decode_EnforcesMaxBodySize(t *testing.T) {
+	handler := apiframework.MaxBodySizeMiddleware(64, http.HandlerFunc(decodeHandler))
+
+	require.Equal(t, http.StatusOK, post(handler, body(64)))
+	require.Equal(t, http.StatusRequestEntityTooLarge, post(handler, body(65)))
+}
+
+func TestUnit_Decode_RouteOverridesMaxBodySize(t *testing.T) {
+	handler := apiframework.MaxBodySizeMiddleware(64, apiframework.WithMaxBodySize(128, decodeHandler))
+
+	require.Equal(t, http.StatusOK, post(handler, body(128)))
+	require.Equal(t, http.StatusRequestEntityTooLarge, post(handler, body(129)))
+}
+
+func TestUnit_Decode_DefaultMaxBodySize(t *testing.T) {
+	handler := http.HandlerFunc(decodeHandler)
+
+	require.Equal(t, http.StatusOK, post(handler, body(int(apiframework.DefaultMaxBodyBytes))))
+	require.Equal(t, http.StatusRequestEntityTooLarge, post(handler, body(int(apiframework.DefaultMaxBodyBytes)+1)))
+}
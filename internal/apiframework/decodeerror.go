@@ -35,6 +35,11 @@ func HandleAPIError(resp *http.Response) error {
 	if jsonErr := json.Unmarshal(body, &apiErr); jsonErr == nil && apiErr.ErrorProperty != "" {
 		return &apiErr
 	}
+	// The OpenAI-compatible routes use the OpenAI error envelope.
+	var openAIErr OpenAIErrorResponse
+	if jsonErr := json.Unmarshal(body, &openAIErr); jsonErr == nil && openAIErr.Error.Message != "" {
+		return &APIError{ErrorProperty: openAIErr.Error.Message}
+	}
 
 	// If not valid JSON error format, return a generic error with response body
 	bodyStr := string(body)
@@ -37,7 +37,7 @@ func Decode[T any](r *http.Request) (T, error) {
 
 	contentTypeHeader := r.Header.Get("Content-Type")
 
-	bodyBytes, err := io.ReadAll(r.Body)
+	bodyBytes, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBodyBytes(r)))
 	if err != nil {
 		return v, fmt.Errorf("%w: %w", ErrReadingRequestBody, err)
 	}
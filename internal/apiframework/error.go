@@ -19,6 +19,8 @@ var (
 	ErrMissingParameter      = errors.New("serverops: missing parameter")
 	ErrEmptyRequest          = errors.New("serverops: empty request")
 	ErrEmptyRequestBody      = errors.New("serverops: empty request body")
+	// ErrPoolSaturated is returned when a pool has no capacity left for another model call.
+	ErrPoolSaturated = errors.New("serverops: pool saturated")
 )
 
 // The generic error types for common HTTP status codes
@@ -153,6 +155,9 @@ func mapErrorToStatus(op Operation, err error) int {
 	if errors.Is(err, libdb.ErrMaxRowsReached) {
 		return http.StatusTooManyRequests // data-count limit reached scenario
 	}
+	if errors.Is(err, ErrPoolSaturated) {
+		return http.StatusTooManyRequests // 429
+	}
 	// These DB errors might be client input or server issues, 409 or 422 are candidates
 	if errors.Is(err, libdb.ErrDataTruncation) ||
 		errors.Is(err, libdb.ErrNumericOutOfRange) ||
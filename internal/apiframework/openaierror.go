@@ -0,0 +1,63 @@
+package apiframework
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// OpenAIErrorResponse is the error envelope of the OpenAI API.
+type OpenAIErrorResponse struct {
+	Error OpenAIErrorDetail `json:"error"`
+}
+
+type OpenAIErrorDetail struct {
+	Message string  `json:"message" example:"serverops: bad request"`
+	Type    string  `json:"type" example:"invalid_request_error"`
+	Param   *string `json:"param"`
+	Code    *string `json:"code" example:"bad_request"`
+}
+
+// openAIErrorType maps a status code to the error type and code OpenAI uses for it.
+func openAIErrorType(status int) (string, string) {
+	switch {
+	case status == http.StatusUnauthorized:
+		return "authentication_error", "invalid_api_key"
+	case status == http.StatusForbidden:
+		return "permission_error", "forbidden"
+	case status == http.StatusNotFound:
+		return "invalid_request_error", "not_found"
+	case status == http.StatusConflict:
+		return "invalid_request_error", "conflict"
+	case status == http.StatusRequestEntityTooLarge:
+		return "invalid_request_error", "request_too_large"
+	case status == http.StatusTooManyRequests:
+		return "rate_limit_error", "rate_limit_exceeded"
+	case status >= 500:
+		return "server_error", "internal_error"
+	default:
+		return "invalid_request_error", "bad_request"
+	}
+}
+
+// OpenAIError sends err like Error, with the same status code, but in the
+// error envelope of the OpenAI API so that OpenAI SDK clients can parse it.
+// It is meant for the OpenAI-compatible /v1 routes only.
+func OpenAIError(w http.ResponseWriter, r *http.Request, err error, op Operation) error {
+	status := mapErrorToStatus(op, err)
+	errType, code := openAIErrorType(status)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	response := OpenAIErrorResponse{Error: OpenAIErrorDetail{
+		Message: err.Error(),
+		Type:    errType,
+		Code:    &code,
+	}}
+	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
+		fmt.Printf("SERVER ERROR: Failed to encode error JSON response after writing header: %v (Original error: %v)\n", encodeErr, err)
+		return fmt.Errorf("encode json: %w (original error: %v)", encodeErr, err)
+	}
+	return nil
+}
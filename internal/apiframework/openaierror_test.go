@@ -0,0 +1,46 @@
+package apiframework_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_OpenAIError(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err    error
+		status int
+		typ    string
+	}{
+		"bad request":  {fmt.Errorf("unknown role %w", apiframework.ErrBadRequest), http.StatusBadRequest, "invalid_request_error"},
+		"not found":    {fmt.Errorf("chain x %w", apiframework.ErrNotFound), http.StatusNotFound, "invalid_request_error"},
+		"unauthorized": {apiframework.ErrUnauthorized, http.StatusUnauthorized, "authentication_error"},
+		"saturated":    {apiframework.ErrPoolSaturated, http.StatusTooManyRequests, "rate_limit_error"},
+		"unclassified": {fmt.Errorf("backend exploded"), http.StatusInternalServerError, "server_error"},
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+		require.NoError(t, apiframework.OpenAIError(rec, req, tc.err, apiframework.ExecuteOperation), name)
+		require.Equal(t, tc.status, rec.Code, name)
+
+		var body apiframework.OpenAIErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), name)
+		require.Equal(t, tc.err.Error(), body.Error.Message, name)
+		require.Equal(t, tc.typ, body.Error.Type, name)
+		require.NotNil(t, body.Error.Code, name)
+	}
+}
+
+func TestUnit_HandleAPIError_OpenAIEnvelope(t *testing.T) {
+	rec := httptest.NewRecorder()
+	_ = apiframework.OpenAIError(rec, httptest.NewRequest(http.MethodPost, "/v1/embeddings", nil), apiframework.ErrBadRequest, apiframework.CreateOperation)
+
+	err := apiframework.HandleAPIError(rec.Result())
+	require.True(t, apiframework.IsAPIError(err))
+	require.Equal(t, apiframework.ErrBadRequest.Error(), err.Error())
+}
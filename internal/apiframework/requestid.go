@@ -3,18 +3,23 @@ package apiframework
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/contenox/runtime/libtracker"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
 )
 
+// RequestIDMiddleware accepts the caller's X-Request-ID or generates one,
+// stores it in the request context and echoes it in the response headers.
 func RequestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestID := r.Header.Get("X-Request-ID")
 		if requestID == "" {
 			requestID = uuid.New().String()
 		}
+		w.Header().Set("X-Request-ID", requestID)
 
 		ctx := context.WithValue(r.Context(), libtracker.ContextKeyRequestID, requestID)
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -22,9 +27,11 @@ func RequestIDMiddleware(next http.Handler) http.Handler {
 }
 
 // TracingMiddleware extracts or generates trace and span IDs.
+// An incoming W3C trace context is also made available to OpenTelemetry,
+// so spans created while handling the request join the caller's trace.
 func TracingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
+		ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
 		traceID := ""
 		spanID := ""
 
@@ -50,3 +57,19 @@ func TracingMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// CaptureContentMiddleware decides whether prompt and response content may be recorded
+// for the request. The X-Capture-Content header overrides the server-wide default.
+func CaptureContentMiddleware(defaultCapture bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capture := defaultCapture
+		if header := r.Header.Get("X-Capture-Content"); header != "" {
+			if parsed, err := strconv.ParseBool(header); err == nil {
+				capture = parsed
+			}
+		}
+
+		ctx := libtracker.WithCaptureContent(r.Context(), capture)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
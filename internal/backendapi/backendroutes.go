@@ -22,6 +22,7 @@ func AddBackendRoutes(mux *http.ServeMux, backendService backendservice.Service,
 	mux.HandleFunc("GET /backends/{id}", b.getBackend)
 	mux.HandleFunc("PUT /backends/{id}", b.updateBackend)
 	mux.HandleFunc("DELETE /backends/{id}", b.deleteBackend)
+	mux.HandleFunc("POST /backends/{id}/sync", b.syncBackend)
 }
 
 type backendSummary struct {
@@ -29,6 +30,8 @@ type backendSummary struct {
 	Name    string `json:"name" example:"backend-name"`
 	BaseURL string `json:"baseUrl" example:"http://localhost:11434"`
 	Type    string `json:"type" example:"ollama"`
+	// HasAPIKey reports whether the backend has its own API key; the key itself is never returned.
+	HasAPIKey bool `json:"hasApiKey" example:"false"`
 
 	Models       []string                    `json:"models"`
 	PulledModels []statetype.ModelPullStatus `json:"pulledModels" openapi_include_type:"statetype.ModelPullStatus"`
@@ -108,10 +111,11 @@ func (b *backendManager) listBackends(w http.ResponseWriter, r *http.Request) {
 	resp := []backendSummary{}
 	for _, backend := range backends {
 		item := backendSummary{
-			ID:      backend.ID,
-			Name:    backend.Name,
-			BaseURL: backend.BaseURL,
-			Type:    backend.Type,
+			ID:        backend.ID,
+			Name:      backend.Name,
+			BaseURL:   backend.BaseURL,
+			Type:      backend.Type,
+			HasAPIKey: backend.HasAPIKey,
 		}
 		ok := false
 		var itemState statetype.BackendRuntimeState
@@ -139,6 +143,7 @@ type backendDetails struct {
 	Name         string                      `json:"name" example:"ollama-production"`
 	BaseURL      string                      `json:"baseUrl" example:"http://ollama-prod.internal:11434"`
 	Type         string                      `json:"type" example:"ollama"`
+	HasAPIKey    bool                        `json:"hasApiKey" example:"false"`
 	Models       []string                    `json:"models" example:"[\"mistral:instruct\", \"llama2:7b\", \"nomic-embed-text:latest\"]"`
 	PulledModels []statetype.ModelPullStatus `json:"pulledModels" openapi_include_type:"statetype.ModelPullStatus"`
 	Error        string                      `json:"error,omitempty" example:"connection timeout: context deadline exceeded"`
@@ -184,6 +189,7 @@ func (b *backendManager) getBackend(w http.ResponseWriter, r *http.Request) {
 		Name:         backend.Name,
 		BaseURL:      backend.BaseURL,
 		Type:         backend.Type,
+		HasAPIKey:    backend.HasAPIKey,
 		Models:       []string{},
 		PulledModels: []statetype.ModelPullStatus{},
 		Error:        "",
@@ -244,3 +250,24 @@ func (b *backendManager) deleteBackend(w http.ResponseWriter, r *http.Request) {
 
 	_ = serverops.Encode(w, r, http.StatusOK, "backend removed") // @response string
 }
+
+// Discovers the models a backend hosts and reconciles them with the model store.
+//
+// Models not yet known are created, and models not in any of the backend's pools are assigned to them.
+// Existing models and pool assignments are never changed or removed; models assigned to the
+// backend's pools but not reported by the backend are listed as missing.
+func (b *backendManager) syncBackend(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := serverops.GetPathParam(r, "id", "The unique identifier for the backend.")
+	if id == "" {
+		_ = serverops.Error(w, r, fmt.Errorf("missing id parameter %w", serverops.ErrBadPathValue), serverops.UpdateOperation)
+		return
+	}
+	result, err := b.service.SyncModels(ctx, id)
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.UpdateOperation)
+		return
+	}
+
+	_ = serverops.Encode(w, r, http.StatusOK, result) // @response backendservice.SyncResult
+}
@@ -0,0 +1,69 @@
+package backendapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	serverops "github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/jobservice"
+)
+
+func AddJobRoutes(mux *http.ServeMux, jobService jobservice.Service) {
+	s := &jobManager{service: jobService}
+	mux.HandleFunc("GET /jobs", s.listJobs)
+	mux.HandleFunc("GET /jobs/counts", s.countJobs)
+}
+
+type jobManager struct {
+	service jobservice.Service
+}
+
+// Lists queued jobs, newest first.
+//
+// Jobs can be filtered by task type and are paginated by creation time.
+func (s *jobManager) listJobs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskType := serverops.GetQueryParam(r, "taskType", "", "Only list jobs of this task type (e.g. 'model_download').")
+	limitStr := serverops.GetQueryParam(r, "limit", "100", "The maximum number of items to return per page.")
+	cursorStr := serverops.GetQueryParam(r, "cursor", "", "An optional RFC3339Nano timestamp to fetch the next page of results.")
+
+	var cursor *time.Time
+	if cursorStr != "" {
+		t, err := time.Parse(time.RFC3339Nano, cursorStr)
+		if err != nil {
+			err = fmt.Errorf("%w: invalid cursor format, expected RFC3339Nano", serverops.ErrUnprocessableEntity)
+			_ = serverops.Error(w, r, err, serverops.ListOperation)
+			return
+		}
+		cursor = &t
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil {
+		err = fmt.Errorf("%w: invalid limit format, expected integer", serverops.ErrUnprocessableEntity)
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+
+	jobs, err := s.service.List(ctx, taskType, cursor, limit)
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+
+	_ = serverops.Encode(w, r, http.StatusOK, jobs) // @response []runtimetypes.Job
+}
+
+// Returns the number of queued jobs per task type.
+func (s *jobManager) countJobs(w http.ResponseWriter, r *http.Request) {
+	counts, err := s.service.CountByType(r.Context())
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+
+	_ = serverops.Encode(w, r, http.StatusOK, counts) // @response map[string]int64
+}
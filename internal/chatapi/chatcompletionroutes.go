@@ -19,6 +19,7 @@ func AddChatRoutes(mux *http.ServeMux, chatService chatservice.Service) {
 
 	// OpenAI-compatible endpoints
 	mux.HandleFunc("POST /{chainID}/v1/chat/completions", h.openAIChatCompletions)
+	mux.HandleFunc("POST /{chainID}/v1/chat/completions/tokens", h.countTokens)
 }
 
 type handler struct {
@@ -41,12 +42,13 @@ type openAIChatResponse struct {
 // This endpoint provides OpenAI-compatible chat completions by executing
 // the configured task chain with the provided request data.
 // The task chain must be configured first using the /chat/taskchain endpoint.
+// Errors are returned in the OpenAI error format: {"error": {"message", "type", "param", "code"}}.
 func (h *handler) openAIChatCompletions(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	chainID := apiframework.GetPathParam(r, "chainID", "The ID of the task chain to use.")
 	req, err := apiframework.Decode[taskengine.OpenAIChatRequest](r) // @request taskengine.OpenAIChatRequest
 	if err != nil {
-		_ = apiframework.Error(w, r, err, apiframework.CreateOperation)
+		_ = apiframework.OpenAIError(w, r, err, apiframework.CreateOperation)
 		return
 	}
 
@@ -54,7 +56,7 @@ func (h *handler) openAIChatCompletions(w http.ResponseWriter, r *http.Request)
 
 	chatResp, traces, err := h.service.OpenAIChatCompletions(ctx, chainID, req)
 	if err != nil {
-		_ = apiframework.Error(w, r, err, apiframework.CreateOperation)
+		_ = apiframework.OpenAIError(w, r, err, apiframework.CreateOperation)
 		return
 	}
 	resp := openAIChatResponse{
@@ -73,6 +75,27 @@ func (h *handler) openAIChatCompletions(w http.ResponseWriter, r *http.Request)
 	_ = apiframework.Encode(w, r, http.StatusOK, resp) // @response chatapi.OpenAIChatResponse
 }
 
+// Counts the prompt tokens a chat completion request would consume, without generating.
+//
+// The messages are assembled exactly as for /v1/chat/completions, including the system
+// instruction of the chain's chat model task, and counted with the model's tokenizer.
+func (h *handler) countTokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	chainID := apiframework.GetPathParam(r, "chainID", "The ID of the task chain to use.")
+	req, err := apiframework.Decode[taskengine.OpenAIChatRequest](r) // @request taskengine.OpenAIChatRequest
+	if err != nil {
+		_ = apiframework.OpenAIError(w, r, err, apiframework.ExecuteOperation)
+		return
+	}
+
+	count, err := h.service.CountTokens(ctx, chainID, req)
+	if err != nil {
+		_ = apiframework.OpenAIError(w, r, err, apiframework.ExecuteOperation)
+		return
+	}
+	_ = apiframework.Encode(w, r, http.StatusOK, count) // @response taskengine.ChatTokenCount
+}
+
 type chainIDResponse struct {
 	// The ID of the Task-Chain used as default for Open-AI chat/completions.
 	ChainID string `json:"taskChainID" example:"openai-compatible-chain"`
@@ -0,0 +1,55 @@
+package chatapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/internal/chatapi"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+type failingChat struct{ err error }
+
+func (f failingChat) OpenAIChatCompletions(context.Context, string, taskengine.OpenAIChatRequest) (*taskengine.OpenAIChatResponse, []taskengine.CapturedStateUnit, error) {
+	return nil, nil, f.err
+}
+
+func (f failingChat) CountTokens(context.Context, string, taskengine.OpenAIChatRequest) (*taskengine.ChatTokenCount, error) {
+	return nil, f.err
+}
+
+func TestUnit_ChatCompletions_OpenAIErrorFormat(t *testing.T) {
+	mux := http.NewServeMux()
+	chatapi.AddChatRoutes(mux, failingChat{err: fmt.Errorf("message 0: unknown message role %q %w", "ai", apiframework.ErrBadRequest)})
+
+	for name, tc := range map[string]struct {
+		body   string
+		status int
+	}{
+		"malformed body": {`{"messages": [`, http.StatusUnprocessableEntity},
+		"unknown role":   {`{"model": "m", "messages": [{"role": "ai", "content": "hi"}]}`, http.StatusBadRequest},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/chain/v1/chat/completions", strings.NewReader(tc.body)))
+		require.Equal(t, tc.status, rec.Code, name)
+
+		var resp struct {
+			Error struct {
+				Message string  `json:"message"`
+				Type    string  `json:"type"`
+				Code    *string `json:"code"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp), name)
+		require.NotEmpty(t, resp.Error.Message, name)
+		require.Equal(t, "invalid_request_error", resp.Error.Type, name)
+		require.NotNil(t, resp.Error.Code, name)
+	}
+}
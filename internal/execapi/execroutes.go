@@ -2,6 +2,7 @@ package execapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -12,7 +13,9 @@ import (
 	"github.com/contenox/runtime/taskengine"
 )
 
-func AddExecRoutes(mux *http.ServeMux, promptService execservice.ExecService, taskService execservice.TasksEnvService, embedService embedservice.Service) {
+// AddExecRoutes registers the execution routes. The batch routes (/tasks/batch,
+// /embed and /v1/embeddings) accept bodies up to batchBodyLimit bytes.
+func AddExecRoutes(mux *http.ServeMux, promptService execservice.ExecService, taskService execservice.TasksEnvService, embedService embedservice.Service, batchBodyLimit int64) {
 	f := &taskManager{
 		promptService: promptService,
 		taskService:   taskService,
@@ -20,8 +23,13 @@ func AddExecRoutes(mux *http.ServeMux, promptService execservice.ExecService, ta
 	}
 	mux.HandleFunc("POST /execute", f.executeSimpleTask)
 	mux.HandleFunc("POST /tasks", f.executeTaskChain)
+	mux.HandleFunc("POST /tasks/batch", serverops.WithMaxBodySize(batchBodyLimit, f.executeTaskChainBatch))
+	mux.HandleFunc("POST /tasks/validate", f.validateTaskChain)
+	mux.HandleFunc("POST /tasks/{id}/resume", f.resumeTaskChain)
 	mux.HandleFunc("GET /supported", f.supported)
-	mux.HandleFunc("POST /embed", f.generateEmbeddings)
+	mux.HandleFunc("GET /hooks", f.hookSchemas)
+	mux.HandleFunc("POST /embed", serverops.WithMaxBodySize(batchBodyLimit, f.generateEmbeddings))
+	mux.HandleFunc("POST /v1/embeddings", serverops.WithMaxBodySize(batchBodyLimit, f.openAIEmbeddings))
 	mux.HandleFunc("GET /defaultmodel", f.defaultModel)
 }
 
@@ -61,6 +69,8 @@ type taskExecutionResponse struct {
 	Output     any                            `json:"output" example:"Paris" openapi_include_type:"object"`
 	OutputType string                         `json:"outputType" example:"string"`
 	State      []taskengine.CapturedStateUnit `json:"state" openapi_include_type:"taskengine.CapturedStateUnit"`
+	// Usage is the token usage summed over all steps in State.
+	Usage taskengine.TokenUsage `json:"usage" openapi_include_type:"taskengine.TokenUsage"`
 }
 
 // Executes dynamic task-chain workflows.
@@ -81,68 +91,199 @@ func (tm *taskManager) executeTaskChain(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Convert req.Input to the appropriate type based on inputType
+	convertedInput, err := convertTaskInput(req.Input, inputType)
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
+		return
+	}
+
+	resp, outputType, capturedStateUnits, err := tm.taskService.Execute(r.Context(), req.Chain, convertedInput, inputType)
+	if err != nil {
+		taskChainError(w, r, err)
+		return
+	}
+	var response taskExecutionResponse
+	response.Output = resp
+	response.OutputType = outputType.String()
+	response.State = capturedStateUnits
+	response.Usage = taskengine.TotalTokenUsage(capturedStateUnits)
+	_ = serverops.Encode(w, r, http.StatusOK, response) // @response execapi.taskExecutionResponse
+}
+
+// taskChainError writes err, passing the checkpoint ID of resumable executions
+// in the X-Checkpoint-ID header.
+func taskChainError(w http.ResponseWriter, r *http.Request, err error) {
+	var resumable *taskengine.ResumableError
+	if errors.As(err, &resumable) {
+		w.Header().Set("X-Checkpoint-ID", resumable.CheckpointID)
+	}
+	_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
+}
+
+// Resumes a failed task-chain execution.
+//
+// Continues a failed execution from the task after the last one that succeeded; completed
+// tasks are not executed again. Failed executions that can be resumed return their checkpoint ID
+// in the X-Checkpoint-ID response header, batch items in their checkpointId field.
+// Requires checkpoints to be enabled (CHAIN_CHECKPOINT_TTL); executions with content capture
+// disabled are not checkpointed. Fails with 404 Not Found if there is no such checkpoint.
+func (tm *taskManager) resumeTaskChain(w http.ResponseWriter, r *http.Request) {
+	id := serverops.GetPathParam(r, "id", "The checkpoint ID of the execution to resume.")
+	resp, outputType, capturedStateUnits, err := tm.taskService.Resume(r.Context(), id)
+	if err != nil {
+		taskChainError(w, r, err)
+		return
+	}
+	var response taskExecutionResponse
+	response.Output = resp
+	response.OutputType = outputType.String()
+	response.State = capturedStateUnits
+	response.Usage = taskengine.TotalTokenUsage(capturedStateUnits)
+	_ = serverops.Encode(w, r, http.StatusOK, response) // @response execapi.taskExecutionResponse
+}
+
+type taskValidationRequest struct {
+	Chain *taskengine.TaskChainDefinition `json:"chain" openapi_include_type:"taskengine.TaskChainDefinition"`
+}
+
+type taskValidationResponse struct {
+	Valid    bool     `json:"valid" example:"true"`
+	Warnings []string `json:"warnings" example:"[\"task classify: conditional branches without a default branch, unmatched outputs fail the chain\"]"`
+}
+
+// Validates a task-chain without executing it.
+//
+// Checks the chain structure and the args of every hook task against what the hook declares,
+// e.g. missing or malformed arguments. Fails with 400 Bad Request describing the first problem found.
+// Likely mistakes that do not invalidate the chain, such as tasks with conditional branches
+// but no default branch, are returned as warnings; set require_default_branch on the chain
+// to reject those instead. Transition cycles that can never reach the end of the chain are
+// reported with their path unless the branch closing the loop sets loop: true.
+func (tm *taskManager) validateTaskChain(w http.ResponseWriter, r *http.Request) {
+	req, err := serverops.Decode[taskValidationRequest](r) // @request execapi.taskValidationRequest
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
+		return
+	}
+	if err := tm.taskService.Validate(r.Context(), req.Chain); err != nil {
+		_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
+		return
+	}
+	resp := taskValidationResponse{Valid: true, Warnings: taskengine.ChainWarnings(req.Chain)}
+	if resp.Warnings == nil {
+		resp.Warnings = []string{}
+	}
+	_ = serverops.Encode(w, r, http.StatusOK, resp) // @response execapi.taskValidationResponse
+}
+
+type batchExecutionRequest struct {
+	Inputs      []any                           `json:"inputs" openapi_include_type:"[]object"`
+	InputType   string                          `json:"inputType" example:"string"`
+	Chain       *taskengine.TaskChainDefinition `json:"chain" openapi_include_type:"taskengine.TaskChainDefinition"`
+	Concurrency int                             `json:"concurrency,omitempty" example:"4"`
+}
+
+type batchExecutionResponse struct {
+	Results []execservice.BatchItemResult `json:"results"`
+}
+
+// Executes a task-chain once per input.
+//
+// Inputs are processed with bounded concurrency and the results are returned in input order.
+// A failing input does not abort the batch; each result carries its own status and error.
+// Batches are limited in size and in total runtime.
+func (tm *taskManager) executeTaskChainBatch(w http.ResponseWriter, r *http.Request) {
+	req, err := serverops.Decode[batchExecutionRequest](r) // @request execapi.batchExecutionRequest
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
+		return
+	}
+	inputType, err := taskengine.DataTypeFromString(req.InputType)
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
+		return
+	}
+
+	inputs := make([]any, len(req.Inputs))
+	for i, input := range req.Inputs {
+		inputs[i], err = convertTaskInput(input, inputType)
+		if err != nil {
+			_ = serverops.Error(w, r, fmt.Errorf("input %d: %w", i, err), serverops.ExecuteOperation)
+			return
+		}
+	}
+
+	results, err := execservice.ExecuteBatch(r.Context(), tm.taskService, req.Chain, inputs, inputType, execservice.BatchConfig{
+		Concurrency: req.Concurrency,
+	})
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
+		return
+	}
+	_ = serverops.Encode(w, r, http.StatusOK, batchExecutionResponse{Results: results}) // @response execapi.batchExecutionResponse
+}
+
+// convertTaskInput converts a decoded JSON input to the Go type expected for inputType.
+func convertTaskInput(input any, inputType taskengine.DataType) (any, error) {
 	var convertedInput any
 	switch inputType {
 	case taskengine.DataTypeChatHistory:
-		// Convert req.Input (map) to ChatHistory
-		data, err := json.Marshal(req.Input)
+		// Convert input (map) to ChatHistory
+		data, err := json.Marshal(input)
 		if err != nil {
-			_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
-			return
+			return nil, err
 		}
 		var chatHistory taskengine.ChatHistory
 		if err := json.Unmarshal(data, &chatHistory); err != nil {
-			_ = serverops.Error(w, r, fmt.Errorf("failed to convert to ChatHistory: %w", err), serverops.ExecuteOperation)
-			return
+			return nil, fmt.Errorf("failed to convert to ChatHistory: %w", err)
+		}
+		if err := chatHistory.NormalizeRoles(); err != nil {
+			return nil, err
 		}
 		convertedInput = chatHistory
 
 	case taskengine.DataTypeOpenAIChat:
-		// Convert req.Input (map) to OpenAIChatRequest
-		data, err := json.Marshal(req.Input)
+		// Convert input (map) to OpenAIChatRequest
+		data, err := json.Marshal(input)
 		if err != nil {
-			_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
-			return
+			return nil, err
 		}
 		var openAIChat taskengine.OpenAIChatRequest
 		if err := json.Unmarshal(data, &openAIChat); err != nil {
-			_ = serverops.Error(w, r, fmt.Errorf("failed to convert to OpenAIChatRequest: %w", err), serverops.ExecuteOperation)
-			return
+			return nil, fmt.Errorf("failed to convert to OpenAIChatRequest: %w", err)
+		}
+		if err := openAIChat.NormalizeRoles(); err != nil {
+			return nil, err
 		}
 		convertedInput = openAIChat
 
 	case taskengine.DataTypeOpenAIChatResponse:
-		// Convert req.Input (map) to OpenAIChatResponse
-		data, err := json.Marshal(req.Input)
+		// Convert input (map) to OpenAIChatResponse
+		data, err := json.Marshal(input)
 		if err != nil {
-			_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
-			return
+			return nil, err
 		}
 		var openAIChatResponse taskengine.OpenAIChatResponse
 		if err := json.Unmarshal(data, &openAIChatResponse); err != nil {
-			_ = serverops.Error(w, r, fmt.Errorf("failed to convert to OpenAIChatResponse: %w", err), serverops.ExecuteOperation)
-			return
+			return nil, fmt.Errorf("failed to convert to OpenAIChatResponse: %w", err)
 		}
 		convertedInput = openAIChatResponse
 
 	case taskengine.DataTypeSearchResults:
-		// Convert req.Input to []SearchResult
-		data, err := json.Marshal(req.Input)
+		// Convert input to []SearchResult
+		data, err := json.Marshal(input)
 		if err != nil {
-			_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
-			return
+			return nil, err
 		}
 		var searchResults []taskengine.SearchResult
 		if err := json.Unmarshal(data, &searchResults); err != nil {
-			_ = serverops.Error(w, r, fmt.Errorf("failed to convert to []SearchResult: %w", err), serverops.ExecuteOperation)
-			return
+			return nil, fmt.Errorf("failed to convert to []SearchResult: %w", err)
 		}
 		convertedInput = searchResults
 
 	case taskengine.DataTypeString:
 		// Convert to string (could be direct value or string representation in map)
-		switch v := req.Input.(type) {
+		switch v := input.(type) {
 		case string:
 			convertedInput = v
 		case map[string]any:
@@ -150,19 +291,19 @@ func (tm *taskManager) executeTaskChain(w http.ResponseWriter, r *http.Request)
 				convertedInput = strVal
 			} else {
 				// Try to marshal the whole map to JSON string
-				if jsonData, err := json.Marshal(req.Input); err == nil {
+				if jsonData, err := json.Marshal(input); err == nil {
 					convertedInput = string(jsonData)
 				} else {
-					convertedInput = fmt.Sprintf("%v", req.Input)
+					convertedInput = fmt.Sprintf("%v", input)
 				}
 			}
 		default:
-			convertedInput = fmt.Sprintf("%v", req.Input)
+			convertedInput = fmt.Sprintf("%v", input)
 		}
 
 	case taskengine.DataTypeBool:
 		// Convert to bool
-		switch v := req.Input.(type) {
+		switch v := input.(type) {
 		case bool:
 			convertedInput = v
 		case string:
@@ -175,14 +316,14 @@ func (tm *taskManager) executeTaskChain(w http.ResponseWriter, r *http.Request)
 			convertedInput = v != 0
 		default:
 			// Try to convert whatever we have to bool
-			strVal := fmt.Sprintf("%v", req.Input)
+			strVal := fmt.Sprintf("%v", input)
 			b, _ := strconv.ParseBool(strVal)
 			convertedInput = b
 		}
 
 	case taskengine.DataTypeInt:
 		// Convert to int
-		switch v := req.Input.(type) {
+		switch v := input.(type) {
 		case int:
 			convertedInput = v
 		case float64:
@@ -194,7 +335,7 @@ func (tm *taskManager) executeTaskChain(w http.ResponseWriter, r *http.Request)
 				convertedInput = 0
 			}
 		default:
-			if i, err := strconv.Atoi(fmt.Sprintf("%v", req.Input)); err == nil {
+			if i, err := strconv.Atoi(fmt.Sprintf("%v", input)); err == nil {
 				convertedInput = i
 			} else {
 				convertedInput = 0
@@ -203,7 +344,7 @@ func (tm *taskManager) executeTaskChain(w http.ResponseWriter, r *http.Request)
 
 	case taskengine.DataTypeFloat:
 		// Convert to float64
-		switch v := req.Input.(type) {
+		switch v := input.(type) {
 		case float64:
 			convertedInput = v
 		case int:
@@ -215,7 +356,7 @@ func (tm *taskManager) executeTaskChain(w http.ResponseWriter, r *http.Request)
 				convertedInput = 0.0
 			}
 		default:
-			if f, err := strconv.ParseFloat(fmt.Sprintf("%v", req.Input), 64); err == nil {
+			if f, err := strconv.ParseFloat(fmt.Sprintf("%v", input), 64); err == nil {
 				convertedInput = f
 			} else {
 				convertedInput = 0.0
@@ -226,19 +367,10 @@ func (tm *taskManager) executeTaskChain(w http.ResponseWriter, r *http.Request)
 		// For JSON type, we can keep it as map or slice
 	default:
 		// For DataTypeAny and any other unrecognized types, use the raw input
-		convertedInput = req.Input
+		convertedInput = input
 	}
 
-	resp, outputType, capturedStateUnits, err := tm.taskService.Execute(r.Context(), req.Chain, convertedInput, inputType)
-	if err != nil {
-		_ = serverops.Error(w, r, err, serverops.ExecuteOperation)
-		return
-	}
-	var response taskExecutionResponse
-	response.Output = resp
-	response.OutputType = outputType.String()
-	response.State = capturedStateUnits
-	_ = serverops.Encode(w, r, http.StatusOK, response) // @response execapi.taskExecutionResponse
+	return convertedInput, nil
 }
 
 // Lists available task-chain hook types.
@@ -254,6 +386,20 @@ func (tm *taskManager) supported(w http.ResponseWriter, r *http.Request) {
 	_ = serverops.Encode(w, r, http.StatusOK, resp) // @response []string
 }
 
+// Lists the schemas of all task-chain hooks.
+//
+// Each schema describes a hook's purpose, the args it accepts with their types and whether
+// they are required, and the data types it consumes and produces.
+func (tm *taskManager) hookSchemas(w http.ResponseWriter, r *http.Request) {
+	resp, err := tm.taskService.Schemas(r.Context())
+	if err != nil {
+		_ = serverops.Error(w, r, err, serverops.ListOperation)
+		return
+	}
+
+	_ = serverops.Encode(w, r, http.StatusOK, resp) // @response []taskengine.HookSchema
+}
+
 type EmbedRequest struct {
 	Text string `json:"text" example:"Hello, world!"`
 }
@@ -283,6 +429,96 @@ func (tm *taskManager) generateEmbeddings(w http.ResponseWriter, r *http.Request
 	_ = serverops.Encode(w, r, http.StatusOK, EmbedResponse{Vector: vector}) // @response execapi.EmbedResponse
 }
 
+// OpenAIEmbeddingRequest mirrors the request body of the OpenAI /v1/embeddings API.
+// Input is either a single string or an array of strings.
+type OpenAIEmbeddingRequest struct {
+	Model string `json:"model" example:"nomic-embed-text:latest"`
+	Input any    `json:"input" example:"Hello, world!" openapi_include_type:"string"`
+}
+
+type OpenAIEmbeddingData struct {
+	Object    string    `json:"object" example:"embedding"`
+	Embedding []float64 `json:"embedding" example:"[0.1, 0.2, 0.3, ...]"`
+	Index     int       `json:"index" example:"0"`
+}
+
+type OpenAIEmbeddingUsage struct {
+	PromptTokens int `json:"prompt_tokens" example:"4"`
+	TotalTokens  int `json:"total_tokens" example:"4"`
+}
+
+// OpenAIEmbeddingResponse mirrors the response body of the OpenAI /v1/embeddings API.
+type OpenAIEmbeddingResponse struct {
+	Object string                `json:"object" example:"list"`
+	Data   []OpenAIEmbeddingData `json:"data"`
+	Model  string                `json:"model" example:"nomic-embed-text:latest"`
+	Usage  OpenAIEmbeddingUsage  `json:"usage"`
+}
+
+// Generates embeddings using an OpenAI-compatible request and response format.
+//
+// Accepts a single string or an array of strings as input; the returned data
+// preserves the order of the inputs. If model is omitted the default embedding model is used.
+// Only models served by the configured embedding provider and pool can be selected;
+// requesting any other model fails with 422 Unprocessable Entity.
+// Arrays with more inputs than the server allows (EMBED_MAX_BATCH_SIZE, default 2048)
+// are rejected with 400 Bad Request.
+// Errors are returned in the OpenAI error format: {"error": {"message", "type", "param", "code"}}.
+func (tm *taskManager) openAIEmbeddings(w http.ResponseWriter, r *http.Request) {
+	req, err := serverops.Decode[OpenAIEmbeddingRequest](r) // @request execapi.OpenAIEmbeddingRequest
+	if err != nil {
+		_ = serverops.OpenAIError(w, r, err, serverops.CreateOperation)
+		return
+	}
+	inputs, err := embeddingInputs(req.Input)
+	if err != nil {
+		_ = serverops.OpenAIError(w, r, err, serverops.CreateOperation)
+		return
+	}
+
+	result, err := tm.embedService.EmbedBatch(r.Context(), req.Model, inputs)
+	if err != nil {
+		_ = serverops.OpenAIError(w, r, err, serverops.CreateOperation)
+		return
+	}
+
+	resp := OpenAIEmbeddingResponse{
+		Object: "list",
+		Data:   make([]OpenAIEmbeddingData, len(result.Vectors)),
+		Model:  result.ModelName,
+		Usage: OpenAIEmbeddingUsage{
+			PromptTokens: result.PromptTokens,
+			TotalTokens:  result.PromptTokens,
+		},
+	}
+	for i, vector := range result.Vectors {
+		resp.Data[i] = OpenAIEmbeddingData{Object: "embedding", Embedding: vector, Index: i}
+	}
+	_ = serverops.Encode(w, r, http.StatusOK, resp) // @response execapi.OpenAIEmbeddingResponse
+}
+
+// embeddingInputs normalizes the OpenAI input field, which may be a string or an array of strings.
+func embeddingInputs(input any) ([]string, error) {
+	switch v := input.(type) {
+	case string:
+		return []string{v}, nil
+	case []any:
+		inputs := make([]string, len(v))
+		for i, item := range v {
+			text, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: input[%d] must be a string", serverops.ErrBadRequest, i)
+			}
+			inputs[i] = text
+		}
+		return inputs, nil
+	case nil:
+		return nil, fmt.Errorf("%w: input is required", serverops.ErrBadRequest)
+	default:
+		return nil, fmt.Errorf("%w: input must be a string or an array of strings", serverops.ErrBadRequest)
+	}
+}
+
 type DefaultModelResponse struct {
 	ModelName string `json:"modelName" example:"mistral:latest"`
 }
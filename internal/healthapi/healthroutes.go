@@ -0,0 +1,102 @@
+package healthapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	libbus "github.com/contenox/runtime/libbus"
+	libdb "github.com/contenox/runtime/libdbexec"
+)
+
+// DefaultCheckTimeout bounds each dependency probe of the readiness check.
+const DefaultCheckTimeout = 2 * time.Second
+
+// Check reports whether a dependency is reachable.
+type Check func(ctx context.Context) error
+
+// AddHealthRoutes registers the liveness and readiness endpoints.
+// Each check is probed concurrently on readiness requests, bounded by timeout.
+func AddHealthRoutes(mux *http.ServeMux, checks map[string]Check, timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	h := &handler{checks: checks, timeout: timeout}
+	mux.HandleFunc("GET /health", h.health)
+	mux.HandleFunc("GET /ready", h.ready)
+}
+
+type handler struct {
+	checks  map[string]Check
+	timeout time.Duration
+}
+
+// Reports that the server process is up.
+//
+// Dependencies are not probed; use /ready for that.
+func (h *handler) health(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+type DependencyStatus struct {
+	Status string `json:"status" example:"ok"`
+	Error  string `json:"error,omitempty" example:"connection refused"`
+}
+
+type ReadinessResponse struct {
+	Status       string                      `json:"status" example:"ok"`
+	Dependencies map[string]DependencyStatus `json:"dependencies" openapi_include_type:"object"`
+}
+
+// Reports whether the server's dependencies are reachable.
+//
+// The database and the message bus are probed concurrently with a short timeout.
+// Responds 200 only when all dependencies are healthy, 503 Service Unavailable otherwise.
+func (h *handler) ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	resp := ReadinessResponse{Status: "ok", Dependencies: make(map[string]DependencyStatus, len(h.checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := DependencyStatus{Status: "ok"}
+			if err := check(ctx); err != nil {
+				status = DependencyStatus{Status: "unavailable", Error: err.Error()}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Dependencies[name] = status
+			if status.Error != "" {
+				resp.Status = "unavailable"
+			}
+		}()
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	if resp.Status != "ok" {
+		code = http.StatusServiceUnavailable
+	}
+	_ = apiframework.Encode(w, r, code, resp) // @response healthapi.ReadinessResponse
+}
+
+// DBCheck probes the database with a trivial query.
+func DBCheck(db libdb.DBManager) Check {
+	return func(ctx context.Context) error {
+		var one int
+		return db.WithoutTransaction().QueryRowContext(ctx, "SELECT 1").Scan(&one)
+	}
+}
+
+// BusCheck probes the message bus by publishing an empty message.
+func BusCheck(bus libbus.Messenger) Check {
+	return func(ctx context.Context) error {
+		return bus.Publish(ctx, "runtime.health", nil)
+	}
+}
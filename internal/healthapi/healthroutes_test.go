@@ -0,0 +1,68 @@
+package healthapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/healthapi"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, checks map[string]healthapi.Check, path string) (*httptest.ResponseRecorder, healthapi.ReadinessResponse) {
+	t.Helper()
+	mux := http.NewServeMux()
+	healthapi.AddHealthRoutes(mux, checks, 50*time.Millisecond)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var resp healthapi.ReadinessResponse
+	if rec.Body.Len() > 0 {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	}
+	return rec, resp
+}
+
+func healthy(context.Context) error { return nil }
+
+func TestUnit_Ready_AllDependenciesHealthy(t *testing.T) {
+	rec, resp := serve(t, map[string]healthapi.Check{"database": healthy, "pubsub": healthy}, "/ready")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "ok", resp.Status)
+	require.Equal(t, "ok", resp.Dependencies["database"].Status)
+	require.Equal(t, "ok", resp.Dependencies["pubsub"].Status)
+}
+
+func TestUnit_Ready_ReportsFailingDependency(t *testing.T) {
+	rec, resp := serve(t, map[string]healthapi.Check{
+		"database": healthy,
+		"pubsub":   func(context.Context) error { return errors.New("connection closed") },
+	}, "/ready")
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Equal(t, "unavailable", resp.Status)
+	require.Equal(t, "ok", resp.Dependencies["database"].Status)
+	require.Equal(t, healthapi.DependencyStatus{Status: "unavailable", Error: "connection closed"}, resp.Dependencies["pubsub"])
+}
+
+func TestUnit_Ready_TimesOutHangingDependency(t *testing.T) {
+	rec, resp := serve(t, map[string]healthapi.Check{
+		"database": func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}, "/ready")
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Equal(t, "unavailable", resp.Dependencies["database"].Status)
+}
+
+func TestUnit_Health_DoesNotProbeDependencies(t *testing.T) {
+	probed := false
+	rec, _ := serve(t, map[string]healthapi.Check{
+		"database": func(context.Context) error { probed = true; return errors.New("down") },
+	}, "/health")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.False(t, probed)
+}
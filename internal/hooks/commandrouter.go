@@ -0,0 +1,187 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/contenox/runtime/taskengine"
+)
+
+// CommandRouterHookName is the hook name chains use to dispatch slash commands.
+const CommandRouterHookName = "command_router"
+
+// CommandRouterDefault is the transition returned for messages that are not a registered command.
+const CommandRouterDefault = "default"
+
+// ErrInvalidCommand is returned when registering a malformed or duplicate command.
+var ErrInvalidCommand = errors.New("invalid command")
+
+// CommandResult is the outcome of a command.
+// A nil Output leaves the hook input unchanged.
+type CommandResult struct {
+	Transition string
+	Output     any
+	OutputType taskengine.DataType
+}
+
+// CommandHandler handles a command. args is the message text after the command token.
+type CommandHandler func(ctx context.Context, args string, input any, inputType taskengine.DataType) (CommandResult, error)
+
+type command struct {
+	description string
+	handler     CommandHandler
+}
+
+// CommandRouter is a hook that dispatches on the leading token of the latest user message.
+//
+// A message "/echo hello" runs the handler registered for "/echo"; the chain branches on
+// the transition it returns. Everything else transitions to "default" with the input unchanged:
+//
+//	hook:
+//	  name: command_router
+//	transition:
+//	  branches:
+//	    - operator: equals
+//	      when: echo
+//	      goto: reply
+//	    - operator: default
+//	      goto: chat
+type CommandRouter struct {
+	mu       sync.RWMutex
+	commands map[string]command
+}
+
+// NewCommandRouter creates a router with the built-in /echo, /help and /reset commands.
+func NewCommandRouter() *CommandRouter {
+	r := &CommandRouter{commands: make(map[string]command)}
+	_ = r.Register("/echo", "Repeats the given text.", echoCommand)
+	_ = r.Register("/help", "Lists the available commands.", r.helpCommand)
+	_ = r.Register("/reset", "Clears the conversation, keeping system messages.", resetCommand)
+	return r
+}
+
+// Register adds a command. Names must start with "/" and contain no whitespace.
+func (r *CommandRouter) Register(name, description string, handler CommandHandler) error {
+	if !strings.HasPrefix(name, "/") || len(name) < 2 || strings.ContainsAny(name, " \t\n") {
+		return fmt.Errorf("%w: %q must start with / and contain no whitespace", ErrInvalidCommand, name)
+	}
+	if handler == nil {
+		return fmt.Errorf("%w: %q has no handler", ErrInvalidCommand, name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.commands[name]; exists {
+		return fmt.Errorf("%w: %q is already registered", ErrInvalidCommand, name)
+	}
+	r.commands[name] = command{description: description, handler: handler}
+	return nil
+}
+
+func (r *CommandRouter) Exec(ctx context.Context, startingTime time.Time, input any, dataType taskengine.DataType, transition string, args *taskengine.HookCall) (any, taskengine.DataType, string, error) {
+	text, ok := commandText(input)
+	if !ok {
+		return input, dataType, CommandRouterDefault, nil
+	}
+	name, rest, _ := strings.Cut(strings.TrimSpace(text), " ")
+
+	r.mu.RLock()
+	cmd, found := r.commands[name]
+	r.mu.RUnlock()
+	if !found {
+		return input, dataType, CommandRouterDefault, nil
+	}
+
+	result, err := cmd.handler(ctx, strings.TrimSpace(rest), input, dataType)
+	if err != nil {
+		return nil, taskengine.DataTypeAny, transition, fmt.Errorf("command %s failed: %w", name, err)
+	}
+	if result.Transition == "" {
+		result.Transition = strings.TrimPrefix(name, "/")
+	}
+	if result.Output == nil {
+		return input, dataType, result.Transition, nil
+	}
+	return result.Output, result.OutputType, result.Transition, nil
+}
+
+// ValidateArgs accepts any args; the router dispatches on its input only.
+func (r *CommandRouter) ValidateArgs(ctx context.Context, args *taskengine.HookCall) error {
+	return nil
+}
+
+func (r *CommandRouter) Supports(ctx context.Context) ([]string, error) {
+	return []string{CommandRouterHookName}, nil
+}
+
+// commandText extracts the text to route on: a string input or the latest user message of a chat.
+func commandText(input any) (string, bool) {
+	switch v := input.(type) {
+	case string:
+		return v, true
+	case taskengine.ChatHistory:
+		for i := len(v.Messages) - 1; i >= 0; i-- {
+			if v.Messages[i].Role == "user" {
+				return v.Messages[i].Content, true
+			}
+		}
+	}
+	return "", false
+}
+
+func echoCommand(_ context.Context, args string, _ any, _ taskengine.DataType) (CommandResult, error) {
+	return CommandResult{Output: args, OutputType: taskengine.DataTypeString}, nil
+}
+
+func (r *CommandRouter) helpCommand(_ context.Context, _ string, _ any, _ taskengine.DataType) (CommandResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.commands))
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s - %s\n", name, r.commands[name].description)
+	}
+	return CommandResult{Output: strings.TrimSuffix(b.String(), "\n"), OutputType: taskengine.DataTypeString}, nil
+}
+
+func resetCommand(_ context.Context, _ string, input any, _ taskengine.DataType) (CommandResult, error) {
+	history, ok := input.(taskengine.ChatHistory)
+	if !ok {
+		return CommandResult{}, nil
+	}
+	reset := taskengine.ChatHistory{Model: history.Model}
+	for _, m := range history.Messages {
+		if m.Role == "system" {
+			reset.Messages = append(reset.Messages, m)
+		}
+	}
+	return CommandResult{Output: reset, OutputType: taskengine.DataTypeChatHistory}, nil
+}
+
+func (r *CommandRouter) Schemas(ctx context.Context) ([]taskengine.HookSchema, error) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.commands))
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+	sort.Strings(names)
+	return []taskengine.HookSchema{{
+		Name: CommandRouterHookName,
+		Description: fmt.Sprintf("Dispatches on the leading command of the latest user message (%s); other messages transition to %q.",
+			strings.Join(names, ", "), CommandRouterDefault),
+		Args:       []taskengine.HookArg{},
+		InputType:  taskengine.DataTypeAny,
+		OutputType: taskengine.DataTypeAny,
+	}}, nil
+}
+
+var _ taskengine.HookRepo = (*CommandRouter)(nil)
@@ -0,0 +1,115 @@
+package hooks_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/hooks"
+	"github.com/contenox/runtime/internal/llmrepo"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+func commandChain() *taskengine.TaskChainDefinition {
+	return &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:      "route",
+				Handler: taskengine.HandleHook,
+				Hook:    &taskengine.HookCall{Name: hooks.CommandRouterHookName},
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpEquals, When: "weather", Goto: "forecast"},
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+			{
+				ID:      "forecast",
+				Handler: taskengine.HandleNoop,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestUnit_CommandRouter_CustomCommandTakesBranch(t *testing.T) {
+	router := hooks.NewCommandRouter()
+	require.NoError(t, router.Register("/weather", "Reports the weather.", func(ctx context.Context, args string, input any, inputType taskengine.DataType) (hooks.CommandResult, error) {
+		return hooks.CommandResult{Output: "sunny in " + args, OutputType: taskengine.DataTypeString}, nil
+	}))
+	require.ErrorIs(t, router.Register("/weather", "", nil), hooks.ErrInvalidCommand)
+
+	exec, err := taskengine.NewExec(context.Background(), struct{ llmrepo.ModelRepo }{}, router, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	env, err := taskengine.NewEnv(context.Background(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{
+		{Role: "system", Content: "be brief"},
+		{Role: "user", Content: "/weather Berlin"},
+	}}
+	out, dt, state, err := env.ExecEnv(context.Background(), commandChain(), history, taskengine.DataTypeChatHistory)
+	require.NoError(t, err)
+	require.Equal(t, "sunny in Berlin", out)
+	require.Equal(t, taskengine.DataTypeString, dt)
+	require.Len(t, state, 2)
+	require.Equal(t, "forecast", state[1].TaskID)
+
+	// Plain messages fall through to default with the input unchanged.
+	out, _, state, err = env.ExecEnv(context.Background(), commandChain(), "what is the weather?", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "what is the weather?", out)
+	require.Len(t, state, 1)
+}
+
+func TestUnit_CommandRouter_BuiltinCommands(t *testing.T) {
+	router := hooks.NewCommandRouter()
+	call := &taskengine.HookCall{Name: hooks.CommandRouterHookName}
+
+	out, _, transition, err := router.Exec(context.Background(), time.Now(), "/echo hi there", taskengine.DataTypeString, "", call)
+	require.NoError(t, err)
+	require.Equal(t, "echo", transition)
+	require.Equal(t, "hi there", out)
+
+	out, _, transition, err = router.Exec(context.Background(), time.Now(), "/help", taskengine.DataTypeString, "", call)
+	require.NoError(t, err)
+	require.Equal(t, "help", transition)
+	require.True(t, strings.HasPrefix(out.(string), "/echo - "))
+
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{
+		{Role: "system", Content: "be brief"},
+		{Role: "user", Content: "hello"},
+		{Role: "assistant", Content: "hi"},
+		{Role: "user", Content: "/reset"},
+	}}
+	out, dt, transition, err := router.Exec(context.Background(), time.Now(), history, taskengine.DataTypeChatHistory, "", call)
+	require.NoError(t, err)
+	require.Equal(t, "reset", transition)
+	require.Equal(t, taskengine.DataTypeChatHistory, dt)
+	require.Equal(t, []taskengine.Message{{Role: "system", Content: "be brief"}}, out.(taskengine.ChatHistory).Messages)
+
+	_, _, transition, err = router.Exec(context.Background(), time.Now(), "/unknown", taskengine.DataTypeString, "", call)
+	require.NoError(t, err)
+	require.Equal(t, hooks.CommandRouterDefault, transition)
+}
+
+func TestUnit_SimpleRepo_Schemas(t *testing.T) {
+	repo := hooks.NewSimpleProvider(map[string]taskengine.HookRepo{
+		hooks.CommandRouterHookName: hooks.NewCommandRouter(),
+	})
+
+	schemas, err := repo.Schemas(context.Background())
+	require.NoError(t, err)
+	require.Len(t, schemas, 1)
+	require.Equal(t, hooks.CommandRouterHookName, schemas[0].Name)
+	require.Empty(t, schemas[0].Args)
+	require.Contains(t, schemas[0].Description, "/echo, /help, /reset")
+}
@@ -0,0 +1,26 @@
+package hooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/contenox/runtime/taskengine"
+)
+
+type metricsHooks struct {
+	taskengine.HookRepo
+	metrics taskengine.Metrics
+}
+
+// WithMetrics reports failed hook executions of repo to metrics.
+func WithMetrics(repo taskengine.HookRepo, metrics taskengine.Metrics) taskengine.HookRepo {
+	return &metricsHooks{HookRepo: repo, metrics: metrics}
+}
+
+func (h *metricsHooks) Exec(ctx context.Context, startingTime time.Time, input any, dataType taskengine.DataType, transition string, args *taskengine.HookCall) (any, taskengine.DataType, string, error) {
+	out, dt, next, err := h.HookRepo.Exec(ctx, startingTime, input, dataType, transition, args)
+	if err != nil {
+		h.metrics.HookFailed(args.Name)
+	}
+	return out, dt, next, err
+}
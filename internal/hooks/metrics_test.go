@@ -0,0 +1,39 @@
+package hooks_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/hooks"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_WithMetrics_CountsHookErrors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics, err := taskengine.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	mock := hooks.NewMockHookRegistry()
+	mock.ErrorSequence = []error{errors.New("unreachable"), nil}
+	repo := hooks.WithMetrics(mock, metrics)
+
+	call := &taskengine.HookCall{Name: "send_email"}
+	_, _, _, err = repo.Exec(context.Background(), time.Now(), "hi", taskengine.DataTypeString, "", call)
+	require.Error(t, err)
+	_, _, _, err = repo.Exec(context.Background(), time.Now(), "hi", taskengine.DataTypeString, "", call)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, mock.CallCount())
+	expected := `
+# HELP taskengine_hook_errors_total Number of failed hook executions.
+# TYPE taskengine_hook_errors_total counter
+taskengine_hook_errors_total{hook="send_email"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "taskengine_hook_errors_total"))
+}
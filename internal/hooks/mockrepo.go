@@ -2,6 +2,7 @@ package hooks
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/contenox/runtime/taskengine"
@@ -115,6 +116,10 @@ func (m *MockHookRepo) WithErrorSequence(errors ...error) *MockHookRepo {
 	return m
 }
 
+func (m *MockHookRepo) ValidateArgs(ctx context.Context, args *taskengine.HookCall) error {
+	return nil
+}
+
 func (m *MockHookRepo) Supports(ctx context.Context) ([]string, error) {
 	supported := make([]string, 0, len(m.ResponseMap))
 	for k := range m.ResponseMap {
@@ -123,4 +128,19 @@ func (m *MockHookRepo) Supports(ctx context.Context) ([]string, error) {
 	return supported, nil
 }
 
-var _ taskengine.HookRegistry = (*MockHookRepo)(nil)
+func (m *MockHookRepo) Schemas(ctx context.Context) ([]taskengine.HookSchema, error) {
+	names, _ := m.Supports(ctx)
+	sort.Strings(names)
+	schemas := make([]taskengine.HookSchema, 0, len(names))
+	for _, name := range names {
+		schemas = append(schemas, taskengine.HookSchema{
+			Name:       name,
+			Args:       []taskengine.HookArg{},
+			InputType:  taskengine.DataTypeAny,
+			OutputType: taskengine.DataTypeAny,
+		})
+	}
+	return schemas, nil
+}
+
+var _ taskengine.HookRepo = (*MockHookRepo)(nil)
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	libdb "github.com/contenox/runtime/libdbexec"
+	"github.com/contenox/runtime/libtracker"
 	"github.com/contenox/runtime/runtimetypes"
 	"github.com/contenox/runtime/taskengine"
 )
@@ -115,6 +116,9 @@ func (p *PersistentRepo) execRemoteHook(
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	if requestID, ok := ctx.Value(libtracker.ContextKeyRequestID).(string); ok && requestID != "" {
+		httpReq.Header.Set("X-Request-ID", requestID)
+	}
 
 	client := p.httpClient
 	resp, err := client.Do(httpReq)
@@ -132,6 +136,8 @@ func (p *PersistentRepo) execRemoteHook(
 		DataType   string `json:"dataType"`
 		Error      string `json:"error,omitempty"`
 		Transition string `json:"transition"`
+		// Meta carries optional values transitions can branch on, see taskengine.SetHookMetadata.
+		Meta map[string]any `json:"meta,omitempty"`
 	}{}
 
 	body, err := io.ReadAll(resp.Body)
@@ -174,6 +180,9 @@ func (p *PersistentRepo) execRemoteHook(
 	} else if errorStatus {
 		err = fmt.Errorf("failed with status %d", resp.StatusCode)
 	}
+	for key, value := range response.Meta {
+		taskengine.SetHookMetadata(ctx, key, value)
+	}
 	convertedOutput, convErr := taskengine.ConvertToType(response.Output, dt)
 	if convErr != nil {
 		return nil, dt, response.Transition,
@@ -182,6 +191,19 @@ func (p *PersistentRepo) execRemoteHook(
 	return convertedOutput, dt, response.Transition, err
 }
 
+// ValidateArgs delegates to local hooks. Remote hooks declare no args, so for
+// them only their existence is checked.
+func (p *PersistentRepo) ValidateArgs(ctx context.Context, args *taskengine.HookCall) error {
+	if hook, ok := p.localHooks[args.Name]; ok {
+		return hook.ValidateArgs(ctx, args)
+	}
+	storeInstance := runtimetypes.New(p.dbInstance.WithoutTransaction())
+	if _, err := storeInstance.GetRemoteHookByName(ctx, args.Name); err != nil {
+		return fmt.Errorf("unknown hook: %s", args.Name)
+	}
+	return nil
+}
+
 func (p *PersistentRepo) Supports(ctx context.Context) ([]string, error) {
 	// Start with local hooks
 	localSupported := make([]string, 0, len(p.localHooks))
@@ -189,7 +211,42 @@ func (p *PersistentRepo) Supports(ctx context.Context) ([]string, error) {
 		localSupported = append(localSupported, k)
 	}
 
-	// Fetch all remote hooks by paginating through the store
+	remoteHooks, err := p.listRemoteHooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range remoteHooks {
+		localSupported = append(localSupported, hook.Name)
+	}
+
+	return localSupported, nil
+}
+
+// Schemas reports the schemas of the local hooks followed by the remote hooks.
+// Remote hooks declare no args and accept and return any data type.
+func (p *PersistentRepo) Schemas(ctx context.Context) ([]taskengine.HookSchema, error) {
+	schemas, err := localSchemas(ctx, p.localHooks)
+	if err != nil {
+		return nil, err
+	}
+	remoteHooks, err := p.listRemoteHooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range remoteHooks {
+		schemas = append(schemas, taskengine.HookSchema{
+			Name:        hook.Name,
+			Description: fmt.Sprintf("Remote hook calling %s %s.", hook.Method, hook.EndpointURL),
+			Args:        []taskengine.HookArg{},
+			InputType:   taskengine.DataTypeAny,
+			OutputType:  taskengine.DataTypeAny,
+		})
+	}
+	return schemas, nil
+}
+
+// listRemoteHooks fetches all remote hooks by paginating through the store.
+func (p *PersistentRepo) listRemoteHooks(ctx context.Context) ([]*runtimetypes.RemoteHook, error) {
 	storeInstance := runtimetypes.New(p.dbInstance.WithoutTransaction())
 	var remoteHooks []*runtimetypes.RemoteHook
 	var lastCursor *time.Time
@@ -211,10 +268,5 @@ func (p *PersistentRepo) Supports(ctx context.Context) ([]string, error) {
 		// Update the cursor for the next iteration
 		lastCursor = &page[len(page)-1].CreatedAt
 	}
-
-	for _, hook := range remoteHooks {
-		localSupported = append(localSupported, hook.Name)
-	}
-
-	return localSupported, nil
+	return remoteHooks, nil
 }
@@ -3,6 +3,7 @@ package hooks
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/contenox/runtime/taskengine"
@@ -25,6 +26,13 @@ func (m *SimpleRepo) Exec(ctx context.Context, startingTime time.Time, input any
 	return nil, taskengine.DataTypeAny, transition, fmt.Errorf("unknown hook type: %s", args.Name)
 }
 
+func (m *SimpleRepo) ValidateArgs(ctx context.Context, args *taskengine.HookCall) error {
+	if hook, ok := m.hooks[args.Name]; ok {
+		return hook.ValidateArgs(ctx, args)
+	}
+	return fmt.Errorf("unknown hook type: %s", args.Name)
+}
+
 func (m *SimpleRepo) Supports(ctx context.Context) ([]string, error) {
 	supported := make([]string, 0, len(m.hooks))
 	for k := range m.hooks {
@@ -33,4 +41,30 @@ func (m *SimpleRepo) Supports(ctx context.Context) ([]string, error) {
 	return supported, nil
 }
 
+func (m *SimpleRepo) Schemas(ctx context.Context) ([]taskengine.HookSchema, error) {
+	return localSchemas(ctx, m.hooks)
+}
+
+// localSchemas collects the schemas of hooks, sorted by name.
+func localSchemas(ctx context.Context, hooks map[string]taskengine.HookRepo) ([]taskengine.HookSchema, error) {
+	names := make([]string, 0, len(hooks))
+	for name := range hooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	schemas := make([]taskengine.HookSchema, 0, len(hooks))
+	for _, name := range names {
+		hookSchemas, err := hooks[name].Schemas(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("hook %s: %w", name, err)
+		}
+		for _, schema := range hookSchemas {
+			if schema.Name == name {
+				schemas = append(schemas, schema)
+			}
+		}
+	}
+	return schemas, nil
+}
+
 var _ taskengine.HookRepo = (*SimpleRepo)(nil)
@@ -0,0 +1,230 @@
+package hooks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/contenox/runtime/taskengine"
+)
+
+// SQLQueryHookName is the hook name chains use to run allowlisted queries.
+const SQLQueryHookName = "sql_query"
+
+const (
+	defaultSQLMaxRows = 100
+	defaultSQLTimeout = 5 * time.Second
+)
+
+// ErrQueryNotAllowed is returned for queries that are not allowlisted or not read-only.
+var ErrQueryNotAllowed = errors.New("sql query not allowed")
+
+// SQLQuery is an allowlisted read-only query.
+// Params names the hook arguments bound, in order, to the placeholders $1..$n.
+type SQLQuery struct {
+	SQL    string   `json:"sql"`
+	Params []string `json:"params"`
+}
+
+// SQLQueryHook runs named, allowlisted SELECT queries and returns the rows as JSON.
+//
+// Chains never supply SQL. They select a query by name and provide only its parameters:
+//
+//	hook:
+//	  name: sql_query
+//	  args:
+//	    query: customer_by_id
+//	    id: "42"
+type SQLQueryHook struct {
+	db      TxBeginner
+	queries map[string]SQLQuery
+	maxRows int
+	timeout time.Duration
+}
+
+// TxBeginner starts database transactions; *sql.DB implements it.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// NewSQLQueryHook creates the hook. Every allowlisted query must be a single SELECT statement.
+// maxRows and timeout fall back to defaults when not positive.
+func NewSQLQueryHook(db TxBeginner, queries map[string]SQLQuery, maxRows int, timeout time.Duration) (taskengine.HookRepo, error) {
+	if db == nil {
+		return nil, fmt.Errorf("sql query hook: db is nil")
+	}
+	for name, q := range queries {
+		if err := validateReadOnly(q.SQL); err != nil {
+			return nil, fmt.Errorf("sql query hook: query %q: %w", name, err)
+		}
+	}
+	if maxRows <= 0 {
+		maxRows = defaultSQLMaxRows
+	}
+	if timeout <= 0 {
+		timeout = defaultSQLTimeout
+	}
+	return &SQLQueryHook{
+		db:      db,
+		queries: queries,
+		maxRows: maxRows,
+		timeout: timeout,
+	}, nil
+}
+
+func (h *SQLQueryHook) Exec(ctx context.Context, startingTime time.Time, input any, dataType taskengine.DataType, transition string, args *taskengine.HookCall) (any, taskengine.DataType, string, error) {
+	name := args.Args["query"]
+	q, ok := h.queries[name]
+	if !ok {
+		return nil, taskengine.DataTypeAny, transition, fmt.Errorf("%w: unknown query %q", ErrQueryNotAllowed, name)
+	}
+	if err := validateReadOnly(q.SQL); err != nil {
+		return nil, taskengine.DataTypeAny, transition, err
+	}
+
+	params := make([]any, len(q.Params))
+	for i, p := range q.Params {
+		v, ok := args.Args[p]
+		if !ok {
+			return nil, taskengine.DataTypeAny, transition, fmt.Errorf("query %q: missing parameter %q", name, p)
+		}
+		params[i] = v
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	// validateReadOnly only screens the statement. The read-only transaction makes
+	// the database reject writes, e.g. from functions the query calls, and it is
+	// always rolled back.
+	tx, err := h.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, taskengine.DataTypeAny, transition, fmt.Errorf("query %q: failed to begin read-only transaction: %w", name, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, q.SQL, params...)
+	if err != nil {
+		return nil, taskengine.DataTypeAny, transition, fmt.Errorf("query %q failed: %w", name, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, taskengine.DataTypeAny, transition, fmt.Errorf("query %q: failed to read columns: %w", name, err)
+	}
+
+	result := make([]map[string]any, 0)
+	for rows.Next() {
+		if len(result) == h.maxRows {
+			break
+		}
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, taskengine.DataTypeAny, transition, fmt.Errorf("query %q: failed to scan row: %w", name, err)
+		}
+		row := make(map[string]any, len(columns))
+		for i, col := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, taskengine.DataTypeAny, transition, fmt.Errorf("query %q: %w", name, err)
+	}
+
+	return result, taskengine.DataTypeJSON, transition, nil
+}
+
+// ValidateArgs checks that the query is allowlisted and all of its parameters are given.
+func (h *SQLQueryHook) ValidateArgs(ctx context.Context, args *taskengine.HookCall) error {
+	if err := taskengine.CheckHookArgs(SQLQueryHookName, []taskengine.HookArg{{Name: "query", Required: true}}, args.Args); err != nil {
+		return err
+	}
+	name := args.Args["query"]
+	q, ok := h.queries[name]
+	if !ok {
+		return fmt.Errorf("%w: unknown query %q", ErrQueryNotAllowed, name)
+	}
+	declared := make([]taskengine.HookArg, len(q.Params))
+	for i, p := range q.Params {
+		declared[i] = taskengine.HookArg{Name: p, Required: true}
+	}
+	return taskengine.CheckHookArgs(SQLQueryHookName, declared, args.Args)
+}
+
+func (h *SQLQueryHook) Supports(ctx context.Context) ([]string, error) {
+	return []string{SQLQueryHookName}, nil
+}
+
+// Schemas declares the query arg and, as optional args, the parameters of all
+// allowlisted queries; which of them are required depends on the chosen query.
+func (h *SQLQueryHook) Schemas(ctx context.Context) ([]taskengine.HookSchema, error) {
+	names := make([]string, 0, len(h.queries))
+	for name := range h.queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	args := []taskengine.HookArg{{
+		Name:        "query",
+		Description: "Name of the allowlisted query to run: " + strings.Join(names, ", ") + ".",
+		Type:        "string",
+		Required:    true,
+	}}
+	seen := map[string]bool{"query": true}
+	for _, name := range names {
+		for _, p := range h.queries[name].Params {
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+			args = append(args, taskengine.HookArg{
+				Name:        p,
+				Description: "Query parameter.",
+				Type:        "string",
+			})
+		}
+	}
+	return []taskengine.HookSchema{{
+		Name:        SQLQueryHookName,
+		Description: fmt.Sprintf("Runs an allowlisted read-only SQL query and returns up to %d rows.", h.maxRows),
+		Args:        args,
+		InputType:   taskengine.DataTypeAny,
+		OutputType:  taskengine.DataTypeJSON,
+	}}, nil
+}
+
+// validateReadOnly accepts a single SELECT statement and rejects everything else.
+// It is a guard against mistakes in the allowlist, not a SQL parser.
+func validateReadOnly(query string) error {
+	stmt := strings.TrimSpace(query)
+	stmt = strings.TrimSuffix(stmt, ";")
+	if strings.Contains(stmt, ";") {
+		return fmt.Errorf("%w: multiple statements", ErrQueryNotAllowed)
+	}
+	fields := strings.Fields(strings.ToLower(stmt))
+	if len(fields) == 0 || fields[0] != "select" {
+		return fmt.Errorf("%w: only SELECT statements are permitted", ErrQueryNotAllowed)
+	}
+	for _, f := range fields {
+		switch f {
+		case "insert", "update", "delete", "drop", "alter", "truncate", "create", "grant", "into":
+			return fmt.Errorf("%w: statement contains %q", ErrQueryNotAllowed, f)
+		}
+	}
+	return nil
+}
+
+var _ taskengine.HookRepo = (*SQLQueryHook)(nil)
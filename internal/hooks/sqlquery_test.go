@@ -0,0 +1,207 @@
+package hooks_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/hooks"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDriver is a minimal database/sql driver that records the last query
+// and its arguments and returns a fixed result set.
+type recordingDriver struct {
+	mu         sync.Mutex
+	lastQuery  string
+	lastArgs   []driver.Value
+	readOnly   bool
+	rolledBack bool
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return &recordingConn{d: d}, nil }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{d: c.d, query: query}, nil
+}
+func (c *recordingConn) Close() error              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+func (c *recordingConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.readOnly = opts.ReadOnly
+	c.d.rolledBack = false
+	return recordingTx{d: c.d}, nil
+}
+
+type recordingTx struct{ d *recordingDriver }
+
+func (tx recordingTx) Commit() error { return nil }
+func (tx recordingTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.rolledBack = true
+	return nil
+}
+
+type recordingStmt struct {
+	d     *recordingDriver
+	query string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+func (s *recordingStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	s.d.lastQuery = s.query
+	s.d.lastArgs = args
+	s.d.mu.Unlock()
+	return &fixedRows{data: [][]driver.Value{{"1", []byte("alice")}, {"2", []byte("bob")}, {"3", []byte("carol")}}}, nil
+}
+
+type fixedRows struct {
+	data [][]driver.Value
+	pos  int
+}
+
+func (r *fixedRows) Columns() []string { return []string{"id", "name"} }
+func (r *fixedRows) Close() error      { return nil }
+func (r *fixedRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.data) {
+		return io.EOF
+	}
+	copy(dest, r.data[r.pos])
+	r.pos++
+	return nil
+}
+
+var (
+	registerOnce sync.Once
+	testDriver   = &recordingDriver{}
+)
+
+func newTestSQLHook(t *testing.T, maxRows int) taskengine.HookRepo {
+	t.Helper()
+	registerOnce.Do(func() { sql.Register("sqlhooktest", testDriver) })
+	db, err := sql.Open("sqlhooktest", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	hook, err := hooks.NewSQLQueryHook(db, map[string]hooks.SQLQuery{
+		"users_by_team": {
+			SQL:    "SELECT id, name FROM users WHERE team = $1 AND active = $2",
+			Params: []string{"team", "active"},
+		},
+	}, maxRows, time.Second)
+	require.NoError(t, err)
+	return hook
+}
+
+func TestUnit_SQLQueryHook_AllowlistedQuery(t *testing.T) {
+	hook := newTestSQLHook(t, 2)
+
+	out, dt, _, err := hook.Exec(context.Background(), time.Now(), nil, taskengine.DataTypeAny, "", &taskengine.HookCall{
+		Name: hooks.SQLQueryHookName,
+		Args: map[string]string{"query": "users_by_team", "team": "core", "active": "true"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, taskengine.DataTypeJSON, dt)
+	require.Equal(t, []map[string]any{
+		{"id": "1", "name": "alice"},
+		{"id": "2", "name": "bob"},
+	}, out, "row limit must cap the result")
+}
+
+func TestUnit_SQLQueryHook_BindsParameters(t *testing.T) {
+	hook := newTestSQLHook(t, 10)
+
+	_, _, _, err := hook.Exec(context.Background(), time.Now(), nil, taskengine.DataTypeAny, "", &taskengine.HookCall{
+		Name: hooks.SQLQueryHookName,
+		Args: map[string]string{"query": "users_by_team", "team": "x'; DROP TABLE users; --", "active": "true"},
+	})
+	require.NoError(t, err)
+
+	testDriver.mu.Lock()
+	defer testDriver.mu.Unlock()
+	require.Equal(t, "SELECT id, name FROM users WHERE team = $1 AND active = $2", testDriver.lastQuery)
+	require.Equal(t, []driver.Value{"x'; DROP TABLE users; --", "true"}, testDriver.lastArgs)
+	require.True(t, testDriver.readOnly, "queries run in a read-only transaction")
+	require.True(t, testDriver.rolledBack, "the transaction is always rolled back")
+
+	_, _, _, err = hook.Exec(context.Background(), time.Now(), nil, taskengine.DataTypeAny, "", &taskengine.HookCall{
+		Name: hooks.SQLQueryHookName,
+		Args: map[string]string{"query": "users_by_team", "team": "core"},
+	})
+	require.ErrorContains(t, err, `missing parameter "active"`)
+}
+
+func TestUnit_SQLQueryHook_RejectsQueries(t *testing.T) {
+	hook := newTestSQLHook(t, 10)
+
+	_, _, _, err := hook.Exec(context.Background(), time.Now(), nil, taskengine.DataTypeAny, "", &taskengine.HookCall{
+		Name: hooks.SQLQueryHookName,
+		Args: map[string]string{"query": "DELETE FROM users"},
+	})
+	require.ErrorIs(t, err, hooks.ErrQueryNotAllowed)
+
+	for _, stmt := range []string{
+		"DELETE FROM users",
+		"UPDATE users SET name = 'x'",
+		"SELECT 1; DROP TABLE users",
+		"SELECT * INTO backup FROM users",
+	} {
+		_, err := hooks.NewSQLQueryHook(&sql.DB{}, map[string]hooks.SQLQuery{"bad": {SQL: stmt}}, 0, 0)
+		require.ErrorIs(t, err, hooks.ErrQueryNotAllowed, stmt)
+	}
+}
+
+func TestUnit_SQLQueryHook_ValidateArgs(t *testing.T) {
+	hook := newTestSQLHook(t, 2)
+	ctx := context.Background()
+
+	require.NoError(t, hook.ValidateArgs(ctx, &taskengine.HookCall{
+		Name: hooks.SQLQueryHookName,
+		Args: map[string]string{"query": "users_by_team", "team": "core", "active": "true"},
+	}))
+
+	err := hook.ValidateArgs(ctx, &taskengine.HookCall{Name: hooks.SQLQueryHookName, Args: map[string]string{}})
+	require.ErrorContains(t, err, `missing required arg "query"`)
+
+	err = hook.ValidateArgs(ctx, &taskengine.HookCall{Name: hooks.SQLQueryHookName, Args: map[string]string{"query": "drop_users"}})
+	require.ErrorIs(t, err, hooks.ErrQueryNotAllowed)
+
+	err = hook.ValidateArgs(ctx, &taskengine.HookCall{
+		Name: hooks.SQLQueryHookName,
+		Args: map[string]string{"query": "users_by_team", "team": "core"},
+	})
+	require.ErrorContains(t, err, `missing required arg "active"`)
+}
+
+func TestUnit_SQLQueryHook_Schemas(t *testing.T) {
+	hook := newTestSQLHook(t, 2)
+
+	schemas, err := hook.Schemas(context.Background())
+	require.NoError(t, err)
+	require.Len(t, schemas, 1)
+	schema := schemas[0]
+	require.Equal(t, hooks.SQLQueryHookName, schema.Name)
+	require.Equal(t, taskengine.DataTypeJSON, schema.OutputType)
+	require.Len(t, schema.Args, 3)
+	require.Equal(t, "query", schema.Args[0].Name)
+	require.True(t, schema.Args[0].Required)
+	require.Contains(t, schema.Args[0].Description, "users_by_team")
+	require.Equal(t, "team", schema.Args[1].Name)
+	require.False(t, schema.Args[1].Required, "parameters depend on the chosen query")
+	require.Equal(t, "active", schema.Args[2].Name)
+}
@@ -0,0 +1,120 @@
+package kvapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/kvservice"
+)
+
+func AddKVRoutes(mux *http.ServeMux, service kvservice.Service) {
+	h := &handler{service: service}
+	mux.HandleFunc("GET /kv", h.list)
+	mux.HandleFunc("GET /kv/{key}", h.get)
+	mux.HandleFunc("PUT /kv/{key}", h.set)
+	mux.HandleFunc("DELETE /kv/{key}", h.delete)
+}
+
+type handler struct {
+	service kvservice.Service
+}
+
+// Lists key-value entries, optionally filtered by key prefix.
+//
+// Entries under blocked prefixes are never listed; asking for such a prefix fails with 403 Forbidden.
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	prefix := apiframework.GetQueryParam(r, "prefix", "", "Only return entries whose key starts with this prefix.")
+	limitStr := apiframework.GetQueryParam(r, "limit", "100", "The maximum number of items to return per page.")
+	cursorStr := apiframework.GetQueryParam(r, "cursor", "", "An optional RFC3339Nano timestamp to fetch the next page of results.")
+
+	var cursor *time.Time
+	if cursorStr != "" {
+		t, err := time.Parse(time.RFC3339Nano, cursorStr)
+		if err != nil {
+			err = fmt.Errorf("%w: invalid cursor format, expected RFC3339Nano", apiframework.ErrUnprocessableEntity)
+			_ = apiframework.Error(w, r, err, apiframework.ListOperation)
+			return
+		}
+		cursor = &t
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 {
+		err = fmt.Errorf("%w: limit must be a positive integer", apiframework.ErrUnprocessableEntity)
+		_ = apiframework.Error(w, r, err, apiframework.ListOperation)
+		return
+	}
+
+	entries, err := h.service.List(ctx, prefix, cursor, limit)
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.ListOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, entries) // @response []runtimetypes.KV
+}
+
+// Returns the raw JSON value stored under a key.
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := apiframework.GetPathParam(r, "key", "The key of the entry.")
+	if key == "" {
+		_ = apiframework.Error(w, r, fmt.Errorf("key is required: %w", apiframework.ErrBadPathValue), apiframework.GetOperation)
+		return
+	}
+
+	value, err := h.service.Get(ctx, key)
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.GetOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, value) // @response json.RawMessage
+}
+
+// Stores the request body, which must be valid JSON, under a key.
+//
+// An existing value is replaced. Fails with 403 Forbidden for keys under blocked prefixes.
+func (h *handler) set(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := apiframework.GetPathParam(r, "key", "The key of the entry.")
+	if key == "" {
+		_ = apiframework.Error(w, r, fmt.Errorf("key is required: %w", apiframework.ErrBadPathValue), apiframework.UpdateOperation)
+		return
+	}
+
+	value, err := apiframework.Decode[json.RawMessage](r) // @request json.RawMessage
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.UpdateOperation)
+		return
+	}
+
+	if err := h.service.Set(ctx, key, value); err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.UpdateOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, value) // @response json.RawMessage
+}
+
+// Deletes the entry stored under a key.
+func (h *handler) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := apiframework.GetPathParam(r, "key", "The key of the entry to delete.")
+	if key == "" {
+		_ = apiframework.Error(w, r, fmt.Errorf("key is required: %w", apiframework.ErrBadPathValue), apiframework.DeleteOperation)
+		return
+	}
+
+	if err := h.service.Delete(ctx, key); err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.DeleteOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, fmt.Sprintf("key %s deleted", key)) // @response string
+}
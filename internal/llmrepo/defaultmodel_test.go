@@ -0,0 +1,32 @@
+package llmrepo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_WithDefaultModel_ResolvesDefault(t *testing.T) {
+	def := ModelConfig{Name: "phi3:3.8b", Provider: "ollama"}
+
+	req, err := withDefaultModel(Request{ContextLength: 2048}, def)
+	require.NoError(t, err)
+	require.Equal(t, []string{"phi3:3.8b"}, req.ModelNames)
+	require.Equal(t, []string{"ollama"}, req.ProviderTypes)
+	require.Equal(t, 2048, req.ContextLength)
+
+	// Models named by the chain take precedence over the default.
+	req, err = withDefaultModel(Request{ModelNames: []string{"gpt-4o"}, ProviderTypes: []string{"openai"}}, def)
+	require.NoError(t, err)
+	require.Equal(t, []string{"gpt-4o"}, req.ModelNames)
+	require.Equal(t, []string{"openai"}, req.ProviderTypes)
+}
+
+func TestUnit_WithDefaultModel_NoDefault(t *testing.T) {
+	_, err := withDefaultModel(Request{}, ModelConfig{})
+	require.ErrorIs(t, err, ErrNoDefaultModel)
+
+	req, err := withDefaultModel(Request{ModelNames: []string{"gpt-4o"}}, ModelConfig{})
+	require.NoError(t, err)
+	require.Empty(t, req.ProviderTypes, "no provider restriction without a default provider")
+}
@@ -27,6 +27,9 @@ type EmbedRequest struct {
 	ModelName    string
 	ProviderType string
 	Tracker      libtracker.ActivityTracker
+	// ExcludeBackends lists backend IDs that must not be selected,
+	// e.g. because they already failed for this request.
+	ExcludeBackends []string
 }
 
 type Meta struct {
@@ -41,7 +44,7 @@ type ModelRepo interface {
 	PromptExecute(
 		ctx context.Context,
 		req Request,
-		systeminstruction string, temperature float32, prompt string,
+		systeminstruction string, temperature *float32, prompt string,
 	) (string, Meta, error)
 	Chat(
 		ctx context.Context,
@@ -139,21 +142,17 @@ func (e *modelManager) CountTokens(ctx context.Context, modelName string, prompt
 func (e *modelManager) PromptExecute(
 	ctx context.Context,
 	req Request,
-	systemInstruction string, temperature float32, prompt string,
+	systemInstruction string, temperature *float32, prompt string,
 ) (string, Meta, error) {
 	if err := validateRequest(req); err != nil {
 		return "", Meta{}, fmt.Errorf("invalid request: %w", err)
 	}
 
-	runtimeStateResolution := e.GetRuntime(ctx)
-
-	// Apply defaults if not provided
-	if len(req.ModelNames) == 0 {
-		req.ModelNames = []string{e.config.DefaultPromptModel.Name}
-	}
-	if len(req.ProviderTypes) == 0 {
-		req.ProviderTypes = []string{e.config.DefaultPromptModel.Provider}
+	req, err := withDefaultModel(req, e.config.DefaultPromptModel)
+	if err != nil {
+		return "", Meta{}, fmt.Errorf("prompt execute: %w", err)
 	}
+	runtimeStateResolution := e.GetRuntime(ctx)
 
 	resolverReq := e.convertToResolverRequest(req)
 	client, provider, backend, err := llmresolver.PromptExecute(ctx,
@@ -165,7 +164,18 @@ func (e *modelManager) PromptExecute(
 		return "", Meta{}, fmt.Errorf("prompt execute: client resolution failed: %w", err)
 	}
 	defer safeClose(client)
+	release, err := e.runtime.Admit(ctx, backend)
+	if err != nil {
+		return "", Meta{}, fmt.Errorf("prompt execute: %w", err)
+	}
+	defer release()
+	ctx, cancel := e.withCallTimeout(ctx, backend)
+	defer cancel()
 
+	if params := modelParams(e.runtime.Get(ctx), backend, provider.ModelName()); temperature == nil && params.Temperature != nil {
+		t := float32(*params.Temperature)
+		temperature = &t
+	}
 	result, err := client.Prompt(ctx, systemInstruction, temperature, prompt)
 	if err != nil {
 		return "", Meta{}, fmt.Errorf("prompt execution failed: %w", err)
@@ -192,15 +202,11 @@ func (e *modelManager) Chat(
 		return libmodelprovider.Message{}, Meta{}, errors.New("messages cannot be empty")
 	}
 
-	runtimeStateResolution := e.GetRuntime(ctx)
-
-	// Apply defaults if not provided
-	if len(req.ModelNames) == 0 {
-		req.ModelNames = []string{e.config.DefaultChatModel.Name}
-	}
-	if len(req.ProviderTypes) == 0 {
-		req.ProviderTypes = []string{e.config.DefaultChatModel.Provider}
+	req, err := withDefaultModel(req, e.config.DefaultChatModel)
+	if err != nil {
+		return libmodelprovider.Message{}, Meta{}, fmt.Errorf("chat: %w", err)
 	}
+	runtimeStateResolution := e.GetRuntime(ctx)
 
 	resolverReq := e.convertToResolverRequest(req)
 	client, provider, backend, err := llmresolver.Chat(ctx,
@@ -212,8 +218,16 @@ func (e *modelManager) Chat(
 		return libmodelprovider.Message{}, Meta{}, fmt.Errorf("chat: client resolution failed: %w", err)
 	}
 	defer safeClose(client)
+	release, err := e.runtime.Admit(ctx, backend)
+	if err != nil {
+		return libmodelprovider.Message{}, Meta{}, fmt.Errorf("chat: %w", err)
+	}
+	defer release()
+	ctx, cancel := e.withCallTimeout(ctx, backend)
+	defer cancel()
 
-	response, err := client.Chat(ctx, messages, opts...)
+	params := modelParams(e.runtime.Get(ctx), backend, provider.ModelName())
+	response, err := client.Chat(ctx, messages, withModelParams(params, opts)...)
 	if err != nil {
 		return libmodelprovider.Message{}, Meta{}, fmt.Errorf("chat execution failed: %w", err)
 	}
@@ -249,23 +263,31 @@ func (e *modelManager) Embed(
 	client, provider, backend, err := llmresolver.Embed(ctx,
 		resolverReq,
 		runtimeStateResolution,
-		llmresolver.Randomly,
+		excludeBackends(llmresolver.Randomly, embedReq.ExcludeBackends),
 	)
 	if err != nil {
 		return nil, Meta{}, fmt.Errorf("embed: client resolution failed: %w", err)
 	}
 	defer safeClose(client)
 
-	embeddings, err := client.Embed(ctx, prompt)
-	if err != nil {
-		return nil, Meta{}, fmt.Errorf("embedding generation failed: %w", err)
-	}
-
 	meta := Meta{
 		ModelName:    provider.ModelName(),
 		ProviderType: provider.GetType(),
 		BackendID:    backend,
 	}
+	release, err := e.runtime.Admit(ctx, backend)
+	if err != nil {
+		return nil, meta, fmt.Errorf("embed: %w", err)
+	}
+	defer release()
+	ctx, cancel := e.withCallTimeout(ctx, backend)
+	defer cancel()
+	embeddings, err := client.Embed(ctx, prompt)
+	if err != nil {
+		// Return meta so callers know which backend failed.
+		return nil, meta, fmt.Errorf("embedding generation failed: %w", err)
+	}
+
 	return embeddings, meta, nil
 }
 
@@ -301,21 +323,33 @@ func (e *modelManager) Stream(
 	if err != nil {
 		return nil, Meta{}, fmt.Errorf("stream: client resolution failed: %w", err)
 	}
+	release, err := e.runtime.Admit(ctx, backend)
+	if err != nil {
+		safeClose(client)
+		return nil, Meta{}, fmt.Errorf("stream: %w", err)
+	}
 
 	stream, err := client.Stream(ctx, prompt)
 	if err != nil {
+		release()
 		safeClose(client)
 		return nil, Meta{}, fmt.Errorf("stream initialization failed: %w", err)
 	}
 
-	// Wrap the stream to close the client when done
+	// Wrap the stream to close the client and free the pool slot when done
 	wrappedStream := make(chan *libmodelprovider.StreamParcel)
 	go func() {
 		defer close(wrappedStream)
 		defer safeClose(client)
+		defer release()
 
 		for parcel := range stream {
-			wrappedStream <- parcel
+			select {
+			case wrappedStream <- parcel:
+			case <-ctx.Done():
+				// The consumer is gone; stop instead of holding the pool slot forever.
+				return
+			}
 			if parcel.Error != nil {
 				break
 			}
@@ -332,7 +366,7 @@ func (e *modelManager) Stream(
 
 func (e *modelManager) GetRuntime(ctx context.Context) runtimestate.ProviderFromRuntimeState {
 	state := e.runtime.Get(ctx)
-	return runtimestate.LocalProviderAdapter(ctx, state)
+	return runtimestate.LocalProviderAdapter(ctx, state, e.runtime.DefaultBackendTimeout())
 }
 
 func (e *modelManager) GetTokenizer(ctx context.Context, modelName string) (Tokenizer, error) {
@@ -370,6 +404,68 @@ func (e *modelManager) convertToResolverEmbedRequest(req EmbedRequest) llmresolv
 	}
 }
 
+// excludeBackends wraps a resolver policy so that the listed backends are never selected.
+// Providers left without any eligible backend are dropped from the candidates.
+func excludeBackends(
+	policy func(candidates []libmodelprovider.Provider) (libmodelprovider.Provider, string, error),
+	excluded []string,
+) func(candidates []libmodelprovider.Provider) (libmodelprovider.Provider, string, error) {
+	if len(excluded) == 0 {
+		return policy
+	}
+	skip := make(map[string]struct{}, len(excluded))
+	for _, id := range excluded {
+		skip[id] = struct{}{}
+	}
+	return func(candidates []libmodelprovider.Provider) (libmodelprovider.Provider, string, error) {
+		filtered := make([]libmodelprovider.Provider, 0, len(candidates))
+		for _, p := range candidates {
+			var backends []string
+			for _, id := range p.GetBackendIDs() {
+				if _, ok := skip[id]; !ok {
+					backends = append(backends, id)
+				}
+			}
+			if len(backends) > 0 {
+				filtered = append(filtered, &filteredProvider{Provider: p, backends: backends})
+			}
+		}
+		if len(filtered) == 0 {
+			return nil, "", llmresolver.ErrNoSatisfactoryModel
+		}
+		return policy(filtered)
+	}
+}
+
+// filteredProvider restricts the backends a provider reports.
+type filteredProvider struct {
+	libmodelprovider.Provider
+	backends []string
+}
+
+func (p *filteredProvider) GetBackendIDs() []string {
+	return p.backends
+}
+
+// ErrNoDefaultModel is returned for requests that name no model when no
+// default model is configured either.
+var ErrNoDefaultModel = errors.New("no model specified and no default model configured")
+
+// withDefaultModel fills in the default model, and its provider, for requests
+// that name no model, e.g. hand-written chains without an execute_config.
+func withDefaultModel(req Request, def ModelConfig) (Request, error) {
+	if len(req.ModelNames) == 0 {
+		if def.Name == "" {
+			return req, ErrNoDefaultModel
+		}
+		req.ModelNames = []string{def.Name}
+	}
+	if len(req.ProviderTypes) == 0 && def.Provider != "" {
+		req.ProviderTypes = []string{def.Provider}
+	}
+	return req, nil
+}
+
 func validateRequest(req Request) error {
 	if req.ContextLength < 0 {
 		return errors.New("context length must be non-negative")
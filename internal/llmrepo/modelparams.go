@@ -0,0 +1,66 @@
+package llmrepo
+
+import (
+	"context"
+	"time"
+
+	libmodelprovider "github.com/contenox/runtime/internal/modelrepo"
+	"github.com/contenox/runtime/internal/runtimestate"
+	"github.com/contenox/runtime/runtimetypes"
+	"github.com/contenox/runtime/statetype"
+)
+
+// modelParams returns the default parameters declared for modelName on the
+// backend with the given URL.
+func modelParams(state map[string]statetype.BackendRuntimeState, backendURL, modelName string) runtimetypes.ModelParams {
+	for _, backend := range state {
+		if backend.Backend.BaseURL != backendURL {
+			continue
+		}
+		for _, model := range backend.PulledModels {
+			if model.Model == modelName {
+				return model.DefaultParams
+			}
+		}
+	}
+	return runtimetypes.ModelParams{}
+}
+
+// callTimeout returns the timeout of model calls to the backend with the given URL.
+func callTimeout(state map[string]statetype.BackendRuntimeState, backendURL string, defaultTimeout time.Duration) time.Duration {
+	for _, backend := range state {
+		if backend.Backend.BaseURL == backendURL {
+			return runtimestate.BackendTimeout(backend.Backend, defaultTimeout)
+		}
+	}
+	return max(defaultTimeout, 0)
+}
+
+// withModelParams puts chat options for the model's default parameters in
+// front of opts, so that parameters set on the request override them.
+func withModelParams(params runtimetypes.ModelParams, opts []libmodelprovider.ChatOption) []libmodelprovider.ChatOption {
+	var defaults []libmodelprovider.ChatOption
+	if params.Temperature != nil {
+		defaults = append(defaults, libmodelprovider.WithTemperature(*params.Temperature))
+	}
+	if params.TopP > 0 {
+		defaults = append(defaults, libmodelprovider.WithTopP(params.TopP))
+	}
+	if params.MaxTokens > 0 {
+		defaults = append(defaults, libmodelprovider.WithMaxTokens(params.MaxTokens))
+	}
+	if len(params.Stop) > 0 {
+		defaults = append(defaults, libmodelprovider.WithStop(params.Stop))
+	}
+	return append(defaults, opts...)
+}
+
+// withCallTimeout bounds a non-streaming model call to backend as a whole,
+// including reading the response, by the backend's timeout.
+func (e *modelManager) withCallTimeout(ctx context.Context, backend string) (context.Context, context.CancelFunc) {
+	timeout := callTimeout(e.runtime.Get(ctx), backend, e.runtime.DefaultBackendTimeout())
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
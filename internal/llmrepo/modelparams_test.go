@@ -0,0 +1,75 @@
+package llmrepo
+
+import (
+	"testing"
+	"time"
+
+	libmodelprovider "github.com/contenox/runtime/internal/modelrepo"
+	"github.com/contenox/runtime/runtimetypes"
+	"github.com/contenox/runtime/statetype"
+	"github.com/stretchr/testify/require"
+)
+
+// chatArgs records the options applied to a chat request.
+type chatArgs struct {
+	temperature float64
+	maxTokens   int
+	topP        float64
+	stop        []string
+}
+
+func (a *chatArgs) SetTemperature(t float64)               { a.temperature = t }
+func (a *chatArgs) SetMaxTokens(n int)                     { a.maxTokens = n }
+func (a *chatArgs) SetTopP(p float64)                      { a.topP = p }
+func (a *chatArgs) SetStop(stop []string)                  { a.stop = stop }
+func (a *chatArgs) SetTools(tools []libmodelprovider.Tool) {}
+func (a *chatArgs) SetKeepAlive(time.Duration)             {}
+
+func ptr[T any](v T) *T { return &v }
+
+func apply(opts []libmodelprovider.ChatOption) *chatArgs {
+	args := &chatArgs{temperature: 0.7}
+	for _, opt := range opts {
+		opt.ApplyTo(args)
+	}
+	return args
+}
+
+func TestUnit_ModelParams_LookupByBackendAndModel(t *testing.T) {
+	state := map[string]statetype.BackendRuntimeState{
+		"b1": {
+			Backend: runtimetypes.Backend{BaseURL: "http://ollama:11434"},
+			PulledModels: []statetype.ModelPullStatus{
+				{Model: "qwen2.5-coder:7b", DefaultParams: runtimetypes.ModelParams{Temperature: ptr(0.1)}},
+				{Model: "mistral:instruct"},
+			},
+		},
+	}
+	require.Equal(t, ptr(0.1), modelParams(state, "http://ollama:11434", "qwen2.5-coder:7b").Temperature)
+	require.Zero(t, modelParams(state, "http://ollama:11434", "mistral:instruct"))
+	require.Zero(t, modelParams(state, "http://other:11434", "qwen2.5-coder:7b"))
+}
+
+func TestUnit_WithModelParams(t *testing.T) {
+	params := runtimetypes.ModelParams{Temperature: ptr(0.1), MaxTokens: 256, Stop: []string{"</code>"}}
+
+	// The model's defaults apply where the request leaves parameters unset.
+	args := apply(withModelParams(params, nil))
+	require.Equal(t, 0.1, args.temperature)
+	require.Equal(t, 256, args.maxTokens)
+	require.Equal(t, []string{"</code>"}, args.stop)
+	require.Zero(t, args.topP)
+
+	// Parameters set on the request override them.
+	args = apply(withModelParams(params, []libmodelprovider.ChatOption{libmodelprovider.WithTemperature(0.9)}))
+	require.Equal(t, 0.9, args.temperature)
+	require.Equal(t, 256, args.maxTokens)
+
+	// Without model defaults the provider's default stays in place.
+	args = apply(withModelParams(runtimetypes.ModelParams{}, nil))
+	require.Equal(t, 0.7, args.temperature)
+
+	// A default temperature of 0 is applied, not treated as unset.
+	args = apply(withModelParams(runtimetypes.ModelParams{Temperature: ptr(0.0)}, nil))
+	require.Zero(t, args.temperature)
+}
@@ -0,0 +1,158 @@
+package llmrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/ollama/ollama/api"
+)
+
+// RetryConfig controls how the retrying embedder handles failures.
+type RetryConfig struct {
+	// MaxAttempts is the number of tries against a single backend before falling back.
+	MaxAttempts int
+	// MaxBackends is the number of distinct backends to try before giving up.
+	MaxBackends int
+	// InitialBackoff is the wait before the first retry; it doubles on each retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryConfig returns a conservative retry configuration.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:    2,
+		MaxBackends:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
+var _ ModelRepo = (*retryingEmbedder)(nil)
+
+type retryingEmbedder struct {
+	ModelRepo
+	config RetryConfig
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryingEmbedder wraps repo so that Embed retries transient failures with backoff
+// and, once a backend keeps failing, falls back to another backend serving the model.
+// All other operations are passed through unchanged.
+func NewRetryingEmbedder(repo ModelRepo, config RetryConfig) ModelRepo {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	if config.MaxBackends < 1 {
+		config.MaxBackends = 1
+	}
+	return &retryingEmbedder{
+		ModelRepo: repo,
+		config:    config,
+		sleep:     sleepCtx,
+	}
+}
+
+func (r *retryingEmbedder) Embed(ctx context.Context, embedReq EmbedRequest, prompt string) ([]float64, Meta, error) {
+	excluded := append([]string(nil), embedReq.ExcludeBackends...)
+	var lastErr error
+
+	for backendTry := 0; backendTry < r.config.MaxBackends; backendTry++ {
+		backoff := r.config.InitialBackoff
+		req := embedReq
+		req.ExcludeBackends = excluded
+
+		var failedBackend string
+		for attempt := 0; attempt < r.config.MaxAttempts; attempt++ {
+			if attempt > 0 {
+				if err := r.sleep(ctx, backoff); err != nil {
+					return nil, Meta{}, err
+				}
+				backoff *= 2
+				if r.config.MaxBackoff > 0 && backoff > r.config.MaxBackoff {
+					backoff = r.config.MaxBackoff
+				}
+			}
+
+			embeddings, meta, err := r.ModelRepo.Embed(ctx, req, prompt)
+			if err == nil {
+				return embeddings, meta, nil
+			}
+			if backendTry > 0 && meta.BackendID == "" {
+				// Every remaining backend has been excluded; report the last real failure.
+				return nil, Meta{}, fmt.Errorf("embedding failed after retries: %w", lastErr)
+			}
+			lastErr = err
+			if !IsRetryable(err) {
+				return nil, meta, err
+			}
+			failedBackend = meta.BackendID
+		}
+
+		// No backend was selected, so there is nothing left to fall back to.
+		if failedBackend == "" {
+			break
+		}
+		excluded = append(excluded, failedBackend)
+	}
+
+	return nil, Meta{}, fmt.Errorf("embedding failed after retries: %w", lastErr)
+}
+
+var statusCodePattern = regexp.MustCompile(`status(?: code)?:? \(?(\d{3})`)
+
+// IsRetryable reports whether err looks like a transient failure worth retrying,
+// such as a timeout, a dropped connection or a 5xx response from the backend.
+// Client errors like a bad request are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var statusErr api.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	if m := statusCodePattern.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code >= 500
+	}
+	return false
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,99 @@
+package llmrepo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/llmrepo"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyEmbedder fails on every backend listed in failing and succeeds on the others.
+type flakyEmbedder struct {
+	llmrepo.ModelRepo
+	backends []string
+	failing  map[string]error
+	calls    []string
+}
+
+func (f *flakyEmbedder) Embed(ctx context.Context, req llmrepo.EmbedRequest, prompt string) ([]float64, llmrepo.Meta, error) {
+	for _, backend := range f.backends {
+		if slices.Contains(req.ExcludeBackends, backend) {
+			continue
+		}
+		f.calls = append(f.calls, backend)
+		meta := llmrepo.Meta{ModelName: req.ModelName, BackendID: backend}
+		if err, ok := f.failing[backend]; ok {
+			return nil, meta, err
+		}
+		return []float64{0.1, 0.2}, meta, nil
+	}
+	return nil, llmrepo.Meta{}, errors.New("no backend available")
+}
+
+func testRetryConfig() llmrepo.RetryConfig {
+	return llmrepo.RetryConfig{
+		MaxAttempts:    2,
+		MaxBackends:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}
+}
+
+func TestUnit_RetryingEmbedder_FallsBackToSecondBackend(t *testing.T) {
+	fake := &flakyEmbedder{
+		backends: []string{"b1", "b2"},
+		failing: map[string]error{
+			"b1": fmt.Errorf("embedding request failed: %w", syscall.ECONNRESET),
+		},
+	}
+	repo := llmrepo.NewRetryingEmbedder(fake, testRetryConfig())
+
+	vec, meta, err := repo.Embed(context.Background(), llmrepo.EmbedRequest{ModelName: "m"}, "hello")
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.1, 0.2}, vec)
+	require.Equal(t, "b2", meta.BackendID)
+	require.Equal(t, []string{"b1", "b1", "b2"}, fake.calls)
+}
+
+func TestUnit_RetryingEmbedder_DoesNotRetryBadRequest(t *testing.T) {
+	fake := &flakyEmbedder{
+		backends: []string{"b1", "b2"},
+		failing: map[string]error{
+			"b1": errors.New("OpenAI API returned non-200 status: 400 for model m"),
+		},
+	}
+	repo := llmrepo.NewRetryingEmbedder(fake, testRetryConfig())
+
+	_, _, err := repo.Embed(context.Background(), llmrepo.EmbedRequest{ModelName: "m"}, "hello")
+	require.Error(t, err)
+	require.Equal(t, []string{"b1"}, fake.calls)
+}
+
+func TestUnit_RetryingEmbedder_GivesUpWhenAllBackendsFail(t *testing.T) {
+	serverErr := errors.New("vLLM API returned non-200 status: 503 for model m")
+	fake := &flakyEmbedder{
+		backends: []string{"b1", "b2"},
+		failing:  map[string]error{"b1": serverErr, "b2": serverErr},
+	}
+	repo := llmrepo.NewRetryingEmbedder(fake, testRetryConfig())
+
+	_, _, err := repo.Embed(context.Background(), llmrepo.EmbedRequest{ModelName: "m"}, "hello")
+	require.Error(t, err)
+	require.ErrorIs(t, err, serverErr)
+	require.Equal(t, []string{"b1", "b1", "b2", "b2"}, fake.calls)
+}
+
+func TestUnit_IsRetryable(t *testing.T) {
+	require.True(t, llmrepo.IsRetryable(context.DeadlineExceeded))
+	require.True(t, llmrepo.IsRetryable(fmt.Errorf("wrapped: %w", syscall.ECONNRESET)))
+	require.True(t, llmrepo.IsRetryable(errors.New("gemini API returned non-200 status: 502 for model x")))
+	require.False(t, llmrepo.IsRetryable(errors.New("gemini API returned non-200 status: 400 for model x")))
+	require.False(t, llmrepo.IsRetryable(context.Canceled))
+	require.False(t, llmrepo.IsRetryable(nil))
+}
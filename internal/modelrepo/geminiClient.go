@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type geminiClient struct {
@@ -21,7 +24,7 @@ type geminiPromptClient struct {
 	geminiClient
 }
 
-func (c *geminiPromptClient) Prompt(ctx context.Context, systeminstruction string, temperature float32, prompt string) (string, error) {
+func (c *geminiPromptClient) Prompt(ctx context.Context, systeminstruction string, temperature *float32, prompt string) (string, error) {
 	// Convert the single prompt string into a Gemini-style message array
 	geminiMessages := []geminiContent{
 		{
@@ -40,10 +43,13 @@ func (c *geminiPromptClient) Prompt(ctx context.Context, systeminstruction strin
 			},
 		},
 		GenerationConfig: &geminiGenerationConfig{
-			Temperature:     float64(temperature),
 			MaxOutputTokens: c.maxTokens,
 		},
 	}
+	if temperature != nil {
+		t := float64(*temperature)
+		request.GenerationConfig.Temperature = &t
+	}
 
 	endpoint := fmt.Sprintf("/v1beta/models/%s:generateContent", c.modelName)
 	var response geminiGenerateContentResponse
@@ -73,11 +79,12 @@ type geminiChatClient struct {
 func (c *geminiChatClient) Chat(ctx context.Context, messages []Message, options ...ChatOption) (Message, error) {
 	geminiMessages, systemInstruction := convertToGeminiMessages(messages)
 
+	defaultTemperature := 0.7
 	request := geminiGenerateContentRequest{
 		SystemInstruction: &systemInstruction,
 		Contents:          geminiMessages,
 		GenerationConfig: &geminiGenerationConfig{
-			Temperature:     0.7,         // default
+			Temperature:     &defaultTemperature,
 			MaxOutputTokens: c.maxTokens, // default
 		},
 	}
@@ -86,8 +93,7 @@ func (c *geminiChatClient) Chat(ctx context.Context, messages []Message, options
 	adapter := &geminiChatRequestAdapter{req: &request}
 	for _, opt := range options {
 		if opt != nil {
-			opt.SetTemperature(adapter.req.GenerationConfig.Temperature)
-			opt.SetMaxTokens(adapter.req.GenerationConfig.MaxOutputTokens)
+			opt.ApplyTo(adapter)
 		}
 	}
 
@@ -102,6 +108,29 @@ func (c *geminiChatClient) Chat(ctx context.Context, messages []Message, options
 	}
 
 	candidate := response.Candidates[0]
+	var toolCalls []ToolCall
+	for _, part := range candidate.Content.Parts {
+		if part.FunctionCall == nil {
+			continue
+		}
+		args, err := json.Marshal(part.FunctionCall.Args)
+		if err != nil {
+			return Message{}, fmt.Errorf("invalid function call arguments from model %s: %w", c.modelName, err)
+		}
+		// Gemini does not assign call IDs; generate one so results can be linked back.
+		toolCalls = append(toolCalls, ToolCall{
+			ID:       "call_" + uuid.NewString(),
+			Type:     "function",
+			Function: FunctionCall{Name: part.FunctionCall.Name, Arguments: string(args)},
+		})
+	}
+	if len(toolCalls) > 0 {
+		var text string
+		for _, part := range candidate.Content.Parts {
+			text += part.Text
+		}
+		return Message{Role: "assistant", Content: text, ToolCalls: toolCalls, FinishReason: FinishReasonToolCalls}, nil
+	}
 	if len(candidate.Content.Parts) == 0 || candidate.Content.Parts[0].Text == "" {
 		if len(candidate.FinishReason) > 0 {
 			return Message{}, fmt.Errorf(
@@ -112,7 +141,11 @@ func (c *geminiChatClient) Chat(ctx context.Context, messages []Message, options
 		return Message{}, fmt.Errorf("empty content from model %s", c.modelName)
 	}
 
-	return Message{Role: "assistant", Content: candidate.Content.Parts[0].Text}, nil
+	finishReason := FinishReasonStop
+	if candidate.FinishReason == "MAX_TOKENS" {
+		finishReason = FinishReasonLength
+	}
+	return Message{Role: "assistant", Content: candidate.Content.Parts[0].Text, FinishReason: finishReason}, nil
 }
 
 // Adapter so ChatOption can modify Gemini requests
@@ -121,13 +154,32 @@ type geminiChatRequestAdapter struct {
 }
 
 func (a *geminiChatRequestAdapter) SetTemperature(temp float64) {
-	a.req.GenerationConfig.Temperature = temp
+	a.req.GenerationConfig.Temperature = &temp
 }
 
 func (a *geminiChatRequestAdapter) SetMaxTokens(max int) {
 	a.req.GenerationConfig.MaxOutputTokens = max
 }
 
+func (a *geminiChatRequestAdapter) SetTopP(topP float64) {
+	a.req.GenerationConfig.TopP = topP
+}
+
+func (a *geminiChatRequestAdapter) SetStop(stop []string) {
+	a.req.GenerationConfig.StopSequences = stop
+}
+
+func (a *geminiChatRequestAdapter) SetTools(tools []Tool) {
+	declarations := make([]any, 0, len(tools))
+	for _, tool := range tools {
+		declarations = append(declarations, tool.Function)
+	}
+	a.req.Tools = []geminiTool{{FunctionDeclarations: declarations}}
+}
+
+// SetKeepAlive is a no-op, keep_alive only applies to Ollama backends.
+func (a *geminiChatRequestAdapter) SetKeepAlive(time.Duration) {}
+
 // geminiEmbedClient implements serverops.LLMEmbedClient
 type geminiEmbedClient struct {
 	geminiClient
@@ -221,10 +221,24 @@ func (p *GeminiProvider) GetStreamConnection(ctx context.Context, backendID stri
 
 // geminiPart represents a part of the content, typically text.
 type geminiPart struct {
-	Text string `json:"text,omitempty"`
+	Text             string                  `json:"text,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
 	// TODO: for multimodal inputs, other fields like inlineData, fileData would go here
 }
 
+// geminiFunctionCall is a function invocation requested by the model.
+type geminiFunctionCall struct {
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// geminiFunctionResponse returns the result of a function call to the model.
+type geminiFunctionResponse struct {
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
 // geminiContent represents a conversational turn with a role and parts.
 type geminiContent struct {
 	Role  string       `json:"role"` // "user" or "model"
@@ -249,7 +263,7 @@ type geminiGenerateContentRequest struct {
 
 // geminiGenerationConfig defines parameters for text generation.
 type geminiGenerationConfig struct {
-	Temperature     float64  `json:"temperature,omitempty"`
+	Temperature     *float64 `json:"temperature,omitempty"`
 	TopP            float64  `json:"topP,omitempty"`
 	TopK            int      `json:"topK,omitempty"`
 	CandidateCount  int      `json:"candidateCount,omitempty"`
@@ -310,19 +324,40 @@ func convertToGeminiMessages(messages []Message) ([]geminiContent, geminiSystemI
 	systemInstruction := geminiSystemInstruction{
 		Parts: []geminiPart{},
 	}
+	toolNames := map[string]string{}
 	for _, msg := range messages {
 		// Gemini API expects "user" and "model" roles for conversational turns.
 		// System instructions are handled separately.
-		if msg.Role == "system" {
+		switch {
+		case msg.Role == "system":
 			systemInstruction.Parts = append(systemInstruction.Parts, geminiPart{Text: msg.Content})
-		} else {
+		case msg.ToolCallID != "":
+			// Tool results are sent back as function responses, matched by function name.
+			geminiMsgs = append(geminiMsgs, geminiContent{
+				Role: "user",
+				Parts: []geminiPart{{FunctionResponse: &geminiFunctionResponse{
+					Name:     toolNames[msg.ToolCallID],
+					Response: map[string]any{"content": msg.Content},
+				}}},
+			})
+		default:
 			role := msg.Role
 			if role == "assistant" {
 				role = "model"
 			}
+			parts := []geminiPart{}
+			if msg.Content != "" || len(msg.ToolCalls) == 0 {
+				parts = append(parts, geminiPart{Text: msg.Content})
+			}
+			for _, call := range msg.ToolCalls {
+				toolNames[call.ID] = call.Function.Name
+				var args map[string]any
+				_ = json.Unmarshal([]byte(call.Function.Arguments), &args)
+				parts = append(parts, geminiPart{FunctionCall: &geminiFunctionCall{Name: call.Function.Name, Args: args}})
+			}
 			geminiMsgs = append(geminiMsgs, geminiContent{
 				Role:  role,
-				Parts: []geminiPart{{Text: msg.Content}},
+				Parts: parts,
 			})
 		}
 	}
@@ -5,11 +5,53 @@ import "context"
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	// ToolCalls lists the tools an assistant message asks the caller to invoke.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolCallID links a "tool" message to the call it answers.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	// FinishReason is set on chat responses and tells why generation ended
+	// (FinishReasonStop or FinishReasonLength). It is never sent to a provider.
+	FinishReason string `json:"-"`
 }
 
+// Finish reasons reported on chat responses.
+const (
+	FinishReasonStop   = "stop"
+	FinishReasonLength = "length"
+	// FinishReasonToolCalls means the model stopped to request tool calls.
+	FinishReasonToolCalls = "tool_calls"
+)
+
+// Tool describes a function the model may call, in the OpenAI tool format.
+type Tool struct {
+	Type     string       `json:"type"`
+	Function FunctionTool `json:"function"`
+}
+
+// FunctionTool is the declaration of a callable function.
+// Parameters is a JSON schema object describing the arguments.
+type FunctionTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+// ToolCall is a function invocation requested by the model.
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall holds the function name and its JSON encoded arguments.
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ChatOption sets a sampling parameter on a chat request.
 type ChatOption interface {
-	SetTemperature(float64)
-	SetMaxTokens(int)
+	ApplyTo(ChatArgument)
 }
 
 type StreamParcel struct {
@@ -31,5 +73,5 @@ type LLMStreamClient interface {
 }
 
 type LLMPromptExecClient interface {
-	Prompt(ctx context.Context, systeminstruction string, temperature float32, prompt string) (string, error)
+	Prompt(ctx context.Context, systeminstruction string, temperature *float32, prompt string) (string, error)
 }
@@ -116,7 +116,7 @@ func (m *MockChatClient) Close() error {
 type MockPromptClient struct{}
 
 // Prompt returns a mock response.
-func (m *MockPromptClient) Prompt(ctx context.Context, systemInstruction string, temperature float32, prompt string) (string, error) {
+func (m *MockPromptClient) Prompt(ctx context.Context, systemInstruction string, temperature *float32, prompt string) (string, error) {
 	return "mock response", nil
 }
 
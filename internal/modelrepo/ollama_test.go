@@ -165,7 +165,8 @@ func TestSystem_Ollama(t *testing.T) {
 		// Test basic prompt
 		system := "You are a Task Engine answering other Machines directly without explanation"
 		prompt := "What is the capital of France?"
-		resp, err := promptClient.Prompt(ctx, system, 0.7, prompt)
+		temperature := float32(0.7)
+		resp, err := promptClient.Prompt(ctx, system, &temperature, prompt)
 		require.NoError(t, err)
 		assert.Contains(t, resp, "Paris")
 		assert.NotContains(t, resp, "think")
@@ -182,7 +183,8 @@ func TestSystem_Ollama(t *testing.T) {
 		require.NoError(t, err)
 
 		// Test with low temperature for deterministic output
-		resp, err := promptClient.Prompt(ctx, "You are a calculator", 0.1, "How much is 2 + 2?")
+		temperature := float32(0.1)
+		resp, err := promptClient.Prompt(ctx, "You are a calculator", &temperature, "How much is 2 + 2?")
 		require.NoError(t, err)
 		assert.Contains(t, resp, "4")
 	})
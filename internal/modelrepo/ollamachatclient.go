@@ -2,8 +2,11 @@ package modelrepo
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/ollama/ollama/api"
 )
 
@@ -17,6 +20,10 @@ type OllamaChatClient struct {
 type ollamaChatRequestAdapter struct {
 	temperature float64
 	maxTokens   int
+	topP        float64
+	stop        []string
+	tools       []Tool
+	keepAlive   *time.Duration
 }
 
 func (a *ollamaChatRequestAdapter) SetTemperature(temp float64) {
@@ -27,15 +34,28 @@ func (a *ollamaChatRequestAdapter) SetMaxTokens(max int) {
 	a.maxTokens = max
 }
 
+func (a *ollamaChatRequestAdapter) SetTopP(topP float64) {
+	a.topP = topP
+}
+
+func (a *ollamaChatRequestAdapter) SetStop(stop []string) {
+	a.stop = stop
+}
+
+func (a *ollamaChatRequestAdapter) SetTools(tools []Tool) {
+	a.tools = tools
+}
+
+func (a *ollamaChatRequestAdapter) SetKeepAlive(keepAlive time.Duration) {
+	a.keepAlive = &keepAlive
+}
+
 var _ LLMChatClient = (*OllamaChatClient)(nil)
 
 func (c *OllamaChatClient) Chat(ctx context.Context, messages []Message, options ...ChatOption) (Message, error) {
-	apiMessages := make([]api.Message, 0, len(messages))
-	for _, msg := range messages {
-		apiMessages = append(apiMessages, api.Message{
-			Role:    msg.Role,
-			Content: msg.Content,
-		})
+	apiMessages, err := toOllamaMessages(messages)
+	if err != nil {
+		return Message{}, fmt.Errorf("invalid messages for model %s: %w", c.modelName, err)
 	}
 
 	// Default values
@@ -49,12 +69,7 @@ func (c *OllamaChatClient) Chat(ctx context.Context, messages []Message, options
 	// Apply ChatOptions using the standard pattern
 	for _, opt := range options {
 		if opt != nil {
-			// First pass: let options observe current values
-			opt.SetTemperature(adapter.temperature)
-			opt.SetMaxTokens(adapter.maxTokens)
-			// Second pass: let options set new values
-			opt.SetTemperature(adapter.temperature)
-			opt.SetMaxTokens(adapter.maxTokens)
+			opt.ApplyTo(adapter)
 		}
 	}
 	llamaOptions := map[string]any{
@@ -64,6 +79,12 @@ func (c *OllamaChatClient) Chat(ctx context.Context, messages []Message, options
 	if adapter.maxTokens > 0 {
 		llamaOptions["num_predict"] = adapter.maxTokens
 	}
+	if adapter.topP > 0 {
+		llamaOptions["top_p"] = adapter.topP
+	}
+	if len(adapter.stop) > 0 {
+		llamaOptions["stop"] = adapter.stop
+	}
 
 	think := api.ThinkValue{
 		Value: false,
@@ -76,13 +97,35 @@ func (c *OllamaChatClient) Chat(ctx context.Context, messages []Message, options
 		Think:    &think,
 		Options:  llamaOptions,
 	}
+	if adapter.keepAlive != nil {
+		req.KeepAlive = &api.Duration{Duration: *adapter.keepAlive}
+	}
+	if len(adapter.tools) > 0 {
+		tools, err := toOllamaTools(adapter.tools)
+		if err != nil {
+			return Message{}, fmt.Errorf("invalid tools for model %s: %w", c.modelName, err)
+		}
+		req.Tools = tools
+	}
 
 	var finalResponse api.ChatResponse
 	var content string
+	var toolCalls []ToolCall
 
 	// Handle the API call first
-	err := c.ollamaClient.Chat(ctx, req, func(res api.ChatResponse) error {
+	err = c.ollamaClient.Chat(ctx, req, func(res api.ChatResponse) error {
 		content += res.Message.Content
+		for _, call := range res.Message.ToolCalls {
+			// Ollama does not assign call IDs; generate one so results can be linked back.
+			toolCalls = append(toolCalls, ToolCall{
+				ID:   "call_" + uuid.NewString(),
+				Type: "function",
+				Function: FunctionCall{
+					Name:      call.Function.Name,
+					Arguments: call.Function.Arguments.String(),
+				},
+			})
+		}
 		// For non-streaming, we expect exactly one response with Done=true
 		if res.Done {
 			finalResponse = res
@@ -109,13 +152,21 @@ func (c *OllamaChatClient) Chat(ctx context.Context, messages []Message, options
 			finalResponse.Message.Content,
 		)
 	case "length":
-		// Treat token limit hits as application errors
-		return Message{}, fmt.Errorf(
-			"token limit reached for model %s (partial response: %q)",
-			c.modelName,
-			finalResponse.Message.Content,
-		)
+		// Token limit reached; return the partial response
+		return Message{
+			Role:         finalResponse.Message.Role,
+			Content:      content,
+			FinishReason: FinishReasonLength,
+		}, nil
 	case "stop":
+		if len(toolCalls) > 0 {
+			return Message{
+				Role:         finalResponse.Message.Role,
+				Content:      content,
+				ToolCalls:    toolCalls,
+				FinishReason: FinishReasonToolCalls,
+			}, nil
+		}
 		// Normal completion, but ensure content exists
 		if finalResponse.Message.Content == "" {
 			return Message{}, fmt.Errorf(
@@ -134,7 +185,52 @@ func (c *OllamaChatClient) Chat(ctx context.Context, messages []Message, options
 
 	// Successful response
 	return Message{
-		Role:    finalResponse.Message.Role,
-		Content: content,
+		Role:         finalResponse.Message.Role,
+		Content:      content,
+		FinishReason: FinishReasonStop,
 	}, nil
 }
+
+// toOllamaMessages converts messages to the Ollama format. Tool results are
+// matched to the name of the tool they answer, since Ollama has no call IDs.
+func toOllamaMessages(messages []Message) ([]api.Message, error) {
+	toolNames := map[string]string{}
+	apiMessages := make([]api.Message, 0, len(messages))
+	for _, msg := range messages {
+		apiMsg := api.Message{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+		for _, call := range msg.ToolCalls {
+			toolNames[call.ID] = call.Function.Name
+			var args api.ToolCallFunctionArguments
+			if call.Function.Arguments != "" {
+				if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+					return nil, fmt.Errorf("tool call %s has invalid arguments: %w", call.ID, err)
+				}
+			}
+			apiMsg.ToolCalls = append(apiMsg.ToolCalls, api.ToolCall{
+				Function: api.ToolCallFunction{Name: call.Function.Name, Arguments: args},
+			})
+		}
+		if msg.ToolCallID != "" {
+			apiMsg.ToolName = toolNames[msg.ToolCallID]
+		}
+		apiMessages = append(apiMessages, apiMsg)
+	}
+	return apiMessages, nil
+}
+
+// toOllamaTools converts tool declarations through their JSON form,
+// which Ollama shares with the OpenAI format.
+func toOllamaTools(tools []Tool) (api.Tools, error) {
+	data, err := json.Marshal(tools)
+	if err != nil {
+		return nil, err
+	}
+	var apiTools api.Tools
+	if err := json.Unmarshal(data, &apiTools); err != nil {
+		return nil, err
+	}
+	return apiTools, nil
+}
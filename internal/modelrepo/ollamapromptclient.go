@@ -14,20 +14,22 @@ type OllamaPromptClient struct {
 }
 
 // Prompt implements serverops.LLMPromptClient.
-func (o *OllamaPromptClient) Prompt(ctx context.Context, systeminstruction string, temperature float32, prompt string) (string, error) {
+func (o *OllamaPromptClient) Prompt(ctx context.Context, systeminstruction string, temperature *float32, prompt string) (string, error) {
 	stream := false
 	think := api.ThinkValue{
 		Value: false,
 	}
+	options := map[string]any{}
+	if temperature != nil {
+		options["temperature"] = *temperature
+	}
 	req := &api.GenerateRequest{
-		Model:  o.modelName,
-		Prompt: prompt,
-		System: systeminstruction,
-		Stream: &stream, // Disable streaming to get a single response
-		Options: map[string]any{
-			"temperature": temperature,
-		},
-		Think: &think,
+		Model:   o.modelName,
+		Prompt:  prompt,
+		System:  systeminstruction,
+		Stream:  &stream, // Disable streaming to get a single response
+		Options: options,
+		Think:   &think,
 	}
 
 	var (
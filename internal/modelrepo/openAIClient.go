@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
 type openAIClient struct {
@@ -21,12 +22,15 @@ type openAIPromptClient struct {
 	openAIClient
 }
 
-func (c *openAIPromptClient) Prompt(ctx context.Context, systemMessage string, temperature float32, prompt string) (string, error) {
+func (c *openAIPromptClient) Prompt(ctx context.Context, systemMessage string, temperature *float32, prompt string) (string, error) {
 	request := openAIChatRequest{
-		Model:       c.modelName,
-		Messages:    []Message{{Role: "system", Content: systemMessage}, {Role: "user", Content: prompt}},
-		Temperature: float64(temperature),
-		MaxTokens:   c.maxTokens,
+		Model:     c.modelName,
+		Messages:  []Message{{Role: "system", Content: systemMessage}, {Role: "user", Content: prompt}},
+		MaxTokens: c.maxTokens,
+	}
+	if temperature != nil {
+		t := float64(*temperature)
+		request.Temperature = &t
 	}
 
 	var response openAIChatResponse
@@ -54,18 +58,34 @@ type chatRequestAdapter struct {
 }
 
 func (a *chatRequestAdapter) SetTemperature(temp float64) {
-	a.req.Temperature = temp
+	a.req.Temperature = &temp
 }
 
 func (a *chatRequestAdapter) SetMaxTokens(max int) {
 	a.req.MaxTokens = max
 }
 
+func (a *chatRequestAdapter) SetTopP(topP float64) {
+	a.req.TopP = topP
+}
+
+func (a *chatRequestAdapter) SetStop(stop []string) {
+	a.req.Stop = stop
+}
+
+func (a *chatRequestAdapter) SetTools(tools []Tool) {
+	a.req.Tools = tools
+}
+
+// SetKeepAlive is a no-op, keep_alive only applies to Ollama backends.
+func (a *chatRequestAdapter) SetKeepAlive(time.Duration) {}
+
 func (c *openAIChatClient) Chat(ctx context.Context, messages []Message, opts ...ChatOption) (Message, error) {
+	defaultTemperature := 0.5
 	request := openAIChatRequest{
 		Model:       c.modelName,
 		Messages:    messages,
-		Temperature: 0.5,         // default
+		Temperature: &defaultTemperature,
 		MaxTokens:   c.maxTokens, // default
 	}
 
@@ -73,9 +93,7 @@ func (c *openAIChatClient) Chat(ctx context.Context, messages []Message, opts ..
 	adapter := &chatRequestAdapter{req: &request}
 	for _, opt := range opts {
 		if opt != nil {
-			// Option receives current value and can override it
-			opt.SetTemperature(adapter.req.Temperature)
-			opt.SetMaxTokens(adapter.req.MaxTokens)
+			opt.ApplyTo(adapter)
 		}
 	}
 
@@ -89,10 +107,18 @@ func (c *openAIChatClient) Chat(ctx context.Context, messages []Message, opts ..
 	}
 
 	choice := response.Choices[0]
+	if len(choice.Message.ToolCalls) > 0 {
+		choice.Message.FinishReason = FinishReasonToolCalls
+		return choice.Message, nil
+	}
+	if choice.FinishReason == FinishReasonLength {
+		choice.Message.FinishReason = FinishReasonLength
+		return choice.Message, nil
+	}
 	if choice.Message.Content == "" {
 		return Message{}, fmt.Errorf("empty content from model %s despite normal completion. Finish reason: %s", c.modelName, choice.FinishReason)
 	}
-
+	choice.Message.FinishReason = FinishReasonStop
 	return choice.Message, nil
 }
 
@@ -181,8 +207,11 @@ func (c *openAIClient) sendRequest(ctx context.Context, endpoint string, request
 type openAIChatRequest struct {
 	Model       string    `json:"model"`
 	Messages    []Message `json:"messages"`
-	Temperature float64   `json:"temperature,omitempty"`
+	Temperature *float64  `json:"temperature,omitempty"`
 	MaxTokens   int       `json:"max_tokens,omitempty"`
+	TopP        float64   `json:"top_p,omitempty"`
+	Stop        []string  `json:"stop,omitempty"`
+	Tools       []Tool    `json:"tools,omitempty"`
 	Stream      bool      `json:"stream,omitempty"`
 }
 
@@ -1,36 +1,98 @@
 package modelrepo
 
-type chatOption struct {
-	apply func(*chatConfig)
-}
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
 
-func (c *chatOption) SetTemperature(temp float64) {
-	c.apply(&chatConfig{temperature: temp})
+// ChatArgument is the provider-specific chat request that options are applied to.
+// Each chat client implements it with an adapter around its request type.
+type ChatArgument interface {
+	SetTemperature(float64)
+	SetMaxTokens(int)
+	SetTopP(float64)
+	SetStop([]string)
+	SetTools([]Tool)
+	SetKeepAlive(time.Duration)
 }
 
-func (c *chatOption) SetMaxTokens(tokens int) {
-	c.apply(&chatConfig{maxTokens: tokens})
+type chatOption struct {
+	apply func(ChatArgument)
 }
 
-// Internal config to hold settings
-type chatConfig struct {
-	temperature float64
-	maxTokens   int
+func (c *chatOption) ApplyTo(arg ChatArgument) {
+	c.apply(arg)
 }
 
 // Functional option constructors
 func WithTemperature(temp float64) ChatOption {
 	return &chatOption{
-		apply: func(config *chatConfig) {
-			config.temperature = temp
+		apply: func(arg ChatArgument) {
+			arg.SetTemperature(temp)
 		},
 	}
 }
 
 func WithMaxTokens(tokens int) ChatOption {
 	return &chatOption{
-		apply: func(config *chatConfig) {
-			config.maxTokens = tokens
+		apply: func(arg ChatArgument) {
+			arg.SetMaxTokens(tokens)
+		},
+	}
+}
+
+func WithTopP(topP float64) ChatOption {
+	return &chatOption{
+		apply: func(arg ChatArgument) {
+			arg.SetTopP(topP)
+		},
+	}
+}
+
+func WithStop(stop []string) ChatOption {
+	return &chatOption{
+		apply: func(arg ChatArgument) {
+			arg.SetStop(stop)
+		},
+	}
+}
+
+// WithTools offers the given tools to the model.
+func WithTools(tools []Tool) ChatOption {
+	return &chatOption{
+		apply: func(arg ChatArgument) {
+			arg.SetTools(tools)
+		},
+	}
+}
+
+// WithKeepAlive sets how long an Ollama backend keeps the model loaded after
+// the request, see ParseKeepAlive. Other backends ignore it.
+func WithKeepAlive(keepAlive time.Duration) ChatOption {
+	return &chatOption{
+		apply: func(arg ChatArgument) {
+			arg.SetKeepAlive(keepAlive)
 		},
 	}
 }
+
+// ParseKeepAlive parses an Ollama keep_alive value: a duration like "10m", or a
+// number of seconds. Negative values keep the model loaded indefinitely and
+// zero unloads it right after the request.
+func ParseKeepAlive(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return -1, nil
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	keepAlive, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid keep_alive %q: expected a duration like \"10m\" or a number of seconds", value)
+	}
+	if keepAlive < 0 {
+		return -1, nil
+	}
+	return keepAlive, nil
+}
@@ -0,0 +1,113 @@
+package modelrepo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/modelrepo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_ParseKeepAlive(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"10m":  10 * time.Minute,
+		"1h":   time.Hour,
+		"3600": time.Hour,
+		"0":    0,
+		"-1":   -1,
+		"-1m":  -1,
+	} {
+		got, err := modelrepo.ParseKeepAlive(value)
+		require.NoError(t, err, value)
+		require.Equal(t, want, got, value)
+	}
+	for _, value := range []string{"", "forever", "10 minutes"} {
+		_, err := modelrepo.ParseKeepAlive(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestUnit_OllamaChat_ForwardsKeepAlive(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/chat", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"mistral:instruct","message":{"role":"assistant","content":"hi"},"done":true,"done_reason":"stop"}`))
+	}))
+	defer server.Close()
+
+	provider := modelrepo.NewOllamaModelProvider("mistral:instruct", []string{server.URL}, server.Client(), modelrepo.CapabilityConfig{CanChat: true})
+	client, err := provider.GetChatConnection(t.Context(), server.URL)
+	require.NoError(t, err)
+	messages := []modelrepo.Message{{Role: "user", Content: "hello"}}
+
+	_, err = client.Chat(t.Context(), messages, modelrepo.WithKeepAlive(10*time.Minute))
+	require.NoError(t, err)
+	_, err = client.Chat(t.Context(), messages, modelrepo.WithKeepAlive(-1))
+	require.NoError(t, err)
+	_, err = client.Chat(t.Context(), messages)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 3)
+	require.Equal(t, "10m0s", requests[0]["keep_alive"])
+	require.Equal(t, float64(-1), requests[1]["keep_alive"])
+	require.NotContains(t, requests[2], "keep_alive", "unset keep_alive leaves the backend default")
+}
+
+func TestUnit_OpenAIChat_ForwardsZeroTemperature(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	provider := modelrepo.NewOpenAIProvider("key", "gpt-4o", []string{server.URL}, modelrepo.CapabilityConfig{CanChat: true}, server.Client())
+	client, err := provider.GetChatConnection(t.Context(), server.URL)
+	require.NoError(t, err)
+	messages := []modelrepo.Message{{Role: "user", Content: "hello"}}
+
+	_, err = client.Chat(t.Context(), messages, modelrepo.WithTemperature(0))
+	require.NoError(t, err)
+	_, err = client.Chat(t.Context(), messages)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	require.Equal(t, float64(0), requests[0]["temperature"])
+	require.Equal(t, 0.5, requests[1]["temperature"])
+}
+
+func TestUnit_OpenAIPrompt_ForwardsZeroTemperature(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	provider := modelrepo.NewOpenAIProvider("key", "gpt-4o", []string{server.URL}, modelrepo.CapabilityConfig{CanPrompt: true}, server.Client())
+	client, err := provider.GetPromptConnection(t.Context(), server.URL)
+	require.NoError(t, err)
+
+	zero := float32(0)
+	_, err = client.Prompt(t.Context(), "system", &zero, "hello")
+	require.NoError(t, err)
+	_, err = client.Prompt(t.Context(), "system", nil, "hello")
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	require.Equal(t, float64(0), requests[0]["temperature"])
+	require.NotContains(t, requests[1], "temperature", "an unset temperature leaves the provider default")
+}
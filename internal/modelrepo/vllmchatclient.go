@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 )
 
 // Base client configuration
@@ -74,7 +75,7 @@ func NewVLLMChatClient(ctx context.Context, baseURL, modelName string, contextLe
 }
 
 // Prompt implements LLMPromptExecClient interface
-func (c *vLLMClient) Prompt(ctx context.Context, systeminstruction string, temperature float32, prompt string) (string, error) {
+func (c *vLLMClient) Prompt(ctx context.Context, systeminstruction string, temperature *float32, prompt string) (string, error) {
 	// Convert system instruction and prompt to proper message format
 	messages := []Message{
 		{Role: "system", Content: systeminstruction},
@@ -85,9 +86,12 @@ func (c *vLLMClient) Prompt(ctx context.Context, systeminstruction string, tempe
 	request := chatRequest{
 		Model:       c.modelName,
 		Messages:    messages,
-		Temperature: float64(temperature),
+		Temperature: 0.7, // default
 		MaxTokens:   c.maxTokens,
 	}
+	if temperature != nil {
+		request.Temperature = float64(*temperature)
+	}
 
 	// Send request to the chat completions endpoint
 	var response chatResponse
@@ -129,12 +133,7 @@ func (c *VLLMChatClient) Chat(ctx context.Context, messages []Message, options .
 	adapter := &vllmChatRequestAdapter{req: &request}
 	for _, opt := range options {
 		if opt != nil {
-			// First: let option observe current values (for relative adjustments)
-			opt.SetTemperature(request.Temperature)
-			opt.SetMaxTokens(request.MaxTokens)
-			// Second: let option set new values (using adapter to modify request)
-			opt.SetTemperature(adapter.req.Temperature)
-			opt.SetMaxTokens(adapter.req.MaxTokens)
+			opt.ApplyTo(adapter)
 		}
 	}
 
@@ -153,13 +152,18 @@ func (c *VLLMChatClient) Chat(ctx context.Context, messages []Message, options .
 		if choice.Message.Content == "" {
 			return Message{}, fmt.Errorf("empty content from model %s despite normal completion", c.modelName)
 		}
+		choice.Message.FinishReason = FinishReasonStop
 		return choice.Message, nil
 	case "length":
-		return Message{}, fmt.Errorf(
-			"token limit reached for model %s (partial response: %q)",
-			c.modelName,
-			choice.Message.Content,
-		)
+		// Token limit reached; return the partial response
+		choice.Message.FinishReason = FinishReasonLength
+		return choice.Message, nil
+	case "tool_calls":
+		if len(choice.Message.ToolCalls) == 0 {
+			return Message{}, fmt.Errorf("no tool calls from model %s despite tool_calls completion", c.modelName)
+		}
+		choice.Message.FinishReason = FinishReasonToolCalls
+		return choice.Message, nil
 	case "content_filter":
 		return Message{}, fmt.Errorf(
 			"content filtered for model %s (partial response: %q)",
@@ -188,6 +192,21 @@ func (a *vllmChatRequestAdapter) SetMaxTokens(max int) {
 	a.req.MaxTokens = max
 }
 
+func (a *vllmChatRequestAdapter) SetTopP(topP float64) {
+	a.req.TopP = topP
+}
+
+func (a *vllmChatRequestAdapter) SetStop(stop []string) {
+	a.req.Stop = stop
+}
+
+func (a *vllmChatRequestAdapter) SetTools(tools []Tool) {
+	a.req.Tools = tools
+}
+
+// SetKeepAlive is a no-op, keep_alive only applies to Ollama backends.
+func (a *vllmChatRequestAdapter) SetKeepAlive(time.Duration) {}
+
 func (c *vLLMClient) sendRequest(ctx context.Context, endpoint string, request interface{}, response interface{}) error {
 	url := c.baseURL + endpoint
 
@@ -251,6 +270,9 @@ type chatRequest struct {
 	Messages    []Message `json:"messages"`
 	Temperature float64   `json:"temperature"`
 	MaxTokens   int       `json:"max_tokens"`
+	TopP        float64   `json:"top_p,omitempty"`
+	Stop        []string  `json:"stop,omitempty"`
+	Tools       []Tool    `json:"tools,omitempty"`
 	Stream      bool      `json:"stream,omitempty"`
 }
 
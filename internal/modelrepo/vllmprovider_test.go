@@ -139,7 +139,8 @@ func TestSystem_VLLM_Smoke(t *testing.T) {
 
 				t.Log("Testing prompt execution...")
 				start := time.Now()
-				resp, err := promptClient.Prompt(ctx, system, 0.7, promptText)
+				temperature := float32(0.7)
+				resp, err := promptClient.Prompt(ctx, system, &temperature, promptText)
 				elapsed := time.Since(start)
 
 				require.NoError(t, err, "failed to execute prompt")
@@ -0,0 +1,211 @@
+package prompttemplateapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/contenox/runtime/execservice"
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/prompttemplateservice"
+)
+
+func AddPromptTemplateRoutes(mux *http.ServeMux, service prompttemplateservice.Service, execService execservice.ExecService) {
+	h := &handler{service: service, execService: execService}
+	mux.HandleFunc("POST /prompt-templates", h.create)
+	mux.HandleFunc("GET /prompt-templates", h.list)
+	mux.HandleFunc("GET /prompt-templates/{name}", h.get)
+	mux.HandleFunc("PUT /prompt-templates/{name}", h.update)
+	mux.HandleFunc("DELETE /prompt-templates/{name}", h.delete)
+	mux.HandleFunc("POST /prompt-templates/{name}/render", h.render)
+	mux.HandleFunc("POST /prompt-templates/{name}/execute", h.execute)
+}
+
+type handler struct {
+	service     prompttemplateservice.Service
+	execService execservice.ExecService
+}
+
+// Stores a new named prompt template.
+//
+// Templates use the same syntax and helper functions as task prompt templates.
+// Every variable listed in variables must be supplied when the template is rendered.
+func (h *handler) create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tmpl, err := apiframework.Decode[prompttemplateservice.PromptTemplate](r) // @request prompttemplateservice.PromptTemplate
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.CreateOperation)
+		return
+	}
+
+	if err := h.service.Create(ctx, &tmpl); err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.CreateOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusCreated, tmpl) // @response prompttemplateservice.PromptTemplate
+}
+
+// Retrieves a prompt template by name.
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := apiframework.GetPathParam(r, "name", "The unique name of the prompt template.")
+	if name == "" {
+		_ = apiframework.Error(w, r, fmt.Errorf("prompt template name is required: %w", apiframework.ErrBadPathValue), apiframework.GetOperation)
+		return
+	}
+
+	tmpl, err := h.service.Get(ctx, name)
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.GetOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, tmpl) // @response prompttemplateservice.PromptTemplate
+}
+
+// Replaces an existing prompt template.
+func (h *handler) update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := apiframework.GetPathParam(r, "name", "The unique name of the prompt template.")
+	if name == "" {
+		_ = apiframework.Error(w, r, fmt.Errorf("prompt template name is required: %w", apiframework.ErrBadPathValue), apiframework.UpdateOperation)
+		return
+	}
+
+	tmpl, err := apiframework.Decode[prompttemplateservice.PromptTemplate](r) // @request prompttemplateservice.PromptTemplate
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.UpdateOperation)
+		return
+	}
+	if tmpl.Name != "" && tmpl.Name != name {
+		err = fmt.Errorf("%w: name in payload does not match URL", apiframework.ErrUnprocessableEntity)
+		_ = apiframework.Error(w, r, err, apiframework.UpdateOperation)
+		return
+	}
+
+	tmpl.Name = name
+	if err := h.service.Update(ctx, &tmpl); err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.UpdateOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, tmpl) // @response prompttemplateservice.PromptTemplate
+}
+
+// Lists prompt templates with pagination.
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	limitStr := apiframework.GetQueryParam(r, "limit", "100", "The maximum number of items to return per page.")
+	cursorStr := apiframework.GetQueryParam(r, "cursor", "", "An optional RFC3339Nano timestamp to fetch the next page of results.")
+
+	var cursor *time.Time
+	if cursorStr != "" {
+		t, err := time.Parse(time.RFC3339Nano, cursorStr)
+		if err != nil {
+			err = fmt.Errorf("%w: invalid cursor format, expected RFC3339Nano", apiframework.ErrUnprocessableEntity)
+			_ = apiframework.Error(w, r, err, apiframework.ListOperation)
+			return
+		}
+		cursor = &t
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 {
+		err = fmt.Errorf("%w: limit must be a positive integer", apiframework.ErrUnprocessableEntity)
+		_ = apiframework.Error(w, r, err, apiframework.ListOperation)
+		return
+	}
+
+	templates, err := h.service.List(ctx, cursor, limit)
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.ListOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, templates) // @response []*prompttemplateservice.PromptTemplate
+}
+
+// Deletes a prompt template.
+func (h *handler) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := apiframework.GetPathParam(r, "name", "The unique name of the prompt template to delete.")
+	if name == "" {
+		_ = apiframework.Error(w, r, fmt.Errorf("prompt template name is required: %w", apiframework.ErrBadPathValue), apiframework.DeleteOperation)
+		return
+	}
+
+	if err := h.service.Delete(ctx, name); err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.DeleteOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, fmt.Sprintf("prompt template %s deleted", name)) // @response string
+}
+
+type renderRequest struct {
+	Variables map[string]any `json:"variables" example:"{\"ticket\": \"Login fails\", \"sentences\": 2}"`
+}
+
+type renderResponse struct {
+	Prompt string `json:"prompt" example:"Summarize this ticket in 2 sentences: Login fails"`
+}
+
+// Renders a prompt template with the given variables without executing it.
+//
+// Fails with 400 Bad Request if a declared variable is missing.
+func (h *handler) render(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := apiframework.GetPathParam(r, "name", "The unique name of the prompt template.")
+	req, err := apiframework.Decode[renderRequest](r) // @request prompttemplateapi.renderRequest
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.ExecuteOperation)
+		return
+	}
+
+	prompt, err := h.service.Render(ctx, name, req.Variables)
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.ExecuteOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, renderResponse{Prompt: prompt}) // @response prompttemplateapi.renderResponse
+}
+
+type executeRequest struct {
+	Variables     map[string]any `json:"variables" example:"{\"ticket\": \"Login fails\", \"sentences\": 2}"`
+	ModelName     string         `json:"model_name,omitempty" example:"gpt-3.5-turbo"`
+	ModelProvider string         `json:"model_provider,omitempty" example:"openai"`
+}
+
+// Renders a prompt template and runs the result like POST /execute.
+//
+// Fails with 400 Bad Request if a declared variable is missing.
+func (h *handler) execute(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := apiframework.GetPathParam(r, "name", "The unique name of the prompt template.")
+	req, err := apiframework.Decode[executeRequest](r) // @request prompttemplateapi.executeRequest
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.ExecuteOperation)
+		return
+	}
+
+	prompt, err := h.service.Render(ctx, name, req.Variables)
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.ExecuteOperation)
+		return
+	}
+
+	resp, err := h.execService.Execute(ctx, &execservice.TaskRequest{
+		Prompt:        prompt,
+		ModelName:     req.ModelName,
+		ModelProvider: req.ModelProvider,
+	})
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.ExecuteOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, resp) // @response execservice.SimpleExecutionResponse
+}
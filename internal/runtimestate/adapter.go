@@ -2,14 +2,19 @@ package runtimestate
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/contenox/runtime/internal/modelrepo"
+	"github.com/contenox/runtime/runtimetypes"
 	"github.com/contenox/runtime/statetype"
 )
 
-// LocalProviderAdapter creates providers for self-hosted backends (Ollama, vLLM)
-func LocalProviderAdapter(ctx context.Context, runtime map[string]statetype.BackendRuntimeState) ProviderFromRuntimeState {
+// LocalProviderAdapter creates providers for self-hosted backends (Ollama, vLLM).
+// Model calls are bounded by each backend's timeout, or defaultTimeout if it has none.
+func LocalProviderAdapter(ctx context.Context, runtime map[string]statetype.BackendRuntimeState, defaultTimeout time.Duration) ProviderFromRuntimeState {
 	// Create a flat list of providers (one per model per backend)
 	providersByType := make(map[string][]modelrepo.Provider)
 
@@ -19,6 +24,7 @@ func LocalProviderAdapter(ctx context.Context, runtime map[string]statetype.Back
 		}
 
 		backendType := state.Backend.Type
+		client := backendHTTPClient(state.Backend, defaultTimeout)
 		if _, ok := providersByType[backendType]; !ok {
 			providersByType[backendType] = []modelrepo.Provider{}
 		}
@@ -39,7 +45,7 @@ func LocalProviderAdapter(ctx context.Context, runtime map[string]statetype.Back
 					modelrepo.NewOllamaModelProvider(
 						model.Model,
 						[]string{state.Backend.BaseURL},
-						http.DefaultClient,
+						client,
 						capability,
 					),
 				)
@@ -49,7 +55,7 @@ func LocalProviderAdapter(ctx context.Context, runtime map[string]statetype.Back
 					modelrepo.NewVLLMModelProvider(
 						model.Model,
 						[]string{state.Backend.BaseURL},
-						http.DefaultClient,
+						client,
 						capability,
 						state.GetAPIKey(),
 					),
@@ -62,7 +68,7 @@ func LocalProviderAdapter(ctx context.Context, runtime map[string]statetype.Back
 						model.Model,
 						[]string{state.Backend.BaseURL},
 						capability,
-						http.DefaultClient,
+						client,
 					),
 				)
 			case "gemini":
@@ -73,7 +79,7 @@ func LocalProviderAdapter(ctx context.Context, runtime map[string]statetype.Back
 						model.Model,
 						[]string{state.Backend.BaseURL},
 						capability,
-						http.DefaultClient,
+						client,
 					),
 				)
 			}
@@ -91,5 +97,42 @@ func LocalProviderAdapter(ctx context.Context, runtime map[string]statetype.Back
 	}
 }
 
+// backendTransports holds one transport per timeout, so connections stay pooled
+// across the clients built for every request.
+var backendTransports sync.Map // time.Duration -> *http.Transport
+
+// BackendTimeout returns the timeout of model calls to backend: its own, or
+// defaultTimeout if it has none. Zero means model calls are not bounded.
+func BackendTimeout(backend runtimetypes.Backend, defaultTimeout time.Duration) time.Duration {
+	if backend.Timeout != "" {
+		if d, err := time.ParseDuration(backend.Timeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	return max(defaultTimeout, 0)
+}
+
+// backendHTTPClient returns the HTTP client for model calls to backend.
+// The timeout bounds connecting, the TLS handshake and the wait for the
+// response headers, but not reading the body, so streamed responses are not
+// cut off once the backend has started answering. Non-streaming calls are
+// bounded as a whole by their context, see the llmrepo package.
+func backendHTTPClient(backend runtimetypes.Backend, defaultTimeout time.Duration) *http.Client {
+	timeout := BackendTimeout(backend, defaultTimeout)
+	if timeout <= 0 {
+		return http.DefaultClient
+	}
+	transport, ok := backendTransports.Load(timeout)
+	if !ok {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		dialer := &net.Dialer{Timeout: min(timeout, 30*time.Second), KeepAlive: 30 * time.Second}
+		t.DialContext = dialer.DialContext
+		t.TLSHandshakeTimeout = min(t.TLSHandshakeTimeout, timeout)
+		t.ResponseHeaderTimeout = timeout
+		transport, _ = backendTransports.LoadOrStore(timeout, t)
+	}
+	return &http.Client{Transport: transport.(*http.Transport)}
+}
+
 // ProviderFromRuntimeState retrieves available model providers
 type ProviderFromRuntimeState func(ctx context.Context, backendTypes ...string) ([]modelrepo.Provider, error)
@@ -2,9 +2,13 @@ package runtimestate_test
 
 import (
 	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/contenox/runtime/internal/modelrepo"
 	"github.com/contenox/runtime/internal/runtimestate"
 	"github.com/contenox/runtime/runtimetypes"
 	"github.com/contenox/runtime/statetype"
@@ -53,7 +57,7 @@ func TestUnit_ModelProviderAdapter_SetsCorrectModelCapabilities(t *testing.T) {
 	}
 
 	// 2. Get the adapter function
-	adapterFunc := runtimestate.LocalProviderAdapter(ctx, runtime)
+	adapterFunc := runtimestate.LocalProviderAdapter(ctx, runtime, 0)
 
 	// 3. Get the providers
 	providers, err := adapterFunc(ctx, "ollama")
@@ -132,7 +136,7 @@ func TestUnit_ModelProviderAdapter_PropagatesCapabilitiesCorrectly(t *testing.T)
 		},
 	}
 
-	adapterFunc := runtimestate.LocalProviderAdapter(ctx, runtime)
+	adapterFunc := runtimestate.LocalProviderAdapter(ctx, runtime, 0)
 
 	providers, err := adapterFunc(ctx, "ollama")
 	require.NoError(t, err, "should not return error")
@@ -188,7 +192,7 @@ func TestUnit_ModelProviderAdapter_HandlesMissingCapabilities(t *testing.T) {
 		},
 	}
 
-	adapterFunc := runtimestate.LocalProviderAdapter(ctx, runtime)
+	adapterFunc := runtimestate.LocalProviderAdapter(ctx, runtime, 0)
 
 	providers, err := adapterFunc(ctx, "ollama")
 	require.NoError(t, err, "should not return error")
@@ -202,3 +206,107 @@ func TestUnit_ModelProviderAdapter_HandlesMissingCapabilities(t *testing.T) {
 	require.False(t, p.CanStream(), "should default to no streaming support")
 	require.Equal(t, 0, p.GetContextLength(), "should default to zero context length")
 }
+
+func TestUnit_ModelProviderAdapter_AppliesBackendTimeouts(t *testing.T) {
+	ctx := context.Background()
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer slowServer.Close()
+
+	model := statetype.ModelPullStatus{Name: "gpt-test", Model: "gpt-test", CanChat: true, ContextLength: 4096}
+	backend := func(id, timeout string) statetype.BackendRuntimeState {
+		return statetype.BackendRuntimeState{
+			ID:           id,
+			Name:         id,
+			Backend:      runtimetypes.Backend{ID: id, Name: id, Type: "openai", BaseURL: slowServer.URL, Timeout: timeout},
+			PulledModels: []statetype.ModelPullStatus{model},
+		}
+	}
+	chat := func(t *testing.T, state statetype.BackendRuntimeState, defaultTimeout time.Duration) error {
+		t.Helper()
+		providers, err := runtimestate.LocalProviderAdapter(ctx, map[string]statetype.BackendRuntimeState{state.ID: state}, defaultTimeout)(ctx, "openai")
+		require.NoError(t, err)
+		require.Len(t, providers, 1)
+		client, err := providers[0].GetChatConnection(ctx, slowServer.URL)
+		require.NoError(t, err)
+		_, err = client.Chat(ctx, []modelrepo.Message{{Role: "user", Content: "hello"}})
+		return err
+	}
+
+	t.Run("short backend timeout fails fast", func(t *testing.T) {
+		start := time.Now()
+		err := chat(t, backend("fast", "50ms"), time.Minute)
+		require.Error(t, err)
+		require.Less(t, time.Since(start), 200*time.Millisecond)
+	})
+
+	t.Run("longer backend timeout succeeds", func(t *testing.T) {
+		require.NoError(t, chat(t, backend("patient", "2s"), 50*time.Millisecond))
+	})
+
+	t.Run("slow body after the headers is not cut off", func(t *testing.T) {
+		streamServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+		}))
+		defer streamServer.Close()
+		state := backend("streaming", "50ms")
+		state.Backend.BaseURL = streamServer.URL
+		providers, err := runtimestate.LocalProviderAdapter(ctx, map[string]statetype.BackendRuntimeState{state.ID: state}, 0)(ctx, "openai")
+		require.NoError(t, err)
+		client, err := providers[0].GetChatConnection(ctx, streamServer.URL)
+		require.NoError(t, err)
+		_, err = client.Chat(ctx, []modelrepo.Message{{Role: "user", Content: "hello"}})
+		require.NoError(t, err)
+	})
+
+	t.Run("backend without timeout uses default", func(t *testing.T) {
+		require.Error(t, chat(t, backend("default", ""), 50*time.Millisecond))
+		require.NoError(t, chat(t, backend("unbounded", ""), 0))
+	})
+}
+
+func TestUnit_ModelProviderAdapter_TimeoutCoversConnecting(t *testing.T) {
+	ctx := context.Background()
+	// The listener completes TCP handshakes but never answers the TLS handshake.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	for name, baseURL := range map[string]string{
+		"tls handshake never completes": "https://" + listener.Addr().String(),
+		"address not accepting":         "http://10.255.255.1:11434",
+	} {
+		t.Run(name, func(t *testing.T) {
+			state := statetype.BackendRuntimeState{
+				ID:           "unreachable",
+				Backend:      runtimetypes.Backend{ID: "unreachable", Type: "openai", BaseURL: baseURL, Timeout: "200ms"},
+				PulledModels: []statetype.ModelPullStatus{{Name: "gpt-test", Model: "gpt-test", CanChat: true, ContextLength: 4096}},
+			}
+			providers, err := runtimestate.LocalProviderAdapter(ctx, map[string]statetype.BackendRuntimeState{state.ID: state}, 0)(ctx, "openai")
+			require.NoError(t, err)
+			client, err := providers[0].GetChatConnection(ctx, baseURL)
+			require.NoError(t, err)
+
+			start := time.Now()
+			_, err = client.Chat(ctx, []modelrepo.Message{{Role: "user", Content: "hello"}})
+			require.Error(t, err)
+			require.Less(t, time.Since(start), 2*time.Second)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package runtimestate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/runtimetypes"
+)
+
+// Admission policies for runtimetypes.Pool.Admission.
+const (
+	AdmissionReject = "reject"
+	AdmissionQueue  = "queue"
+)
+
+// DefaultAdmissionWait is how long a call waits for a free slot in a pool
+// using the queue policy before it is rejected.
+const DefaultAdmissionWait = 2 * time.Second
+
+// ValidAdmission reports whether policy is a known admission policy.
+// The empty string selects AdmissionReject.
+func ValidAdmission(policy string) bool {
+	switch policy {
+	case "", AdmissionReject, AdmissionQueue:
+		return true
+	}
+	return false
+}
+
+type poolGate struct {
+	poolID string
+	queue  bool
+	slots  chan struct{}
+}
+
+// acquire takes a slot, waiting up to wait for one to become free.
+func (g *poolGate) acquire(ctx context.Context, wait time.Duration) error {
+	select {
+	case g.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if wait <= 0 {
+		return fmt.Errorf("pool %s has %d calls in flight: %w", g.poolID, cap(g.slots), apiframework.ErrPoolSaturated)
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case g.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return fmt.Errorf("pool %s: no free slot within %s: %w", g.poolID, wait, apiframework.ErrPoolSaturated)
+	}
+}
+
+func (g *poolGate) release() {
+	<-g.slots
+}
+
+// Admission limits the number of model calls in flight per pool.
+// A backend belonging to several limited pools takes a slot in each of them.
+type Admission struct {
+	wait time.Duration
+
+	mu       sync.RWMutex
+	gates    map[string]*poolGate
+	backends map[string][]*poolGate
+}
+
+// NewAdmission returns an Admission without any limits. wait bounds how long
+// queueing pools hold a call; non-positive selects DefaultAdmissionWait.
+func NewAdmission(wait time.Duration) *Admission {
+	if wait <= 0 {
+		wait = DefaultAdmissionWait
+	}
+	return &Admission{
+		wait:     wait,
+		gates:    make(map[string]*poolGate),
+		backends: make(map[string][]*poolGate),
+	}
+}
+
+// SetPools replaces the configured limits. backendPools maps backend IDs to the
+// IDs of the pools they belong to. Gates of pools whose limit and policy are
+// unchanged are kept, so calls in flight stay counted.
+func (a *Admission) SetPools(pools []*runtimetypes.Pool, backendPools map[string][]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	gates := make(map[string]*poolGate)
+	for _, pool := range pools {
+		if pool.MaxInFlight <= 0 {
+			continue
+		}
+		queue := pool.Admission == AdmissionQueue
+		if gate, ok := a.gates[pool.ID]; ok && cap(gate.slots) == pool.MaxInFlight && gate.queue == queue {
+			gates[pool.ID] = gate
+			continue
+		}
+		gates[pool.ID] = &poolGate{poolID: pool.ID, queue: queue, slots: make(chan struct{}, pool.MaxInFlight)}
+	}
+
+	backends := make(map[string][]*poolGate)
+	for backendID, poolIDs := range backendPools {
+		for _, poolID := range poolIDs {
+			if gate, ok := gates[poolID]; ok {
+				backends[backendID] = append(backends[backendID], gate)
+			}
+		}
+		// Acquire in a fixed order so queueing calls cannot deadlock each other.
+		sort.Slice(backends[backendID], func(i, j int) bool {
+			return backends[backendID][i].poolID < backends[backendID][j].poolID
+		})
+	}
+	a.gates = gates
+	a.backends = backends
+}
+
+// Admit reserves a slot for a call to backendID in every limited pool the backend
+// belongs to. The returned release must be called once the call has completed.
+// It fails with apiframework.ErrPoolSaturated if a pool has no capacity left.
+func (a *Admission) Admit(ctx context.Context, backendID string) (func(), error) {
+	return a.admit(ctx, backendID, true)
+}
+
+// TryAdmit is like Admit but never waits, regardless of the pools' policies.
+// Background work uses it to stay out of the way of real requests.
+func (a *Admission) TryAdmit(backendID string) (func(), error) {
+	return a.admit(context.Background(), backendID, false)
+}
+
+func (a *Admission) admit(ctx context.Context, backendID string, allowQueue bool) (func(), error) {
+	a.mu.RLock()
+	gates := a.backends[backendID]
+	a.mu.RUnlock()
+
+	for i, gate := range gates {
+		wait := time.Duration(0)
+		if allowQueue && gate.queue {
+			wait = a.wait
+		}
+		if err := gate.acquire(ctx, wait); err != nil {
+			for _, acquired := range gates[:i] {
+				acquired.release()
+			}
+			return nil, err
+		}
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			for _, gate := range gates {
+				gate.release()
+			}
+		})
+	}, nil
+}
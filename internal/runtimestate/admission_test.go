@@ -0,0 +1,103 @@
+package runtimestate_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/internal/runtimestate"
+	"github.com/contenox/runtime/runtimetypes"
+	"github.com/stretchr/testify/require"
+)
+
+func saturatedAdmission(t *testing.T, policy string, wait time.Duration) (*runtimestate.Admission, []func()) {
+	t.Helper()
+	admission := runtimestate.NewAdmission(wait)
+	admission.SetPools(
+		[]*runtimetypes.Pool{{ID: "chat", MaxInFlight: 2, Admission: policy}},
+		map[string][]string{"b1": {"chat"}, "b2": {"chat"}},
+	)
+	var releases []func()
+	for _, backend := range []string{"b1", "b2"} {
+		release, err := admission.Admit(context.Background(), backend)
+		require.NoError(t, err)
+		releases = append(releases, release)
+	}
+	return admission, releases
+}
+
+func TestUnit_Admission_RejectsWhenSaturated(t *testing.T) {
+	admission, releases := saturatedAdmission(t, runtimestate.AdmissionReject, time.Second)
+
+	start := time.Now()
+	_, err := admission.Admit(context.Background(), "b1")
+	require.ErrorIs(t, err, apiframework.ErrPoolSaturated)
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+
+	releases[0]()
+	releases[0]() // releasing twice must not free a second slot
+	release, err := admission.Admit(context.Background(), "b2")
+	require.NoError(t, err)
+	_, err = admission.Admit(context.Background(), "b1")
+	require.ErrorIs(t, err, apiframework.ErrPoolSaturated)
+	release()
+}
+
+func TestUnit_Admission_QueuesUntilSlotFrees(t *testing.T) {
+	admission, releases := saturatedAdmission(t, runtimestate.AdmissionQueue, time.Second)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		releases[1]()
+	}()
+	start := time.Now()
+	release, err := admission.Admit(context.Background(), "b1")
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	release()
+}
+
+func TestUnit_Admission_QueueTimesOut(t *testing.T) {
+	admission, _ := saturatedAdmission(t, runtimestate.AdmissionQueue, 30*time.Millisecond)
+
+	_, err := admission.Admit(context.Background(), "b1")
+	require.ErrorIs(t, err, apiframework.ErrPoolSaturated)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	admission, _ = saturatedAdmission(t, runtimestate.AdmissionQueue, time.Second)
+	_, err = admission.Admit(ctx, "b1")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestUnit_Admission_UnlimitedPoolsAndUnknownBackends(t *testing.T) {
+	admission := runtimestate.NewAdmission(0)
+	admission.SetPools(
+		[]*runtimetypes.Pool{{ID: "open"}, {ID: "tight", MaxInFlight: 1}},
+		map[string][]string{"b1": {"open"}, "b2": {"open", "tight"}},
+	)
+	for range 10 {
+		_, err := admission.Admit(context.Background(), "b1")
+		require.NoError(t, err)
+		_, err = admission.Admit(context.Background(), "unknown")
+		require.NoError(t, err)
+	}
+
+	release, err := admission.Admit(context.Background(), "b2")
+	require.NoError(t, err)
+	_, err = admission.Admit(context.Background(), "b2")
+	require.ErrorIs(t, err, apiframework.ErrPoolSaturated)
+	release()
+
+	// Reconfiguring with the same limit keeps in-flight calls counted.
+	release, err = admission.Admit(context.Background(), "b2")
+	require.NoError(t, err)
+	admission.SetPools(
+		[]*runtimetypes.Pool{{ID: "tight", MaxInFlight: 1}},
+		map[string][]string{"b2": {"tight"}},
+	)
+	_, err = admission.Admit(context.Background(), "b2")
+	require.ErrorIs(t, err, apiframework.ErrPoolSaturated)
+	release()
+}
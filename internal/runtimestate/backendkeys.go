@@ -0,0 +1,86 @@
+package runtimestate
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/contenox/runtime/libcipher"
+	"github.com/contenox/runtime/runtimetypes"
+)
+
+// ErrBackendKeysDisabled is returned when a backend API key is set or used but
+// no encryption key for backend API keys is configured.
+var ErrBackendKeysDisabled = errors.New("backend api keys require an encryption key")
+
+// BackendKeys encrypts backend API keys for storage with AES-GCM. Every ciphertext
+// is bound to its backend ID, so a stored key cannot be moved to another backend.
+type BackendKeys struct {
+	encryptor libcipher.Encryptor
+	decryptor libcipher.Decryptor
+}
+
+// NewBackendKeys returns BackendKeys using secret as AES key (16, 24 or 32 bytes).
+func NewBackendKeys(secret []byte) (*BackendKeys, error) {
+	encryptor, err := libcipher.NewGCMEncryptor(secret, rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backend key secret: %w", err)
+	}
+	decryptor, err := libcipher.NewGCMDecryptor(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backend key secret: %w", err)
+	}
+	return &BackendKeys{encryptor: encryptor, decryptor: decryptor}, nil
+}
+
+// Seal encrypts apiKey for storage on the backend with the given ID.
+func (k *BackendKeys) Seal(backendID, apiKey string) (string, error) {
+	if k == nil {
+		return "", ErrBackendKeysDisabled
+	}
+	sealed, err := k.encryptor.Crypt([]byte(apiKey), []byte(backendID))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt api key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts the API key stored on the backend with the given ID.
+func (k *BackendKeys) Open(backendID, sealed string) (string, error) {
+	if k == nil {
+		return "", ErrBackendKeysDisabled
+	}
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", fmt.Errorf("malformed api key ciphertext: %w", err)
+	}
+	apiKey, boundTo, err := k.decryptor.Crypt(raw)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt api key: %w", err)
+	}
+	if string(boundTo) != backendID {
+		return "", fmt.Errorf("api key belongs to backend %q, not %q", boundTo, backendID)
+	}
+	return string(apiKey), nil
+}
+
+// WithBackendKeys lets the runtime decrypt per-backend API keys. Backends with
+// their own key use it instead of the provider-wide key.
+func WithBackendKeys(keys *BackendKeys) Option {
+	return func(s *State) {
+		s.backendKeys = keys
+	}
+}
+
+// backendAPIKey returns the decrypted API key of backend and whether it has one.
+func (s *State) backendAPIKey(backend *runtimetypes.Backend) (string, bool, error) {
+	if backend.EncryptedAPIKey == "" {
+		return "", false, nil
+	}
+	apiKey, err := s.backendKeys.Open(backend.ID, backend.EncryptedAPIKey)
+	if err != nil {
+		return "", true, err
+	}
+	return apiKey, true, nil
+}
@@ -0,0 +1,81 @@
+package runtimestate
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/contenox/runtime/runtimetypes"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestUnit_BackendKeys_EncryptsAtRest(t *testing.T) {
+	keys, err := NewBackendKeys(testSecret)
+	require.NoError(t, err)
+
+	sealed, err := keys.Seal("b1", "sk-secret-key")
+	require.NoError(t, err)
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "sk-secret-key")
+
+	again, err := keys.Seal("b1", "sk-secret-key")
+	require.NoError(t, err)
+	require.NotEqual(t, sealed, again, "every seal uses a fresh nonce")
+
+	apiKey, err := keys.Open("b1", sealed)
+	require.NoError(t, err)
+	require.Equal(t, "sk-secret-key", apiKey)
+
+	_, err = keys.Open("b2", sealed)
+	require.Error(t, err, "ciphertexts are bound to their backend")
+
+	other, err := NewBackendKeys([]byte("fedcba9876543210fedcba9876543210"))
+	require.NoError(t, err)
+	_, err = other.Open("b1", sealed)
+	require.Error(t, err)
+
+	var disabled *BackendKeys
+	_, err = disabled.Seal("b1", "sk-secret-key")
+	require.ErrorIs(t, err, ErrBackendKeysDisabled)
+}
+
+func TestUnit_BackendKeys_DecryptedKeyReachesClient(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if authorization != "Bearer sk-secret-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"object": "list", "data": [{"id": "qwen"}]}`))
+	}))
+	defer server.Close()
+
+	keys, err := NewBackendKeys(testSecret)
+	require.NoError(t, err)
+	sealed, err := keys.Seal("b1", "sk-secret-key")
+	require.NoError(t, err)
+
+	s := &State{backendKeys: keys}
+	backend := &runtimetypes.Backend{ID: "b1", Name: "vllm", Type: "vllm", BaseURL: server.URL, EncryptedAPIKey: sealed}
+	s.processVLLMBackend(context.Background(), backend, []*runtimetypes.Model{{ID: "qwen", Model: "qwen"}})
+
+	require.Equal(t, "Bearer sk-secret-key", authorization)
+	observed := s.Get(context.Background())["b1"]
+	require.Empty(t, observed.Error)
+	require.Equal(t, "sk-secret-key", observed.GetAPIKey())
+	require.Empty(t, observed.Backend.EncryptedAPIKey, "key material is not part of the state snapshot")
+
+	// Without the encryption key the backend is reported broken instead of
+	// being called unauthenticated.
+	authorization = ""
+	s = &State{}
+	s.processVLLMBackend(context.Background(), backend, nil)
+	require.Empty(t, authorization)
+	require.Contains(t, s.Get(context.Background())["b1"].Error, "decrypt")
+}
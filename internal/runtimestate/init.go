@@ -84,6 +84,7 @@ func initEmbedPool(ctx context.Context, config *Config, tx libdb.Exec, created b
 			ID:          EmbedPoolID,
 			Name:        EmbedPoolName,
 			PurposeType: "Internal Embeddings",
+			Capability:  runtimetypes.CapabilityEmbed,
 		})
 		if err != nil {
 			return nil, err
@@ -104,6 +105,7 @@ func initTaskPool(ctx context.Context, config *Config, tx libdb.Exec, created bo
 			ID:          TasksPoolID,
 			Name:        TasksPoolName,
 			PurposeType: "Internal Tasks",
+			Capability:  runtimetypes.CapabilityPrompt,
 		})
 		if err != nil {
 			return nil, err
@@ -0,0 +1,206 @@
+package runtimestate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/contenox/runtime/runtimetypes"
+	"github.com/ollama/ollama/api"
+)
+
+// DefaultKeepWarmInterval is the pause between two warm-up rounds.
+const DefaultKeepWarmInterval = 4 * time.Minute
+
+// maxKeepWarmBackoff caps how long an unreachable backend is skipped.
+const maxKeepWarmBackoff = 30 * time.Minute
+
+// WarmTarget is a model that should stay loaded on a backend.
+type WarmTarget struct {
+	Backend runtimetypes.Backend
+	Model   string
+}
+
+// WarmTargetSource lists the models to keep warm.
+type WarmTargetSource func(ctx context.Context) ([]WarmTarget, error)
+
+type warmBackoff struct {
+	failures int
+	until    time.Time
+}
+
+// KeepWarm periodically loads models on Ollama backends so that they are not
+// unloaded while idle and the next real request does not pay for a cold start.
+//
+// A warm-up is a generate request without a prompt, which makes Ollama load the
+// model and extend its keep-alive without generating anything. Backends that fail
+// are skipped with exponential backoff, and warm-ups are not sent to backends whose
+// pools have no spare capacity, so they never compete with real traffic.
+type KeepWarm struct {
+	interval  time.Duration
+	targets   WarmTargetSource
+	admission *Admission
+	client    func(backend runtimetypes.Backend) *http.Client
+	now       func() time.Time
+
+	mu      sync.Mutex
+	backoff map[string]warmBackoff
+}
+
+// NewKeepWarm returns a KeepWarm warming the targets listed by targets every interval.
+// A non-positive interval selects DefaultKeepWarmInterval.
+func NewKeepWarm(interval time.Duration, targets WarmTargetSource) *KeepWarm {
+	if interval <= 0 {
+		interval = DefaultKeepWarmInterval
+	}
+	return &KeepWarm{
+		interval: interval,
+		targets:  targets,
+		client:   func(runtimetypes.Backend) *http.Client { return http.DefaultClient },
+		now:      time.Now,
+		backoff:  make(map[string]warmBackoff),
+	}
+}
+
+// NewKeepWarm returns a KeepWarm for the KeepWarm models of the state's pools,
+// honoring the pools' admission limits and the backends' timeouts.
+func (s *State) NewKeepWarm(interval time.Duration) *KeepWarm {
+	k := NewKeepWarm(interval, s.WarmTargets)
+	k.SetAdmission(s.admission)
+	k.client = func(backend runtimetypes.Backend) *http.Client {
+		return backendHTTPClient(backend, s.defaultTimeout)
+	}
+	return k
+}
+
+// SetAdmission makes warm-ups skip backends whose pools have no free slot in admission.
+func (k *KeepWarm) SetAdmission(admission *Admission) {
+	k.admission = admission
+}
+
+// Run warms all targets immediately and then every interval until ctx is done.
+func (k *KeepWarm) Run(ctx context.Context) {
+	ticker := time.NewTicker(k.interval)
+	defer ticker.Stop()
+	for {
+		if err := k.RunCycle(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("keep-warm cycle failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunCycle sends one warm-up request per target. Failures are recorded for
+// backoff and do not abort the cycle.
+func (k *KeepWarm) RunCycle(ctx context.Context) error {
+	targets, err := k.targets(ctx)
+	if err != nil {
+		return fmt.Errorf("listing keep-warm targets: %w", err)
+	}
+	for _, target := range targets {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !k.due(target.Backend.ID) {
+			continue
+		}
+		err := k.warm(ctx, target)
+		k.record(target.Backend.ID, err)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("keep-warm of %s on backend %s failed: %v", target.Model, target.Backend.ID, err)
+		}
+	}
+	return nil
+}
+
+func (k *KeepWarm) warm(ctx context.Context, target WarmTarget) error {
+	if k.admission != nil {
+		release, err := k.admission.TryAdmit(target.Backend.ID)
+		if err != nil {
+			// The pool is busy serving real requests, which keeps the model loaded anyway.
+			return nil
+		}
+		defer release()
+	}
+	baseURL, err := url.Parse(target.Backend.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid backend URL: %w", err)
+	}
+	client := api.NewClient(baseURL, k.client(target.Backend))
+	// Keep the model loaded at least until the next round.
+	keepAlive := &api.Duration{Duration: 2 * k.interval}
+	return client.Generate(ctx, &api.GenerateRequest{
+		Model:     target.Model,
+		KeepAlive: keepAlive,
+	}, func(api.GenerateResponse) error { return nil })
+}
+
+// due reports whether backendID is not backing off after failures.
+func (k *KeepWarm) due(backendID string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return !k.now().Before(k.backoff[backendID].until)
+}
+
+func (k *KeepWarm) record(backendID string, err error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err == nil {
+		delete(k.backoff, backendID)
+		return
+	}
+	b := k.backoff[backendID]
+	b.failures++
+	delay := k.interval << min(b.failures-1, 16)
+	if delay <= 0 || delay > maxKeepWarmBackoff {
+		delay = max(maxKeepWarmBackoff, k.interval)
+	}
+	b.until = k.now().Add(delay)
+	k.backoff[backendID] = b
+}
+
+// WarmTargets lists the KeepWarm models of all pools for the pools' healthy
+// Ollama backends that have the model pulled. Pools are read during the
+// pool-aware backend cycle.
+func (s *State) WarmTargets(ctx context.Context) ([]WarmTarget, error) {
+	s.keepWarmMu.RLock()
+	wanted := s.keepWarm
+	s.keepWarmMu.RUnlock()
+
+	snapshot := s.Get(ctx)
+	var targets []WarmTarget
+	for backendID, models := range wanted {
+		backend, ok := snapshot[backendID]
+		if !ok {
+			continue
+		}
+		if backend.Error != "" || backend.Backend.Type != "ollama" {
+			continue
+		}
+		pulled := make(map[string]struct{}, len(backend.PulledModels))
+		for _, model := range backend.PulledModels {
+			pulled[model.Model] = struct{}{}
+		}
+		for _, model := range models {
+			if _, ok := pulled[model]; ok {
+				targets = append(targets, WarmTarget{Backend: backend.Backend, Model: model})
+			}
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Backend.ID != targets[j].Backend.ID {
+			return targets[i].Backend.ID < targets[j].Backend.ID
+		}
+		return targets[i].Model < targets[j].Model
+	})
+	return targets, nil
+}
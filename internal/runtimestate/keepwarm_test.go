@@ -0,0 +1,126 @@
+package runtimestate_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/runtimestate"
+	"github.com/contenox/runtime/runtimetypes"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOllama records generate requests and answers them with status.
+type fakeOllama struct {
+	mu     sync.Mutex
+	status int
+	models []string
+}
+
+func (f *fakeOllama) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Model  string `json:"model"`
+		Prompt string `json:"prompt"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path != "/api/generate" || req.Prompt != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.models = append(f.models, req.Model)
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		_, _ = w.Write([]byte(`{"error": "unavailable"}`))
+		return
+	}
+	_, _ = w.Write([]byte(`{"model": "` + req.Model + `", "done": true, "done_reason": "load"}`))
+}
+
+func (f *fakeOllama) requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.models)
+}
+
+func staticTargets(baseURL string, models ...string) runtimestate.WarmTargetSource {
+	backend := runtimetypes.Backend{ID: "b1", Type: "ollama", BaseURL: baseURL}
+	return func(ctx context.Context) ([]runtimestate.WarmTarget, error) {
+		var targets []runtimestate.WarmTarget
+		for _, model := range models {
+			targets = append(targets, runtimestate.WarmTarget{Backend: backend, Model: model})
+		}
+		return targets, nil
+	}
+}
+
+func TestUnit_KeepWarm_SendsWarmRequestsAtIntervalUntilStopped(t *testing.T) {
+	backend := &fakeOllama{}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	keepWarm := runtimestate.NewKeepWarm(40*time.Millisecond, staticTargets(server.URL, "llama3", "phi3"))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		keepWarm.Run(ctx)
+		close(done)
+	}()
+
+	// One round immediately, then one per interval.
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	<-done
+	sent := backend.requests()
+	require.GreaterOrEqual(t, sent, 4)
+	require.LessOrEqual(t, sent, 8)
+	require.Equal(t, 0, sent%2)
+	require.Equal(t, []string{"llama3", "phi3"}, backend.models[:2])
+
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, sent, backend.requests())
+}
+
+func TestUnit_KeepWarm_BacksOffUnreachableBackend(t *testing.T) {
+	backend := &fakeOllama{status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	keepWarm := runtimestate.NewKeepWarm(time.Hour, staticTargets(server.URL, "llama3", "phi3"))
+	require.NoError(t, keepWarm.RunCycle(context.Background()))
+	// The first failure stops the cycle from hitting the same backend again.
+	require.Equal(t, 1, backend.requests())
+
+	require.NoError(t, keepWarm.RunCycle(context.Background()))
+	require.Equal(t, 1, backend.requests())
+}
+
+func TestUnit_KeepWarm_SkipsSaturatedPools(t *testing.T) {
+	backend := &fakeOllama{}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	admission := runtimestate.NewAdmission(time.Second)
+	admission.SetPools(
+		[]*runtimetypes.Pool{{ID: "chat", MaxInFlight: 1, Admission: runtimestate.AdmissionQueue}},
+		map[string][]string{"b1": {"chat"}},
+	)
+	release, err := admission.Admit(context.Background(), "b1")
+	require.NoError(t, err)
+
+	keepWarm := runtimestate.NewKeepWarm(time.Hour, staticTargets(server.URL, "llama3"))
+	keepWarm.SetAdmission(admission)
+	start := time.Now()
+	require.NoError(t, keepWarm.RunCycle(context.Background()))
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+	require.Equal(t, 0, backend.requests())
+
+	release()
+	require.NoError(t, keepWarm.RunCycle(context.Background()))
+	require.Equal(t, 1, backend.requests())
+}
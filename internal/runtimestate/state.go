@@ -12,6 +12,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -45,6 +46,14 @@ type State struct {
 	dwQueue       dwqueue
 	withPools     bool
 	providerCache sync.Map
+	// defaultTimeout bounds model calls to backends without their own timeout.
+	defaultTimeout time.Duration
+	admissionWait  time.Duration
+	admission      *Admission
+	backendKeys    *BackendKeys
+	// keepWarm maps backend IDs to the models their pools want kept loaded.
+	keepWarmMu sync.RWMutex
+	keepWarm   map[string][]string
 }
 
 type Option func(*State)
@@ -55,6 +64,33 @@ func WithPools() Option {
 	}
 }
 
+// WithDefaultBackendTimeout bounds model calls to backends that do not set
+// their own timeout. Zero leaves such calls unbounded.
+func WithDefaultBackendTimeout(d time.Duration) Option {
+	return func(s *State) {
+		s.defaultTimeout = d
+	}
+}
+
+// WithAdmissionWait sets how long calls wait for a free slot in pools using
+// the queue admission policy. Default: DefaultAdmissionWait.
+func WithAdmissionWait(d time.Duration) Option {
+	return func(s *State) {
+		s.admissionWait = d
+	}
+}
+
+// DefaultBackendTimeout returns the timeout for backends without their own.
+func (s *State) DefaultBackendTimeout() time.Duration {
+	return s.defaultTimeout
+}
+
+// Admit reserves capacity for a model call to backendID in the pools the backend
+// belongs to; see Admission.Admit. Limits apply once a pool-aware backend cycle has run.
+func (s *State) Admit(ctx context.Context, backendID string) (func(), error) {
+	return s.admission.Admit(ctx, backendID)
+}
+
 // New creates and initializes a new State manager.
 // It requires a database manager (dbInstance) to load the desired configurations
 // and a messenger instance (psInstance) for event handling and progress updates.
@@ -78,6 +114,7 @@ func New(ctx context.Context, dbInstance libdb.DBManager, psInstance libbus.Mess
 	for _, option := range options {
 		option(s)
 	}
+	s.admission = NewAdmission(s.admissionWait)
 	return s, nil
 }
 
@@ -251,6 +288,8 @@ func (s *State) syncBackendsWithPools(ctx context.Context) error {
 	allBackendObjects := make(map[string]*runtimetypes.Backend)
 	backendToAggregatedModels := make(map[string]map[string]*runtimetypes.Model)
 	activeBackendIDs := make(map[string]struct{})
+	backendPools := make(map[string][]string)
+	keepWarm := make(map[string][]string)
 
 	for _, pool := range allPools {
 		poolBackends, err := dbStore.ListBackendsForPool(ctx, pool.ID)
@@ -265,6 +304,12 @@ func (s *State) syncBackendsWithPools(ctx context.Context) error {
 
 		for _, backend := range poolBackends {
 			activeBackendIDs[backend.ID] = struct{}{}
+			backendPools[backend.ID] = append(backendPools[backend.ID], pool.ID)
+			for _, model := range pool.KeepWarm {
+				if !slices.Contains(keepWarm[backend.ID], model) {
+					keepWarm[backend.ID] = append(keepWarm[backend.ID], model)
+				}
+			}
 			if _, exists := allBackendObjects[backend.ID]; !exists {
 				allBackendObjects[backend.ID] = backend
 			}
@@ -277,6 +322,11 @@ func (s *State) syncBackendsWithPools(ctx context.Context) error {
 		}
 	}
 
+	s.admission.SetPools(allPools, backendPools)
+	s.keepWarmMu.Lock()
+	s.keepWarm = keepWarm
+	s.keepWarmMu.Unlock()
+
 	// Now, process each unique backend once with its fully aggregated list of models.
 	for backendID, backendObj := range allBackendObjects {
 		modelsForThisBackend := make([]*runtimetypes.Model, 0, len(backendToAggregatedModels[backendID]))
@@ -509,6 +559,7 @@ func (s *State) processOllamaBackend(ctx context.Context, backend *runtimetypes.
 			lmr.CanEmbed = declaredModel.CanEmbed
 			lmr.CanPrompt = declaredModel.CanPrompt
 			lmr.CanStream = declaredModel.CanStream
+			lmr.DefaultParams = declaredModel.DefaultParams
 		}
 
 		pulledModels = append(pulledModels, *lmr)
@@ -546,6 +597,21 @@ func (s *State) processVLLMBackend(ctx context.Context, backend *runtimetypes.Ba
 		})
 		return
 	}
+	apiKey, hasKey, err := s.backendAPIKey(backend)
+	if err != nil {
+		s.state.Store(backend.ID, &statetype.BackendRuntimeState{
+			ID:           backend.ID,
+			Name:         backend.Name,
+			Models:       []string{},
+			PulledModels: []statetype.ModelPullStatus{},
+			Backend:      *backend,
+			Error:        fmt.Sprintf("Failed to decrypt backend API key: %v", err),
+		})
+		return
+	}
+	if hasKey {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -640,12 +706,14 @@ func (s *State) processVLLMBackend(ctx context.Context, backend *runtimetypes.Ba
 				CanEmbed:      m.CanEmbed,
 				CanPrompt:     m.CanPrompt,
 				CanStream:     m.CanStream,
+				DefaultParams: m.DefaultParams,
 			}
 		}
 	}
 	if !found {
 		res.Error = fmt.Sprintf("backend has model %s, yet it's not declared in the configuration", servedModel)
 	}
+	res.SetAPIKey(apiKey)
 	s.state.Store(backend.ID, res)
 }
 
@@ -660,7 +728,15 @@ func (s *State) processGeminiBackend(ctx context.Context, backend *runtimetypes.
 	// Retrieve API key configuration
 	cfg := ProviderConfig{}
 	storeInstance := runtimetypes.New(s.dbInstance.WithoutTransaction())
-	if err := storeInstance.GetKV(ctx, GeminiKey, &cfg); err != nil {
+	// A key set on the backend itself takes precedence over the provider-wide key.
+	if apiKey, hasKey, err := s.backendAPIKey(backend); hasKey {
+		if err != nil {
+			stateInstance.Error = fmt.Sprintf("Failed to decrypt backend API key: %v", err)
+			s.state.Store(backend.ID, stateInstance)
+			return
+		}
+		cfg.APIKey = apiKey
+	} else if err := storeInstance.GetKV(ctx, GeminiKey, &cfg); err != nil {
 		if errors.Is(err, libdb.ErrNotFound) {
 			stateInstance.Error = "API key not configured"
 		} else {
@@ -773,7 +849,15 @@ func (s *State) processOpenAIBackend(ctx context.Context, backend *runtimetypes.
 	// Retrieve API key configuration
 	cfg := ProviderConfig{}
 	storeInstance := runtimetypes.New(s.dbInstance.WithoutTransaction())
-	if err := storeInstance.GetKV(ctx, OpenaiKey, &cfg); err != nil {
+	// A key set on the backend itself takes precedence over the provider-wide key.
+	if apiKey, hasKey, err := s.backendAPIKey(backend); hasKey {
+		if err != nil {
+			stateInstance.Error = fmt.Sprintf("Failed to decrypt backend API key: %v", err)
+			s.state.Store(backend.ID, stateInstance)
+			return
+		}
+		cfg.APIKey = apiKey
+	} else if err := storeInstance.GetKV(ctx, OpenaiKey, &cfg); err != nil {
 		if errors.Is(err, libdb.ErrNotFound) {
 			stateInstance.Error = "API key not configured"
 		} else {
@@ -808,6 +892,7 @@ func (s *State) processOpenAIBackend(ctx context.Context, backend *runtimetypes.
 						pulledModels[i].CanEmbed = declaredModel.CanEmbed
 						pulledModels[i].CanPrompt = declaredModel.CanPrompt
 						pulledModels[i].CanStream = declaredModel.CanStream
+						pulledModels[i].DefaultParams = declaredModel.DefaultParams
 					} else {
 						undeclaredModels = append(undeclaredModels, modelID)
 					}
@@ -903,6 +988,7 @@ func (s *State) processOpenAIBackend(ctx context.Context, backend *runtimetypes.
 			modelResp.CanEmbed = declaredModel.CanEmbed
 			modelResp.CanPrompt = declaredModel.CanPrompt
 			modelResp.CanStream = declaredModel.CanStream
+			modelResp.DefaultParams = declaredModel.DefaultParams
 		} else {
 			undeclaredModels = append(undeclaredModels, modelID)
 		}
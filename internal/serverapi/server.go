@@ -2,12 +2,16 @@ package serverapi
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,22 +21,29 @@ import (
 	"github.com/contenox/runtime/embedservice"
 	"github.com/contenox/runtime/execservice"
 	"github.com/contenox/runtime/hookproviderservice"
+	"github.com/contenox/runtime/internal/activityapi"
 	"github.com/contenox/runtime/internal/apiframework"
 	"github.com/contenox/runtime/internal/backendapi"
 	"github.com/contenox/runtime/internal/chatapi"
 	"github.com/contenox/runtime/internal/execapi"
+	"github.com/contenox/runtime/internal/healthapi"
 	"github.com/contenox/runtime/internal/hooksapi"
+	"github.com/contenox/runtime/internal/kvapi"
 	"github.com/contenox/runtime/internal/llmrepo"
 	"github.com/contenox/runtime/internal/poolapi"
+	"github.com/contenox/runtime/internal/prompttemplateapi"
 	"github.com/contenox/runtime/internal/providerapi"
 	"github.com/contenox/runtime/internal/runtimestate"
 	"github.com/contenox/runtime/internal/taskchainapi"
+	"github.com/contenox/runtime/jobservice"
+	"github.com/contenox/runtime/kvservice"
 	libbus "github.com/contenox/runtime/libbus"
 	libdb "github.com/contenox/runtime/libdbexec"
 	"github.com/contenox/runtime/libroutine"
 	"github.com/contenox/runtime/libtracker"
 	"github.com/contenox/runtime/modelservice"
 	"github.com/contenox/runtime/poolservice"
+	"github.com/contenox/runtime/prompttemplateservice"
 	"github.com/contenox/runtime/providerservice"
 	"github.com/contenox/runtime/stateservice"
 	"github.com/contenox/runtime/taskchainservice"
@@ -49,29 +60,40 @@ func New(
 	repo llmrepo.ModelRepo,
 	environmentExec taskengine.EnvExecutor,
 	state *runtimestate.State,
-	hookRegistry taskengine.HookRegistry,
-	// kvManager libkv.KVManager,
+	hookRegistry taskengine.HookRepo,
+	inFlight *taskengine.InFlightRegistry,
+	activity *taskengine.KVActivitySink,
 ) (http.Handler, func() error, error) {
 	cleanup := func() error { return nil }
 	mux := http.NewServeMux()
 	var handler http.Handler = mux
-	// tracker := taskengine.NewKVActivityTracker(kvManager)
 	stdOuttracker := libtracker.NewLogActivityTracker(slog.Default())
 	serveropsChainedTracker := libtracker.ChainedTracker{
-		// tracker,
 		stdOuttracker,
 	}
+	// The activity store is optional; without it events only reach the log.
+	if activity != nil {
+		serveropsChainedTracker = append(serveropsChainedTracker, activity)
+		activityapi.AddActivityRoutes(mux, activity)
+		activityapi.AddAlertRoutes(mux, activity)
+	}
+	activityapi.AddInFlightRoutes(mux, inFlight)
 	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
 		apiframework.Error(w, r, apiframework.ErrNotFound, apiframework.ListOperation)
 	})
-	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
-		// OK
-	})
+	healthapi.AddHealthRoutes(mux, map[string]healthapi.Check{
+		"database": healthapi.DBCheck(dbInstance),
+		"pubsub":   healthapi.BusCheck(pubsub),
+	}, healthapi.DefaultCheckTimeout)
 	version := apiframework.GetVersion()
 	mux.HandleFunc("GET /version", func(w http.ResponseWriter, r *http.Request) {
 		apiframework.Encode(w, r, http.StatusOK, apiframework.AboutServer{Version: version, NodeInstanceID: nodeInstanceID, Tenancy: tenancy})
 	})
-	backendService := backendservice.New(dbInstance)
+	backendKeys, err := config.BackendKeys()
+	if err != nil {
+		return nil, cleanup, err
+	}
+	backendService := backendservice.New(dbInstance, backendservice.WithBackendKeys(backendKeys))
 	backendService = backendservice.WithActivityTracker(backendService, serveropsChainedTracker)
 	stateService := stateservice.New(state)
 	stateService = stateservice.WithActivityTracker(stateService, serveropsChainedTracker)
@@ -105,9 +127,34 @@ func New(
 		},
 	)
 
+	if config.BackendModelSyncInterval != "" {
+		interval, err := time.ParseDuration(config.BackendModelSyncInterval)
+		if err != nil || interval <= 0 {
+			return nil, cleanup, fmt.Errorf("invalid backend model sync interval %q", config.BackendModelSyncInterval)
+		}
+		pool.StartLoop(
+			ctx,
+			&libroutine.LoopConfig{
+				Key:          "backendModelSync",
+				Threshold:    3,
+				ResetTimeout: interval,
+				Interval:     interval,
+				Operation:    backendService.SyncAll,
+			},
+		)
+	}
+
+	if config.KeepWarmInterval != "" {
+		interval, err := time.ParseDuration(config.KeepWarmInterval)
+		if err != nil || interval <= 0 {
+			return nil, cleanup, fmt.Errorf("invalid keep warm interval %q", config.KeepWarmInterval)
+		}
+		go state.NewKeepWarm(interval).Run(ctx)
+	}
+
 	// Add this after the pool loops are started in serverapi.New
 	triggerCh := make(chan []byte, 10)
-	err := pubsub.Publish(ctx, "trigger_cycle", []byte("trigger"))
+	err = pubsub.Publish(ctx, "trigger_cycle", []byte("trigger"))
 	if err != nil {
 		log.Fatalf("failed to publish trigger_cycle message: %v", err)
 	}
@@ -135,18 +182,44 @@ func New(
 	downloadService := downloadservice.New(dbInstance, pubsub)
 	downloadService = downloadservice.WithActivityTracker(downloadService, serveropsChainedTracker)
 	backendapi.AddQueueRoutes(mux, downloadService)
+	jobService := jobservice.New(dbInstance)
+	jobService = jobservice.WithActivityTracker(jobService, serveropsChainedTracker)
+	backendapi.AddJobRoutes(mux, jobService)
 	modelService := modelservice.New(dbInstance, config.EmbedModel)
 	modelService = modelservice.WithActivityTracker(modelService, serveropsChainedTracker)
 	backendapi.AddModelRoutes(mux, modelService, downloadService)
 	execService := execservice.NewExec(ctx, repo)
 	execService = execservice.WithActivityTracker(execService, serveropsChainedTracker)
 	taskService := execservice.NewTasksEnv(ctx, environmentExec, hookRegistry)
-	embedService := embedservice.New(repo, config.EmbedModel, config.EmbedProvider)
+	embedBatchSize, err := config.EmbedBatchSizeLimit()
+	if err != nil {
+		return nil, cleanup, err
+	}
+	embedService := embedservice.New(repo, config.EmbedModel, config.EmbedProvider, embedservice.WithMaxBatchSize(embedBatchSize))
 	embedService = embedservice.WithActivityTracker(embedService, serveropsChainedTracker)
-	taskChainService := taskchainservice.New(dbInstance)
-	taskChainService = taskchainservice.WithActivityTracker(taskChainService, serveropsChainedTracker)
+	chainVersions, err := config.ChainVersionLimit()
+	if err != nil {
+		return nil, cleanup, err
+	}
+	taskChainService := taskchainservice.New(dbInstance, taskchainservice.WithMaxVersions(chainVersions))
+	taskChainCache := taskchainservice.WithCache(taskChainService, taskchainservice.DefaultCacheTTL, taskchainservice.DefaultCacheSize)
+	taskChainService = taskchainservice.WithActivityTracker(taskChainCache, serveropsChainedTracker)
 	taskchainapi.AddTaskChainRoutes(mux, taskChainService)
-	execapi.AddExecRoutes(mux, execService, taskService, embedService)
+	batchBodyBytes, err := config.MaxBatchBodyBytes()
+	if err != nil {
+		return nil, cleanup, err
+	}
+	execapi.AddExecRoutes(mux, execService, taskService, embedService, batchBodyBytes)
+	promptTemplateService := prompttemplateservice.New(dbInstance)
+	promptTemplateService = prompttemplateservice.WithActivityTracker(promptTemplateService, serveropsChainedTracker)
+	prompttemplateapi.AddPromptTemplateRoutes(mux, promptTemplateService, execService)
+	kvService := kvservice.New(dbInstance, config.KVBlockedPrefixList())
+	// Chains edited through /kv must not be served from the chain cache.
+	kvService = kvservice.WithWriteHook(kvService, func(key string) {
+		taskchainservice.InvalidateKey(taskChainCache, key)
+	})
+	kvService = kvservice.WithActivityTracker(kvService, serveropsChainedTracker)
+	kvapi.AddKVRoutes(mux, kvService)
 	providerService := providerservice.New(dbInstance)
 	providerService = providerservice.WithActivityTracker(providerService, serveropsChainedTracker)
 	providerapi.AddProviderRoutes(mux, providerService)
@@ -156,10 +229,21 @@ func New(
 	chatService := chatservice.New(
 		taskService,
 		taskChainService,
+		repo,
 	)
 	chatService = chatservice.WithActivityTracker(chatService, serveropsChainedTracker)
 	chatapi.AddChatRoutes(mux, chatService)
 
+	maxBodyBytes, err := config.MaxBodyBytes()
+	if err != nil {
+		return nil, cleanup, err
+	}
+	captureContent, err := config.CaptureContentDefault()
+	if err != nil {
+		return nil, cleanup, err
+	}
+	handler = apiframework.MaxBodySizeMiddleware(maxBodyBytes, handler)
+	handler = apiframework.CaptureContentMiddleware(captureContent, handler)
 	handler = apiframework.RequestIDMiddleware(handler)
 	handler = apiframework.TracingMiddleware(handler)
 	if config.Token != "" {
@@ -186,8 +270,231 @@ type Config struct {
 	TaskModelContextLength  string `json:"task_model_context_length"`
 	VectorStoreURL          string `json:"vector_store_url"`
 	Token                   string `json:"token"`
+	// CaptureContent controls whether prompt and response content is recorded in
+	// activity logs by default ("true" unless set to "false"). Clients can override
+	// it per request with the X-Capture-Content header.
+	CaptureContent string `json:"capture_content"`
+	// SQLHookDatabaseURL enables the sql_query hook against the given (read-replica) database.
+	SQLHookDatabaseURL string `json:"sql_hook_database_url"`
+	// SQLHookQueries is a JSON object mapping query names to {"sql": "...", "params": [...]}.
+	SQLHookQueries string `json:"sql_hook_queries"`
+	// SQLHookMaxRows caps the rows a sql_query hook call returns (default 100).
+	SQLHookMaxRows string `json:"sql_hook_max_rows"`
+	// SQLHookTimeout bounds each sql_query hook call (e.g. "10s", default 5s).
+	SQLHookTimeout string `json:"sql_hook_timeout"`
+	// BackendModelSyncInterval enables periodic model discovery on all backends (e.g. "5m").
+	BackendModelSyncInterval string `json:"backend_model_sync_interval"`
+	// KeepWarmInterval enables periodic loading of the pools' keepWarm models on
+	// Ollama backends (e.g. "4m"), preventing cold starts after idle periods.
+	KeepWarmInterval string `json:"keep_warm_interval"`
+	// EnableMetrics exposes Prometheus metrics for chain and task execution on /metrics ("true" to enable).
+	EnableMetrics string `json:"enable_metrics"`
+	// MaxChainSteps limits the number of tasks a chain may run per execution
+	// (default 1000); chains can only lower it with max_steps.
+	MaxChainSteps string `json:"max_chain_steps"`
+	// BackendTimeout bounds model calls to backends that do not set their own timeout
+	// (e.g. "5m"), see runtimetypes.Backend.Timeout.
+	// Unset leaves such calls unbounded.
+	BackendTimeout string `json:"backend_timeout"`
+	// MaxRequestBodyBytes limits the size of decoded request bodies (default 10 MiB).
+	MaxRequestBodyBytes string `json:"max_request_body_bytes"`
+	// BackendKeySecret is the hex-encoded AES key (16, 24 or 32 bytes) used to encrypt
+	// per-backend API keys at rest. Unset disables per-backend API keys.
+	BackendKeySecret string `json:"backend_key_secret"`
+	// KVBlockedPrefixes is a comma-separated list of key prefixes the /kv admin routes
+	// refuse to expose, in addition to the stored provider credentials.
+	KVBlockedPrefixes string `json:"kv_blocked_prefixes"`
+	// EmbedMaxBatchSize limits the number of inputs per /v1/embeddings request (default 2048).
+	EmbedMaxBatchSize string `json:"embed_max_batch_size"`
+	// ActivityStoreAddr is the address (host:port) of the Valkey instance activity events
+	// are recorded in; it enables the /activity log, export and alert routes.
+	// Unset leaves activity events in the process log only.
+	ActivityStoreAddr     string `json:"activity_store_addr"`
+	ActivityStorePassword string `json:"activity_store_password"`
+	// MaxBatchRequestBodyBytes limits the request body of the batch routes /tasks/batch,
+	// /embed and /v1/embeddings (default 50 MiB) in place of MaxRequestBodyBytes.
+	MaxBatchRequestBodyBytes string `json:"max_batch_request_body_bytes"`
+	// ChainCheckpointTTL enables task-chain checkpoints in the activity store, e.g. "24h",
+	// so failed executions can be resumed via /tasks/{id}/resume until they expire.
+	// Requires ActivityStoreAddr. Unset disables checkpoints.
+	ChainCheckpointTTL string `json:"chain_checkpoint_ttl"`
+	// TaskChainMaxVersions limits how many previous versions are kept per task chain
+	// (default 20); "0" disables the version history.
+	TaskChainMaxVersions string `json:"task_chain_max_versions"`
 }
 
+// KVBlockedPrefixList returns the key prefixes hidden from the /kv admin routes.
+// The configured prefixes extend kvservice.DefaultBlockedPrefixes, they never replace them.
+func (c *Config) KVBlockedPrefixList() []string {
+	prefixes := append([]string{}, kvservice.DefaultBlockedPrefixes...)
+	if c.KVBlockedPrefixes == "" {
+		return prefixes
+	}
+	for _, prefix := range strings.Split(c.KVBlockedPrefixes, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// BackendKeys returns the cipher for per-backend API keys, or nil if none is configured.
+func (c *Config) BackendKeys() (*runtimestate.BackendKeys, error) {
+	if c.BackendKeySecret == "" {
+		return nil, nil
+	}
+	secret, err := hex.DecodeString(c.BackendKeySecret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backend key secret: %w", err)
+	}
+	return runtimestate.NewBackendKeys(secret)
+}
+
+// DefaultBackendTimeout returns the configured timeout for backends without their own.
+func (c *Config) DefaultBackendTimeout() (time.Duration, error) {
+	if c.BackendTimeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.BackendTimeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid backend timeout %q", c.BackendTimeout)
+	}
+	return d, nil
+}
+
+// SQLHookRowLimit returns the configured row cap of the sql_query hook, or zero
+// to keep the hook's default.
+func (c *Config) SQLHookRowLimit() (int, error) {
+	if c.SQLHookMaxRows == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(c.SQLHookMaxRows)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid sql hook max rows %q", c.SQLHookMaxRows)
+	}
+	return limit, nil
+}
+
+// SQLHookQueryTimeout returns the configured timeout of sql_query hook calls, or
+// zero to keep the hook's default.
+func (c *Config) SQLHookQueryTimeout() (time.Duration, error) {
+	if c.SQLHookTimeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.SQLHookTimeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid sql hook timeout %q", c.SQLHookTimeout)
+	}
+	return d, nil
+}
+
+// CheckpointTTL returns how long task-chain checkpoints are kept, or 0 if
+// checkpoints are disabled.
+func (c *Config) CheckpointTTL() (time.Duration, error) {
+	if c.ChainCheckpointTTL == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.ChainCheckpointTTL)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid chain checkpoint ttl %q", c.ChainCheckpointTTL)
+	}
+	if c.ActivityStoreAddr == "" {
+		return 0, fmt.Errorf("chain checkpoint ttl requires activity_store_addr")
+	}
+	return d, nil
+}
+
+// MaxBodyBytes returns the configured request body limit.
+func (c *Config) MaxBodyBytes() (int64, error) {
+	if c.MaxRequestBodyBytes == "" {
+		return apiframework.DefaultMaxBodyBytes, nil
+	}
+	limit, err := strconv.ParseInt(c.MaxRequestBodyBytes, 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid max request body bytes %q", c.MaxRequestBodyBytes)
+	}
+	return limit, nil
+}
+
+// MaxBatchBodyBytes returns the configured request body limit of the batch routes.
+func (c *Config) MaxBatchBodyBytes() (int64, error) {
+	if c.MaxBatchRequestBodyBytes == "" {
+		return apiframework.DefaultMaxBatchBodyBytes, nil
+	}
+	limit, err := strconv.ParseInt(c.MaxBatchRequestBodyBytes, 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid max batch request body bytes %q", c.MaxBatchRequestBodyBytes)
+	}
+	return limit, nil
+}
+
+// EmbedBatchSizeLimit returns the configured number of inputs per embeddings
+// request, or zero to keep the embed service's default.
+func (c *Config) EmbedBatchSizeLimit() (int, error) {
+	if c.EmbedMaxBatchSize == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(c.EmbedMaxBatchSize)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid embed max batch size %q", c.EmbedMaxBatchSize)
+	}
+	return limit, nil
+}
+
+// ChainStepLimit returns the configured number of tasks a chain may run per
+// execution, or zero to keep the task engine's default.
+func (c *Config) ChainStepLimit() (int, error) {
+	if c.MaxChainSteps == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(c.MaxChainSteps)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid max chain steps %q", c.MaxChainSteps)
+	}
+	return limit, nil
+}
+
+// ChainVersionLimit returns the configured number of previous versions kept per
+// task chain, or -1 to keep the task chain service's default.
+func (c *Config) ChainVersionLimit() (int, error) {
+	if c.TaskChainMaxVersions == "" {
+		return -1, nil
+	}
+	limit, err := strconv.Atoi(c.TaskChainMaxVersions)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid task chain max versions %q", c.TaskChainMaxVersions)
+	}
+	return limit, nil
+}
+
+// MetricsEnabled reports whether Prometheus metrics should be collected and exposed.
+func (c *Config) MetricsEnabled() bool {
+	enabled, err := strconv.ParseBool(c.EnableMetrics)
+	return err == nil && enabled
+}
+
+// CaptureContentDefault reports the server-wide default for content capture.
+func (c *Config) CaptureContentDefault() (bool, error) {
+	if c.CaptureContent == "" {
+		return true, nil
+	}
+	capture, err := strconv.ParseBool(c.CaptureContent)
+	if err != nil {
+		return false, fmt.Errorf("invalid capture content %q: expected true or false", c.CaptureContent)
+	}
+	return capture, nil
+}
+
+// EnvironmentVar names the environment (e.g. "staging") whose overlay LoadConfig applies.
+const EnvironmentVar = "CONTENOX_ENV"
+
+var environmentName = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// LoadConfig fills cfg from the environment variables matching its JSON field
+// names, case-insensitively. If CONTENOX_ENV names an environment, variables
+// prefixed with that name override the base ones, e.g. with CONTENOX_ENV=staging
+// STAGING_DATABASE_URL takes precedence over DATABASE_URL. Empty overlay
+// variables leave the base value in place.
 func LoadConfig[T any](cfg *T) error {
 	config := map[string]string{}
 	for _, kvPair := range os.Environ() {
@@ -199,6 +506,18 @@ func LoadConfig[T any](cfg *T) error {
 		value := ar[1]
 		config[key] = value
 	}
+	if env := strings.ToLower(config[strings.ToLower(EnvironmentVar)]); env != "" {
+		if !environmentName.MatchString(env) {
+			return fmt.Errorf("invalid %s %q: expected letters and digits only", EnvironmentVar, env)
+		}
+		overlay := map[string]string{}
+		for key, value := range config {
+			if field, ok := strings.CutPrefix(key, env+"_"); ok && field != "" && value != "" {
+				overlay[field] = value
+			}
+		}
+		maps.Copy(config, overlay)
+	}
 
 	b, err := json.Marshal(config)
 	if err != nil {
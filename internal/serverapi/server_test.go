@@ -0,0 +1,133 @@
+package serverapi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/internal/runtimestate"
+	"github.com/contenox/runtime/internal/serverapi"
+	"github.com/contenox/runtime/kvservice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_LoadConfig_EnvironmentOverlay(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://base")
+	t.Setenv("PORT", "8080")
+	t.Setenv("TASK_MODEL", "qwen2.5:1.5b")
+	t.Setenv("STAGING_DATABASE_URL", "postgres://staging")
+	t.Setenv("STAGING_TASK_MODEL", "")
+	t.Setenv("PROD_PORT", "9090")
+
+	var base serverapi.Config
+	require.NoError(t, serverapi.LoadConfig(&base))
+	require.Equal(t, "postgres://base", base.DatabaseURL)
+	require.Equal(t, "8080", base.Port)
+
+	t.Setenv(serverapi.EnvironmentVar, "Staging")
+	var staging serverapi.Config
+	require.NoError(t, serverapi.LoadConfig(&staging))
+	require.Equal(t, "postgres://staging", staging.DatabaseURL, "overlay fields override the base")
+	require.Equal(t, "8080", staging.Port, "other environments' overlays do not apply")
+	require.Equal(t, "qwen2.5:1.5b", staging.TaskModel, "empty overlay fields inherit the base")
+}
+
+func TestUnit_LoadConfig_InvalidEnvironment(t *testing.T) {
+	t.Setenv(serverapi.EnvironmentVar, "prod/eu")
+	var cfg serverapi.Config
+	require.ErrorContains(t, serverapi.LoadConfig(&cfg), `invalid CONTENOX_ENV "prod/eu"`)
+}
+
+func TestUnit_Config_CaptureContentDefault(t *testing.T) {
+	for value, want := range map[string]bool{"": true, "true": true, "false": false, "0": false} {
+		capture, err := (&serverapi.Config{CaptureContent: value}).CaptureContentDefault()
+		require.NoError(t, err, value)
+		require.Equal(t, want, capture, value)
+	}
+	_, err := (&serverapi.Config{CaptureContent: "flase"}).CaptureContentDefault()
+	require.ErrorContains(t, err, `invalid capture content "flase"`)
+}
+
+func TestUnit_Config_MaxBatchBodyBytes(t *testing.T) {
+	limit, err := (&serverapi.Config{}).MaxBatchBodyBytes()
+	require.NoError(t, err)
+	require.Equal(t, apiframework.DefaultMaxBatchBodyBytes, limit)
+
+	limit, err = (&serverapi.Config{MaxBatchRequestBodyBytes: "104857600"}).MaxBatchBodyBytes()
+	require.NoError(t, err)
+	require.Equal(t, int64(100<<20), limit)
+
+	_, err = (&serverapi.Config{MaxBatchRequestBodyBytes: "0"}).MaxBatchBodyBytes()
+	require.Error(t, err)
+}
+
+func TestUnit_Config_KVBlockedPrefixesKeepProviderKeys(t *testing.T) {
+	t.Setenv("KV_BLOCKED_PREFIXES", "secret:, internal:")
+	var cfg serverapi.Config
+	require.NoError(t, serverapi.LoadConfig(&cfg))
+
+	prefixes := cfg.KVBlockedPrefixList()
+	require.Equal(t, []string{runtimestate.ProviderKeyPrefix, "secret:", "internal:"}, prefixes)
+
+	svc := kvservice.New(nil, prefixes)
+	_, err := svc.Get(context.Background(), runtimestate.OpenaiKey)
+	require.ErrorIs(t, err, apiframework.ErrForbidden, "configured prefixes must not unblock provider keys")
+	_, err = svc.Get(context.Background(), "secret:token")
+	require.ErrorIs(t, err, apiframework.ErrForbidden)
+}
+
+func TestUnit_Config_CheckpointTTL(t *testing.T) {
+	ttl, err := (&serverapi.Config{}).CheckpointTTL()
+	require.NoError(t, err)
+	require.Zero(t, ttl)
+
+	ttl, err = (&serverapi.Config{ChainCheckpointTTL: "24h", ActivityStoreAddr: "valkey:6379"}).CheckpointTTL()
+	require.NoError(t, err)
+	require.Equal(t, 24*time.Hour, ttl)
+
+	_, err = (&serverapi.Config{ChainCheckpointTTL: "24h"}).CheckpointTTL()
+	require.ErrorContains(t, err, "requires activity_store_addr")
+	_, err = (&serverapi.Config{ChainCheckpointTTL: "-1h", ActivityStoreAddr: "valkey:6379"}).CheckpointTTL()
+	require.Error(t, err)
+}
+
+func TestUnit_Config_ChainVersionLimit(t *testing.T) {
+	limit, err := (&serverapi.Config{}).ChainVersionLimit()
+	require.NoError(t, err)
+	require.Equal(t, -1, limit, "unset keeps the service default")
+
+	limit, err = (&serverapi.Config{TaskChainMaxVersions: "0"}).ChainVersionLimit()
+	require.NoError(t, err)
+	require.Zero(t, limit)
+
+	limit, err = (&serverapi.Config{TaskChainMaxVersions: "5"}).ChainVersionLimit()
+	require.NoError(t, err)
+	require.Equal(t, 5, limit)
+
+	_, err = (&serverapi.Config{TaskChainMaxVersions: "-3"}).ChainVersionLimit()
+	require.ErrorContains(t, err, `invalid task chain max versions "-3"`)
+}
+
+func TestUnit_Config_SQLHookLimits(t *testing.T) {
+	var unset serverapi.Config
+	rows, err := unset.SQLHookRowLimit()
+	require.NoError(t, err)
+	require.Zero(t, rows)
+	timeout, err := unset.SQLHookQueryTimeout()
+	require.NoError(t, err)
+	require.Zero(t, timeout)
+
+	cfg := serverapi.Config{SQLHookMaxRows: "500", SQLHookTimeout: "30s"}
+	rows, err = cfg.SQLHookRowLimit()
+	require.NoError(t, err)
+	require.Equal(t, 500, rows)
+	timeout, err = cfg.SQLHookQueryTimeout()
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, timeout)
+
+	_, err = (&serverapi.Config{SQLHookMaxRows: "0"}).SQLHookRowLimit()
+	require.ErrorContains(t, err, `invalid sql hook max rows "0"`)
+	_, err = (&serverapi.Config{SQLHookTimeout: "5"}).SQLHookQueryTimeout()
+	require.ErrorContains(t, err, `invalid sql hook timeout "5"`)
+}
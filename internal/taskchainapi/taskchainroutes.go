@@ -18,6 +18,8 @@ func AddTaskChainRoutes(mux *http.ServeMux, service taskchainservice.Service) {
 	mux.HandleFunc("GET /taskchains/{id}", h.getTaskChain)
 	mux.HandleFunc("PUT /taskchains/{id}", h.updateTaskChain)
 	mux.HandleFunc("DELETE /taskchains/{id}", h.deleteTaskChain)
+	mux.HandleFunc("GET /taskchains/{id}/versions", h.listTaskChainVersions)
+	mux.HandleFunc("POST /taskchains/{id}/versions/{version}/rollback", h.rollbackTaskChain)
 }
 
 type handler struct {
@@ -152,3 +154,50 @@ func (h *handler) deleteTaskChain(w http.ResponseWriter, r *http.Request) {
 
 	_ = apiframework.Encode(w, r, http.StatusOK, fmt.Sprintf("task chain %s deleted", id)) // @response string
 }
+
+// Lists the retained previous versions of a task chain, newest first.
+//
+// A version is recorded every time the task chain is overwritten; the server keeps
+// the most recent ones (TASK_CHAIN_MAX_VERSIONS, default 20).
+func (h *handler) listTaskChainVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := apiframework.GetPathParam(r, "id", "The unique identifier for the task chain.")
+	if id == "" {
+		_ = apiframework.Error(w, r, fmt.Errorf("task chain ID is required: %w", apiframework.ErrBadPathValue), apiframework.ListOperation)
+		return
+	}
+
+	versions, err := h.service.ListVersions(ctx, id)
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.ListOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, versions) // @response []*taskchainservice.ChainVersion
+}
+
+// Restores a previous version of a task chain.
+//
+// The current definition is recorded as a new version before being replaced.
+func (h *handler) rollbackTaskChain(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := apiframework.GetPathParam(r, "id", "The unique identifier for the task chain.")
+	if id == "" {
+		_ = apiframework.Error(w, r, fmt.Errorf("task chain ID is required: %w", apiframework.ErrBadPathValue), apiframework.UpdateOperation)
+		return
+	}
+	versionStr := apiframework.GetPathParam(r, "version", "The version number to restore.")
+	version, err := strconv.Atoi(versionStr)
+	if err != nil || version < 1 {
+		_ = apiframework.Error(w, r, fmt.Errorf("%w: version must be a positive integer", apiframework.ErrBadPathValue), apiframework.UpdateOperation)
+		return
+	}
+
+	chain, err := h.service.Rollback(ctx, id, version)
+	if err != nil {
+		_ = apiframework.Error(w, r, err, apiframework.UpdateOperation)
+		return
+	}
+
+	_ = apiframework.Encode(w, r, http.StatusOK, chain) // @response taskengine.TaskChainDefinition
+}
@@ -0,0 +1,37 @@
+package jobservice
+
+import (
+	"context"
+	"time"
+
+	libdb "github.com/contenox/runtime/libdbexec"
+	"github.com/contenox/runtime/runtimetypes"
+)
+
+// Service exposes the job queue for inspection.
+type Service interface {
+	// List returns queued jobs, newest first. An empty taskType lists jobs of all types.
+	List(ctx context.Context, taskType string, createdAtCursor *time.Time, limit int) ([]*runtimetypes.Job, error)
+	// CountByType returns the number of queued jobs per task type.
+	CountByType(ctx context.Context) (map[string]int64, error)
+}
+
+type service struct {
+	dbInstance libdb.DBManager
+}
+
+func New(db libdb.DBManager) Service {
+	return &service{dbInstance: db}
+}
+
+func (s *service) List(ctx context.Context, taskType string, createdAtCursor *time.Time, limit int) ([]*runtimetypes.Job, error) {
+	storeInstance := runtimetypes.New(s.dbInstance.WithoutTransaction())
+	if taskType == "" {
+		return storeInstance.ListJobs(ctx, createdAtCursor, limit)
+	}
+	return storeInstance.ListJobsForType(ctx, taskType, createdAtCursor, limit)
+}
+
+func (s *service) CountByType(ctx context.Context) (map[string]int64, error) {
+	return runtimetypes.New(s.dbInstance.WithoutTransaction()).CountJobsByType(ctx)
+}
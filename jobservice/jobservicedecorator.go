@@ -0,0 +1,55 @@
+package jobservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/runtimetypes"
+)
+
+type activityTrackerDecorator struct {
+	service Service
+	tracker libtracker.ActivityTracker
+}
+
+func (d *activityTrackerDecorator) List(ctx context.Context, taskType string, createdAtCursor *time.Time, limit int) ([]*runtimetypes.Job, error) {
+	reportErrFn, _, endFn := d.tracker.Start(
+		ctx,
+		"list",
+		"jobs",
+		"taskType", taskType,
+		"cursor", fmt.Sprintf("%v", createdAtCursor),
+		"limit", fmt.Sprintf("%d", limit),
+	)
+	defer endFn()
+
+	jobs, err := d.service.List(ctx, taskType, createdAtCursor, limit)
+	if err != nil {
+		reportErrFn(err)
+	}
+
+	return jobs, err
+}
+
+func (d *activityTrackerDecorator) CountByType(ctx context.Context) (map[string]int64, error) {
+	reportErrFn, _, endFn := d.tracker.Start(ctx, "count", "jobs")
+	defer endFn()
+
+	counts, err := d.service.CountByType(ctx)
+	if err != nil {
+		reportErrFn(err)
+	}
+
+	return counts, err
+}
+
+func WithActivityTracker(service Service, tracker libtracker.ActivityTracker) Service {
+	return &activityTrackerDecorator{
+		service: service,
+		tracker: tracker,
+	}
+}
+
+var _ Service = (*activityTrackerDecorator)(nil)
@@ -0,0 +1,131 @@
+package kvservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/internal/runtimestate"
+	libdb "github.com/contenox/runtime/libdbexec"
+	"github.com/contenox/runtime/runtimetypes"
+)
+
+// DefaultBlockedPrefixes hides the stored provider credentials. They are
+// always blocked, whatever other prefixes are configured.
+var DefaultBlockedPrefixes = []string{runtimestate.ProviderKeyPrefix}
+
+// Service gives operators raw access to the key-value store, e.g. to inspect or
+// repair chain definitions and cached state. Keys under a blocked prefix can
+// neither be read, written nor listed.
+type Service interface {
+	// List returns the entries whose key starts with prefix, newest first.
+	List(ctx context.Context, prefix string, cursor *time.Time, limit int) ([]*runtimetypes.KV, error)
+	// Get returns the raw JSON value stored under key.
+	Get(ctx context.Context, key string) (json.RawMessage, error)
+	// Set stores value under key, replacing any existing value.
+	Set(ctx context.Context, key string, value json.RawMessage) error
+	// Delete removes key.
+	Delete(ctx context.Context, key string) error
+}
+
+type service struct {
+	db      libdb.DBManager
+	blocked []string
+}
+
+// New returns a Service over db that refuses access to keys starting with any
+// of blockedPrefixes.
+func New(db libdb.DBManager, blockedPrefixes []string) Service {
+	return &service{db: db, blocked: blockedPrefixes}
+}
+
+func (s *service) List(ctx context.Context, prefix string, cursor *time.Time, limit int) ([]*runtimetypes.KV, error) {
+	if blocked := s.isBlocked(prefix); blocked != "" {
+		return nil, fmt.Errorf("keys with prefix %q are not accessible: %w", blocked, apiframework.ErrForbidden)
+	}
+	storeInstance := runtimetypes.New(s.db.WithoutTransaction())
+	var entries []*runtimetypes.KV
+	var err error
+	if prefix == "" {
+		entries, err = storeInstance.ListKV(ctx, cursor, limit)
+	} else {
+		entries, err = storeInstance.ListKVPrefix(ctx, prefix, cursor, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]*runtimetypes.KV, 0, len(entries))
+	for _, entry := range entries {
+		if s.isBlocked(entry.Key) == "" {
+			visible = append(visible, entry)
+		}
+	}
+	return visible, nil
+}
+
+func (s *service) Get(ctx context.Context, key string) (json.RawMessage, error) {
+	if err := s.checkKey(key); err != nil {
+		return nil, err
+	}
+	var value json.RawMessage
+	if err := runtimetypes.New(s.db.WithoutTransaction()).GetKV(ctx, key, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (s *service) Set(ctx context.Context, key string, value json.RawMessage) error {
+	if err := s.checkKey(key); err != nil {
+		return err
+	}
+	if len(value) == 0 || !json.Valid(value) {
+		return fmt.Errorf("value must be valid JSON: %w", apiframework.ErrBadRequest)
+	}
+	tx, commit, release, err := s.db.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	storeInstance := runtimetypes.New(tx)
+	count, err := storeInstance.EstimateKVCount(ctx)
+	if err != nil {
+		return err
+	}
+	if err := storeInstance.EnforceMaxRowCount(ctx, count); err != nil {
+		return err
+	}
+	if err := storeInstance.SetKV(ctx, key, value); err != nil {
+		return err
+	}
+	return commit(ctx)
+}
+
+func (s *service) Delete(ctx context.Context, key string) error {
+	if err := s.checkKey(key); err != nil {
+		return err
+	}
+	return runtimetypes.New(s.db.WithoutTransaction()).DeleteKV(ctx, key)
+}
+
+func (s *service) checkKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("key is required: %w", apiframework.ErrBadRequest)
+	}
+	if blocked := s.isBlocked(key); blocked != "" {
+		return fmt.Errorf("keys with prefix %q are not accessible: %w", blocked, apiframework.ErrForbidden)
+	}
+	return nil
+}
+
+// isBlocked returns the blocked prefix key starts with, if any.
+func (s *service) isBlocked(key string) string {
+	for _, blocked := range s.blocked {
+		if strings.HasPrefix(key, blocked) {
+			return blocked
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package kvservice_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/kvservice"
+	libdb "github.com/contenox/runtime/libdbexec"
+	"github.com/contenox/runtime/runtimetypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_KVService_RejectsBlockedPrefixes(t *testing.T) {
+	ctx := context.Background()
+	// Blocked keys are refused before the store is touched.
+	svc := kvservice.New(nil, []string{"secret:"})
+
+	_, err := svc.Get(ctx, "secret:token")
+	require.ErrorIs(t, err, apiframework.ErrForbidden)
+	require.ErrorIs(t, svc.Set(ctx, "secret:token", json.RawMessage(`"x"`)), apiframework.ErrForbidden)
+	require.ErrorIs(t, svc.Delete(ctx, "secret:token"), apiframework.ErrForbidden)
+	_, err = svc.List(ctx, "secret:", nil, 10)
+	require.ErrorIs(t, err, apiframework.ErrForbidden)
+	_, err = svc.List(ctx, "secret:tok", nil, 10)
+	require.ErrorIs(t, err, apiframework.ErrForbidden)
+
+	_, err = svc.Get(ctx, "")
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	require.ErrorIs(t, svc.Set(ctx, "chain:a", json.RawMessage(`{"broken"`)), apiframework.ErrBadRequest)
+	require.ErrorIs(t, svc.Set(ctx, "chain:a", nil), apiframework.ErrBadRequest)
+}
+
+func TestSystem_KVService_ListReadWrite(t *testing.T) {
+	ctx := context.TODO()
+	connStr, _, cleanup, err := libdb.SetupLocalInstance(ctx, "test", "test", "test")
+	require.NoError(t, err)
+	dbManager, err := libdb.NewPostgresDBManager(ctx, connStr, runtimetypes.Schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dbManager.Close())
+		cleanup()
+	})
+
+	svc := kvservice.New(dbManager, []string{"secret:"})
+	require.NoError(t, svc.Set(ctx, "chain:a", json.RawMessage(`{"id":"a"}`)))
+	require.NoError(t, svc.Set(ctx, "chain:b", json.RawMessage(`{"id":"b"}`)))
+	require.NoError(t, svc.Set(ctx, "other", json.RawMessage(`42`)))
+	require.NoError(t, runtimetypes.New(dbManager.WithoutTransaction()).SetKV(ctx, "secret:token", json.RawMessage(`"hidden"`)))
+
+	value, err := svc.Get(ctx, "chain:a")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"a"}`, string(value))
+
+	require.NoError(t, svc.Set(ctx, "chain:a", json.RawMessage(`{"id":"a2"}`)))
+	value, err = svc.Get(ctx, "chain:a")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"a2"}`, string(value))
+
+	entries, err := svc.List(ctx, "chain:", nil, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		require.Contains(t, []string{"chain:a", "chain:b"}, entry.Key)
+	}
+
+	entries, err = svc.List(ctx, "", nil, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3, "blocked entries are not listed")
+
+	require.NoError(t, svc.Delete(ctx, "chain:b"))
+	_, err = svc.Get(ctx, "chain:b")
+	require.Error(t, err)
+}
+
+func TestUnit_KVService_WithWriteHook(t *testing.T) {
+	ctx := context.Background()
+	var written []string
+	svc := kvservice.WithWriteHook(kvservice.New(nil, []string{"secret:"}), func(key string) {
+		written = append(written, key)
+	})
+
+	_, _ = svc.Get(ctx, "secret:token")
+	_ = svc.Set(ctx, "secret:token", json.RawMessage(`"x"`))
+	_ = svc.Delete(ctx, "secret:other")
+	require.Equal(t, []string{"secret:token", "secret:other"}, written, "reads do not call the hook")
+}
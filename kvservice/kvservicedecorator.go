@@ -0,0 +1,118 @@
+package kvservice
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/runtimetypes"
+)
+
+type activityTrackerDecorator struct {
+	service Service
+	tracker libtracker.ActivityTracker
+}
+
+func (d *activityTrackerDecorator) List(ctx context.Context, prefix string, cursor *time.Time, limit int) ([]*runtimetypes.KV, error) {
+	reportErrFn, _, endFn := d.tracker.Start(
+		ctx,
+		"list",
+		"kv",
+		"prefix", prefix,
+		"limit", limit,
+	)
+	defer endFn()
+
+	entries, err := d.service.List(ctx, prefix, cursor, limit)
+	if err != nil {
+		reportErrFn(err)
+	}
+	return entries, err
+}
+
+func (d *activityTrackerDecorator) Get(ctx context.Context, key string) (json.RawMessage, error) {
+	reportErrFn, _, endFn := d.tracker.Start(
+		ctx,
+		"get",
+		"kv",
+		"key", key,
+	)
+	defer endFn()
+
+	value, err := d.service.Get(ctx, key)
+	if err != nil {
+		reportErrFn(err)
+	}
+	return value, err
+}
+
+func (d *activityTrackerDecorator) Set(ctx context.Context, key string, value json.RawMessage) error {
+	reportErrFn, reportChangeFn, endFn := d.tracker.Start(
+		ctx,
+		"set",
+		"kv",
+		"key", key,
+		"size", len(value),
+	)
+	defer endFn()
+
+	err := d.service.Set(ctx, key, value)
+	if err != nil {
+		reportErrFn(err)
+	} else {
+		reportChangeFn(key, map[string]interface{}{
+			"key":  key,
+			"size": len(value),
+		})
+	}
+	return err
+}
+
+func (d *activityTrackerDecorator) Delete(ctx context.Context, key string) error {
+	reportErrFn, reportChangeFn, endFn := d.tracker.Start(
+		ctx,
+		"delete",
+		"kv",
+		"key", key,
+	)
+	defer endFn()
+
+	err := d.service.Delete(ctx, key)
+	if err != nil {
+		reportErrFn(err)
+	} else {
+		reportChangeFn(key, nil)
+	}
+	return err
+}
+
+// WithActivityTracker records every KV access, including reads, as operators
+// use the service for debugging.
+func WithActivityTracker(service Service, tracker libtracker.ActivityTracker) Service {
+	return &activityTrackerDecorator{
+		service: service,
+		tracker: tracker,
+	}
+}
+
+type writeHookDecorator struct {
+	Service
+	onWrite func(key string)
+}
+
+func (d *writeHookDecorator) Set(ctx context.Context, key string, value json.RawMessage) error {
+	defer d.onWrite(key)
+	return d.Service.Set(ctx, key, value)
+}
+
+func (d *writeHookDecorator) Delete(ctx context.Context, key string) error {
+	defer d.onWrite(key)
+	return d.Service.Delete(ctx, key)
+}
+
+// WithWriteHook calls onWrite with the key of every Set and Delete once it
+// returned, e.g. to drop caches of values edited through the service.
+func WithWriteHook(service Service, onWrite func(key string)) Service {
+	return &writeHookDecorator{Service: service, onWrite: onWrite}
+}
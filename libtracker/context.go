@@ -5,6 +5,7 @@ import "context"
 var ContextKeyRequestID = contextKey("request_id")
 var ContextKeyTraceID = contextKey("trace_id")
 var ContextKeySpanID = contextKey("span_id")
+var ContextKeyCaptureContent = contextKey("capture_content")
 
 func CopyTrackingValues(src context.Context, dst context.Context) context.Context {
 	requestID := src.Value(ContextKeyRequestID)
@@ -13,5 +14,21 @@ func CopyTrackingValues(src context.Context, dst context.Context) context.Contex
 	ctx := context.WithValue(dst, ContextKeyRequestID, requestID)
 	ctx = context.WithValue(ctx, ContextKeyTraceID, traceID)
 	ctx = context.WithValue(ctx, ContextKeySpanID, spanID)
+	if capture, ok := src.Value(ContextKeyCaptureContent).(bool); ok {
+		ctx = context.WithValue(ctx, ContextKeyCaptureContent, capture)
+	}
 	return ctx
 }
+
+// WithCaptureContent sets whether prompt and response content may be recorded
+// for the operations executed with the returned context.
+func WithCaptureContent(ctx context.Context, capture bool) context.Context {
+	return context.WithValue(ctx, ContextKeyCaptureContent, capture)
+}
+
+// CaptureContentEnabled reports whether content may be recorded for ctx.
+// Capturing is enabled unless it was explicitly disabled via WithCaptureContent.
+func CaptureContentEnabled(ctx context.Context) bool {
+	capture, ok := ctx.Value(ContextKeyCaptureContent).(bool)
+	return !ok || capture
+}
@@ -87,6 +87,16 @@ func validate(model *runtimetypes.Model) error {
 	if !model.CanChat && !model.CanEmbed && !model.CanPrompt && !model.CanStream {
 		return fmt.Errorf("%w %w: capabilities are required", apiframework.ErrBadRequest, ErrInvalidModel)
 	}
+	params := model.DefaultParams
+	if t := params.Temperature; t != nil && (*t < 0 || *t > 2) {
+		return fmt.Errorf("%w %w: default temperature %v out of range [0, 2]", apiframework.ErrBadRequest, ErrInvalidModel, *t)
+	}
+	if params.TopP < 0 || params.TopP > 1 {
+		return fmt.Errorf("%w %w: default topP %v out of range [0, 1]", apiframework.ErrBadRequest, ErrInvalidModel, params.TopP)
+	}
+	if params.MaxTokens < 0 {
+		return fmt.Errorf("%w %w: default maxTokens must not be negative", apiframework.ErrBadRequest, ErrInvalidModel)
+	}
 	return nil
 }
 
@@ -1,9 +1,11 @@
 package playground_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/contenox/runtime/backendservice"
 	"github.com/contenox/runtime/playground"
 	"github.com/contenox/runtime/runtimetypes"
 	"github.com/stretchr/testify/assert"
@@ -80,3 +82,60 @@ func TestSystem_BackendService(t *testing.T) {
 		assert.Error(t, err, "Should return error for deleted backend")
 	})
 }
+
+func TestSystem_BackendService_SyncModels(t *testing.T) {
+	ctx := t.Context()
+
+	reported := []*runtimetypes.Model{
+		{Model: "mistral:instruct", ContextLength: 8192, CanChat: true, CanPrompt: true, CanStream: true},
+		{Model: "nomic-embed-text:latest", ContextLength: 2048, CanEmbed: true},
+	}
+	discover := func(ctx context.Context, backend *runtimetypes.Backend) ([]*runtimetypes.Model, error) {
+		return reported, nil
+	}
+
+	p := playground.New()
+	backends, err := p.WithPostgresTestContainer(ctx).GetBackendService(backendservice.WithModelDiscovery(discover))
+	require.NoError(t, err)
+	defer p.CleanUp()
+	pools, err := p.GetPoolService()
+	require.NoError(t, err)
+	models, err := p.GetModelService()
+	require.NoError(t, err)
+
+	backend := &runtimetypes.Backend{Name: "sync-backend", BaseURL: "http://localhost:11434", Type: "ollama"}
+	require.NoError(t, backends.Create(ctx, backend))
+	pool := &runtimetypes.Pool{Name: "sync-pool", PurposeType: "chat"}
+	require.NoError(t, pools.Create(ctx, pool))
+	require.NoError(t, pools.AssignBackend(ctx, pool.ID, backend.ID))
+
+	// A manually assigned model the backend doesn't host.
+	manual := &runtimetypes.Model{Model: "llama2:7b", ContextLength: 4096, CanChat: true}
+	require.NoError(t, models.Append(ctx, manual))
+	require.NoError(t, pools.AssignModel(ctx, pool.ID, manual.ID))
+
+	result, err := backends.SyncModels(ctx, backend.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"mistral:instruct", "nomic-embed-text:latest"}, result.Added)
+	require.ElementsMatch(t, []string{"mistral:instruct", "nomic-embed-text:latest"}, result.Assigned)
+	require.Equal(t, []string{"llama2:7b"}, result.Missing)
+
+	assigned, err := pools.ListModels(ctx, pool.ID)
+	require.NoError(t, err)
+	names := make([]string, 0, len(assigned))
+	for _, m := range assigned {
+		names = append(names, m.Model)
+		if m.Model == "nomic-embed-text:latest" {
+			require.True(t, m.CanEmbed)
+			require.Equal(t, 2048, m.ContextLength)
+		}
+	}
+	require.ElementsMatch(t, []string{"llama2:7b", "mistral:instruct", "nomic-embed-text:latest"}, names)
+
+	// Re-running converges without further changes.
+	result, err = backends.SyncModels(ctx, backend.ID)
+	require.NoError(t, err)
+	require.Empty(t, result.Added)
+	require.Empty(t, result.Assigned)
+	require.Equal(t, []string{"llama2:7b"}, result.Missing)
+}
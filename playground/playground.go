@@ -24,6 +24,7 @@ import (
 	"github.com/contenox/runtime/libtracker"
 	"github.com/contenox/runtime/modelservice"
 	"github.com/contenox/runtime/poolservice"
+	"github.com/contenox/runtime/prompttemplateservice"
 	"github.com/contenox/runtime/providerservice"
 	"github.com/contenox/runtime/runtimetypes"
 	"github.com/contenox/runtime/stateservice"
@@ -382,14 +383,14 @@ func (p *Playground) WithOllamaBackend(ctx context.Context, name, tag string, as
 }
 
 // GetBackendService returns a new backend service instance.
-func (p *Playground) GetBackendService() (backendservice.Service, error) {
+func (p *Playground) GetBackendService(opts ...backendservice.Option) (backendservice.Service, error) {
 	if p.Error != nil {
 		return nil, p.Error
 	}
 	if p.db == nil {
 		return nil, errors.New("cannot get backend service: database is not initialized")
 	}
-	return backendservice.New(p.db), nil
+	return backendservice.New(p.db, opts...), nil
 }
 
 // GetDownloadService returns a new download service instance.
@@ -478,6 +479,17 @@ func (p *Playground) GetTaskChainService() (taskchainservice.Service, error) {
 	return taskchainservice.New(p.db), nil
 }
 
+// GetPromptTemplateService returns a new prompt template service instance.
+func (p *Playground) GetPromptTemplateService() (prompttemplateservice.Service, error) {
+	if p.Error != nil {
+		return nil, p.Error
+	}
+	if p.db == nil {
+		return nil, errors.New("cannot get prompt template service: database is not initialized")
+	}
+	return prompttemplateservice.New(p.db), nil
+}
+
 // GetExecService returns a new exec service instance.
 func (p *Playground) GetExecService(ctx context.Context) (execservice.ExecService, error) {
 	if p.Error != nil {
@@ -530,7 +542,11 @@ func (p *Playground) GetChatService(ctx context.Context) (chatservice.Service, e
 		return nil, fmt.Errorf("failed to get task chain service for chat service: %w", err)
 	}
 
-	return chatservice.New(envExec, taskChainService), nil
+	if p.llmRepo == nil {
+		return nil, errors.New("cannot get chat service: llmRepo is not initialized")
+	}
+
+	return chatservice.New(envExec, taskChainService, p.llmRepo), nil
 }
 
 // GetHookProviderService returns a new hook provider service instance.
@@ -0,0 +1,47 @@
+package playground_test
+
+import (
+	"testing"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/playground"
+	"github.com/contenox/runtime/prompttemplateservice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystem_PromptTemplateService(t *testing.T) {
+	ctx := t.Context()
+
+	p := playground.New()
+	templates, err := p.WithPostgresTestContainer(ctx).GetPromptTemplateService()
+	require.NoError(t, err)
+	defer p.CleanUp()
+
+	tmpl := &prompttemplateservice.PromptTemplate{
+		Name:      "greeting",
+		Template:  "Say hello to {{.name}} in {{.language}}.",
+		Variables: []string{"name", "language"},
+	}
+	require.NoError(t, templates.Create(ctx, tmpl))
+	require.ErrorIs(t, templates.Create(ctx, tmpl), apiframework.ErrConflict)
+
+	stored, err := templates.Get(ctx, "greeting")
+	require.NoError(t, err)
+	require.Equal(t, tmpl.Template, stored.Template)
+	require.Equal(t, tmpl.Variables, stored.Variables)
+
+	prompt, err := templates.Render(ctx, "greeting", map[string]any{"name": "Ada", "language": "French"})
+	require.NoError(t, err)
+	require.Equal(t, "Say hello to Ada in French.", prompt)
+
+	_, err = templates.Render(ctx, "greeting", map[string]any{"name": "Ada"})
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+
+	list, err := templates.List(ctx, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	require.NoError(t, templates.Delete(ctx, "greeting"))
+	_, err = templates.Get(ctx, "greeting")
+	require.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package playground_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/contenox/runtime/playground"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystem_TaskChainService_Versions(t *testing.T) {
+	ctx := t.Context()
+
+	p := playground.New()
+	chains, err := p.WithPostgresTestContainer(ctx).GetTaskChainService()
+	require.NoError(t, err)
+	defer p.CleanUp()
+
+	newChain := func(description string) *taskengine.TaskChainDefinition {
+		return &taskengine.TaskChainDefinition{
+			ID:          "versioned-chain",
+			Description: description,
+			Tasks: []taskengine.TaskDefinition{
+				{
+					ID:      "noop",
+					Handler: taskengine.HandleNoop,
+					Transition: taskengine.TaskTransition{
+						Branches: []taskengine.TransitionBranch{
+							{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	require.NoError(t, chains.Create(ctx, newChain("v0")))
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, chains.Update(ctx, newChain(fmt.Sprintf("v%d", i))))
+	}
+
+	versions, err := chains.ListVersions(ctx, "versioned-chain")
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	require.Equal(t, 3, versions[0].Version)
+	require.Equal(t, "v2", versions[0].Chain.Description)
+	require.Equal(t, 1, versions[2].Version)
+	require.Equal(t, "v0", versions[2].Chain.Description)
+
+	restored, err := chains.Rollback(ctx, "versioned-chain", 1)
+	require.NoError(t, err)
+	require.Equal(t, "v0", restored.Description)
+
+	active, err := chains.Get(ctx, "versioned-chain")
+	require.NoError(t, err)
+	require.Equal(t, "v0", active.Description)
+
+	// The definition replaced by the rollback is kept as the newest version.
+	versions, err = chains.ListVersions(ctx, "versioned-chain")
+	require.NoError(t, err)
+	require.Len(t, versions, 4)
+	require.Equal(t, "v3", versions[0].Chain.Description)
+
+	// Versions do not show up as chains.
+	all, err := chains.List(ctx, nil, 100)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	require.NoError(t, chains.Delete(ctx, "versioned-chain"))
+	versions, err = chains.ListVersions(ctx, "versioned-chain")
+	require.NoError(t, err)
+	require.Empty(t, versions)
+}
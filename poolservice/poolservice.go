@@ -45,6 +45,9 @@ type Service interface {
 }
 
 func (s *service) Create(ctx context.Context, pool *runtimetypes.Pool) error {
+	if err := validate(pool); err != nil {
+		return err
+	}
 	pool.ID = uuid.New().String()
 	tx := s.dbInstance.WithoutTransaction()
 	storeInstance := runtimetypes.New(tx)
@@ -73,8 +76,23 @@ func (s *service) Update(ctx context.Context, pool *runtimetypes.Pool) error {
 	if pool.ID == runtimestate.EmbedPoolID {
 		return fmt.Errorf("pool %s is immutable", pool.ID)
 	}
+	if err := validate(pool); err != nil {
+		return err
+	}
 	tx := s.dbInstance.WithoutTransaction()
-	return runtimetypes.New(tx).UpdatePool(ctx, pool)
+	storeInstance := runtimetypes.New(tx)
+	if pool.Capability != "" {
+		models, err := storeInstance.ListModelsForPool(ctx, pool.ID)
+		if err != nil {
+			return err
+		}
+		for _, model := range models {
+			if err := CheckModelCapability(pool, model); err != nil {
+				return err
+			}
+		}
+	}
+	return storeInstance.UpdatePool(ctx, pool)
 }
 
 func (s *service) Delete(ctx context.Context, id string) error {
@@ -128,7 +146,14 @@ func (s *service) RemoveBackend(ctx context.Context, poolID, backendID string) e
 
 func (s *service) ListBackends(ctx context.Context, poolID string) ([]*runtimetypes.Backend, error) {
 	tx := s.dbInstance.WithoutTransaction()
-	return runtimetypes.New(tx).ListBackendsForPool(ctx, poolID)
+	backends, err := runtimetypes.New(tx).ListBackendsForPool(ctx, poolID)
+	if err != nil {
+		return nil, err
+	}
+	for _, backend := range backends {
+		backend.Redact()
+	}
+	return backends, nil
 }
 
 func (s *service) ListPoolsForBackend(ctx context.Context, backendID string) ([]*runtimetypes.Pool, error) {
@@ -138,7 +163,19 @@ func (s *service) ListPoolsForBackend(ctx context.Context, backendID string) ([]
 
 func (s *service) AssignModel(ctx context.Context, poolID, modelID string) error {
 	tx := s.dbInstance.WithoutTransaction()
-	return runtimetypes.New(tx).AssignModelToPool(ctx, poolID, modelID)
+	storeInstance := runtimetypes.New(tx)
+	pool, err := storeInstance.GetPool(ctx, poolID)
+	if err != nil {
+		return err
+	}
+	model, err := storeInstance.GetModel(ctx, modelID)
+	if err != nil {
+		return err
+	}
+	if err := CheckModelCapability(pool, model); err != nil {
+		return err
+	}
+	return storeInstance.AssignModelToPool(ctx, poolID, modelID)
 }
 
 func (s *service) RemoveModel(ctx context.Context, poolID, modelID string) error {
@@ -158,3 +195,30 @@ func (s *service) ListPoolsForModel(ctx context.Context, modelID string) ([]*run
 	tx := s.dbInstance.WithoutTransaction()
 	return runtimetypes.New(tx).ListPoolsForModel(ctx, modelID)
 }
+
+func validate(pool *runtimetypes.Pool) error {
+	if pool.MaxInFlight < 0 {
+		return fmt.Errorf("%w: maxInFlight must not be negative: %w", ErrInvalidPool, apiframework.ErrBadRequest)
+	}
+	if !runtimestate.ValidAdmission(pool.Admission) {
+		return fmt.Errorf("%w: admission must be %q or %q: %w", ErrInvalidPool, runtimestate.AdmissionReject, runtimestate.AdmissionQueue, apiframework.ErrBadRequest)
+	}
+	if !runtimetypes.ValidCapability(pool.Capability) {
+		return fmt.Errorf("%w: unknown capability %q: %w", ErrInvalidPool, pool.Capability, apiframework.ErrBadRequest)
+	}
+	for _, model := range pool.KeepWarm {
+		if model == "" {
+			return fmt.Errorf("%w: keepWarm model names must not be empty: %w", ErrInvalidPool, apiframework.ErrBadRequest)
+		}
+	}
+	return nil
+}
+
+// CheckModelCapability reports an error if model lacks the capability pool
+// requires, e.g. a chat-only model assigned to an embedding pool.
+func CheckModelCapability(pool *runtimetypes.Pool, model *runtimetypes.Model) error {
+	if model.Supports(pool.Capability) {
+		return nil
+	}
+	return fmt.Errorf("%w: model %s lacks capability %q required by pool %s: %w", ErrInvalidPool, model.Model, pool.Capability, pool.Name, apiframework.ErrBadRequest)
+}
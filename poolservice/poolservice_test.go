@@ -0,0 +1,40 @@
+package poolservice_test
+
+import (
+	"testing"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/poolservice"
+	"github.com/contenox/runtime/runtimetypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_CheckModelCapability(t *testing.T) {
+	embedder := &runtimetypes.Model{Model: "nomic-embed-text:latest", CanEmbed: true}
+	chatter := &runtimetypes.Model{Model: "mistral:instruct", CanChat: true, CanPrompt: true, CanStream: true}
+	embedPool := &runtimetypes.Pool{Name: "embeddings", Capability: runtimetypes.CapabilityEmbed}
+	chatPool := &runtimetypes.Pool{Name: "chat", Capability: runtimetypes.CapabilityChat}
+	anyPool := &runtimetypes.Pool{Name: "any"}
+
+	require.NoError(t, poolservice.CheckModelCapability(embedPool, embedder))
+	require.NoError(t, poolservice.CheckModelCapability(chatPool, chatter))
+	require.NoError(t, poolservice.CheckModelCapability(anyPool, embedder))
+	require.NoError(t, poolservice.CheckModelCapability(anyPool, chatter))
+
+	err := poolservice.CheckModelCapability(chatPool, embedder)
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	require.ErrorIs(t, err, poolservice.ErrInvalidPool)
+	require.ErrorContains(t, err, `lacks capability "chat"`)
+
+	err = poolservice.CheckModelCapability(embedPool, chatter)
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	require.ErrorContains(t, err, `lacks capability "embed"`)
+}
+
+func TestUnit_ValidCapability(t *testing.T) {
+	for _, capability := range []string{"", "chat", "embed", "prompt", "stream"} {
+		require.True(t, runtimetypes.ValidCapability(capability), capability)
+	}
+	require.False(t, runtimetypes.ValidCapability("tools"))
+	require.False(t, runtimetypes.ValidCapability("Chat"))
+}
@@ -0,0 +1,198 @@
+package prompttemplateservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	libdb "github.com/contenox/runtime/libdbexec"
+	"github.com/contenox/runtime/runtimetypes"
+	"github.com/contenox/runtime/taskengine"
+)
+
+const promptTemplatePrefix = "prompt:"
+
+// PromptTemplate is a named, reusable prompt.
+// The template is rendered like a task PromptTemplate; every declared variable
+// must be supplied when it is rendered.
+type PromptTemplate struct {
+	Name        string    `json:"name" example:"summarize-ticket"`
+	Description string    `json:"description,omitempty" example:"Summarizes a support ticket"`
+	Template    string    `json:"template" example:"Summarize this ticket in {{.sentences}} sentences: {{.ticket}}"`
+	Variables   []string  `json:"variables,omitempty" example:"[\"ticket\", \"sentences\"]"`
+	CreatedAt   time.Time `json:"createdAt" example:"2023-11-15T14:30:45Z"`
+	UpdatedAt   time.Time `json:"updatedAt" example:"2023-11-15T14:30:45Z"`
+}
+
+type Service interface {
+	// Create stores a new prompt template. It fails if the name is taken.
+	Create(ctx context.Context, tmpl *PromptTemplate) error
+
+	// Get a prompt template by name
+	Get(ctx context.Context, name string) (*PromptTemplate, error)
+
+	// Update an existing prompt template
+	Update(ctx context.Context, tmpl *PromptTemplate) error
+
+	// Delete a prompt template
+	Delete(ctx context.Context, name string) error
+
+	// List prompt templates with pagination
+	List(ctx context.Context, cursor *time.Time, limit int) ([]*PromptTemplate, error)
+
+	// Render renders the named template with the given variables
+	Render(ctx context.Context, name string, vars map[string]any) (string, error)
+}
+
+type service struct {
+	db libdb.DBManager
+}
+
+func New(db libdb.DBManager) Service {
+	return &service{db: db}
+}
+
+func (s *service) Create(ctx context.Context, tmpl *PromptTemplate) error {
+	if err := Validate(tmpl); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	tmpl.CreatedAt = now
+	tmpl.UpdatedAt = now
+	value, err := json.Marshal(tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to serialize prompt template: %w", err)
+	}
+	// A single insert, so concurrent creates of the same name cannot both succeed.
+	storeInstance := runtimetypes.New(s.db.WithoutTransaction())
+	err = storeInstance.InsertKV(ctx, promptTemplatePrefix+tmpl.Name, value)
+	if errors.Is(err, libdb.ErrUniqueViolation) {
+		return fmt.Errorf("prompt template %q already exists: %w", tmpl.Name, apiframework.ErrConflict)
+	}
+	return err
+}
+
+func (s *service) Get(ctx context.Context, name string) (*PromptTemplate, error) {
+	if name == "" {
+		return nil, fmt.Errorf("prompt template name is required: %w", apiframework.ErrBadRequest)
+	}
+	var tmpl PromptTemplate
+	storeInstance := runtimetypes.New(s.db.WithoutTransaction())
+	if err := storeInstance.GetKV(ctx, promptTemplatePrefix+name, &tmpl); err != nil {
+		return nil, fmt.Errorf("failed to get prompt template: %w", err)
+	}
+	return &tmpl, nil
+}
+
+func (s *service) Update(ctx context.Context, tmpl *PromptTemplate) error {
+	if err := Validate(tmpl); err != nil {
+		return err
+	}
+	tx, commit, release, err := s.db.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	storeInstance := runtimetypes.New(tx)
+
+	var existing PromptTemplate
+	if err := storeInstance.GetKV(ctx, promptTemplatePrefix+tmpl.Name, &existing); err != nil {
+		return fmt.Errorf("failed to update prompt template: %w", err)
+	}
+	tmpl.CreatedAt = existing.CreatedAt
+	tmpl.UpdatedAt = time.Now().UTC()
+	if err := setTemplate(ctx, storeInstance, tmpl); err != nil {
+		return err
+	}
+	return commit(ctx)
+}
+
+func (s *service) Delete(ctx context.Context, name string) error {
+	if name == "" {
+		return fmt.Errorf("prompt template name is required: %w", apiframework.ErrBadRequest)
+	}
+	storeInstance := runtimetypes.New(s.db.WithoutTransaction())
+	return storeInstance.DeleteKV(ctx, promptTemplatePrefix+name)
+}
+
+func (s *service) List(ctx context.Context, cursor *time.Time, limit int) ([]*PromptTemplate, error) {
+	storeInstance := runtimetypes.New(s.db.WithoutTransaction())
+	kvs, err := storeInstance.ListKVPrefix(ctx, promptTemplatePrefix, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+
+	templates := make([]*PromptTemplate, 0, len(kvs))
+	for _, kv := range kvs {
+		var tmpl PromptTemplate
+		if err := json.Unmarshal(kv.Value, &tmpl); err != nil {
+			continue
+		}
+		templates = append(templates, &tmpl)
+	}
+	return templates, nil
+}
+
+func (s *service) Render(ctx context.Context, name string, vars map[string]any) (string, error) {
+	tmpl, err := s.Get(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return Render(tmpl, vars)
+}
+
+// Validate checks that tmpl has a name, a template that parses and no duplicate variables.
+func Validate(tmpl *PromptTemplate) error {
+	if tmpl.Name == "" {
+		return fmt.Errorf("prompt template name is required: %w", apiframework.ErrBadRequest)
+	}
+	if strings.TrimSpace(tmpl.Template) == "" {
+		return fmt.Errorf("prompt template %q has no template: %w", tmpl.Name, apiframework.ErrBadRequest)
+	}
+	placeholders := make(map[string]any, len(tmpl.Variables))
+	for _, v := range tmpl.Variables {
+		if v == "" {
+			return fmt.Errorf("prompt template %q declares an empty variable name: %w", tmpl.Name, apiframework.ErrBadRequest)
+		}
+		if _, ok := placeholders[v]; ok {
+			return fmt.Errorf("prompt template %q declares variable %q twice: %w", tmpl.Name, v, apiframework.ErrBadRequest)
+		}
+		placeholders[v] = ""
+	}
+	if _, err := taskengine.RenderTemplate(tmpl.Template, placeholders); err != nil {
+		return fmt.Errorf("prompt template %q is invalid: %v: %w", tmpl.Name, err, apiframework.ErrBadRequest)
+	}
+	return nil
+}
+
+// Render renders tmpl with vars. It fails if a declared variable is not supplied.
+func Render(tmpl *PromptTemplate, vars map[string]any) (string, error) {
+	var missing []string
+	for _, v := range tmpl.Variables {
+		if _, ok := vars[v]; !ok {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("prompt template %q is missing variables %s: %w", tmpl.Name, strings.Join(missing, ", "), apiframework.ErrBadRequest)
+	}
+	prompt, err := taskengine.RenderTemplate(tmpl.Template, vars)
+	if err != nil {
+		return "", fmt.Errorf("failed to render prompt template %q: %v: %w", tmpl.Name, err, apiframework.ErrBadRequest)
+	}
+	return prompt, nil
+}
+
+func setTemplate(ctx context.Context, storeInstance runtimetypes.Store, tmpl *PromptTemplate) error {
+	value, err := json.Marshal(tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to serialize prompt template: %w", err)
+	}
+	return storeInstance.SetKV(ctx, promptTemplatePrefix+tmpl.Name, value)
+}
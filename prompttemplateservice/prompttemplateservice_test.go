@@ -0,0 +1,48 @@
+package prompttemplateservice_test
+
+import (
+	"testing"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/prompttemplateservice"
+	"github.com/stretchr/testify/require"
+)
+
+func ticketTemplate() *prompttemplateservice.PromptTemplate {
+	return &prompttemplateservice.PromptTemplate{
+		Name:      "summarize-ticket",
+		Template:  "Summarize in {{.sentences}} sentences: {{.ticket | trim}}",
+		Variables: []string{"ticket", "sentences"},
+	}
+}
+
+func TestUnit_Render_SubstitutesVariables(t *testing.T) {
+	prompt, err := prompttemplateservice.Render(ticketTemplate(), map[string]any{
+		"ticket":    "  Login fails  ",
+		"sentences": 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Summarize in 2 sentences: Login fails", prompt)
+}
+
+func TestUnit_Render_MissingVariable(t *testing.T) {
+	_, err := prompttemplateservice.Render(ticketTemplate(), map[string]any{"ticket": "Login fails"})
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	require.ErrorContains(t, err, "missing variables sentences")
+
+	_, err = prompttemplateservice.Render(ticketTemplate(), nil)
+	require.ErrorContains(t, err, "missing variables sentences, ticket")
+}
+
+func TestUnit_Validate(t *testing.T) {
+	require.NoError(t, prompttemplateservice.Validate(ticketTemplate()))
+
+	for _, tmpl := range []*prompttemplateservice.PromptTemplate{
+		{Template: "hello"},
+		{Name: "empty"},
+		{Name: "broken", Template: "{{.ticket"},
+		{Name: "dup", Template: "{{.a}}", Variables: []string{"a", "a"}},
+	} {
+		require.ErrorIs(t, prompttemplateservice.Validate(tmpl), apiframework.ErrBadRequest, tmpl.Name)
+	}
+}
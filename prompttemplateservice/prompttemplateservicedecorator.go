@@ -0,0 +1,143 @@
+package prompttemplateservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/contenox/runtime/libtracker"
+)
+
+type activityTrackerDecorator struct {
+	service Service
+	tracker libtracker.ActivityTracker
+}
+
+func (d *activityTrackerDecorator) Create(ctx context.Context, tmpl *PromptTemplate) error {
+	reportErrFn, reportChangeFn, endFn := d.tracker.Start(
+		ctx,
+		"create",
+		"prompt_template",
+		"name", tmpl.Name,
+	)
+	defer endFn()
+
+	err := d.service.Create(ctx, tmpl)
+	if err != nil {
+		reportErrFn(err)
+	} else {
+		reportChangeFn(tmpl.Name, map[string]interface{}{
+			"name":      tmpl.Name,
+			"variables": tmpl.Variables,
+		})
+	}
+
+	return err
+}
+
+func (d *activityTrackerDecorator) Get(ctx context.Context, name string) (*PromptTemplate, error) {
+	reportErrFn, _, endFn := d.tracker.Start(
+		ctx,
+		"get",
+		"prompt_template",
+		"name", name,
+	)
+	defer endFn()
+
+	tmpl, err := d.service.Get(ctx, name)
+	if err != nil {
+		reportErrFn(err)
+	}
+
+	return tmpl, err
+}
+
+func (d *activityTrackerDecorator) Update(ctx context.Context, tmpl *PromptTemplate) error {
+	reportErrFn, reportChangeFn, endFn := d.tracker.Start(
+		ctx,
+		"update",
+		"prompt_template",
+		"name", tmpl.Name,
+	)
+	defer endFn()
+
+	err := d.service.Update(ctx, tmpl)
+	if err != nil {
+		reportErrFn(err)
+	} else {
+		reportChangeFn(tmpl.Name, map[string]interface{}{
+			"name":      tmpl.Name,
+			"variables": tmpl.Variables,
+		})
+	}
+
+	return err
+}
+
+func (d *activityTrackerDecorator) Delete(ctx context.Context, name string) error {
+	reportErrFn, reportChangeFn, endFn := d.tracker.Start(
+		ctx,
+		"delete",
+		"prompt_template",
+		"name", name,
+	)
+	defer endFn()
+
+	err := d.service.Delete(ctx, name)
+	if err != nil {
+		reportErrFn(err)
+	} else {
+		reportChangeFn(name, nil)
+	}
+
+	return err
+}
+
+func (d *activityTrackerDecorator) List(ctx context.Context, cursor *time.Time, limit int) ([]*PromptTemplate, error) {
+	cursorStr := "nil"
+	if cursor != nil {
+		cursorStr = cursor.Format(time.RFC3339)
+	}
+
+	reportErrFn, _, endFn := d.tracker.Start(
+		ctx,
+		"list",
+		"prompt_templates",
+		"cursor", cursorStr,
+		"limit", fmt.Sprintf("%d", limit),
+	)
+	defer endFn()
+
+	templates, err := d.service.List(ctx, cursor, limit)
+	if err != nil {
+		reportErrFn(err)
+	}
+
+	return templates, err
+}
+
+func (d *activityTrackerDecorator) Render(ctx context.Context, name string, vars map[string]any) (string, error) {
+	reportErrFn, _, endFn := d.tracker.Start(
+		ctx,
+		"render",
+		"prompt_template",
+		"name", name,
+		"variableCount", len(vars),
+	)
+	defer endFn()
+
+	prompt, err := d.service.Render(ctx, name, vars)
+	if err != nil {
+		reportErrFn(err)
+	}
+
+	return prompt, err
+}
+
+// WithActivityTracker wraps a prompt template service with activity tracking capabilities
+func WithActivityTracker(service Service, tracker libtracker.ActivityTracker) Service {
+	return &activityTrackerDecorator{
+		service: service,
+		tracker: tracker,
+	}
+}
@@ -3,6 +3,7 @@ package runtimesdk
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -257,3 +258,58 @@ func (s *HTTPBackendService) List(ctx context.Context, createdAtCursor *time.Tim
 
 	return backends, nil
 }
+
+// SyncModels implements backendservice.Service.SyncModels
+func (s *HTTPBackendService) SyncModels(ctx context.Context, id string) (*backendservice.SyncResult, error) {
+	url := fmt.Sprintf("%s/backends/%s/sync", s.baseURL, id)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set headers
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Check for error status codes
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiframework.HandleAPIError(resp)
+	}
+
+	var result backendservice.SyncResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SyncAll implements backendservice.Service.SyncAll
+func (s *HTTPBackendService) SyncAll(ctx context.Context) error {
+	var cursor *time.Time
+	var errs []error
+	for {
+		backends, err := s.List(ctx, cursor, 100)
+		if err != nil {
+			return err
+		}
+		for _, backend := range backends {
+			if _, err := s.SyncModels(ctx, backend.ID); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(backends) < 100 {
+			break
+		}
+		cursor = &backends[len(backends)-1].CreatedAt
+	}
+	return errors.Join(errs...)
+}
@@ -99,3 +99,39 @@ func (s *HTTPChatService) OpenAIChatCompletions(ctx context.Context, chainID str
 
 	return chatResponse, response.StackTrace, nil
 }
+
+// CountTokens implements chatservice.Service.CountTokens
+func (s *HTTPChatService) CountTokens(ctx context.Context, chainID string, req taskengine.OpenAIChatRequest) (*taskengine.ChatTokenCount, error) {
+	url := s.baseURL + "/" + chainID + "/v1/chat/completions/tokens"
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal chat request: %w", err)
+	}
+
+	reqHTTP, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+
+	reqHTTP.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		reqHTTP.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(reqHTTP)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiframework.HandleAPIError(resp)
+	}
+
+	var count taskengine.ChatTokenCount
+	if err := json.NewDecoder(resp.Body).Decode(&count); err != nil {
+		return nil, fmt.Errorf("failed to decode token count response: %w", err)
+	}
+	return &count, nil
+}
@@ -14,8 +14,10 @@ import (
 	"github.com/contenox/runtime/execservice"
 	"github.com/contenox/runtime/hookproviderservice"
 	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/jobservice"
 	"github.com/contenox/runtime/modelservice"
 	"github.com/contenox/runtime/poolservice"
+	"github.com/contenox/runtime/prompttemplateservice"
 	"github.com/contenox/runtime/providerservice"
 	"github.com/contenox/runtime/stateservice"
 	"github.com/contenox/runtime/taskchainservice"
@@ -35,6 +37,8 @@ type Client struct {
 	EmbedService     embedservice.Service
 	TaskChainService taskchainservice.Service
 	ChatService      chatservice.Service
+	JobService       jobservice.Service
+	PromptService    prompttemplateservice.Service
 }
 
 // Config holds configuration for the SDK client
@@ -58,6 +62,8 @@ func createClient(config Config, httpClient *http.Client) (*Client, error) {
 		EmbedService:     NewHTTPEmbedService(config.BaseURL, config.Token, httpClient),
 		TaskChainService: NewHTTPTaskChainService(config.BaseURL, config.Token, httpClient),
 		ChatService:      NewHTTPChatService(config.BaseURL, config.Token, httpClient),
+		JobService:       NewHTTPJobService(config.BaseURL, config.Token, httpClient),
+		PromptService:    NewHTTPPromptTemplateService(config.BaseURL, config.Token, httpClient),
 	}, nil
 }
 
@@ -1,6 +1,7 @@
 package runtimesdk
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -84,6 +85,71 @@ func (s *HTTPEmbedService) Embed(ctx context.Context, text string) ([]float64, e
 	return response.Vector, nil
 }
 
+// EmbedBatch implements embedservice.Service.EmbedBatch
+func (s *HTTPEmbedService) EmbedBatch(ctx context.Context, modelName string, texts []string) (*embedservice.BatchResult, error) {
+	url := s.baseURL + "/v1/embeddings"
+
+	reqBody := struct {
+		Model string   `json:"model,omitempty"`
+		Input []string `json:"input"`
+	}{Model: modelName, Input: texts}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Handle non-200 responses
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiframework.HandleAPIError(resp)
+	}
+
+	// Parse response
+	var response struct {
+		Data []struct {
+			Embedding []float64 `json:"embedding"`
+			Index     int       `json:"index"`
+		} `json:"data"`
+		Model string `json:"model"`
+		Usage struct {
+			PromptTokens int `json:"prompt_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	result := &embedservice.BatchResult{
+		ModelName:    response.Model,
+		Vectors:      make([][]float64, len(texts)),
+		PromptTokens: response.Usage.PromptTokens,
+	}
+	for _, d := range response.Data {
+		if d.Index < 0 || d.Index >= len(result.Vectors) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		result.Vectors[d.Index] = d.Embedding
+	}
+	return result, nil
+}
+
 // DefaultModelName implements embedservice.Service.
 func (s *HTTPEmbedService) DefaultModelName(ctx context.Context) (string, error) {
 	url := s.baseURL + "/defaultmodel"
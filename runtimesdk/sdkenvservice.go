@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/contenox/runtime/execservice"
@@ -72,16 +73,54 @@ func (s *HTTPTasksEnvService) Execute(ctx context.Context, chain *taskengine.Tas
 
 	// Check for error status codes
 	if resp.StatusCode != http.StatusOK {
-		return nil, taskengine.DataTypeAny, nil, apiframework.HandleAPIError(resp)
+		return nil, taskengine.DataTypeAny, nil, taskChainError(resp)
 	}
 
-	// Decode response
+	return decodeTaskExecutionResponse(resp.Body)
+}
+
+// Resume implements execservice.TasksEnvService.Resume
+func (s *HTTPTasksEnvService) Resume(ctx context.Context, checkpointID string) (any, taskengine.DataType, []taskengine.CapturedStateUnit, error) {
+	url := fmt.Sprintf("%s/tasks/%s/resume", s.baseURL, url.PathEscape(checkpointID))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, taskengine.DataTypeAny, nil, err
+	}
+	if s.token != "" {
+		req.Header.Set("X-API-Key", s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, taskengine.DataTypeAny, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, taskengine.DataTypeAny, nil, taskChainError(resp)
+	}
+	return decodeTaskExecutionResponse(resp.Body)
+}
+
+// taskChainError converts an error response of a task-chain execution,
+// restoring the checkpoint ID of resumable executions.
+func taskChainError(resp *http.Response) error {
+	err := apiframework.HandleAPIError(resp)
+	if checkpointID := resp.Header.Get("X-Checkpoint-ID"); checkpointID != "" {
+		return &taskengine.ResumableError{CheckpointID: checkpointID, Err: err}
+	}
+	return err
+}
+
+// decodeTaskExecutionResponse decodes the output of a task-chain execution.
+func decodeTaskExecutionResponse(body io.Reader) (any, taskengine.DataType, []taskengine.CapturedStateUnit, error) {
 	var response struct {
 		Output     any                            `json:"output"`
 		OutputType string                         `json:"outputType"`
 		State      []taskengine.CapturedStateUnit `json:"state"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
 		return nil, taskengine.DataTypeAny, response.State, err
 	}
 	dt, err := taskengine.DataTypeFromString(response.OutputType)
@@ -95,6 +134,38 @@ func (s *HTTPTasksEnvService) Execute(ctx context.Context, chain *taskengine.Tas
 	return converted, dt, response.State, nil
 }
 
+// Validate implements execservice.TasksEnvService.Validate
+func (s *HTTPTasksEnvService) Validate(ctx context.Context, chain *taskengine.TaskChainDefinition) error {
+	url := s.baseURL + "/tasks/validate"
+
+	body, err := json.Marshal(map[string]any{"chain": chain})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("X-API-Key", s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apiframework.HandleAPIError(resp)
+	}
+	return nil
+}
+
 // Supports implements execservice.TasksEnvService.Supports (via taskengine.HookRegistry)
 func (s *HTTPTasksEnvService) Supports(ctx context.Context) ([]string, error) {
 	url := s.baseURL + "/supported"
@@ -128,3 +199,36 @@ func (s *HTTPTasksEnvService) Supports(ctx context.Context) ([]string, error) {
 
 	return hooks, nil
 }
+
+// Schemas implements execservice.TasksEnvService.Schemas (via taskengine.HookRegistry)
+func (s *HTTPTasksEnvService) Schemas(ctx context.Context) ([]taskengine.HookSchema, error) {
+	url := s.baseURL + "/hooks"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set headers
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Check for error status codes
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiframework.HandleAPIError(resp)
+	}
+
+	var schemas []taskengine.HookSchema
+	if err := json.NewDecoder(resp.Body).Decode(&schemas); err != nil {
+		return nil, err
+	}
+
+	return schemas, nil
+}
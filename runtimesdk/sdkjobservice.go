@@ -0,0 +1,91 @@
+package runtimesdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/jobservice"
+	"github.com/contenox/runtime/runtimetypes"
+)
+
+// HTTPJobService implements the jobservice.Service interface
+// using HTTP calls to the API
+type HTTPJobService struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewHTTPJobService creates a new HTTP client that implements jobservice.Service
+func NewHTTPJobService(baseURL, token string, client *http.Client) jobservice.Service {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	// Ensure baseURL doesn't end with a slash
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	return &HTTPJobService{
+		client:  client,
+		baseURL: baseURL,
+		token:   token,
+	}
+}
+
+// List implements jobservice.Service.List
+func (s *HTTPJobService) List(ctx context.Context, taskType string, createdAtCursor *time.Time, limit int) ([]*runtimetypes.Job, error) {
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(limit))
+	if taskType != "" {
+		params.Set("taskType", taskType)
+	}
+	if createdAtCursor != nil {
+		params.Set("cursor", createdAtCursor.Format(time.RFC3339Nano))
+	}
+
+	var jobs []*runtimetypes.Job
+	if err := s.get(ctx, s.baseURL+"/jobs?"+params.Encode(), &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// CountByType implements jobservice.Service.CountByType
+func (s *HTTPJobService) CountByType(ctx context.Context) (map[string]int64, error) {
+	var counts map[string]int64
+	if err := s.get(ctx, s.baseURL+"/jobs/counts", &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (s *HTTPJobService) get(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	// Set headers
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Check for error status codes
+	if resp.StatusCode != http.StatusOK {
+		return apiframework.HandleAPIError(resp)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
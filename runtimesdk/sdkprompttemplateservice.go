@@ -0,0 +1,138 @@
+package runtimesdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/prompttemplateservice"
+)
+
+// HTTPPromptTemplateService implements the prompttemplateservice.Service interface
+// using HTTP calls to the API
+type HTTPPromptTemplateService struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// NewHTTPPromptTemplateService creates a new HTTP client that implements prompttemplateservice.Service
+func NewHTTPPromptTemplateService(baseURL, token string, client *http.Client) prompttemplateservice.Service {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTPPromptTemplateService{
+		client:  client,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}
+}
+
+// Create implements prompttemplateservice.Service.Create
+func (s *HTTPPromptTemplateService) Create(ctx context.Context, tmpl *prompttemplateservice.PromptTemplate) error {
+	return s.do(ctx, http.MethodPost, "/prompt-templates", tmpl, http.StatusCreated, tmpl)
+}
+
+// Get implements prompttemplateservice.Service.Get
+func (s *HTTPPromptTemplateService) Get(ctx context.Context, name string) (*prompttemplateservice.PromptTemplate, error) {
+	if name == "" {
+		return nil, fmt.Errorf("prompt template name is required")
+	}
+	var tmpl prompttemplateservice.PromptTemplate
+	if err := s.do(ctx, http.MethodGet, "/prompt-templates/"+url.PathEscape(name), nil, http.StatusOK, &tmpl); err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// Update implements prompttemplateservice.Service.Update
+func (s *HTTPPromptTemplateService) Update(ctx context.Context, tmpl *prompttemplateservice.PromptTemplate) error {
+	if tmpl.Name == "" {
+		return fmt.Errorf("prompt template name is required")
+	}
+	return s.do(ctx, http.MethodPut, "/prompt-templates/"+url.PathEscape(tmpl.Name), tmpl, http.StatusOK, tmpl)
+}
+
+// Delete implements prompttemplateservice.Service.Delete
+func (s *HTTPPromptTemplateService) Delete(ctx context.Context, name string) error {
+	if name == "" {
+		return fmt.Errorf("prompt template name is required")
+	}
+	return s.do(ctx, http.MethodDelete, "/prompt-templates/"+url.PathEscape(name), nil, http.StatusOK, nil)
+}
+
+// List implements prompttemplateservice.Service.List
+func (s *HTTPPromptTemplateService) List(ctx context.Context, cursor *time.Time, limit int) ([]*prompttemplateservice.PromptTemplate, error) {
+	path := fmt.Sprintf("/prompt-templates?limit=%d", limit)
+	if cursor != nil {
+		path += "&cursor=" + url.QueryEscape(cursor.Format(time.RFC3339Nano))
+	}
+	var templates []*prompttemplateservice.PromptTemplate
+	if err := s.do(ctx, http.MethodGet, path, nil, http.StatusOK, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// Render implements prompttemplateservice.Service.Render
+func (s *HTTPPromptTemplateService) Render(ctx context.Context, name string, vars map[string]any) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("prompt template name is required")
+	}
+	var response struct {
+		Prompt string `json:"prompt"`
+	}
+	request := map[string]any{"variables": vars}
+	if err := s.do(ctx, http.MethodPost, "/prompt-templates/"+url.PathEscape(name)+"/render", request, http.StatusOK, &response); err != nil {
+		return "", err
+	}
+	return response.Prompt, nil
+}
+
+// do sends body as JSON to path and decodes the response into out if it is not nil.
+func (s *HTTPPromptTemplateService) do(ctx context.Context, method, path string, body any, wantStatus int, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantStatus {
+		return apiframework.HandleAPIError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode prompt template response: %w", err)
+	}
+	return nil
+}
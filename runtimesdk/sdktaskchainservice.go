@@ -231,3 +231,79 @@ func (s *HTTPTaskChainService) List(ctx context.Context, cursor *time.Time, limi
 
 	return chains, nil
 }
+
+// ListVersions implements taskchainservice.Service.ListVersions
+func (s *HTTPTaskChainService) ListVersions(ctx context.Context, id string) ([]*taskchainservice.ChainVersion, error) {
+	if id == "" {
+		return nil, fmt.Errorf("task chain ID is required")
+	}
+
+	url := fmt.Sprintf("%s/taskchains/%s/versions", s.baseURL, url.PathEscape(id))
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set headers
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	// Execute request
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Handle non-200 responses
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiframework.HandleAPIError(resp)
+	}
+
+	// Decode response
+	var versions []*taskchainservice.ChainVersion
+	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
+		return nil, fmt.Errorf("failed to decode task chain versions response: %w", err)
+	}
+
+	return versions, nil
+}
+
+// Rollback implements taskchainservice.Service.Rollback
+func (s *HTTPTaskChainService) Rollback(ctx context.Context, id string, version int) (*taskengine.TaskChainDefinition, error) {
+	if id == "" {
+		return nil, fmt.Errorf("task chain ID is required")
+	}
+
+	url := fmt.Sprintf("%s/taskchains/%s/versions/%d/rollback", s.baseURL, url.PathEscape(id), version)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set headers
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	// Execute request
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Handle non-200 responses
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiframework.HandleAPIError(resp)
+	}
+
+	// Decode response
+	var chain taskengine.TaskChainDefinition
+	if err := json.NewDecoder(resp.Body).Decode(&chain); err != nil {
+		return nil, fmt.Errorf("failed to decode task chain response: %w", err)
+	}
+
+	return &chain, nil
+}
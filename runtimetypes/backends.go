@@ -22,12 +22,14 @@ func (s *store) CreateBackend(ctx context.Context, backend *Backend) error {
 	}
 	_, err := s.Exec.ExecContext(ctx, `
 		INSERT INTO llm_backends
-		(id, name, base_url, type, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)`,
+		(id, name, base_url, type, timeout, api_key, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
 		backend.ID,
 		backend.Name,
 		backend.BaseURL,
 		backend.Type,
+		backend.Timeout,
+		backend.EncryptedAPIKey,
 		backend.CreatedAt,
 		backend.UpdatedAt,
 	)
@@ -37,7 +39,7 @@ func (s *store) CreateBackend(ctx context.Context, backend *Backend) error {
 func (s *store) GetBackend(ctx context.Context, id string) (*Backend, error) {
 	var backend Backend
 	err := s.Exec.QueryRowContext(ctx, `
-		SELECT id, name, base_url, type, created_at, updated_at
+		SELECT id, name, base_url, type, timeout, api_key, created_at, updated_at
 		FROM llm_backends
 		WHERE id = $1`,
 		id,
@@ -46,6 +48,8 @@ func (s *store) GetBackend(ctx context.Context, id string) (*Backend, error) {
 		&backend.Name,
 		&backend.BaseURL,
 		&backend.Type,
+		&backend.Timeout,
+		&backend.EncryptedAPIKey,
 		&backend.CreatedAt,
 		&backend.UpdatedAt,
 	)
@@ -64,12 +68,14 @@ func (s *store) UpdateBackend(ctx context.Context, backend *Backend) error {
 		SET name = $2,
 			base_url = $3,
 			type = $4,
-			updated_at = $5
+			timeout = $5,
+			updated_at = $6
 		WHERE id = $1`,
 		backend.ID,
 		backend.Name,
 		backend.BaseURL,
 		backend.Type,
+		backend.Timeout,
 		backend.UpdatedAt,
 	)
 
@@ -80,6 +86,24 @@ func (s *store) UpdateBackend(ctx context.Context, backend *Backend) error {
 	return checkRowsAffected(result)
 }
 
+// SetBackendAPIKey replaces the stored, already encrypted API key of a backend.
+func (s *store) SetBackendAPIKey(ctx context.Context, id string, encryptedAPIKey string) error {
+	result, err := s.Exec.ExecContext(ctx, `
+		UPDATE llm_backends
+		SET api_key = $2,
+			updated_at = $3
+		WHERE id = $1`,
+		id,
+		encryptedAPIKey,
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update backend api key: %w", err)
+	}
+
+	return checkRowsAffected(result)
+}
+
 func (s *store) DeleteBackend(ctx context.Context, id string) error {
 	result, err := s.Exec.ExecContext(ctx, `
 		DELETE FROM llm_backends
@@ -96,7 +120,7 @@ func (s *store) DeleteBackend(ctx context.Context, id string) error {
 
 func (s *store) ListAllBackends(ctx context.Context) ([]*Backend, error) {
 	rows, err := s.Exec.QueryContext(ctx, `
-        SELECT id, name, base_url, type, created_at, updated_at
+        SELECT id, name, base_url, type, timeout, api_key, created_at, updated_at
         FROM llm_backends
         ORDER BY created_at DESC, id DESC;
     `)
@@ -113,6 +137,8 @@ func (s *store) ListAllBackends(ctx context.Context) ([]*Backend, error) {
 			&backend.Name,
 			&backend.BaseURL,
 			&backend.Type,
+			&backend.Timeout,
+			&backend.EncryptedAPIKey,
 			&backend.CreatedAt,
 			&backend.UpdatedAt,
 		); err != nil {
@@ -137,7 +163,7 @@ func (s *store) ListBackends(ctx context.Context, createdAtCursor *time.Time, li
 		return nil, ErrLimitParamExceeded
 	}
 	rows, err := s.Exec.QueryContext(ctx, `
-        SELECT id, name, base_url, type, created_at, updated_at
+        SELECT id, name, base_url, type, timeout, api_key, created_at, updated_at
         FROM llm_backends
         WHERE created_at < $1
         ORDER BY created_at DESC, id DESC
@@ -156,6 +182,8 @@ func (s *store) ListBackends(ctx context.Context, createdAtCursor *time.Time, li
 			&backend.Name,
 			&backend.BaseURL,
 			&backend.Type,
+			&backend.Timeout,
+			&backend.EncryptedAPIKey,
 			&backend.CreatedAt,
 			&backend.UpdatedAt,
 		); err != nil {
@@ -174,7 +202,7 @@ func (s *store) ListBackends(ctx context.Context, createdAtCursor *time.Time, li
 func (s *store) GetBackendByName(ctx context.Context, name string) (*Backend, error) {
 	var backend Backend
 	err := s.Exec.QueryRowContext(ctx, `
-		SELECT id, name, base_url, type, created_at, updated_at
+		SELECT id, name, base_url, type, timeout, api_key, created_at, updated_at
 		FROM llm_backends
 		WHERE name = $1`,
 		name,
@@ -183,6 +211,8 @@ func (s *store) GetBackendByName(ctx context.Context, name string) (*Backend, er
 		&backend.Name,
 		&backend.BaseURL,
 		&backend.Type,
+		&backend.Timeout,
+		&backend.EncryptedAPIKey,
 		&backend.CreatedAt,
 		&backend.UpdatedAt,
 	)
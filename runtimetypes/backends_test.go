@@ -1,6 +1,7 @@
 package runtimetypes_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"testing"
@@ -184,3 +185,25 @@ func TestUnit_Backend_GetNonexistentReturnsNotFound(t *testing.T) {
 	_, err = s.GetBackendByName(ctx, "non-existent-name")
 	require.ErrorIs(t, err, libdb.ErrNotFound)
 }
+
+func TestUnit_Backend_RedactHidesKeyMaterial(t *testing.T) {
+	backend := &runtimetypes.Backend{
+		ID:              "b1",
+		Name:            "vllm",
+		Type:            "vllm",
+		APIKey:          "sk-secret-key",
+		EncryptedAPIKey: "c2VhbGVk",
+	}
+	backend.Redact()
+
+	require.True(t, backend.HasAPIKey)
+	encoded, err := json.Marshal(backend)
+	require.NoError(t, err)
+	require.NotContains(t, string(encoded), "sk-secret-key")
+	require.NotContains(t, string(encoded), "c2VhbGVk")
+	require.Contains(t, string(encoded), `"hasApiKey":true`)
+
+	plain := &runtimetypes.Backend{ID: "b2"}
+	plain.Redact()
+	require.False(t, plain.HasAPIKey)
+}
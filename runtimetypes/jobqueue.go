@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/contenox/runtime/libtracker"
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 )
@@ -16,10 +17,13 @@ func (s *store) AppendJob(ctx context.Context, job Job) error {
 		job.ID = uuid.New().String()
 	}
 	job.CreatedAt = time.Now().UTC()
+	if job.RequestID == "" {
+		job.RequestID = requestIDFromContext(ctx)
+	}
 	_, err := s.Exec.ExecContext(ctx, `
 		INSERT INTO job_queue_v2
-		(id, task_type, payload, scheduled_for, valid_until, retry_count, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+		(id, task_type, payload, scheduled_for, valid_until, retry_count, created_at, request_id, dedup_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
 		job.ID,
 		job.TaskType,
 		job.Payload,
@@ -27,11 +31,52 @@ func (s *store) AppendJob(ctx context.Context, job Job) error {
 		job.ValidUntil,
 		job.RetryCount,
 		job.CreatedAt,
+		job.RequestID,
+		job.DedupKey,
 	)
 
 	return err
 }
 
+// AppendJobIfAbsent inserts job unless a queued job with the same dedupKey
+// exists, e.g. to ignore redelivered webhooks. It reports whether the job was
+// enqueued. The key is released once the job is popped.
+func (s *store) AppendJobIfAbsent(ctx context.Context, job Job, dedupKey string) (bool, error) {
+	if dedupKey == "" {
+		return false, fmt.Errorf("dedup key is required")
+	}
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	job.CreatedAt = time.Now().UTC()
+	if job.RequestID == "" {
+		job.RequestID = requestIDFromContext(ctx)
+	}
+	result, err := s.Exec.ExecContext(ctx, `
+		INSERT INTO job_queue_v2
+		(id, task_type, payload, scheduled_for, valid_until, retry_count, created_at, request_id, dedup_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (dedup_key) WHERE dedup_key <> '' DO NOTHING;`,
+		job.ID,
+		job.TaskType,
+		job.Payload,
+		job.ScheduledFor,
+		job.ValidUntil,
+		job.RetryCount,
+		job.CreatedAt,
+		job.RequestID,
+		dedupKey,
+	)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return inserted > 0, nil
+}
+
 func (s *store) AppendJobs(ctx context.Context, jobs ...*Job) error {
 	if len(jobs) == 0 {
 		return nil
@@ -40,16 +85,20 @@ func (s *store) AppendJobs(ctx context.Context, jobs ...*Job) error {
 		return ErrAppendLimitExceeded
 	}
 	now := time.Now().UTC()
+	requestID := requestIDFromContext(ctx)
 	valueStrings := make([]string, 0, len(jobs))
-	valueArgs := make([]interface{}, 0, len(jobs)*7)
+	valueArgs := make([]interface{}, 0, len(jobs)*9)
 
 	for i, job := range jobs {
 		job.CreatedAt = now
+		if job.RequestID == "" {
+			job.RequestID = requestID
+		}
 
-		// Build placeholders like ($1, $2, ..., $7)
-		startIdx := i*7 + 1
-		placeholders := make([]string, 7)
-		for j := 0; j < 7; j++ {
+		// Build placeholders like ($1, $2, ..., $9)
+		startIdx := i*9 + 1
+		placeholders := make([]string, 9)
+		for j := 0; j < 9; j++ {
 			placeholders[j] = fmt.Sprintf("$%d", startIdx+j)
 		}
 		valueStrings = append(valueStrings, "("+strings.Join(placeholders, ", ")+")")
@@ -63,12 +112,14 @@ func (s *store) AppendJobs(ctx context.Context, jobs ...*Job) error {
 			job.ValidUntil,
 			job.RetryCount,
 			job.CreatedAt,
+			job.RequestID,
+			job.DedupKey,
 		)
 	}
 
 	stmt := fmt.Sprintf(`
         INSERT INTO job_queue_v2
-        (id, task_type, payload, scheduled_for, valid_until, retry_count, created_at)
+        (id, task_type, payload, scheduled_for, valid_until, retry_count, created_at, request_id, dedup_key)
         VALUES %s`,
 		strings.Join(valueStrings, ","),
 	)
@@ -81,7 +132,7 @@ func (s *store) AppendJobs(ctx context.Context, jobs ...*Job) error {
 func (s *store) PopAllJobs(ctx context.Context) ([]*Job, error) {
 	query := `
 	DELETE FROM job_queue_v2
-	RETURNING id, task_type, payload, scheduled_for, valid_until, retry_count, created_at;
+	RETURNING id, task_type, payload, scheduled_for, valid_until, retry_count, created_at, request_id, dedup_key;
 	`
 	rows, err := s.Exec.QueryContext(ctx, query)
 	if err != nil {
@@ -92,7 +143,7 @@ func (s *store) PopAllJobs(ctx context.Context) ([]*Job, error) {
 	var jobs []*Job
 	for rows.Next() {
 		var job Job
-		if err := rows.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt); err != nil {
+		if err := rows.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt, &job.RequestID, &job.DedupKey); err != nil {
 			return nil, err
 		}
 		jobs = append(jobs, &job)
@@ -105,7 +156,7 @@ func (s *store) PopJobsForType(ctx context.Context, taskType string) ([]*Job, er
 	query := `
 	DELETE FROM job_queue_v2
 	WHERE task_type = $1
-	RETURNING id, task_type, payload, scheduled_for, valid_until, retry_count, created_at;
+	RETURNING id, task_type, payload, scheduled_for, valid_until, retry_count, created_at, request_id, dedup_key;
 	`
 	rows, err := s.Exec.QueryContext(ctx, query, taskType)
 	if err != nil {
@@ -116,7 +167,7 @@ func (s *store) PopJobsForType(ctx context.Context, taskType string) ([]*Job, er
 	var jobs []*Job
 	for rows.Next() {
 		var job Job
-		if err := rows.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt); err != nil {
+		if err := rows.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt, &job.RequestID, &job.DedupKey); err != nil {
 			return nil, err
 		}
 		jobs = append(jobs, &job)
@@ -130,12 +181,12 @@ func (s *store) PopJobForType(ctx context.Context, taskType string) (*Job, error
 	WHERE id = (
 		SELECT id FROM job_queue_v2 WHERE task_type = $1 ORDER BY created_at LIMIT 1
 	)
-	RETURNING id, task_type, payload, scheduled_for, valid_until, retry_count, created_at;
+	RETURNING id, task_type, payload, scheduled_for, valid_until, retry_count, created_at, request_id, dedup_key;
 	`
 	row := s.Exec.QueryRowContext(ctx, query, taskType)
 
 	var job Job
-	if err := row.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt); err != nil {
+	if err := row.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt, &job.RequestID, &job.DedupKey); err != nil {
 		return nil, err
 	}
 
@@ -151,7 +202,7 @@ func (s *store) PopNJobsForType(ctx context.Context, taskType string, n int) ([]
             ORDER BY created_at, id
             LIMIT $2
         )
-        RETURNING id, task_type, payload, scheduled_for, valid_until, retry_count, created_at;
+        RETURNING id, task_type, payload, scheduled_for, valid_until, retry_count, created_at, request_id, dedup_key;
     `
 	rows, err := s.Exec.QueryContext(ctx, query, taskType, n)
 	if err != nil {
@@ -162,7 +213,7 @@ func (s *store) PopNJobsForType(ctx context.Context, taskType string, n int) ([]
 	var jobs []*Job
 	for rows.Next() {
 		var job Job
-		if err := rows.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt); err != nil {
+		if err := rows.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt, &job.RequestID, &job.DedupKey); err != nil {
 			return nil, err
 		}
 		jobs = append(jobs, &job)
@@ -172,7 +223,7 @@ func (s *store) PopNJobsForType(ctx context.Context, taskType string, n int) ([]
 
 func (s *store) GetJobsForType(ctx context.Context, taskType string) ([]*Job, error) {
 	query := `
-		SELECT id, task_type, payload, scheduled_for, valid_until, retry_count, created_at
+		SELECT id, task_type, payload, scheduled_for, valid_until, retry_count, created_at, request_id, dedup_key
 		FROM job_queue_v2
 		WHERE task_type = $1
 		ORDER BY created_at;
@@ -186,7 +237,7 @@ func (s *store) GetJobsForType(ctx context.Context, taskType string) ([]*Job, er
 	var jobs []*Job
 	for rows.Next() {
 		var job Job
-		if err := rows.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt); err != nil {
+		if err := rows.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt, &job.RequestID, &job.DedupKey); err != nil {
 			return nil, err
 		}
 		jobs = append(jobs, &job)
@@ -196,7 +247,7 @@ func (s *store) GetJobsForType(ctx context.Context, taskType string) ([]*Job, er
 
 func (s *store) ListJobs(ctx context.Context, createdAtCursor *time.Time, limit int) ([]*Job, error) {
 	query := `
-		SELECT id, task_type, payload, scheduled_for, valid_until, retry_count, created_at
+		SELECT id, task_type, payload, scheduled_for, valid_until, retry_count, created_at, request_id, dedup_key
 		FROM job_queue_v2
 		WHERE created_at < $1
 		ORDER BY created_at DESC
@@ -215,7 +266,7 @@ func (s *store) ListJobs(ctx context.Context, createdAtCursor *time.Time, limit
 	var jobs []*Job
 	for rows.Next() {
 		var job Job
-		if err := rows.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt); err != nil {
+		if err := rows.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt, &job.RequestID, &job.DedupKey); err != nil {
 			return nil, err
 		}
 		jobs = append(jobs, &job)
@@ -223,6 +274,66 @@ func (s *store) ListJobs(ctx context.Context, createdAtCursor *time.Time, limit
 	return jobs, nil
 }
 
+// ListJobsForType works like ListJobs but only returns jobs of the given task type.
+func (s *store) ListJobsForType(ctx context.Context, taskType string, createdAtCursor *time.Time, limit int) ([]*Job, error) {
+	query := `
+		SELECT id, task_type, payload, scheduled_for, valid_until, retry_count, created_at, request_id, dedup_key
+		FROM job_queue_v2
+		WHERE task_type = $1 AND created_at < $2
+		ORDER BY created_at DESC
+		LIMIT $3;
+	`
+	cursor := time.Now().UTC()
+	if createdAtCursor != nil {
+		cursor = *createdAtCursor
+	}
+	rows, err := s.Exec.QueryContext(ctx, query, taskType, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		var job Job
+		if err := rows.Scan(&job.ID, &job.TaskType, &job.Payload, &job.ScheduledFor, &job.ValidUntil, &job.RetryCount, &job.CreatedAt, &job.RequestID, &job.DedupKey); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, rows.Err()
+}
+
+// CountJobsByType returns the exact number of queued jobs per task type.
+func (s *store) CountJobsByType(ctx context.Context) (map[string]int64, error) {
+	rows, err := s.Exec.QueryContext(ctx, `
+		SELECT task_type, COUNT(*)
+		FROM job_queue_v2
+		GROUP BY task_type;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var taskType string
+		var count int64
+		if err := rows.Scan(&taskType, &count); err != nil {
+			return nil, err
+		}
+		counts[taskType] = count
+	}
+	return counts, rows.Err()
+}
+
+// requestIDFromContext returns the request ID of the operation enqueuing a job, if any.
+func requestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(libtracker.ContextKeyRequestID).(string)
+	return requestID
+}
+
 func (s *store) EstimateJobCount(ctx context.Context) (int64, error) {
 	return s.estimateCount(ctx, "job_queue_v2")
 }
@@ -1,10 +1,12 @@
 package runtimetypes_test
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 	"time"
 
+	"github.com/contenox/runtime/libtracker"
 	"github.com/contenox/runtime/runtimetypes"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -332,3 +334,91 @@ func TestUnit_JobQueue_ListJobsPagination(t *testing.T) {
 		require.Empty(t, result, "ListJobs with limit 0 should return no jobs")
 	})
 }
+
+func TestUnit_JobQueue_ListJobsForTypeAndCounts(t *testing.T) {
+	ctx, s := runtimetypes.SetupStore(t)
+
+	var downloads []string
+	for range 3 {
+		job := newTestUnit_JobQueue_Job("model_download")
+		require.NoError(t, s.AppendJob(ctx, *job))
+		downloads = append(downloads, job.ID)
+		time.Sleep(time.Millisecond)
+	}
+	for range 2 {
+		require.NoError(t, s.AppendJob(ctx, *newTestUnit_JobQueue_Job("other")))
+	}
+
+	page, err := s.ListJobsForType(ctx, "model_download", nil, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, downloads[2], page[0].ID)
+	require.Equal(t, downloads[1], page[1].ID)
+
+	page, err = s.ListJobsForType(ctx, "model_download", &page[1].CreatedAt, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, downloads[0], page[0].ID)
+
+	counts, err := s.CountJobsByType(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"model_download": 3, "other": 2}, counts)
+}
+
+func TestUnit_JobQueue_PropagatesRequestID(t *testing.T) {
+	ctx, s := runtimetypes.SetupStore(t)
+	ctx = context.WithValue(ctx, libtracker.ContextKeyRequestID, "req-123")
+
+	require.NoError(t, s.AppendJob(ctx, runtimetypes.Job{
+		ID:       uuid.New().String(),
+		TaskType: "test-task",
+		Payload:  []byte(`{}`),
+	}))
+	require.NoError(t, s.AppendJobs(ctx,
+		&runtimetypes.Job{ID: uuid.New().String(), TaskType: "test-task", Payload: []byte(`{}`)},
+		&runtimetypes.Job{ID: uuid.New().String(), TaskType: "test-task", Payload: []byte(`{}`), RequestID: "explicit"},
+	))
+
+	jobs, err := s.PopAllJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 3)
+
+	var requestIDs []string
+	for _, job := range jobs {
+		requestIDs = append(requestIDs, job.RequestID)
+	}
+	require.ElementsMatch(t, []string{"req-123", "req-123", "explicit"}, requestIDs)
+}
+
+func TestUnit_JobQueue_AppendJobIfAbsent(t *testing.T) {
+	ctx, s := runtimetypes.SetupStore(t)
+
+	enqueued, err := s.AppendJobIfAbsent(ctx, *newTestUnit_JobQueue_Job("webhook"), "delivery-1")
+	require.NoError(t, err)
+	require.True(t, enqueued)
+
+	enqueued, err = s.AppendJobIfAbsent(ctx, *newTestUnit_JobQueue_Job("webhook"), "delivery-1")
+	require.NoError(t, err)
+	require.False(t, enqueued, "redelivery must not enqueue a second job")
+
+	enqueued, err = s.AppendJobIfAbsent(ctx, *newTestUnit_JobQueue_Job("webhook"), "delivery-2")
+	require.NoError(t, err)
+	require.True(t, enqueued)
+
+	jobs, err := s.PopJobsForType(ctx, "webhook")
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	var keys []string
+	for _, job := range jobs {
+		keys = append(keys, job.DedupKey)
+	}
+	require.ElementsMatch(t, []string{"delivery-1", "delivery-2"}, keys)
+
+	// Once the job was popped, the key can be used again.
+	enqueued, err = s.AppendJobIfAbsent(ctx, *newTestUnit_JobQueue_Job("webhook"), "delivery-1")
+	require.NoError(t, err)
+	require.True(t, enqueued)
+
+	_, err = s.AppendJobIfAbsent(ctx, *newTestUnit_JobQueue_Job("webhook"), "")
+	require.Error(t, err)
+}
@@ -27,6 +27,22 @@ func (s *store) SetKV(ctx context.Context, key string, value json.RawMessage) er
 	return err
 }
 
+// InsertKV stores a new key-value pair. It fails with libdb.ErrUniqueViolation
+// if the key exists, so concurrent inserts of the same key cannot overwrite each other.
+func (s *store) InsertKV(ctx context.Context, key string, value json.RawMessage) error {
+	now := time.Now().UTC()
+
+	_, err := s.Exec.ExecContext(ctx, `
+		INSERT INTO kv (key, value, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)`,
+		key,
+		value,
+		now,
+		now,
+	)
+	return err
+}
+
 func (s *store) UpdateKV(ctx context.Context, key string, value json.RawMessage) error {
 	now := time.Now().UTC()
 
@@ -336,6 +336,20 @@ func TestUnitKV(t *testing.T) {
 		require.JSONEq(t, string(value), string(kv.Value))
 	})
 
+	t.Run("Insert", func(t *testing.T) {
+		key := "insert-" + uuid.NewString()
+		err := s.InsertKV(ctx, key, json.RawMessage(`{"field1": "first", "field2": 1}`))
+		require.NoError(t, err)
+		defer s.DeleteKV(ctx, key)
+
+		err = s.InsertKV(ctx, key, json.RawMessage(`{"field1": "second", "field2": 2}`))
+		require.ErrorIs(t, err, libdb.ErrUniqueViolation)
+
+		var tv testValue
+		require.NoError(t, s.GetKV(ctx, key, &tv))
+		require.Equal(t, testValue{Field1: "first", Field2: 1}, tv)
+	})
+
 	t.Run("Upsert", func(t *testing.T) {
 		key := "upsert-" + uuid.NewString()
 		initial := json.RawMessage(`{"field1": "initial", "field2": 1}`)
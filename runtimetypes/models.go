@@ -3,6 +3,8 @@ package runtimetypes
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -23,8 +25,8 @@ func (s *store) AppendModel(ctx context.Context, model *Model) error {
 	}
 	_, err := s.Exec.ExecContext(ctx, `
 		INSERT INTO ollama_models
-		(id, model, context_length, can_chat, can_embed, can_prompt, can_stream, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		(id, model, context_length, can_chat, can_embed, can_prompt, can_stream, default_params, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
 		model.ID,
 		model.Model,
 		model.ContextLength,
@@ -32,6 +34,7 @@ func (s *store) AppendModel(ctx context.Context, model *Model) error {
 		model.CanEmbed,
 		model.CanPrompt,
 		model.CanStream,
+		model.DefaultParams,
 		model.CreatedAt,
 		model.UpdatedAt,
 	)
@@ -41,7 +44,7 @@ func (s *store) AppendModel(ctx context.Context, model *Model) error {
 func (s *store) GetModel(ctx context.Context, id string) (*Model, error) {
 	var model Model
 	err := s.Exec.QueryRowContext(ctx, `
-        SELECT id, model, context_length, can_chat, can_embed, can_prompt, can_stream, created_at, updated_at
+        SELECT id, model, context_length, can_chat, can_embed, can_prompt, can_stream, default_params, created_at, updated_at
         FROM ollama_models
         WHERE id = $1`,
 		id,
@@ -53,6 +56,7 @@ func (s *store) GetModel(ctx context.Context, id string) (*Model, error) {
 		&model.CanEmbed,
 		&model.CanPrompt,
 		&model.CanStream,
+		&model.DefaultParams,
 		&model.CreatedAt,
 		&model.UpdatedAt,
 	)
@@ -66,7 +70,7 @@ func (s *store) GetModel(ctx context.Context, id string) (*Model, error) {
 func (s *store) GetModelByName(ctx context.Context, name string) (*Model, error) {
 	var model Model
 	err := s.Exec.QueryRowContext(ctx, `
-        SELECT id, model, context_length, can_chat, can_embed, can_prompt, can_stream, created_at, updated_at
+        SELECT id, model, context_length, can_chat, can_embed, can_prompt, can_stream, default_params, created_at, updated_at
         FROM ollama_models
         WHERE model = $1`,
 		name,
@@ -78,6 +82,7 @@ func (s *store) GetModelByName(ctx context.Context, name string) (*Model, error)
 		&model.CanEmbed,
 		&model.CanPrompt,
 		&model.CanStream,
+		&model.DefaultParams,
 		&model.CreatedAt,
 		&model.UpdatedAt,
 	)
@@ -104,7 +109,7 @@ func (s *store) DeleteModel(ctx context.Context, modelName string) error {
 
 func (s *store) ListAllModels(ctx context.Context) ([]*Model, error) {
 	rows, err := s.Exec.QueryContext(ctx, `
-        SELECT id, model, context_length, can_chat, can_embed, can_prompt, can_stream, created_at, updated_at
+        SELECT id, model, context_length, can_chat, can_embed, can_prompt, can_stream, default_params, created_at, updated_at
         FROM ollama_models
         ORDER BY created_at DESC, id DESC;
     `)
@@ -124,6 +129,7 @@ func (s *store) ListAllModels(ctx context.Context) ([]*Model, error) {
 			&model.CanEmbed,
 			&model.CanPrompt,
 			&model.CanStream,
+			&model.DefaultParams,
 			&model.CreatedAt,
 			&model.UpdatedAt,
 		); err != nil {
@@ -158,7 +164,8 @@ func (s *store) UpdateModel(ctx context.Context, data *Model) error {
 			can_embed = $5,
 			can_prompt = $6,
 			can_stream = $7,
-			updated_at = $8
+			default_params = $8,
+			updated_at = $9
 		WHERE id = $1`,
 		data.ID,
 		data.Model,
@@ -167,6 +174,7 @@ func (s *store) UpdateModel(ctx context.Context, data *Model) error {
 		data.CanEmbed,
 		data.CanPrompt,
 		data.CanStream,
+		data.DefaultParams,
 		data.UpdatedAt,
 	)
 
@@ -186,7 +194,7 @@ func (s *store) ListModels(ctx context.Context, createdAtCursor *time.Time, limi
 		return nil, ErrLimitParamExceeded
 	}
 	rows, err := s.Exec.QueryContext(ctx, `
-        SELECT id, model, context_length, can_chat, can_embed, can_prompt, can_stream, created_at, updated_at
+        SELECT id, model, context_length, can_chat, can_embed, can_prompt, can_stream, default_params, created_at, updated_at
         FROM ollama_models
         WHERE created_at < $1
         ORDER BY created_at DESC, id DESC
@@ -208,6 +216,7 @@ func (s *store) ListModels(ctx context.Context, createdAtCursor *time.Time, limi
 			&model.CanEmbed,
 			&model.CanPrompt,
 			&model.CanStream,
+			&model.DefaultParams,
 			&model.CreatedAt,
 			&model.UpdatedAt,
 		); err != nil {
@@ -226,3 +235,23 @@ func (s *store) ListModels(ctx context.Context, createdAtCursor *time.Time, limi
 func (s *store) EstimateModelCount(ctx context.Context) (int64, error) {
 	return s.estimateCount(ctx, "ollama_models")
 }
+
+// Value stores the parameters as a JSON object.
+func (p ModelParams) Value() (driver.Value, error) {
+	b, err := json.Marshal(p)
+	return string(b), err
+}
+
+func (p *ModelParams) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*p = ModelParams{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("unsupported type %T for model params", src)
+	}
+}
@@ -266,3 +266,26 @@ func TestUnit_Models_InvalidContextLength(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "context length cannot be zero")
 }
+
+func TestUnit_Models_DefaultParams(t *testing.T) {
+	ctx, s := runtimetypes.SetupStore(t)
+
+	temperature := 0.0
+	model := &runtimetypes.Model{
+		Model:         "qwen2.5-coder:7b",
+		ContextLength: 8192,
+		CanChat:       true,
+		DefaultParams: runtimetypes.ModelParams{Temperature: &temperature, Stop: []string{"</code>"}},
+	}
+	require.NoError(t, s.AppendModel(ctx, model))
+
+	got, err := s.GetModel(ctx, model.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.DefaultParams, got.DefaultParams)
+
+	got.DefaultParams = runtimetypes.ModelParams{MaxTokens: 512}
+	require.NoError(t, s.UpdateModel(ctx, got))
+	got, err = s.GetModelByName(ctx, model.Model)
+	require.NoError(t, err)
+	require.Equal(t, runtimetypes.ModelParams{MaxTokens: 512}, got.DefaultParams)
+}
@@ -3,6 +3,8 @@ package runtimetypes
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -20,9 +22,9 @@ func (s *store) CreatePool(ctx context.Context, pool *Pool) error {
 	}
 	_, err := s.Exec.ExecContext(ctx, `
 		INSERT INTO llm_pool
-		(id, name, purpose_type, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)`,
-		pool.ID, pool.Name, pool.PurposeType, pool.CreatedAt, pool.UpdatedAt,
+		(id, name, purpose_type, max_in_flight, admission, keep_warm, capability, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		pool.ID, pool.Name, pool.PurposeType, pool.MaxInFlight, pool.Admission, stringList(pool.KeepWarm), pool.Capability, pool.CreatedAt, pool.UpdatedAt,
 	)
 	return err
 }
@@ -30,9 +32,9 @@ func (s *store) CreatePool(ctx context.Context, pool *Pool) error {
 func (s *store) GetPool(ctx context.Context, id string) (*Pool, error) {
 	var pool Pool
 	err := s.Exec.QueryRowContext(ctx, `
-		SELECT id, name, purpose_type, created_at, updated_at
+		SELECT id, name, purpose_type, max_in_flight, admission, keep_warm, capability, created_at, updated_at
 		FROM llm_pool WHERE id = $1`, id,
-	).Scan(&pool.ID, &pool.Name, &pool.PurposeType, &pool.CreatedAt, &pool.UpdatedAt)
+	).Scan(&pool.ID, &pool.Name, &pool.PurposeType, &pool.MaxInFlight, &pool.Admission, (*stringList)(&pool.KeepWarm), &pool.Capability, &pool.CreatedAt, &pool.UpdatedAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, libdb.ErrNotFound
@@ -43,9 +45,9 @@ func (s *store) GetPool(ctx context.Context, id string) (*Pool, error) {
 func (s *store) GetPoolByName(ctx context.Context, name string) (*Pool, error) {
 	var pool Pool
 	err := s.Exec.QueryRowContext(ctx, `
-		SELECT id, name, purpose_type, created_at, updated_at
+		SELECT id, name, purpose_type, max_in_flight, admission, keep_warm, capability, created_at, updated_at
 		FROM llm_pool WHERE name = $1`, name,
-	).Scan(&pool.ID, &pool.Name, &pool.PurposeType, &pool.CreatedAt, &pool.UpdatedAt)
+	).Scan(&pool.ID, &pool.Name, &pool.PurposeType, &pool.MaxInFlight, &pool.Admission, (*stringList)(&pool.KeepWarm), &pool.Capability, &pool.CreatedAt, &pool.UpdatedAt)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, libdb.ErrNotFound
@@ -58,9 +60,9 @@ func (s *store) UpdatePool(ctx context.Context, pool *Pool) error {
 
 	result, err := s.Exec.ExecContext(ctx, `
 		UPDATE llm_pool SET
-		name = $2, purpose_type = $3, updated_at = $4
+		name = $2, purpose_type = $3, max_in_flight = $4, admission = $5, keep_warm = $6, capability = $7, updated_at = $8
 		WHERE id = $1`,
-		pool.ID, pool.Name, pool.PurposeType, pool.UpdatedAt,
+		pool.ID, pool.Name, pool.PurposeType, pool.MaxInFlight, pool.Admission, stringList(pool.KeepWarm), pool.Capability, pool.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update pool: %w", err)
@@ -80,7 +82,7 @@ func (s *store) DeletePool(ctx context.Context, id string) error {
 
 func (s *store) ListAllPools(ctx context.Context) ([]*Pool, error) {
 	rows, err := s.Exec.QueryContext(ctx, `
-        SELECT id, name, purpose_type, created_at, updated_at
+        SELECT id, name, purpose_type, max_in_flight, admission, keep_warm, capability, created_at, updated_at
         FROM llm_pool
         ORDER BY created_at DESC, id DESC;
     `)
@@ -96,6 +98,10 @@ func (s *store) ListAllPools(ctx context.Context) ([]*Pool, error) {
 			&pool.ID,
 			&pool.Name,
 			&pool.PurposeType,
+			&pool.MaxInFlight,
+			&pool.Admission,
+			(*stringList)(&pool.KeepWarm),
+			&pool.Capability,
 			&pool.CreatedAt,
 			&pool.UpdatedAt,
 		); err != nil {
@@ -121,7 +127,7 @@ func (s *store) ListPools(ctx context.Context, createdAtCursor *time.Time, limit
 		return nil, ErrLimitParamExceeded
 	}
 	rows, err := s.Exec.QueryContext(ctx, `
-        SELECT id, name, purpose_type, created_at, updated_at
+        SELECT id, name, purpose_type, max_in_flight, admission, keep_warm, capability, created_at, updated_at
         FROM llm_pool
         WHERE created_at < $1
         ORDER BY created_at DESC, id DESC
@@ -135,7 +141,7 @@ func (s *store) ListPools(ctx context.Context, createdAtCursor *time.Time, limit
 	var pools []*Pool
 	for rows.Next() {
 		var pool Pool
-		if err := rows.Scan(&pool.ID, &pool.Name, &pool.PurposeType, &pool.CreatedAt, &pool.UpdatedAt); err != nil {
+		if err := rows.Scan(&pool.ID, &pool.Name, &pool.PurposeType, &pool.MaxInFlight, &pool.Admission, (*stringList)(&pool.KeepWarm), &pool.Capability, &pool.CreatedAt, &pool.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan pool: %w", err)
 		}
 		pools = append(pools, &pool)
@@ -160,7 +166,7 @@ func (s *store) ListPoolsByPurpose(ctx context.Context, purposeType string, crea
 	}
 
 	rows, err := s.Exec.QueryContext(ctx, `
-        SELECT id, name, purpose_type, created_at, updated_at
+        SELECT id, name, purpose_type, max_in_flight, admission, keep_warm, capability, created_at, updated_at
         FROM llm_pool WHERE purpose_type = $1 AND created_at < $2
         ORDER BY created_at DESC, id DESC
         LIMIT $3`,
@@ -173,7 +179,7 @@ func (s *store) ListPoolsByPurpose(ctx context.Context, purposeType string, crea
 	var pools []*Pool
 	for rows.Next() {
 		var pool Pool
-		if err := rows.Scan(&pool.ID, &pool.Name, &pool.PurposeType, &pool.CreatedAt, &pool.UpdatedAt); err != nil {
+		if err := rows.Scan(&pool.ID, &pool.Name, &pool.PurposeType, &pool.MaxInFlight, &pool.Admission, (*stringList)(&pool.KeepWarm), &pool.Capability, &pool.CreatedAt, &pool.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan pool: %w", err)
 		}
 		pools = append(pools, &pool)
@@ -209,7 +215,7 @@ func (s *store) RemoveBackendFromPool(ctx context.Context, poolID, backendID str
 
 func (s *store) ListBackendsForPool(ctx context.Context, poolID string) ([]*Backend, error) {
 	rows, err := s.Exec.QueryContext(ctx, `
-		SELECT b.id, b.name, b.base_url, b.type, b.created_at, b.updated_at
+		SELECT b.id, b.name, b.base_url, b.type, b.timeout, b.api_key, b.created_at, b.updated_at
 		FROM llm_backends b
 		INNER JOIN llm_pool_backend_assignments a ON b.id = a.backend_id
 		WHERE a.pool_id = $1
@@ -222,7 +228,7 @@ func (s *store) ListBackendsForPool(ctx context.Context, poolID string) ([]*Back
 	var backends []*Backend
 	for rows.Next() {
 		var b Backend
-		if err := rows.Scan(&b.ID, &b.Name, &b.BaseURL, &b.Type, &b.CreatedAt, &b.UpdatedAt); err != nil {
+		if err := rows.Scan(&b.ID, &b.Name, &b.BaseURL, &b.Type, &b.Timeout, &b.EncryptedAPIKey, &b.CreatedAt, &b.UpdatedAt); err != nil {
 			return nil, err
 		}
 		backends = append(backends, &b)
@@ -239,7 +245,7 @@ func (s *store) ListBackendsForPool(ctx context.Context, poolID string) ([]*Back
 
 func (s *store) ListPoolsForBackend(ctx context.Context, backendID string) ([]*Pool, error) {
 	rows, err := s.Exec.QueryContext(ctx, `
-		SELECT p.id, p.name, p.purpose_type, p.created_at, p.updated_at
+		SELECT p.id, p.name, p.purpose_type, p.max_in_flight, p.admission, p.keep_warm, p.capability, p.created_at, p.updated_at
 		FROM llm_pool p
 		INNER JOIN llm_pool_backend_assignments a ON p.id = a.pool_id
 		WHERE a.backend_id = $1
@@ -252,7 +258,7 @@ func (s *store) ListPoolsForBackend(ctx context.Context, backendID string) ([]*P
 	var pools []*Pool
 	for rows.Next() {
 		var p Pool
-		if err := rows.Scan(&p.ID, &p.Name, &p.PurposeType, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.PurposeType, &p.MaxInFlight, &p.Admission, (*stringList)(&p.KeepWarm), &p.Capability, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, err
 		}
 		pools = append(pools, &p)
@@ -288,7 +294,7 @@ func (s *store) RemoveModelFromPool(ctx context.Context, poolID, modelID string)
 
 func (s *store) ListModelsForPool(ctx context.Context, poolID string) ([]*Model, error) {
 	rows, err := s.Exec.QueryContext(ctx, `
-        SELECT m.id, m.model, m.context_length, m.can_chat, m.can_embed, m.can_prompt, m.can_stream, m.created_at, m.updated_at
+        SELECT m.id, m.model, m.context_length, m.can_chat, m.can_embed, m.can_prompt, m.can_stream, m.default_params, m.created_at, m.updated_at
         FROM ollama_models m
         INNER JOIN ollama_model_assignments a ON m.id = a.model_id
         WHERE a.llm_pool_id = $1
@@ -309,6 +315,7 @@ func (s *store) ListModelsForPool(ctx context.Context, poolID string) ([]*Model,
 			&m.CanEmbed,
 			&m.CanPrompt,
 			&m.CanStream,
+			&m.DefaultParams,
 			&m.CreatedAt,
 			&m.UpdatedAt,
 		); err != nil {
@@ -328,7 +335,7 @@ func (s *store) ListModelsForPool(ctx context.Context, poolID string) ([]*Model,
 
 func (s *store) ListPoolsForModel(ctx context.Context, modelID string) ([]*Pool, error) {
 	rows, err := s.Exec.QueryContext(ctx, `
-		SELECT p.id, p.name, p.purpose_type, p.created_at, p.updated_at
+		SELECT p.id, p.name, p.purpose_type, p.max_in_flight, p.admission, p.keep_warm, p.capability, p.created_at, p.updated_at
 		FROM llm_pool p
 		INNER JOIN ollama_model_assignments a ON p.id = a.llm_pool_id
 		WHERE a.model_id = $1
@@ -341,7 +348,7 @@ func (s *store) ListPoolsForModel(ctx context.Context, modelID string) ([]*Pool,
 	var pools []*Pool
 	for rows.Next() {
 		var p Pool
-		if err := rows.Scan(&p.ID, &p.Name, &p.PurposeType, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.PurposeType, &p.MaxInFlight, &p.Admission, (*stringList)(&p.KeepWarm), &p.Capability, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, err
 		}
 		pools = append(pools, &p)
@@ -359,3 +366,28 @@ func (s *store) ListPoolsForModel(ctx context.Context, modelID string) ([]*Pool,
 func (s *store) EstimatePoolCount(ctx context.Context) (int64, error) {
 	return s.estimateCount(ctx, "llm_pool")
 }
+
+// stringList stores a []string column as a JSON array.
+type stringList []string
+
+func (l stringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(l))
+	return string(b), err
+}
+
+func (l *stringList) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("unsupported type %T for string list", src)
+	}
+}
@@ -56,9 +56,23 @@ CREATE TABLE IF NOT EXISTS job_queue_v2 (
     scheduled_for INT,
     valid_until INT,
     retry_count INT NOT NULL DEFAULT 0,
-    created_at TIMESTAMP NOT NULL
+    created_at TIMESTAMP NOT NULL,
+    request_id VARCHAR(255) NOT NULL DEFAULT ''
 );
 
+ALTER TABLE ollama_models ADD COLUMN IF NOT EXISTS default_params JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE job_queue_v2 ADD COLUMN IF NOT EXISTS request_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE job_queue_v2 ADD COLUMN IF NOT EXISTS dedup_key VARCHAR(512) NOT NULL DEFAULT '';
+
+ALTER TABLE llm_backends ADD COLUMN IF NOT EXISTS timeout VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE llm_backends ADD COLUMN IF NOT EXISTS api_key TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE llm_pool ADD COLUMN IF NOT EXISTS max_in_flight INT NOT NULL DEFAULT 0;
+ALTER TABLE llm_pool ADD COLUMN IF NOT EXISTS admission VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE llm_pool ADD COLUMN IF NOT EXISTS keep_warm JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE llm_pool ADD COLUMN IF NOT EXISTS capability VARCHAR(32) NOT NULL DEFAULT '';
+
 CREATE TABLE IF NOT EXISTS entity_events (
     id VARCHAR(255) PRIMARY KEY,
     entity_id VARCHAR(255) NOT NULL,
@@ -88,6 +102,7 @@ CREATE TABLE IF NOT EXISTS remote_hooks (
 
 
 CREATE INDEX IF NOT EXISTS idx_job_queue_v2_task_type ON job_queue_v2 USING hash(task_type);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_queue_v2_dedup_key ON job_queue_v2 (dedup_key) WHERE dedup_key <> '';
 
 CREATE OR REPLACE FUNCTION estimate_row_count(table_name TEXT)
 RETURNS BIGINT AS $$
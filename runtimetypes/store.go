@@ -38,11 +38,30 @@ type Backend struct {
 	Name    string `json:"name" example:"ollama-production"`
 	BaseURL string `json:"baseUrl" example:"http://ollama-prod.internal:11434"`
 	Type    string `json:"type" example:"ollama"`
+	// Timeout bounds each model call to the backend (e.g. "90s"), including connecting.
+	// Streamed responses are bounded until the backend starts responding and may take
+	// longer in total. Empty uses the server-wide default.
+	Timeout string `json:"timeout,omitempty" example:"90s"`
+	// APIKey authenticates requests to the backend, e.g. for OpenAI-compatible endpoints.
+	// It is write-only: it is stored encrypted and never returned; HasAPIKey reports
+	// whether one is set. Updates without an APIKey keep the current key.
+	APIKey    string `json:"apiKey,omitempty" example:"sk-..."`
+	HasAPIKey bool   `json:"hasApiKey" example:"true"`
+	// EncryptedAPIKey is the stored form of APIKey.
+	EncryptedAPIKey string `json:"-"`
 
 	CreatedAt time.Time `json:"createdAt" example:"2023-11-15T14:30:45Z"`
 	UpdatedAt time.Time `json:"updatedAt" example:"2023-11-15T14:30:45Z"`
 }
 
+// Redact strips key material from the backend before it leaves a service,
+// keeping only whether a key is set in HasAPIKey.
+func (b *Backend) Redact() {
+	b.HasAPIKey = b.EncryptedAPIKey != ""
+	b.APIKey = ""
+	b.EncryptedAPIKey = ""
+}
+
 type Model struct {
 	ID            string    `json:"id" example:"m7d8e9f0a-1b2c-3d4e-5f6a-7b8c9d0e1f2a"`
 	Model         string    `json:"model" example:"mistral:instruct"`
@@ -53,12 +72,72 @@ type Model struct {
 	CanStream     bool      `json:"canStream" example:"true"`
 	CreatedAt     time.Time `json:"createdAt" example:"2023-11-15T14:30:45Z"`
 	UpdatedAt     time.Time `json:"updatedAt" example:"2023-11-15T14:30:45Z"`
+
+	// DefaultParams are applied to requests for this model that leave them unset.
+	DefaultParams ModelParams `json:"defaultParams" openapi_include_type:"runtimetypes.ModelParams"`
+}
+
+// ModelParams are per-model sampling defaults. They take precedence over the
+// defaults of the model's provider, and parameters set on a request take
+// precedence over them. Zero values are unset, except for Temperature, where
+// nil is unset so that a default of 0 can be declared.
+type ModelParams struct {
+	Temperature *float64 `json:"temperature,omitempty" example:"0.2"`
+	TopP        float64  `json:"topP,omitempty" example:"0.9"`
+	MaxTokens   int      `json:"maxTokens,omitempty" example:"1024"`
+	Stop        []string `json:"stop,omitempty" example:"[\"</code>\"]"`
+}
+
+// Model capabilities a pool can require of the models assigned to it.
+const (
+	CapabilityChat   = "chat"
+	CapabilityEmbed  = "embed"
+	CapabilityPrompt = "prompt"
+	CapabilityStream = "stream"
+)
+
+// ValidCapability reports whether capability is empty or a known model capability.
+func ValidCapability(capability string) bool {
+	switch capability {
+	case "", CapabilityChat, CapabilityEmbed, CapabilityPrompt, CapabilityStream:
+		return true
+	}
+	return false
+}
+
+// Supports reports whether the model declares capability. Every model supports
+// the empty capability.
+func (m *Model) Supports(capability string) bool {
+	switch capability {
+	case "":
+		return true
+	case CapabilityChat:
+		return m.CanChat
+	case CapabilityEmbed:
+		return m.CanEmbed
+	case CapabilityPrompt:
+		return m.CanPrompt
+	case CapabilityStream:
+		return m.CanStream
+	}
+	return false
 }
 
 type Pool struct {
 	ID          string `json:"id" example:"p9a8b7c6-d5e4-f3a2-b1c0-d9e8f7a6b5c4"`
 	Name        string `json:"name" example:"production-chat"`
 	PurposeType string `json:"purposeType" example:"Internal Tasks"`
+	// MaxInFlight caps concurrent model calls dispatched to the pool's backends; 0 means unlimited.
+	MaxInFlight int `json:"maxInFlight,omitempty" example:"8"`
+	// Admission decides what happens to calls beyond MaxInFlight:
+	// "reject" (default) fails them immediately, "queue" waits briefly for a free slot.
+	Admission string `json:"admission,omitempty" example:"queue"`
+	// KeepWarm lists models that are periodically loaded on the pool's Ollama
+	// backends so they are not unloaded while idle.
+	KeepWarm []string `json:"keepWarm,omitempty" example:"[\"mistral:instruct\"]"`
+	// Capability, if set, is required of every model assigned to the pool:
+	// "chat", "embed", "prompt" or "stream".
+	Capability string `json:"capability,omitempty" example:"chat"`
 
 	CreatedAt time.Time `json:"createdAt" example:"2023-11-15T14:30:45Z"`
 	UpdatedAt time.Time `json:"updatedAt" example:"2023-11-15T14:30:45Z"`
@@ -72,6 +151,11 @@ type Job struct {
 	ValidUntil   int64     `json:"validUntil" example:"1717024400"`
 	RetryCount   int       `json:"retryCount" example:"0"`
 	CreatedAt    time.Time `json:"createdAt" example:"2023-11-15T14:30:45Z"`
+	// RequestID correlates the job with the request that enqueued it.
+	RequestID string `json:"requestId,omitempty" example:"c1d2e3f4-a5b6-7c8d-9e0f-1a2b3c4d5e6f"`
+	// DedupKey identifies the event that caused the job, like an external
+	// message ID. At most one queued job can carry a given non-empty key.
+	DedupKey string `json:"dedupKey,omitempty" example:"github-delivery-72d3162e"`
 }
 
 // KV represents a key-value pair in the database
@@ -96,6 +180,7 @@ type Store interface {
 	CreateBackend(ctx context.Context, backend *Backend) error
 	GetBackend(ctx context.Context, id string) (*Backend, error)
 	UpdateBackend(ctx context.Context, backend *Backend) error
+	SetBackendAPIKey(ctx context.Context, id string, encryptedAPIKey string) error
 	DeleteBackend(ctx context.Context, id string) error
 	ListAllBackends(ctx context.Context) ([]*Backend, error)
 	ListBackends(ctx context.Context, createdAtCursor *time.Time, limit int) ([]*Backend, error)
@@ -133,15 +218,19 @@ type Store interface {
 
 	AppendJob(ctx context.Context, job Job) error
 	AppendJobs(ctx context.Context, jobs ...*Job) error
+	AppendJobIfAbsent(ctx context.Context, job Job, dedupKey string) (bool, error)
 	PopAllJobs(ctx context.Context) ([]*Job, error)
 	PopJobsForType(ctx context.Context, taskType string) ([]*Job, error)
 	PopNJobsForType(ctx context.Context, taskType string, n int) ([]*Job, error)
 	PopJobForType(ctx context.Context, taskType string) (*Job, error)
 	GetJobsForType(ctx context.Context, taskType string) ([]*Job, error)
 	ListJobs(ctx context.Context, createdAtCursor *time.Time, limit int) ([]*Job, error)
+	ListJobsForType(ctx context.Context, taskType string, createdAtCursor *time.Time, limit int) ([]*Job, error)
+	CountJobsByType(ctx context.Context) (map[string]int64, error)
 	EstimateJobCount(ctx context.Context) (int64, error)
 
 	SetKV(ctx context.Context, key string, value json.RawMessage) error
+	InsertKV(ctx context.Context, key string, value json.RawMessage) error
 	UpdateKV(ctx context.Context, key string, value json.RawMessage) error
 	GetKV(ctx context.Context, key string, out interface{}) error
 	DeleteKV(ctx context.Context, key string) error
@@ -33,6 +33,8 @@ type ModelPullStatus struct {
 	CanEmbed      bool         `json:"canEmbed" example:"false"`
 	CanPrompt     bool         `json:"canPrompt" example:"true"`
 	CanStream     bool         `json:"canStream" example:"true"`
+	// DefaultParams are the sampling defaults declared for the model.
+	DefaultParams runtimetypes.ModelParams `json:"defaultParams" openapi_include_type:"runtimetypes.ModelParams"`
 }
 
 type ModelDetails struct {
@@ -0,0 +1,161 @@
+package taskchainservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/contenox/runtime/taskengine"
+)
+
+const (
+	// DefaultCacheTTL bounds how long a cached chain may lag behind edits made by other processes.
+	DefaultCacheTTL = 30 * time.Second
+	// DefaultCacheSize is the maximum number of chains held in the cache.
+	DefaultCacheSize = 256
+)
+
+type cachedChain struct {
+	// Chains are cached serialized, execution mutates the definitions it is given.
+	value   json.RawMessage
+	expires time.Time
+}
+
+type cacheDecorator struct {
+	service Service
+	ttl     time.Duration
+	size    int
+	now     func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]cachedChain
+	// generations counts the invalidations per chain ID, so that a read that
+	// started before a write does not put the old definition back.
+	generations map[string]uint64
+}
+
+// WithCache fronts Get with an in-memory cache keyed by chain ID. Writes through the
+// returned service invalidate the affected entry immediately; edits made by other
+// processes become visible once the entry's ttl expires. Non-positive ttl or size
+// select DefaultCacheTTL and DefaultCacheSize.
+func WithCache(service Service, ttl time.Duration, size int) Service {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &cacheDecorator{
+		service:     service,
+		ttl:         ttl,
+		size:        size,
+		now:         time.Now,
+		entries:     make(map[string]cachedChain),
+		generations: make(map[string]uint64),
+	}
+}
+
+func (c *cacheDecorator) Get(ctx context.Context, id string) (*taskengine.TaskChainDefinition, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[id]
+	generation := c.generations[id]
+	c.mu.RUnlock()
+	if ok && c.now().Before(entry.expires) {
+		var chain taskengine.TaskChainDefinition
+		if err := json.Unmarshal(entry.value, &chain); err != nil {
+			return nil, fmt.Errorf("failed to decode cached task chain: %w", err)
+		}
+		return &chain, nil
+	}
+
+	chain, err := c.service.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(chain)
+	if err != nil {
+		return chain, nil
+	}
+	c.store(id, value, generation)
+	return chain, nil
+}
+
+// store caches value unless id was invalidated since generation was read.
+func (c *cacheDecorator) store(id string, value json.RawMessage, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[id] != generation {
+		return
+	}
+
+	now := c.now()
+	if _, ok := c.entries[id]; !ok && len(c.entries) >= c.size {
+		// Drop expired entries first, then the entry closest to expiry.
+		oldest := ""
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+				continue
+			}
+			if oldest == "" || entry.expires.Before(c.entries[oldest].expires) {
+				oldest = key
+			}
+		}
+		if len(c.entries) >= c.size {
+			delete(c.entries, oldest)
+		}
+	}
+	c.entries[id] = cachedChain{value: value, expires: now.Add(c.ttl)}
+}
+
+func (c *cacheDecorator) invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+	c.generations[id]++
+}
+
+// InvalidateKey drops the cached chain stored under the key-value store key.
+// It is meant for writes that bypass the service, such as the /kv admin routes,
+// and does nothing unless service was returned by WithCache.
+func InvalidateKey(service Service, key string) {
+	c, ok := service.(*cacheDecorator)
+	if !ok {
+		return
+	}
+	if id, found := strings.CutPrefix(key, taskChainPrefix); found {
+		c.invalidate(id)
+	}
+}
+
+func (c *cacheDecorator) Create(ctx context.Context, chain *taskengine.TaskChainDefinition) error {
+	defer c.invalidate(chain.ID)
+	return c.service.Create(ctx, chain)
+}
+
+func (c *cacheDecorator) Update(ctx context.Context, chain *taskengine.TaskChainDefinition) error {
+	defer c.invalidate(chain.ID)
+	return c.service.Update(ctx, chain)
+}
+
+func (c *cacheDecorator) Delete(ctx context.Context, id string) error {
+	defer c.invalidate(id)
+	return c.service.Delete(ctx, id)
+}
+
+func (c *cacheDecorator) Rollback(ctx context.Context, id string, version int) (*taskengine.TaskChainDefinition, error) {
+	defer c.invalidate(id)
+	return c.service.Rollback(ctx, id, version)
+}
+
+func (c *cacheDecorator) List(ctx context.Context, cursor *time.Time, limit int) ([]*taskengine.TaskChainDefinition, error) {
+	return c.service.List(ctx, cursor, limit)
+}
+
+func (c *cacheDecorator) ListVersions(ctx context.Context, id string) ([]*ChainVersion, error) {
+	return c.service.ListVersions(ctx, id)
+}
@@ -0,0 +1,216 @@
+package taskchainservice_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/taskchainservice"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// countingStore is an in-memory Service that counts reads.
+type countingStore struct {
+	taskchainservice.Service
+	mu     sync.Mutex
+	chains map[string]taskengine.TaskChainDefinition
+	gets   int
+}
+
+func newCountingStore() *countingStore {
+	return &countingStore{chains: map[string]taskengine.TaskChainDefinition{}}
+}
+
+func (s *countingStore) Get(ctx context.Context, id string) (*taskengine.TaskChainDefinition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gets++
+	chain, ok := s.chains[id]
+	if !ok {
+		return nil, fmt.Errorf("task chain %s not found", id)
+	}
+	return &chain, nil
+}
+
+func (s *countingStore) Update(ctx context.Context, chain *taskengine.TaskChainDefinition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chains[chain.ID] = *chain
+	return nil
+}
+
+func (s *countingStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.chains, id)
+	return nil
+}
+
+func (s *countingStore) reads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gets
+}
+
+func chain(id, description string) *taskengine.TaskChainDefinition {
+	return &taskengine.TaskChainDefinition{
+		ID:          id,
+		Description: description,
+		Tasks:       []taskengine.TaskDefinition{{ID: "t1", Handler: taskengine.HandleModelExecution}},
+	}
+}
+
+func TestUnit_ChainCache_CachedReadSkipsStore(t *testing.T) {
+	store := newCountingStore()
+	store.chains["chat"] = *chain("chat", "v1")
+	cached := taskchainservice.WithCache(store, time.Minute, 10)
+
+	first, err := cached.Get(context.Background(), "chat")
+	require.NoError(t, err)
+	// Execution mutates definitions; that must not leak into the cache.
+	first.Tasks[0].ExecuteConfig = &taskengine.LLMExecutionConfig{Model: "leaked"}
+
+	second, err := cached.Get(context.Background(), "chat")
+	require.NoError(t, err)
+	require.Equal(t, 1, store.reads())
+	require.Equal(t, "v1", second.Description)
+	require.Nil(t, second.Tasks[0].ExecuteConfig)
+}
+
+func TestUnit_ChainCache_WritesInvalidateEntry(t *testing.T) {
+	store := newCountingStore()
+	store.chains["chat"] = *chain("chat", "v1")
+	cached := taskchainservice.WithCache(store, time.Minute, 10)
+
+	_, err := cached.Get(context.Background(), "chat")
+	require.NoError(t, err)
+	require.NoError(t, cached.Update(context.Background(), chain("chat", "v2")))
+
+	got, err := cached.Get(context.Background(), "chat")
+	require.NoError(t, err)
+	require.Equal(t, "v2", got.Description)
+	require.Equal(t, 2, store.reads())
+
+	require.NoError(t, cached.Delete(context.Background(), "chat"))
+	_, err = cached.Get(context.Background(), "chat")
+	require.Error(t, err)
+}
+
+func TestUnit_ChainCache_ExpiresAfterTTL(t *testing.T) {
+	store := newCountingStore()
+	store.chains["chat"] = *chain("chat", "v1")
+	cached := taskchainservice.WithCache(store, 20*time.Millisecond, 10)
+
+	_, err := cached.Get(context.Background(), "chat")
+	require.NoError(t, err)
+	// An edit by another process goes straight to the store.
+	store.chains["chat"] = *chain("chat", "v2")
+
+	got, err := cached.Get(context.Background(), "chat")
+	require.NoError(t, err)
+	require.Equal(t, "v1", got.Description)
+
+	time.Sleep(30 * time.Millisecond)
+	got, err = cached.Get(context.Background(), "chat")
+	require.NoError(t, err)
+	require.Equal(t, "v2", got.Description)
+}
+
+func TestUnit_ChainCache_SizeIsBounded(t *testing.T) {
+	store := newCountingStore()
+	for i := range 3 {
+		id := fmt.Sprintf("chain-%d", i)
+		store.chains[id] = *chain(id, "v1")
+	}
+	cached := taskchainservice.WithCache(store, time.Minute, 2)
+
+	for i := range 3 {
+		_, err := cached.Get(context.Background(), fmt.Sprintf("chain-%d", i))
+		require.NoError(t, err)
+	}
+	// chain-0 was evicted to make room for chain-2.
+	_, err := cached.Get(context.Background(), "chain-0")
+	require.NoError(t, err)
+	require.Equal(t, 4, store.reads())
+}
+
+func TestUnit_ChainCache_ConcurrentAccess(t *testing.T) {
+	store := newCountingStore()
+	store.chains["chat"] = *chain("chat", "v1")
+	cached := taskchainservice.WithCache(store, time.Minute, 10)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%5 == 0 {
+				require.NoError(t, cached.Update(context.Background(), chain("chat", fmt.Sprintf("v%d", i))))
+				return
+			}
+			_, err := cached.Get(context.Background(), "chat")
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+// stallingStore holds Get calls after reading the chain until proceed is closed.
+type stallingStore struct {
+	*countingStore
+	read    chan struct{}
+	proceed chan struct{}
+}
+
+func (s *stallingStore) Get(ctx context.Context, id string) (*taskengine.TaskChainDefinition, error) {
+	chain, err := s.countingStore.Get(ctx, id)
+	s.read <- struct{}{}
+	<-s.proceed
+	return chain, err
+}
+
+func TestUnit_ChainCache_ReadRacingWriteDoesNotCacheStaleChain(t *testing.T) {
+	store := &stallingStore{countingStore: newCountingStore(), read: make(chan struct{}, 1), proceed: make(chan struct{})}
+	store.chains["chat"] = *chain("chat", "v1")
+	cached := taskchainservice.WithCache(store, time.Minute, 10)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		got, err := cached.Get(context.Background(), "chat")
+		require.NoError(t, err)
+		require.Equal(t, "v1", got.Description)
+	}()
+	<-store.read
+	// The update lands after the read fetched v1 but before it is cached.
+	require.NoError(t, cached.Update(context.Background(), chain("chat", "v2")))
+	close(store.proceed)
+	<-done
+
+	got, err := cached.Get(context.Background(), "chat")
+	require.NoError(t, err)
+	require.Equal(t, "v2", got.Description)
+}
+
+func TestUnit_ChainCache_InvalidateKey(t *testing.T) {
+	store := newCountingStore()
+	store.chains["chat"] = *chain("chat", "v1")
+	cached := taskchainservice.WithCache(store, time.Minute, 10)
+
+	_, err := cached.Get(context.Background(), "chat")
+	require.NoError(t, err)
+	// An edit through the /kv routes bypasses the service.
+	store.chains["chat"] = *chain("chat", "v2")
+	taskchainservice.InvalidateKey(cached, "prompt:chat")
+	got, err := cached.Get(context.Background(), "chat")
+	require.NoError(t, err)
+	require.Equal(t, "v1", got.Description, "keys of other kinds leave the cache alone")
+
+	taskchainservice.InvalidateKey(cached, "taskchain:chat")
+	got, err = cached.Get(context.Background(), "chat")
+	require.NoError(t, err)
+	require.Equal(t, "v2", got.Description)
+}
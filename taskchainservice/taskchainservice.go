@@ -3,7 +3,11 @@ package taskchainservice
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	libdb "github.com/contenox/runtime/libdbexec"
@@ -12,9 +16,20 @@ import (
 )
 
 const (
-	taskChainPrefix = "taskchain:"
+	taskChainPrefix        = "taskchain:"
+	taskChainVersionPrefix = "taskchainversion:"
+
+	// DefaultMaxVersions is the number of previous versions retained per chain.
+	DefaultMaxVersions = 20
 )
 
+// ChainVersion is a snapshot of a task chain taken before it was overwritten.
+type ChainVersion struct {
+	Version   int                             `json:"version" example:"3"`
+	CreatedAt time.Time                       `json:"createdAt" example:"2023-11-15T14:30:45Z"`
+	Chain     *taskengine.TaskChainDefinition `json:"chain" openapi_include_type:"taskengine.TaskChainDefinition"`
+}
+
 type Service interface {
 	// Create a new task chain
 	Create(ctx context.Context, chain *taskengine.TaskChainDefinition) error
@@ -30,14 +45,38 @@ type Service interface {
 
 	// List task chains with pagination
 	List(ctx context.Context, cursor *time.Time, limit int) ([]*taskengine.TaskChainDefinition, error)
+
+	// ListVersions returns the retained previous versions of a task chain, newest first
+	ListVersions(ctx context.Context, id string) ([]*ChainVersion, error)
+
+	// Rollback restores a previous version as the active task chain
+	Rollback(ctx context.Context, id string, version int) (*taskengine.TaskChainDefinition, error)
 }
 
 type service struct {
-	db libdb.DBManager
+	db          libdb.DBManager
+	maxVersions int
+}
+
+// Option configures the task chain service.
+type Option func(*service)
+
+// WithMaxVersions sets how many previous versions are retained per chain.
+// Zero disables the version history; negative values keep DefaultMaxVersions.
+func WithMaxVersions(n int) Option {
+	return func(s *service) {
+		if n >= 0 {
+			s.maxVersions = n
+		}
+	}
 }
 
-func New(db libdb.DBManager) Service {
-	return &service{db: db}
+func New(db libdb.DBManager, opts ...Option) Service {
+	s := &service{db: db, maxVersions: DefaultMaxVersions}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *service) Create(ctx context.Context, chain *taskengine.TaskChainDefinition) error {
@@ -49,14 +88,16 @@ func (s *service) Create(ctx context.Context, chain *taskengine.TaskChainDefinit
 	if len(chain.Tasks) == 0 {
 		return fmt.Errorf("task chain must contain at least one task")
 	}
-
-	key := taskChainPrefix + chain.ID
-	value, err := json.Marshal(chain)
-	if err != nil {
-		return fmt.Errorf("failed to serialize task chain: %w", err)
+	if err := taskengine.ValidateSchemas(ctx, chain); err != nil {
+		return err
 	}
-	storeInstance := runtimetypes.New(s.db.WithoutTransaction())
-	return storeInstance.SetKV(ctx, key, value)
+	if chain.OnComplete != nil {
+		if err := chain.OnComplete.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return s.write(ctx, chain, false)
 }
 
 func (s *service) Get(ctx context.Context, id string) (*taskengine.TaskChainDefinition, error) {
@@ -79,15 +120,160 @@ func (s *service) Update(ctx context.Context, chain *taskengine.TaskChainDefinit
 	if chain.ID == "" {
 		return fmt.Errorf("task chain ID is required")
 	}
+	if err := taskengine.ValidateSchemas(ctx, chain); err != nil {
+		return err
+	}
+	if chain.OnComplete != nil {
+		if err := chain.OnComplete.Validate(); err != nil {
+			return err
+		}
+	}
 
+	return s.write(ctx, chain, true)
+}
+
+// write stores chain as the active definition. If a definition already exists
+// it is snapshotted as a new version first, and versions beyond the retention
+// limit are pruned.
+func (s *service) write(ctx context.Context, chain *taskengine.TaskChainDefinition, mustExist bool) error {
 	key := taskChainPrefix + chain.ID
 	value, err := json.Marshal(chain)
 	if err != nil {
 		return fmt.Errorf("failed to serialize task chain: %w", err)
 	}
+
+	tx, commit, release, err := s.db.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	storeInstance := runtimetypes.New(tx)
+
+	var previous json.RawMessage
+	err = storeInstance.GetKV(ctx, key, &previous)
+	switch {
+	case err == nil:
+		if err := s.snapshot(ctx, storeInstance, chain.ID, previous); err != nil {
+			return err
+		}
+	case errors.Is(err, libdb.ErrNotFound):
+		if mustExist {
+			return fmt.Errorf("failed to update task chain: %w", err)
+		}
+	default:
+		return fmt.Errorf("failed to read task chain: %w", err)
+	}
+
+	if err := storeInstance.SetKV(ctx, key, value); err != nil {
+		return err
+	}
+	return commit(ctx)
+}
+
+func (s *service) snapshot(ctx context.Context, storeInstance runtimetypes.Store, id string, previous json.RawMessage) error {
+	versions, err := listVersionKVs(ctx, storeInstance, id)
+	if err != nil {
+		return err
+	}
+	if s.maxVersions > 0 {
+		next := 1
+		if len(versions) > 0 {
+			next = versions[0].version + 1
+		}
+		if err := storeInstance.SetKV(ctx, versionKey(id, next), previous); err != nil {
+			return fmt.Errorf("failed to snapshot task chain: %w", err)
+		}
+	}
+	for _, old := range prunedVersions(versions, s.maxVersions) {
+		if err := storeInstance.DeleteKV(ctx, old.kv.Key); err != nil {
+			return fmt.Errorf("failed to prune task chain version %d: %w", old.version, err)
+		}
+	}
+	return nil
+}
+
+func (s *service) ListVersions(ctx context.Context, id string) ([]*ChainVersion, error) {
+	if id == "" {
+		return nil, fmt.Errorf("task chain ID is required")
+	}
+
 	storeInstance := runtimetypes.New(s.db.WithoutTransaction())
+	kvs, err := listVersionKVs(ctx, storeInstance, id)
+	if err != nil {
+		return nil, err
+	}
 
-	return storeInstance.UpdateKV(ctx, key, value)
+	versions := make([]*ChainVersion, 0, len(kvs))
+	for _, v := range kvs {
+		var chain taskengine.TaskChainDefinition
+		if err := json.Unmarshal(v.kv.Value, &chain); err != nil {
+			continue
+		}
+		versions = append(versions, &ChainVersion{
+			Version:   v.version,
+			CreatedAt: v.kv.CreatedAt,
+			Chain:     &chain,
+		})
+	}
+	return versions, nil
+}
+
+func (s *service) Rollback(ctx context.Context, id string, version int) (*taskengine.TaskChainDefinition, error) {
+	if id == "" {
+		return nil, fmt.Errorf("task chain ID is required")
+	}
+
+	var chain taskengine.TaskChainDefinition
+	storeInstance := runtimetypes.New(s.db.WithoutTransaction())
+	if err := storeInstance.GetKV(ctx, versionKey(id, version), &chain); err != nil {
+		return nil, fmt.Errorf("failed to get task chain version %d: %w", version, err)
+	}
+	chain.ID = id
+
+	if err := s.write(ctx, &chain, false); err != nil {
+		return nil, err
+	}
+	return &chain, nil
+}
+
+// prunedVersions returns the stored versions, ordered newest first, that exceed
+// maxVersions once the next snapshot is added. With maxVersions zero no
+// snapshot is added and all stored versions are pruned.
+func prunedVersions(versions []versionKV, maxVersions int) []versionKV {
+	keep := max(maxVersions-1, 0)
+	return versions[min(keep, len(versions)):]
+}
+
+type versionKV struct {
+	version int
+	kv      *runtimetypes.KV
+}
+
+func versionKey(id string, version int) string {
+	return fmt.Sprintf("%s%s:v%d", taskChainVersionPrefix, id, version)
+}
+
+// listVersionKVs returns all stored versions of a chain ordered newest first.
+func listVersionKVs(ctx context.Context, storeInstance runtimetypes.Store, id string) ([]versionKV, error) {
+	prefix := taskChainVersionPrefix + id + ":v"
+	kvs, err := storeInstance.ListKVPrefix(ctx, prefix, nil, runtimetypes.MAXLIMIT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task chain versions: %w", err)
+	}
+
+	versions := make([]versionKV, 0, len(kvs))
+	for _, kv := range kvs {
+		n, err := strconv.Atoi(strings.TrimPrefix(kv.Key, prefix))
+		if err != nil {
+			// Key belongs to a chain whose ID shares this prefix.
+			continue
+		}
+		versions = append(versions, versionKV{version: n, kv: kv})
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].version > versions[j].version
+	})
+	return versions, nil
 }
 
 func (s *service) Delete(ctx context.Context, id string) error {
@@ -96,9 +282,26 @@ func (s *service) Delete(ctx context.Context, id string) error {
 	}
 
 	key := taskChainPrefix + id
-	storeInstance := runtimetypes.New(s.db.WithoutTransaction())
+	tx, commit, release, err := s.db.WithTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	storeInstance := runtimetypes.New(tx)
 
-	return storeInstance.DeleteKV(ctx, key)
+	if err := storeInstance.DeleteKV(ctx, key); err != nil {
+		return err
+	}
+	versions, err := listVersionKVs(ctx, storeInstance, id)
+	if err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if err := storeInstance.DeleteKV(ctx, v.kv.Key); err != nil {
+			return fmt.Errorf("failed to delete task chain version %d: %w", v.version, err)
+		}
+	}
+	return commit(ctx)
 }
 
 func (s *service) List(ctx context.Context, cursor *time.Time, limit int) ([]*taskengine.TaskChainDefinition, error) {
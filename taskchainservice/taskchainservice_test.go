@@ -0,0 +1,34 @@
+package taskchainservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_PrunedVersions(t *testing.T) {
+	stored := []versionKV{{version: 3}, {version: 2}, {version: 1}}
+	versions := func(kvs []versionKV) []int {
+		var ns []int
+		for _, kv := range kvs {
+			ns = append(ns, kv.version)
+		}
+		return ns
+	}
+
+	// The next snapshot counts towards the limit.
+	require.Equal(t, []int{2, 1}, versions(prunedVersions(stored, 2)))
+	require.Equal(t, []int{3, 2, 1}, versions(prunedVersions(stored, 1)))
+	require.Empty(t, prunedVersions(stored, 4))
+	require.Empty(t, prunedVersions(stored, DefaultMaxVersions))
+
+	// Zero keeps no history at all.
+	require.Equal(t, []int{3, 2, 1}, versions(prunedVersions(stored, 0)))
+}
+
+func TestUnit_WithMaxVersions(t *testing.T) {
+	for n, want := range map[int]int{0: 0, 5: 5, -1: DefaultMaxVersions} {
+		s := New(nil, WithMaxVersions(n)).(*service)
+		require.Equal(t, want, s.maxVersions, n)
+	}
+}
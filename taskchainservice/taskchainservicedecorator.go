@@ -122,6 +122,46 @@ func (d *activityTrackerDecorator) List(ctx context.Context, cursor *time.Time,
 	return chains, err
 }
 
+func (d *activityTrackerDecorator) ListVersions(ctx context.Context, id string) ([]*ChainVersion, error) {
+	reportErrFn, _, endFn := d.tracker.Start(
+		ctx,
+		"list_versions",
+		"taskchain",
+		"id", id,
+	)
+	defer endFn()
+
+	versions, err := d.service.ListVersions(ctx, id)
+	if err != nil {
+		reportErrFn(err)
+	}
+
+	return versions, err
+}
+
+func (d *activityTrackerDecorator) Rollback(ctx context.Context, id string, version int) (*taskengine.TaskChainDefinition, error) {
+	reportErrFn, reportChangeFn, endFn := d.tracker.Start(
+		ctx,
+		"rollback",
+		"taskchain",
+		"id", id,
+		"version", version,
+	)
+	defer endFn()
+
+	chain, err := d.service.Rollback(ctx, id, version)
+	if err != nil {
+		reportErrFn(err)
+	} else {
+		reportChangeFn(id, map[string]interface{}{
+			"version":   version,
+			"taskCount": len(chain.Tasks),
+		})
+	}
+
+	return chain, err
+}
+
 // WithActivityTracker wraps a task chain service with activity tracking capabilities
 func WithActivityTracker(service Service, tracker libtracker.ActivityTracker) Service {
 	return &activityTrackerDecorator{
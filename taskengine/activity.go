@@ -14,6 +14,7 @@ import (
 
 type KVActivitySink struct {
 	kvManager libkv.KVManager
+	alerts    alertEvaluator
 }
 
 func NewKVActivityTracker(kvManager libkv.KVManager) *KVActivitySink {
@@ -129,9 +130,32 @@ func (t *KVActivitySink) Start(
 		}
 
 		// Maintain last 1000 events
-		if err := kv.ListTrim(ctx, "activity:log", 0, 999); err != nil {
+		if err := kv.ListTrim(ctx, "activity:log", 0, activityLogCap-1); err != nil {
 			log.Printf("SERVERBUG: Failed to trim activity log: %v", err)
 		}
+
+		// Per-operation log so operation filters don't need to scan the full log
+		opKey := activityLogKey(event.Operation)
+		if err := kv.ListPush(ctx, opKey, data); err != nil {
+			log.Printf("SERVERBUG: Failed to push operation activity event: %v", err)
+		}
+		if err := kv.ListTrim(ctx, opKey, 0, activityLogCap-1); err != nil {
+			log.Printf("SERVERBUG: Failed to trim operation activity log: %v", err)
+		}
+		for _, alert := range t.alerts.evaluate(event) {
+			alertData, err := json.Marshal(alert)
+			if err != nil {
+				log.Printf("SERVERBUG: Failed to marshal alert: %v", err)
+				continue
+			}
+			if err := kv.ListPush(ctx, alertsKey, alertData); err != nil {
+				log.Printf("SERVERBUG: Failed to push alert: %v", err)
+			}
+			if err := kv.ListTrim(ctx, alertsKey, 0, activityLogCap-1); err != nil {
+				log.Printf("SERVERBUG: Failed to trim alerts: %v", err)
+			}
+		}
+
 		if event.RequestID != "" {
 			reqKey := "activity:request:" + event.RequestID
 			if err := kv.ListPush(ctx, reqKey, data); err != nil {
@@ -177,11 +201,58 @@ func extractMetadata(args ...any) map[string]string {
 	return meta
 }
 
-func (t *KVActivitySink) GetRecentRequestIDs(ctx context.Context, limit int) ([]TrackedRequest, error) {
+// ActivityFilter narrows activity queries.
+// Zero values disable the respective filter; a zero ActivityFilter returns everything.
+type ActivityFilter struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Operation string    `json:"operation"`
+}
+
+func (f ActivityFilter) isZero() bool {
+	return f.From.IsZero() && f.To.IsZero() && f.Operation == ""
+}
+
+func (f ActivityFilter) hasTimeRange() bool {
+	return !f.From.IsZero() || !f.To.IsZero()
+}
+
+func activityLogKey(operation string) string {
+	if operation == "" {
+		return "activity:log"
+	}
+	return "activity:log:" + operation
+}
+
+func (t *KVActivitySink) GetRecentRequestIDs(ctx context.Context, limit int, filter ActivityFilter) ([]TrackedRequest, error) {
 	if limit <= 0 {
 		limit = 100
 	}
 
+	if !filter.isZero() {
+		// The request set carries no timestamps or operations, so derive
+		// the requests from the matching events instead.
+		events, err := t.GetActivityLogs(ctx, activityLogCap, filter)
+		if err != nil {
+			return nil, err
+		}
+		var requestIDs []TrackedRequest
+		seen := make(map[string]struct{})
+		for _, evt := range events {
+			if evt.RequestID == "" {
+				continue
+			}
+			if _, exists := seen[evt.RequestID]; !exists {
+				seen[evt.RequestID] = struct{}{}
+				requestIDs = append(requestIDs, TrackedRequest{ID: evt.RequestID})
+			}
+			if len(requestIDs) == limit {
+				break
+			}
+		}
+		return requestIDs, nil
+	}
+
 	kv, err := t.kvManager.Executor(ctx)
 	if err != nil {
 		return nil, err
@@ -209,7 +280,13 @@ func (t *KVActivitySink) GetRecentRequestIDs(ctx context.Context, limit int) ([]
 	return requestIDs, nil
 }
 
-func (t *KVActivitySink) GetActivityLogs(ctx context.Context, limit int) ([]TrackedEvent, error) {
+// activityLogCap is the number of events retained per activity log.
+const activityLogCap = 1000
+
+// GetActivityLogs returns up to limit events, newest first.
+// An operation filter reads the per-operation log. Events are logged when
+// they end, so a time range on their start scans the whole log.
+func (t *KVActivitySink) GetActivityLogs(ctx context.Context, limit int, filter ActivityFilter) ([]TrackedEvent, error) {
 	kv, err := t.kvManager.Executor(ctx)
 	if err != nil {
 		return nil, err
@@ -218,30 +295,59 @@ func (t *KVActivitySink) GetActivityLogs(ctx context.Context, limit int) ([]Trac
 	if limit <= 0 {
 		limit = 100
 	}
+	key := activityLogKey(filter.Operation)
 
 	// Get list length
-	listLen, err := kv.ListLength(ctx, "activity:log")
+	listLen, err := kv.ListLength(ctx, key)
 	if err != nil {
 		return nil, err
 	}
 
-	// Determine range
-	start := int64(0)
-	stop := int64(limit - 1)
-	if listLen < stop+1 {
-		stop = listLen - 1
-	}
+	if !filter.hasTimeRange() {
+		// Determine range
+		start := int64(0)
+		stop := int64(limit - 1)
+		if listLen < stop+1 {
+			stop = listLen - 1
+		}
 
-	rawItems, err := kv.ListRange(ctx, "activity:log", start, stop)
-	if err != nil {
-		return nil, err
+		rawItems, err := kv.ListRange(ctx, key, start, stop)
+		if err != nil {
+			return nil, err
+		}
+
+		var results []TrackedEvent
+		for _, raw := range rawItems {
+			var evt TrackedEvent
+			if err := json.Unmarshal(raw, &evt); err == nil {
+				results = append(results, evt)
+			}
+		}
+		return results, nil
 	}
 
 	var results []TrackedEvent
-	for _, raw := range rawItems {
-		var evt TrackedEvent
-		if err := json.Unmarshal(raw, &evt); err == nil {
+	page := int64(limit)
+	for start := int64(0); start < listLen; start += page {
+		rawItems, err := kv.ListRange(ctx, key, start, start+page-1)
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range rawItems {
+			var evt TrackedEvent
+			if err := json.Unmarshal(raw, &evt); err != nil {
+				continue
+			}
+			if !filter.To.IsZero() && evt.Start.After(filter.To) {
+				continue
+			}
+			if !filter.From.IsZero() && evt.Start.Before(filter.From) {
+				continue
+			}
 			results = append(results, evt)
+			if len(results) == limit {
+				return results, nil
+			}
 		}
 	}
 
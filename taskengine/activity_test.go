@@ -0,0 +1,296 @@
+package taskengine_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	libkv "github.com/contenox/runtime/libkvstore"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// memKV is an in-memory KVManager implementing the list and set operations used by the activity sink.
+type memKV struct {
+	libkv.KVExecutor
+	mu     sync.Mutex
+	lists  map[string][]json.RawMessage
+	sets   map[string]map[string]json.RawMessage
+	values map[string]json.RawMessage
+}
+
+func newMemKV() *memKV {
+	return &memKV{
+		lists:  map[string][]json.RawMessage{},
+		sets:   map[string]map[string]json.RawMessage{},
+		values: map[string]json.RawMessage{},
+	}
+}
+
+func (m *memKV) Executor(ctx context.Context) (libkv.KVExecutor, error) { return m, nil }
+func (m *memKV) Close() error                                           { return nil }
+
+func (m *memKV) Get(ctx context.Context, key libkv.Key) (json.RawMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.values[key]
+	if !ok {
+		return nil, libkv.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *memKV) Set(ctx context.Context, key libkv.Key, value json.RawMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	return nil
+}
+
+func (m *memKV) SetWithTTL(ctx context.Context, key libkv.Key, value json.RawMessage, _ time.Duration) error {
+	return m.Set(ctx, key, value)
+}
+
+func (m *memKV) Delete(ctx context.Context, key libkv.Key) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.values[key]; !ok {
+		return libkv.ErrNotFound
+	}
+	delete(m.values, key)
+	return nil
+}
+
+func (m *memKV) ListPush(ctx context.Context, key libkv.Key, value json.RawMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lists[key] = append([]json.RawMessage{value}, m.lists[key]...)
+	return nil
+}
+
+func (m *memKV) ListRange(ctx context.Context, key libkv.Key, start, stop int64) ([]json.RawMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := m.lists[key]
+	n := int64(len(list))
+	if stop < 0 || stop >= n {
+		stop = n - 1
+	}
+	if start >= n || start > stop {
+		return nil, nil
+	}
+	return append([]json.RawMessage(nil), list[start:stop+1]...), nil
+}
+
+func (m *memKV) ListTrim(ctx context.Context, key libkv.Key, start, stop int64) error {
+	items, _ := m.ListRange(ctx, key, start, stop)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lists[key] = items
+	return nil
+}
+
+func (m *memKV) ListLength(ctx context.Context, key libkv.Key) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return int64(len(m.lists[key])), nil
+}
+
+func (m *memKV) SetAdd(ctx context.Context, key libkv.Key, member json.RawMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sets[key] == nil {
+		m.sets[key] = map[string]json.RawMessage{}
+	}
+	m.sets[key][string(member)] = member
+	return nil
+}
+
+func (m *memKV) SetMembers(ctx context.Context, key libkv.Key) ([]json.RawMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var members []json.RawMessage
+	for _, v := range m.sets[key] {
+		members = append(members, v)
+	}
+	return members, nil
+}
+
+func TestUnit_KVActivitySink_FilterByOperation(t *testing.T) {
+	sink := taskengine.NewKVActivityTracker(newMemKV())
+	ctx := context.WithValue(context.Background(), libtracker.ContextKeyRequestID, "req-1")
+
+	for _, op := range []string{"embed", "chat", "embed"} {
+		_, _, end := sink.Start(ctx, op, "model")
+		end()
+	}
+	reportErr, _, end := sink.Start(context.WithValue(context.Background(), libtracker.ContextKeyRequestID, "req-2"), "chat", "model")
+	reportErr(errors.New("boom"))
+	end()
+
+	all, err := sink.GetActivityLogs(context.Background(), 0, taskengine.ActivityFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+
+	embeds, err := sink.GetActivityLogs(context.Background(), 0, taskengine.ActivityFilter{Operation: "embed"})
+	require.NoError(t, err)
+	require.Len(t, embeds, 2)
+	for _, evt := range embeds {
+		require.Equal(t, "embed", evt.Operation)
+	}
+
+	requests, err := sink.GetRecentRequestIDs(context.Background(), 0, taskengine.ActivityFilter{Operation: "chat"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []taskengine.TrackedRequest{{ID: "req-1"}, {ID: "req-2"}}, requests)
+
+	requests, err = sink.GetRecentRequestIDs(context.Background(), 0, taskengine.ActivityFilter{Operation: "embed"})
+	require.NoError(t, err)
+	require.Equal(t, []taskengine.TrackedRequest{{ID: "req-1"}}, requests)
+}
+
+func TestUnit_KVActivitySink_FilterByTimeRange(t *testing.T) {
+	kv := newMemKV()
+	sink := taskengine.NewKVActivityTracker(kv)
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Seed oldest first so the log ends up newest first, as written by the sink.
+	for i := range 10 {
+		data, err := json.Marshal(taskengine.TrackedEvent{
+			ID:        string(rune('a' + i)),
+			Operation: "op",
+			Start:     base.Add(time.Duration(i) * time.Minute),
+			RequestID: "req-" + string(rune('a'+i)),
+		})
+		require.NoError(t, err)
+		require.NoError(t, kv.ListPush(context.Background(), "activity:log", data))
+	}
+
+	events, err := sink.GetActivityLogs(context.Background(), 3, taskengine.ActivityFilter{
+		From: base.Add(2 * time.Minute),
+		To:   base.Add(6 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 3, "limit applies after filtering")
+	require.Equal(t, []string{"g", "f", "e"}, []string{events[0].ID, events[1].ID, events[2].ID})
+
+	events, err = sink.GetActivityLogs(context.Background(), 100, taskengine.ActivityFilter{
+		From: base.Add(8 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	requests, err := sink.GetRecentRequestIDs(context.Background(), 0, taskengine.ActivityFilter{
+		To: base.Add(1 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []taskengine.TrackedRequest{{ID: "req-b"}, {ID: "req-a"}}, requests)
+}
+
+func TestUnit_KVActivitySink_FilterByTimeRange_LongRunningEvent(t *testing.T) {
+	kv := newMemKV()
+	sink := taskengine.NewKVActivityTracker(kv)
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	// The log is ordered by end time, so a long-running event that started
+	// early can sit between events that started after it.
+	for i, start := range []time.Duration{5 * time.Minute, 0, 6 * time.Minute} {
+		data, err := json.Marshal(taskengine.TrackedEvent{
+			ID:        string(rune('a' + i)),
+			Operation: "op",
+			Start:     base.Add(start),
+		})
+		require.NoError(t, err)
+		require.NoError(t, kv.ListPush(context.Background(), "activity:log", data))
+	}
+
+	events, err := sink.GetActivityLogs(context.Background(), 100, taskengine.ActivityFilter{
+		From: base.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, []string{"c", "a"}, []string{events[0].ID, events[1].ID})
+}
+
+func TestUnit_KVActivitySink_ExportActivityLogs(t *testing.T) {
+	kv := newMemKV()
+	sink := taskengine.NewKVActivityTracker(kv)
+	ctx := context.Background()
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	boom := "boom"
+	for i := range 250 {
+		evt := taskengine.TrackedEvent{
+			ID:        fmt.Sprintf("evt-%d", i),
+			Operation: "create",
+			Subject:   "backend",
+			Start:     base.Add(time.Duration(i) * time.Minute),
+			RequestID: fmt.Sprintf("req-%d", i),
+			Duration:  1.5,
+		}
+		if i == 120 {
+			evt.Error = &boom
+		}
+		data, err := json.Marshal(evt)
+		require.NoError(t, err)
+		require.NoError(t, kv.ListPush(ctx, "activity:log", data))
+	}
+
+	var out bytes.Buffer
+	n, err := sink.ExportActivityLogs(ctx, &out, taskengine.ActivityFilter{
+		From: base.Add(10 * time.Minute),
+		To:   base.Add(209 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 200, n)
+
+	var records []taskengine.ActivityRecord
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var record taskengine.ActivityRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, records, 200)
+	require.Equal(t, base.Add(10*time.Minute), records[0].Timestamp, "oldest first")
+	require.Equal(t, base.Add(209*time.Minute), records[199].Timestamp)
+	require.Equal(t, "backend.create", records[0].EventType)
+	require.Equal(t, "req-10", records[0].RequestID)
+	require.Equal(t, 1.5, records[0].DurationMs)
+	require.Equal(t, "boom", records[110].Error)
+}
+
+func TestUnit_KVActivitySink_ExportActivityLogs_LongRunningEvent(t *testing.T) {
+	kv := newMemKV()
+	sink := taskengine.NewKVActivityTracker(kv)
+	ctx := context.Background()
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Logged oldest first by end time: an event starting after the range ends
+	// before a long-running one that started inside it.
+	for i, start := range []time.Duration{0, 10 * time.Minute, 2 * time.Minute} {
+		data, err := json.Marshal(taskengine.TrackedEvent{
+			ID:        fmt.Sprintf("evt-%d", i),
+			Operation: "create",
+			Subject:   "backend",
+			Start:     base.Add(start),
+		})
+		require.NoError(t, err)
+		require.NoError(t, kv.ListPush(ctx, "activity:log", data))
+	}
+
+	var out bytes.Buffer
+	n, err := sink.ExportActivityLogs(ctx, &out, taskengine.ActivityFilter{To: base.Add(5 * time.Minute)})
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, 2, strings.Count(out.String(), "\n"))
+	require.NotContains(t, out.String(), "12:10:00")
+}
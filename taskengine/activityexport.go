@@ -0,0 +1,92 @@
+package taskengine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ActivityRecord is the export schema of an activity event. Its fields are kept
+// stable for external consumers such as SIEMs; add fields, never rename them.
+type ActivityRecord struct {
+	Timestamp  time.Time         `json:"timestamp"`
+	EventType  string            `json:"eventType"` // "<subject>.<operation>"
+	Subject    string            `json:"subject"`
+	Operation  string            `json:"operation"`
+	RequestID  string            `json:"requestId,omitempty"`
+	EntityID   string            `json:"entityId,omitempty"`
+	DurationMs float64           `json:"durationMs"`
+	Error      string            `json:"error,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+func newActivityRecord(evt TrackedEvent) ActivityRecord {
+	record := ActivityRecord{
+		Timestamp:  evt.Start,
+		EventType:  evt.Subject + "." + evt.Operation,
+		Subject:    evt.Subject,
+		Operation:  evt.Operation,
+		RequestID:  evt.RequestID,
+		DurationMs: evt.Duration,
+		Metadata:   evt.Metadata,
+	}
+	if evt.EntityID != nil {
+		record.EntityID = *evt.EntityID
+	}
+	if evt.Error != nil {
+		record.Error = *evt.Error
+	}
+	return record
+}
+
+// activityExportPage is the number of events read from the store at a time.
+const activityExportPage = 100
+
+// ExportActivityLogs writes the events matching filter to w as JSON Lines, one
+// ActivityRecord per line, oldest first. Events are read and written page by page,
+// so the export is never held in memory as a whole. It returns the number of
+// records written.
+func (t *KVActivitySink) ExportActivityLogs(ctx context.Context, w io.Writer, filter ActivityFilter) (int, error) {
+	kv, err := t.kvManager.Executor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	key := activityLogKey(filter.Operation)
+	listLen, err := kv.ListLength(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	// The log is newest first, so walk it from the tail.
+	enc := json.NewEncoder(w)
+	written := 0
+	for stop := listLen - 1; stop >= 0; stop -= activityExportPage {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		start := max(stop-activityExportPage+1, 0)
+		rawItems, err := kv.ListRange(ctx, key, start, stop)
+		if err != nil {
+			return written, err
+		}
+		for i := len(rawItems) - 1; i >= 0; i-- {
+			var evt TrackedEvent
+			if err := json.Unmarshal(rawItems[i], &evt); err != nil {
+				continue
+			}
+			if !filter.From.IsZero() && evt.Start.Before(filter.From) {
+				continue
+			}
+			// Events are logged when they end, so a later start does not end the range.
+			if !filter.To.IsZero() && evt.Start.After(filter.To) {
+				continue
+			}
+			if err := enc.Encode(newActivityRecord(evt)); err != nil {
+				return written, err
+			}
+			written++
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,240 @@
+package taskengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	libkv "github.com/contenox/runtime/libkvstore"
+	"github.com/google/uuid"
+)
+
+// AlertRulesKey is the KV key LoadAlertRules reads the alert rules from, as a JSON array of AlertRule.
+const AlertRulesKey = "activity:alertrules"
+
+const alertsKey = "activity:alerts"
+
+const (
+	AlertSeverityWarning  = "warning"
+	AlertSeverityCritical = "critical"
+)
+
+// Duration is a time.Duration that is written to and read from JSON as a
+// duration string, e.g. "5m" or "250ms".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5m\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// AlertRule is a threshold evaluated against activity events as they are recorded.
+// A rule sets either MaxErrorRate or MaxLatency.
+type AlertRule struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	// Operation restricts the rule to events of this operation; empty matches all.
+	Operation string `json:"operation,omitempty"`
+	// MaxErrorRate (0..1) fires when the share of failed events within Window exceeds it.
+	MaxErrorRate float64 `json:"maxErrorRate,omitempty"`
+	// Window is the sliding window for MaxErrorRate, and the minimum time between two alerts of the rule.
+	Window Duration `json:"window,omitempty"`
+	// MinEvents is the number of events required in the window before the error rate is evaluated.
+	MinEvents int `json:"minEvents,omitempty"`
+	// MaxLatency fires for any single event taking longer than this.
+	MaxLatency Duration `json:"maxLatency,omitempty"`
+}
+
+// Alert is produced when an AlertRule's threshold is crossed.
+type Alert struct {
+	ID        string            `json:"id"`
+	RuleID    string            `json:"ruleId"`
+	Severity  string            `json:"severity"`
+	Message   string            `json:"message"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Context   map[string]string `json:"context,omitempty"`
+}
+
+func (r AlertRule) validate() error {
+	if r.ID == "" {
+		return errors.New("alert rule id is required")
+	}
+	switch r.Severity {
+	case AlertSeverityWarning, AlertSeverityCritical:
+	default:
+		return fmt.Errorf("alert rule %q: unknown severity %q", r.ID, r.Severity)
+	}
+	if (r.MaxErrorRate > 0) == (r.MaxLatency > 0) {
+		return fmt.Errorf("alert rule %q: exactly one of maxErrorRate or maxLatency must be set", r.ID)
+	}
+	if r.MaxErrorRate > 1 {
+		return fmt.Errorf("alert rule %q: maxErrorRate must be between 0 and 1", r.ID)
+	}
+	if r.MaxErrorRate > 0 && r.Window <= 0 {
+		return fmt.Errorf("alert rule %q: window is required for maxErrorRate", r.ID)
+	}
+	return nil
+}
+
+type windowSample struct {
+	at     time.Time
+	failed bool
+}
+
+// alertEvaluator keeps per-rule sliding windows so rules are checked incrementally per event.
+type alertEvaluator struct {
+	mu        sync.Mutex
+	rules     []AlertRule
+	samples   map[string][]windowSample
+	lastFired map[string]time.Time
+}
+
+func (e *alertEvaluator) setRules(rules []AlertRule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = rules
+	e.samples = make(map[string][]windowSample)
+	e.lastFired = make(map[string]time.Time)
+}
+
+func (e *alertEvaluator) evaluate(event *TrackedEvent) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now().UTC()
+	if event.End != nil {
+		now = *event.End
+	}
+	var alerts []Alert
+	for _, rule := range e.rules {
+		if rule.Operation != "" && rule.Operation != event.Operation {
+			continue
+		}
+		if last, ok := e.lastFired[rule.ID]; ok && now.Sub(last) < time.Duration(rule.Window) {
+			continue
+		}
+
+		var message string
+		ctx := map[string]string{
+			"operation": event.Operation,
+			"subject":   event.Subject,
+		}
+		if event.RequestID != "" {
+			ctx["requestID"] = event.RequestID
+		}
+
+		if rule.MaxLatency > 0 {
+			latency := time.Duration(event.Duration * float64(time.Millisecond))
+			if latency <= time.Duration(rule.MaxLatency) {
+				continue
+			}
+			message = fmt.Sprintf("%s %s took %s, exceeding %s", event.Operation, event.Subject, latency, time.Duration(rule.MaxLatency))
+			ctx["latencyMs"] = fmt.Sprintf("%.0f", event.Duration)
+		} else {
+			window := append(e.samples[rule.ID], windowSample{at: now, failed: event.Error != nil})
+			cutoff := now.Add(-time.Duration(rule.Window))
+			for len(window) > 0 && window[0].at.Before(cutoff) {
+				window = window[1:]
+			}
+			e.samples[rule.ID] = window
+			if len(window) < max(rule.MinEvents, 1) {
+				continue
+			}
+			failed := 0
+			for _, s := range window {
+				if s.failed {
+					failed++
+				}
+			}
+			rate := float64(failed) / float64(len(window))
+			if rate <= rule.MaxErrorRate {
+				continue
+			}
+			message = fmt.Sprintf("error rate %.0f%% over %d events in %s exceeds %.0f%%", rate*100, len(window), time.Duration(rule.Window), rule.MaxErrorRate*100)
+			ctx["errorRate"] = fmt.Sprintf("%.4f", rate)
+			ctx["events"] = fmt.Sprint(len(window))
+			delete(e.samples, rule.ID)
+		}
+
+		e.lastFired[rule.ID] = now
+		alerts = append(alerts, Alert{
+			ID:        uuid.NewString(),
+			RuleID:    rule.ID,
+			Severity:  rule.Severity,
+			Message:   message,
+			CreatedAt: now,
+			Context:   ctx,
+		})
+	}
+	return alerts
+}
+
+// SetAlertRules replaces the alert rules evaluated for every recorded event.
+func (t *KVActivitySink) SetAlertRules(rules []AlertRule) error {
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	t.alerts.setRules(rules)
+	return nil
+}
+
+// LoadAlertRules reads the alert rules stored under AlertRulesKey and activates them.
+// A missing key leaves alerting disabled.
+func (t *KVActivitySink) LoadAlertRules(ctx context.Context) error {
+	kv, err := t.kvManager.Executor(ctx)
+	if err != nil {
+		return err
+	}
+	raw, err := kv.Get(ctx, AlertRulesKey)
+	if err != nil {
+		if errors.Is(err, libkv.ErrNotFound) {
+			return t.SetAlertRules(nil)
+		}
+		return err
+	}
+	var rules []AlertRule
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return fmt.Errorf("invalid alert rules: %w", err)
+	}
+	return t.SetAlertRules(rules)
+}
+
+// FetchAlerts returns up to limit alerts, newest first.
+func (t *KVActivitySink) FetchAlerts(ctx context.Context, limit int) ([]Alert, error) {
+	kv, err := t.kvManager.Executor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	rawItems, err := kv.ListRange(ctx, alertsKey, 0, int64(limit-1))
+	if err != nil {
+		return nil, err
+	}
+	var alerts []Alert
+	for _, raw := range rawItems {
+		var alert Alert
+		if err := json.Unmarshal(raw, &alert); err == nil {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
@@ -0,0 +1,101 @@
+package taskengine_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_KVActivitySink_ErrorRateAlert(t *testing.T) {
+	sink := taskengine.NewKVActivityTracker(newMemKV())
+	require.NoError(t, sink.SetAlertRules([]taskengine.AlertRule{{
+		ID:           "embed-errors",
+		Severity:     taskengine.AlertSeverityCritical,
+		Operation:    "embed",
+		MaxErrorRate: 0.5,
+		Window:       taskengine.Duration(time.Minute),
+		MinEvents:    4,
+	}}))
+
+	record := func(op string, fail bool) {
+		reportErr, _, end := sink.Start(context.Background(), op, "model")
+		if fail {
+			reportErr(errors.New("backend unavailable"))
+		}
+		end()
+	}
+
+	record("embed", true)
+	record("embed", false)
+	record("chat", true) // other operations are ignored by the rule
+	record("embed", true)
+	alerts, err := sink.FetchAlerts(context.Background(), 0)
+	require.NoError(t, err)
+	require.Empty(t, alerts, "below MinEvents no alert may fire")
+
+	record("embed", true)
+	alerts, err = sink.FetchAlerts(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	require.Equal(t, "embed-errors", alerts[0].RuleID)
+	require.Equal(t, taskengine.AlertSeverityCritical, alerts[0].Severity)
+	require.Equal(t, "embed", alerts[0].Context["operation"])
+	require.Equal(t, "4", alerts[0].Context["events"])
+
+	// The rule stays quiet for the rest of its window.
+	record("embed", true)
+	alerts, err = sink.FetchAlerts(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+}
+
+func TestUnit_KVActivitySink_LatencyAlertFromKVRules(t *testing.T) {
+	kv := newMemKV()
+	rules, err := json.Marshal([]taskengine.AlertRule{{
+		ID:         "slow-tasks",
+		Severity:   taskengine.AlertSeverityWarning,
+		MaxLatency: taskengine.Duration(10 * time.Millisecond),
+	}})
+	require.NoError(t, err)
+	require.NoError(t, kv.Set(context.Background(), taskengine.AlertRulesKey, rules))
+
+	sink := taskengine.NewKVActivityTracker(kv)
+	require.NoError(t, sink.LoadAlertRules(context.Background()))
+
+	_, _, end := sink.Start(context.Background(), "task", "fast")
+	end()
+	_, _, end = sink.Start(context.Background(), "task", "slow")
+	time.Sleep(20 * time.Millisecond)
+	end()
+
+	alerts, err := sink.FetchAlerts(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	require.Equal(t, "slow-tasks", alerts[0].RuleID)
+	require.Equal(t, "slow", alerts[0].Context["subject"])
+}
+
+func TestUnit_KVActivitySink_RejectsInvalidAlertRules(t *testing.T) {
+	sink := taskengine.NewKVActivityTracker(newMemKV())
+	require.Error(t, sink.SetAlertRules([]taskengine.AlertRule{{ID: "r", Severity: "loud", MaxLatency: taskengine.Duration(time.Second)}}))
+	require.Error(t, sink.SetAlertRules([]taskengine.AlertRule{{ID: "r", Severity: taskengine.AlertSeverityWarning}}))
+	require.Error(t, sink.SetAlertRules([]taskengine.AlertRule{{ID: "r", Severity: taskengine.AlertSeverityWarning, MaxErrorRate: 0.1}}))
+}
+
+func TestUnit_AlertRule_DurationsAsStrings(t *testing.T) {
+	var rules []taskengine.AlertRule
+	require.NoError(t, json.Unmarshal([]byte(`[{"id":"r","severity":"warning","maxErrorRate":0.2,"window":"5m"}]`), &rules))
+	require.Equal(t, taskengine.Duration(5*time.Minute), rules[0].Window)
+
+	data, err := json.Marshal(rules[0])
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"window":"5m0s"`)
+
+	require.Error(t, json.Unmarshal([]byte(`[{"id":"r","window":300000000000}]`), &rules))
+	require.Error(t, json.Unmarshal([]byte(`[{"id":"r","window":"soon"}]`), &rules))
+}
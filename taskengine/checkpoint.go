@@ -0,0 +1,349 @@
+package taskengine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	libkv "github.com/contenox/runtime/libkvstore"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/google/uuid"
+)
+
+// Checkpoint is the state of a chain execution after its last successful task.
+type Checkpoint struct {
+	// ID identifies the execution; every execution gets its own, even if it
+	// shares the request ID with others, e.g. the items of a batch.
+	ID         string               `json:"id"`
+	RequestID  string               `json:"requestId"`
+	Chain      *TaskChainDefinition `json:"chain"`
+	Input      json.RawMessage      `json:"input"`
+	InputType  string               `json:"inputType"`
+	NextTaskID string               `json:"nextTaskId"`
+	// Completed lists the IDs of the tasks with a TaskCheckpoint, in the order
+	// they last completed.
+	Completed []string  `json:"completed"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Fingerprint is the hash of Chain and Input. Task outputs with another
+	// fingerprint were produced by a different execution and are not restored.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// TaskCheckpoint is the persisted output of a completed task.
+type TaskCheckpoint struct {
+	TaskID      string          `json:"taskId"`
+	Output      json.RawMessage `json:"output"`
+	OutputType  string          `json:"outputType"`
+	Fingerprint string          `json:"fingerprint"`
+}
+
+// CheckpointStore persists checkpoints keyed by checkpoint ID and task ID.
+type CheckpointStore interface {
+	// Save records the output of a completed task together with the updated checkpoint.
+	Save(ctx context.Context, cp Checkpoint, task TaskCheckpoint) error
+	// Load returns the checkpoint and its task outputs in completion order.
+	Load(ctx context.Context, checkpointID string) (*Checkpoint, []TaskCheckpoint, error)
+	// Delete removes the checkpoint and its task outputs.
+	Delete(ctx context.Context, checkpointID string) error
+}
+
+// WithCheckpoints persists the output of every task of executions that carry a
+// request ID, so that a failed execution can be continued with ResumeChain.
+// Checkpoints hold full task content, so executions with content capture
+// disabled, or with a task whose capture mode is not "full", are not
+// checkpointed. Checkpoints are removed once the chain completes.
+func WithCheckpoints(store CheckpointStore) EnvOption {
+	return func(e *SimpleEnv) {
+		e.checkpoints = store
+	}
+}
+
+// ResumableError is returned by executions that failed after a checkpoint was
+// saved. Passing CheckpointID to ResumeChain continues the execution.
+type ResumableError struct {
+	CheckpointID string
+	Err          error
+}
+
+func (e *ResumableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ResumableError) Unwrap() error {
+	return e.Err
+}
+
+// executionCheckpoint is the checkpoint state of a single execution.
+type executionCheckpoint struct {
+	// id is "" if the execution is not checkpointed.
+	id        string
+	requestID string
+	// save is false if task outputs must not be persisted, see checkpointCaptureAllowed.
+	save bool
+	// saved is set once a checkpoint of the execution exists.
+	saved bool
+}
+
+// resumePoint is where a resumed execution continues.
+type resumePoint struct {
+	checkpoint *Checkpoint
+	tasks      []TaskCheckpoint
+}
+
+// ResumeChain continues the failed execution with the given checkpoint ID,
+// see ResumableError, from the task after the last one that succeeded.
+// Completed tasks are not executed again; their checkpointed outputs are
+// restored as chain variables.
+func (exe SimpleEnv) ResumeChain(ctx context.Context, checkpointID string) (any, DataType, []CapturedStateUnit, error) {
+	if exe.checkpoints == nil {
+		return nil, DataTypeAny, nil, fmt.Errorf("checkpoints are not enabled %w", apiframework.ErrBadRequest)
+	}
+	cp, tasks, err := exe.checkpoints.Load(ctx, checkpointID)
+	if err != nil {
+		return nil, DataTypeAny, nil, err
+	}
+	input, inputType, err := decodeCheckpointValue(cp.Input, cp.InputType)
+	if err != nil {
+		return nil, DataTypeAny, nil, fmt.Errorf("checkpoint %s: restoring input: %w", checkpointID, err)
+	}
+	from := &resumePoint{checkpoint: cp, tasks: tasks}
+	return exe.run(ctx, cp.Chain, input, inputType, from)
+}
+
+// restore sets the chain variables from the checkpointed task outputs and
+// returns the output of the last completed task.
+func (from *resumePoint) restore(index taskIndex, vars map[string]any, varTypes map[string]DataType) (any, DataType, []string, error) {
+	var output any
+	outputType := DataTypeAny
+	completed := make([]string, 0, len(from.tasks))
+	for _, tc := range from.tasks {
+		task, err := index.find(tc.TaskID)
+		if err != nil {
+			return nil, DataTypeAny, nil, fmt.Errorf("checkpoint %s: restoring checkpoint: %w", from.checkpoint.ID, err)
+		}
+		output, outputType, err = decodeCheckpointValue(tc.Output, tc.OutputType)
+		if err != nil {
+			return nil, DataTypeAny, nil, fmt.Errorf("checkpoint %s: restoring task %s: %w", from.checkpoint.ID, tc.TaskID, err)
+		}
+		vars[task.ID], varTypes[task.ID] = output, outputType
+		if task.Compose != nil {
+			vars[task.ID+"_composed"], varTypes[task.ID+"_composed"] = output, outputType
+		}
+		completed = append(completed, task.ID)
+	}
+	vars["previous_output"], varTypes["previous_output"] = output, outputType
+	return output, outputType, completed, nil
+}
+
+// checkpointFor returns the checkpoint state of an execution of chain. New
+// executions that carry a request ID get a fresh checkpoint ID; a resumed
+// execution keeps its ID so the checkpoint is removed when it completes.
+func (exe SimpleEnv) checkpointFor(ctx context.Context, chain *TaskChainDefinition, from *resumePoint) *executionCheckpoint {
+	if exe.checkpoints == nil || chain.DryRun {
+		return &executionCheckpoint{}
+	}
+	if from != nil {
+		return &executionCheckpoint{
+			id:        from.checkpoint.ID,
+			requestID: from.checkpoint.RequestID,
+			save:      checkpointCaptureAllowed(ctx, chain),
+			saved:     true,
+		}
+	}
+	requestID, _ := ctx.Value(libtracker.ContextKeyRequestID).(string)
+	if requestID == "" || !checkpointCaptureAllowed(ctx, chain) {
+		return &executionCheckpoint{}
+	}
+	return &executionCheckpoint{id: uuid.NewString(), requestID: requestID, save: true}
+}
+
+// checkpointCaptureAllowed reports whether the full task content of chain may
+// be persisted for ctx.
+func checkpointCaptureAllowed(ctx context.Context, chain *TaskChainDefinition) bool {
+	if !libtracker.CaptureContentEnabled(ctx) {
+		return false
+	}
+	for _, task := range chain.Tasks {
+		if task.Capture != "" && task.Capture != CaptureFull {
+			return false
+		}
+	}
+	return true
+}
+
+// saveCheckpoint records the output of task. Failures are logged only, an
+// execution is not aborted because it cannot be checkpointed.
+func (exe SimpleEnv) saveCheckpoint(ctx context.Context, ec *executionCheckpoint, chain *TaskChainDefinition, input any, inputType DataType, completed []string, taskID string, output any, outputType DataType, next string) {
+	rawInput, err := json.Marshal(input)
+	if err != nil {
+		log.Printf("taskengine: checkpoint %s: encoding input: %v", ec.id, err)
+		return
+	}
+	rawOutput, err := json.Marshal(output)
+	if err != nil {
+		log.Printf("taskengine: checkpoint %s: encoding output of task %s: %v", ec.id, taskID, err)
+		return
+	}
+	fingerprint, err := checkpointFingerprint(chain, rawInput)
+	if err != nil {
+		log.Printf("taskengine: checkpoint %s: encoding chain: %v", ec.id, err)
+		return
+	}
+	cp := Checkpoint{
+		ID:          ec.id,
+		RequestID:   ec.requestID,
+		Chain:       chain,
+		Input:       rawInput,
+		InputType:   inputType.String(),
+		NextTaskID:  next,
+		Completed:   completed,
+		UpdatedAt:   time.Now().UTC(),
+		Fingerprint: fingerprint,
+	}
+	task := TaskCheckpoint{TaskID: taskID, Output: rawOutput, OutputType: outputType.String(), Fingerprint: fingerprint}
+	if err := exe.checkpoints.Save(ctx, cp, task); err != nil {
+		log.Printf("taskengine: checkpoint %s: %v", ec.id, err)
+		return
+	}
+	ec.saved = true
+}
+
+// checkpointFingerprint hashes the chain and input of an execution.
+func checkpointFingerprint(chain *TaskChainDefinition, rawInput json.RawMessage) (string, error) {
+	rawChain, err := json.Marshal(chain)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write(rawChain)
+	h.Write([]byte{0})
+	h.Write(rawInput)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// markCompleted moves taskID to the end of completed, so that tasks that run
+// more than once are listed at their last completion.
+func markCompleted(completed []string, taskID string) []string {
+	completed = slices.DeleteFunc(completed, func(id string) bool { return id == taskID })
+	return append(completed, taskID)
+}
+
+func decodeCheckpointValue(raw json.RawMessage, typeName string) (any, DataType, error) {
+	dataType, err := DataTypeFromString(typeName)
+	if err != nil {
+		return nil, DataTypeAny, err
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, DataTypeAny, err
+	}
+	value, err = ConvertToType(value, dataType)
+	if err != nil {
+		return nil, DataTypeAny, err
+	}
+	return value, dataType, nil
+}
+
+// KVCheckpointStore keeps checkpoints in the key-value store. Every entry
+// expires after the store's TTL, so abandoned executions are cleaned up.
+type KVCheckpointStore struct {
+	kvManager libkv.KVManager
+	ttl       time.Duration
+}
+
+// NewKVCheckpointStore creates a checkpoint store whose entries expire after ttl.
+func NewKVCheckpointStore(kvManager libkv.KVManager, ttl time.Duration) *KVCheckpointStore {
+	return &KVCheckpointStore{kvManager: kvManager, ttl: ttl}
+}
+
+func checkpointKey(checkpointID string) string {
+	return "checkpoint:" + checkpointID
+}
+
+func taskCheckpointKey(checkpointID, taskID string) string {
+	return "checkpoint:" + checkpointID + ":task:" + taskID
+}
+
+func (s *KVCheckpointStore) Save(ctx context.Context, cp Checkpoint, task TaskCheckpoint) error {
+	kv, err := s.kvManager.Executor(ctx)
+	if err != nil {
+		return err
+	}
+	rawTask, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	if err := kv.SetWithTTL(ctx, taskCheckpointKey(cp.ID, task.TaskID), rawTask, s.ttl); err != nil {
+		return err
+	}
+	rawCheckpoint, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return kv.SetWithTTL(ctx, checkpointKey(cp.ID), rawCheckpoint, s.ttl)
+}
+
+func (s *KVCheckpointStore) Load(ctx context.Context, checkpointID string) (*Checkpoint, []TaskCheckpoint, error) {
+	kv, err := s.kvManager.Executor(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, err := kv.Get(ctx, checkpointKey(checkpointID))
+	if errors.Is(err, libkv.ErrNotFound) {
+		return nil, nil, fmt.Errorf("no checkpoint %s %w", checkpointID, apiframework.ErrNotFound)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(raw, &cp); err != nil {
+		return nil, nil, fmt.Errorf("checkpoint %s: decoding checkpoint: %w", checkpointID, err)
+	}
+	tasks := make([]TaskCheckpoint, 0, len(cp.Completed))
+	for _, taskID := range cp.Completed {
+		raw, err := kv.Get(ctx, taskCheckpointKey(checkpointID, taskID))
+		if err != nil {
+			return nil, nil, fmt.Errorf("checkpoint %s: loading task %s: %w", checkpointID, taskID, err)
+		}
+		var task TaskCheckpoint
+		if err := json.Unmarshal(raw, &task); err != nil {
+			return nil, nil, fmt.Errorf("checkpoint %s: decoding task %s: %w", checkpointID, taskID, err)
+		}
+		if task.TaskID != taskID || task.Fingerprint != cp.Fingerprint {
+			return nil, nil, fmt.Errorf("checkpoint %s: output of task %s belongs to a different execution", checkpointID, taskID)
+		}
+		tasks = append(tasks, task)
+	}
+	return &cp, tasks, nil
+}
+
+func (s *KVCheckpointStore) Delete(ctx context.Context, checkpointID string) error {
+	kv, err := s.kvManager.Executor(ctx)
+	if err != nil {
+		return err
+	}
+	raw, err := kv.Get(ctx, checkpointKey(checkpointID))
+	if errors.Is(err, libkv.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(raw, &cp); err != nil {
+		return err
+	}
+	for _, taskID := range cp.Completed {
+		if err := kv.Delete(ctx, taskCheckpointKey(checkpointID, taskID)); err != nil && !errors.Is(err, libkv.ErrNotFound) {
+			return err
+		}
+	}
+	return kv.Delete(ctx, checkpointKey(checkpointID))
+}
@@ -0,0 +1,207 @@
+package taskengine_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyExec appends the task ID to its input and fails the task named failOn
+// on its first run.
+type flakyExec struct {
+	failOn string
+	failed bool
+	ran    []string
+	inputs map[string]any
+}
+
+func (f *flakyExec) TaskExec(_ context.Context, _ time.Time, _ int, task *taskengine.TaskDefinition, input any, _ taskengine.DataType) (any, taskengine.DataType, string, error) {
+	f.ran = append(f.ran, task.ID)
+	f.inputs[task.ID] = input
+	if task.ID == f.failOn && !f.failed {
+		f.failed = true
+		return nil, taskengine.DataTypeAny, "", errors.New("backend unavailable")
+	}
+	return fmt.Sprintf("%v>%s", input, task.ID), taskengine.DataTypeString, "ok", nil
+}
+
+func checkpointChain() *taskengine.TaskChainDefinition {
+	chain := &taskengine.TaskChainDefinition{ID: "pipeline"}
+	for i := 1; i <= 4; i++ {
+		next := fmt.Sprintf("task%d", i+1)
+		if i == 4 {
+			next = taskengine.TermEnd
+		}
+		chain.Tasks = append(chain.Tasks, taskengine.TaskDefinition{
+			ID:      fmt.Sprintf("task%d", i),
+			Handler: taskengine.HandleNoop,
+			Transition: taskengine.TaskTransition{
+				Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: next}},
+			},
+		})
+	}
+	chain.Tasks[3].InputVar = "task1"
+	return chain
+}
+
+func TestUnit_SimpleEnv_ResumeChain(t *testing.T) {
+	exec := &flakyExec{failOn: "task3", inputs: map[string]any{}}
+	kv := newMemKV()
+	store := taskengine.NewKVCheckpointStore(kv, time.Hour)
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector(), taskengine.WithCheckpoints(store))
+	require.NoError(t, err)
+
+	ctx := context.WithValue(t.Context(), libtracker.ContextKeyRequestID, "req-1")
+	_, _, _, err = env.ExecEnv(ctx, checkpointChain(), "in", taskengine.DataTypeString)
+	require.ErrorContains(t, err, "backend unavailable")
+	require.Equal(t, []string{"task1", "task2", "task3"}, exec.ran)
+	var resumable *taskengine.ResumableError
+	require.ErrorAs(t, err, &resumable)
+
+	cp, tasks, err := store.Load(t.Context(), resumable.CheckpointID)
+	require.NoError(t, err)
+	require.Equal(t, "req-1", cp.RequestID)
+	require.Equal(t, "task3", cp.NextTaskID)
+	require.Equal(t, []string{"task1", "task2"}, cp.Completed)
+	require.Len(t, tasks, 2)
+
+	// The resume runs under a different request; the checkpoint is found by ID.
+	exec.ran = nil
+	out, outType, _, err := env.ResumeChain(context.WithValue(t.Context(), libtracker.ContextKeyRequestID, "req-2"), resumable.CheckpointID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"task3", "task4"}, exec.ran, "tasks 1 and 2 are not executed again")
+	require.Equal(t, "in>task1>task2", exec.inputs["task3"])
+	require.Equal(t, "in>task1", exec.inputs["task4"], "restored task outputs stay addressable")
+	require.Equal(t, "in>task1>task4", out)
+	require.Equal(t, taskengine.DataTypeString, outType)
+
+	// Checkpoints are removed once the chain completes.
+	require.Empty(t, kv.values)
+	_, _, _, err = env.ResumeChain(t.Context(), resumable.CheckpointID)
+	require.ErrorIs(t, err, apiframework.ErrNotFound)
+}
+
+// inputExec appends the task ID to its input and fails task3 for inputs
+// listed in failOn.
+type inputExec struct {
+	mu     sync.Mutex
+	failOn map[string]bool
+}
+
+func (e *inputExec) TaskExec(_ context.Context, _ time.Time, _ int, task *taskengine.TaskDefinition, input any, _ taskengine.DataType) (any, taskengine.DataType, string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if task.ID == "task3" && e.failOn[strings.SplitN(fmt.Sprint(input), ">", 2)[0]] {
+		return nil, taskengine.DataTypeAny, "", errors.New("backend unavailable")
+	}
+	return fmt.Sprintf("%v>%s", input, task.ID), taskengine.DataTypeString, "ok", nil
+}
+
+func TestUnit_SimpleEnv_CheckpointsPerExecution(t *testing.T) {
+	exec := &inputExec{failOn: map[string]bool{"bad": true}}
+	kv := newMemKV()
+	store := taskengine.NewKVCheckpointStore(kv, time.Hour)
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector(), taskengine.WithCheckpoints(store))
+	require.NoError(t, err)
+
+	// Both executions share the request ID, like the items of a batch.
+	ctx := context.WithValue(t.Context(), libtracker.ContextKeyRequestID, "req-1")
+	inputs := []string{"good", "bad"}
+	errs := make([]error, len(inputs))
+	var wg sync.WaitGroup
+	for i, input := range inputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _, errs[i] = env.ExecEnv(ctx, checkpointChain(), input, taskengine.DataTypeString)
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, errs[0])
+	var resumable *taskengine.ResumableError
+	require.ErrorAs(t, errs[1], &resumable)
+
+	// The successful execution did not remove its sibling's checkpoint.
+	cp, tasks, err := store.Load(t.Context(), resumable.CheckpointID)
+	require.NoError(t, err)
+	require.JSONEq(t, `"bad"`, string(cp.Input))
+	require.Len(t, tasks, 2)
+
+	exec.mu.Lock()
+	exec.failOn = nil
+	exec.mu.Unlock()
+	out, _, _, err := env.ResumeChain(t.Context(), resumable.CheckpointID)
+	require.NoError(t, err)
+	require.Equal(t, "bad>task1>task4", out, "only outputs of the failed execution are restored")
+	require.Empty(t, kv.values)
+}
+
+func TestUnit_KVCheckpointStore_RejectsForeignTaskOutputs(t *testing.T) {
+	kv := newMemKV()
+	store := taskengine.NewKVCheckpointStore(kv, time.Hour)
+	cp := taskengine.Checkpoint{ID: "cp-1", Completed: []string{"task1"}, Fingerprint: "a"}
+	require.NoError(t, store.Save(t.Context(), cp, taskengine.TaskCheckpoint{TaskID: "task1", Output: json.RawMessage(`"x"`), OutputType: "string", Fingerprint: "b"}))
+
+	_, _, err := store.Load(t.Context(), "cp-1")
+	require.ErrorContains(t, err, "belongs to a different execution")
+}
+
+func TestUnit_SimpleEnv_CheckpointsNeedRequestID(t *testing.T) {
+	exec := &flakyExec{failOn: "task3", inputs: map[string]any{}}
+	kv := newMemKV()
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector(),
+		taskengine.WithCheckpoints(taskengine.NewKVCheckpointStore(kv, time.Hour)))
+	require.NoError(t, err)
+
+	_, _, _, err = env.ExecEnv(t.Context(), checkpointChain(), "in", taskengine.DataTypeString)
+	require.Error(t, err)
+	require.Empty(t, kv.values)
+}
+
+func TestUnit_SimpleEnv_ResumeChainWithoutCheckpoints(t *testing.T) {
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, &taskengine.MockTaskExecutor{}, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+	_, _, _, err = env.ResumeChain(t.Context(), "req-1")
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+}
+
+func TestUnit_SimpleEnv_CheckpointsRespectCapture(t *testing.T) {
+	t.Run("capture disabled", func(t *testing.T) {
+		exec := &flakyExec{failOn: "task3", inputs: map[string]any{}}
+		kv := newMemKV()
+		env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector(),
+			taskengine.WithCheckpoints(taskengine.NewKVCheckpointStore(kv, time.Hour)))
+		require.NoError(t, err)
+
+		ctx := context.WithValue(t.Context(), libtracker.ContextKeyRequestID, "req-1")
+		ctx = libtracker.WithCaptureContent(ctx, false)
+		_, _, _, err = env.ExecEnv(ctx, checkpointChain(), "in", taskengine.DataTypeString)
+		require.Error(t, err)
+		require.Empty(t, kv.values)
+	})
+
+	t.Run("task not fully captured", func(t *testing.T) {
+		exec := &flakyExec{failOn: "task3", inputs: map[string]any{}}
+		kv := newMemKV()
+		env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector(),
+			taskengine.WithCheckpoints(taskengine.NewKVCheckpointStore(kv, time.Hour)))
+		require.NoError(t, err)
+
+		chain := checkpointChain()
+		chain.Tasks[1].Capture = taskengine.CaptureSummary
+		ctx := context.WithValue(t.Context(), libtracker.ContextKeyRequestID, "req-1")
+		_, _, _, err = env.ExecEnv(ctx, chain, "in", taskengine.DataTypeString)
+		require.Error(t, err)
+		require.Empty(t, kv.values)
+	})
+}
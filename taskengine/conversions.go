@@ -0,0 +1,95 @@
+package taskengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// TypeConverter converts a value of one DataType into another.
+type TypeConverter func(value any) (any, error)
+
+type conversionKey struct {
+	from DataType
+	to   DataType
+}
+
+var (
+	conversionsMu sync.RWMutex
+	conversions   = map[conversionKey]TypeConverter{
+		{DataTypeChatHistory, DataTypeString}:   chatHistoryToString,
+		{DataTypeString, DataTypeJSON}:          stringToJSON,
+		{DataTypeSearchResults, DataTypeString}: searchResultsToString,
+	}
+)
+
+// RegisterConversion adds or replaces the converter used when a task declares
+// the input type to but receives from.
+func RegisterConversion(from, to DataType, fn TypeConverter) {
+	conversionsMu.Lock()
+	defer conversionsMu.Unlock()
+	conversions[conversionKey{from, to}] = fn
+}
+
+// Convert converts value from one DataType to another using the registered converters.
+// It returns ErrTypeMismatch if no converter exists for the pair.
+func Convert(value any, from, to DataType) (any, error) {
+	if from == to {
+		return value, nil
+	}
+	conversionsMu.RLock()
+	fn, ok := conversions[conversionKey{from, to}]
+	conversionsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: no conversion from %s to %s", ErrTypeMismatch, from.String(), to.String())
+	}
+	return fn(value)
+}
+
+func hasConversion(from, to DataType) bool {
+	conversionsMu.RLock()
+	defer conversionsMu.RUnlock()
+	_, ok := conversions[conversionKey{from, to}]
+	return ok
+}
+
+// chatHistoryToString returns the content of the last assistant message.
+func chatHistoryToString(value any) (any, error) {
+	history, ok := value.(ChatHistory)
+	if !ok {
+		return nil, fmt.Errorf("expected ChatHistory, got %T", value)
+	}
+	for i := len(history.Messages) - 1; i >= 0; i-- {
+		if history.Messages[i].Role == "assistant" {
+			return history.Messages[i].Content, nil
+		}
+	}
+	return nil, fmt.Errorf("chat history has no assistant message")
+}
+
+// stringToJSON parses a JSON document from a string.
+func stringToJSON(value any) (any, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected string, got %T", value)
+	}
+	var parsed any
+	if err := json.Unmarshal([]byte(str), &parsed); err != nil {
+		return nil, fmt.Errorf("string is not valid JSON: %w", err)
+	}
+	return parsed, nil
+}
+
+// searchResultsToString renders one search result per line.
+func searchResultsToString(value any) (any, error) {
+	results, ok := value.([]SearchResult)
+	if !ok {
+		return nil, fmt.Errorf("expected []SearchResult, got %T", value)
+	}
+	lines := make([]string, len(results))
+	for i, r := range results {
+		lines[i] = fmt.Sprintf("%s (%s, distance %.4f)", r.ID, r.ResourceType, r.Distance)
+	}
+	return strings.Join(lines, "\n"), nil
+}
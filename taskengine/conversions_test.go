@@ -0,0 +1,77 @@
+package taskengine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_Convert_ChatHistoryToString(t *testing.T) {
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "first"},
+		{Role: "user", Content: "again"},
+		{Role: "assistant", Content: "last"},
+	}}
+	got, err := taskengine.Convert(history, taskengine.DataTypeChatHistory, taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "last", got)
+
+	_, err = taskengine.Convert(taskengine.ChatHistory{}, taskengine.DataTypeChatHistory, taskengine.DataTypeString)
+	require.Error(t, err)
+}
+
+func TestUnit_Convert_StringToJSON(t *testing.T) {
+	got, err := taskengine.Convert(`{"a": [1, 2]}`, taskengine.DataTypeString, taskengine.DataTypeJSON)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"a": []any{1.0, 2.0}}, got)
+
+	_, err = taskengine.Convert("not json", taskengine.DataTypeString, taskengine.DataTypeJSON)
+	require.Error(t, err)
+}
+
+func TestUnit_Convert_SearchResultsToString(t *testing.T) {
+	results := []taskengine.SearchResult{
+		{ID: "doc1", ResourceType: "document", Distance: 0.1},
+		{ID: "doc2", ResourceType: "chunk", Distance: 0.25},
+	}
+	got, err := taskengine.Convert(results, taskengine.DataTypeSearchResults, taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "doc1 (document, distance 0.1000)\ndoc2 (chunk, distance 0.2500)", got)
+}
+
+func TestUnit_Convert_UnsupportedPair(t *testing.T) {
+	_, err := taskengine.Convert(1.5, taskengine.DataTypeFloat, taskengine.DataTypeChatHistory)
+	require.ErrorIs(t, err, taskengine.ErrTypeMismatch)
+}
+
+func TestUnit_SimpleEnv_ExecEnv_ConvertsDeclaredInputType(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{MockOutput: "ok"}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:         "consume",
+				Handler:    taskengine.HandleNoop,
+				InputTypes: []taskengine.DataType{taskengine.DataTypeString},
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{
+		{Role: "user", Content: "question"},
+		{Role: "assistant", Content: "answer"},
+	}}
+	_, _, _, err = env.ExecEnv(context.Background(), chain, history, taskengine.DataTypeChatHistory)
+	require.NoError(t, err)
+	require.Equal(t, "answer", mockExec.CalledWithInput)
+}
@@ -23,6 +23,11 @@ func ConvertChatHistoryToOpenAI(id string, chatHistory ChatHistory, config *LLME
 		resp.Model = config.Model
 	}
 
+	finishReason := chatHistory.FinishReason
+	if finishReason == "" {
+		finishReason = "stop"
+	}
+
 	// The last message in the history is assumed to be the assistant's completion.
 	if len(chatHistory.Messages) > 0 {
 		lastMessage := chatHistory.Messages[len(chatHistory.Messages)-1]
@@ -32,7 +37,7 @@ func ConvertChatHistoryToOpenAI(id string, chatHistory ChatHistory, config *LLME
 				Role:    lastMessage.Role,
 				Content: lastMessage.Content,
 			},
-			FinishReason: "stop",
+			FinishReason: finishReason,
 		}
 		resp.Choices = append(resp.Choices, choice)
 	}
@@ -49,16 +54,35 @@ func ConvertOpenAIToChatHistory(request OpenAIChatRequest) (ChatHistory, int, LL
 	}
 
 	for _, reqMsg := range request.Messages {
+		// Unknown roles are kept as sent; they are rejected once the history
+		// reaches a model task.
+		role, err := NormalizeRole(reqMsg.Role)
+		if err != nil {
+			role = reqMsg.Role
+		}
 		chatHistory.Messages = append(chatHistory.Messages, Message{
-			Role:      reqMsg.Role,
+			Role:      role,
 			Content:   reqMsg.Content,
 			Timestamp: time.Now().UTC(),
 		})
 	}
 
 	config := LLMExecutionConfig{
-		Model:       request.Model,
-		Temperature: float32(request.Temperature),
+		Model:     request.Model,
+		TopP:      request.TopP,
+		MaxTokens: request.MaxTokens,
+		Stop:      request.Stop,
+		KeepAlive: request.KeepAlive,
+	}
+	if request.Temperature != nil {
+		temperature := float32(*request.Temperature)
+		config.Temperature = &temperature
+	}
+	if rf := request.ResponseFormat; rf != nil {
+		config.ResponseFormat = &ResponseFormat{Type: rf.Type}
+		if rf.JSONSchema != nil {
+			config.ResponseFormat.Schema = rf.JSONSchema.Schema
+		}
 	}
 
 	return chatHistory, request.MaxTokens, config
@@ -78,12 +102,9 @@ func ConvertChatHistoryToOpenAIRequest(
 		})
 	}
 
-	temperature := 0.0
-
 	return OpenAIChatRequest{
 		Model:            model,
 		Messages:         messages,
-		Temperature:      temperature,
 		TopP:             0.0,
 		PresencePenalty:  0.0,
 		FrequencyPenalty: 0.0,
@@ -0,0 +1,85 @@
+package taskengine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// endlessCycles returns the transition cycles of tasks that can never reach
+// the end of the chain, each as the task IDs along the cycle with the first
+// task repeated at the end. Branches marked as Loop are left out, and so are
+// cycles that have a branch leaving them, since their exit condition may hold.
+func endlessCycles(tasks []TaskDefinition) [][]string {
+	index := make(map[string]*TaskDefinition, len(tasks))
+	for i := range tasks {
+		index[tasks[i].ID] = &tasks[i]
+	}
+
+	// A task terminates if a branch leads to the end of the chain, to a task
+	// that terminates, or to something that is not a task and fails the chain.
+	terminates := map[string]bool{}
+	for changed := true; changed; {
+		changed = false
+		for i := range tasks {
+			task := &tasks[i]
+			if terminates[task.ID] {
+				continue
+			}
+			for _, branch := range task.Transition.Branches {
+				_, isTask := index[branch.Goto]
+				if !isTask || terminates[branch.Goto] {
+					terminates[task.ID] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := map[string]int{}
+	var path []string
+	var cycles [][]string
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = onPath
+		path = append(path, id)
+		for _, branch := range index[id].Transition.Branches {
+			next := branch.Goto
+			if branch.Loop || terminates[next] {
+				continue
+			}
+			if _, isTask := index[next]; !isTask {
+				continue
+			}
+			switch state[next] {
+			case onPath:
+				start := len(path) - 1
+				for path[start] != next {
+					start--
+				}
+				cycle := append([]string{}, path[start:]...)
+				cycles = append(cycles, append(cycle, next))
+			case unvisited:
+				visit(next)
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+	}
+	for i := range tasks {
+		if id := tasks[i].ID; !terminates[id] && state[id] == unvisited {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// cycleWarning describes a cycle found by endlessCycles.
+func cycleWarning(cycle []string) string {
+	return fmt.Sprintf("transition cycle %s never reaches the end of the chain, mark intended loops with loop: true", strings.Join(cycle, " -> "))
+}
@@ -0,0 +1,65 @@
+package taskengine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ TaskExecutor = dryRunExecutor{}
+
+// dryRunExecutor stands in for the real TaskExecutor when a chain is executed with DryRun set.
+//
+// It never calls a model or a hook. Every task passes its input through unchanged,
+// except hook tasks which output a description of the call they would have made.
+// The transition value is stubbed so that the first branch is taken.
+type dryRunExecutor struct{}
+
+func (dryRunExecutor) TaskExec(_ context.Context, _ time.Time, _ int, task *TaskDefinition, input any, dataType DataType) (any, DataType, string, error) {
+	transition := stubTransition(task.Transition)
+	if task.Handler == HandleHook {
+		if task.Hook == nil {
+			return nil, DataTypeAny, "", fmt.Errorf("hook task %s has no hook defined", task.ID)
+		}
+		return fmt.Sprintf("would call hook %q with args %v", task.Hook.Name, task.Hook.Args), DataTypeString, transition, nil
+	}
+	return input, dataType, transition, nil
+}
+
+// stubTransition returns a transition value that satisfies the first branch.
+func stubTransition(transition TaskTransition) string {
+	if len(transition.Branches) == 0 {
+		return ""
+	}
+	branch := transition.Branches[0]
+	switch branch.Operator {
+	case OpGreaterThan, OpGt:
+		if n, err := parseNumber(branch.When); err == nil {
+			return strconv.FormatFloat(n+1, 'f', -1, 64)
+		}
+	case OpLessThan, OpLt:
+		if n, err := parseNumber(branch.When); err == nil {
+			return strconv.FormatFloat(n-1, 'f', -1, 64)
+		}
+	case OpInRange:
+		if lower, _, ok := strings.Cut(branch.When, "-"); ok {
+			return strings.TrimSpace(lower)
+		}
+	}
+	return branch.When
+}
+
+// SkippedTransition is the transition value of a task skipped for its input type.
+const SkippedTransition = "skipped"
+
+var _ TaskExecutor = skipExecutor{}
+
+// skipExecutor stands in for the real TaskExecutor on a task skipped because of
+// its input type. The input passes through unchanged.
+type skipExecutor struct{}
+
+func (skipExecutor) TaskExec(_ context.Context, _ time.Time, _ int, _ *TaskDefinition, input any, dataType DataType) (any, DataType, string, error) {
+	return input, dataType, SkippedTransition, nil
+}
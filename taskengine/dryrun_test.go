@@ -0,0 +1,61 @@
+package taskengine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_SimpleEnv_ExecEnv_DryRun(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{
+		MockOutput:          "should not be used",
+		MockTransitionValue: "ok",
+	}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		DryRun: true,
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:             "classify",
+				Handler:        taskengine.HandleConditionKey,
+				PromptTemplate: `Is this spam? {{.input}}`,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpEquals, When: "yes", Goto: "notify"},
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+			{
+				ID:      "notify",
+				Handler: taskengine.HandleHook,
+				Hook: &taskengine.HookCall{
+					Name: "send_email",
+					Args: map[string]string{"to": "admin"},
+				},
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+
+	_, _, state, err := env.ExecEnv(context.Background(), chain, "buy now", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, 0, mockExec.CallCount(), "dry run must not execute any task")
+
+	require.Len(t, state, 2)
+	require.Equal(t, "classify", state[0].TaskID)
+	require.Equal(t, "Is this spam? buy now", state[0].Input)
+	require.Equal(t, "yes", state[0].Transition)
+	require.Equal(t, "notify", state[1].TaskID)
+	require.Contains(t, state[1].Output, `would call hook "send_email"`)
+	require.Contains(t, state[1].Output, "to:admin")
+}
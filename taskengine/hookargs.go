@@ -0,0 +1,94 @@
+package taskengine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/contenox/runtime/internal/apiframework"
+)
+
+// HookArg declares an argument a hook accepts.
+type HookArg struct {
+	Name        string `json:"name" example:"query"`
+	Description string `json:"description,omitempty" example:"Name of the allowlisted query to run."`
+	// Type names the expected format of the value, e.g. "string", "int" or "float".
+	Type     string `json:"type" example:"string"`
+	Required bool   `json:"required" example:"true"`
+	// Check validates a present value. A nil Check accepts any value.
+	Check func(value string) error `json:"-"`
+}
+
+// HookSchema describes a hook for chain authors: what it does, the args it
+// accepts and the data types it consumes and produces.
+type HookSchema struct {
+	Name        string    `json:"name" example:"sql_query"`
+	Description string    `json:"description" example:"Runs an allowlisted read-only SQL query."`
+	Args        []HookArg `json:"args"`
+	InputType   DataType  `json:"inputType" example:"any" openapi_include_type:"string"`
+	OutputType  DataType  `json:"outputType" example:"json" openapi_include_type:"string"`
+}
+
+// CheckHookArgs validates args against the arguments a hook declares.
+// Arguments the hook does not declare are ignored.
+func CheckHookArgs(hook string, declared []HookArg, args map[string]string) error {
+	for _, arg := range declared {
+		value, ok := args[arg.Name]
+		if !ok {
+			if arg.Required {
+				return fmt.Errorf("hook %s: missing required arg %q", hook, arg.Name)
+			}
+			continue
+		}
+		if arg.Check == nil {
+			continue
+		}
+		if err := arg.Check(value); err != nil {
+			return fmt.Errorf("hook %s: arg %q: %w", hook, arg.Name, err)
+		}
+	}
+	return nil
+}
+
+// PositiveInt is a HookArg check accepting integers greater than zero.
+func PositiveInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return fmt.Errorf("%q is not a positive integer", value)
+	}
+	return nil
+}
+
+// NonNegativeFloat is a HookArg check accepting numbers greater than or equal to zero.
+func NonNegativeFloat(value string) error {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		return fmt.Errorf("%q is not a non-negative number", value)
+	}
+	return nil
+}
+
+// ValidateChainHooks checks the args of every hook task in chain with hooks,
+// so that misconfigured hooks are reported before any task runs.
+func ValidateChainHooks(ctx context.Context, hooks HookRepo, chain *TaskChainDefinition) error {
+	if _, err := validateChain(chain.Tasks, chain.RequireDefaultBranch); err != nil {
+		return err
+	}
+	if chain.OnComplete != nil {
+		if err := chain.OnComplete.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, task := range chain.Tasks {
+		if task.Handler != HandleHook {
+			continue
+		}
+		if task.Hook == nil {
+			return fmt.Errorf("task %s: hook task missing hook definition %w", task.ID, apiframework.ErrBadRequest)
+		}
+		if err := hooks.ValidateArgs(ctx, task.Hook); err != nil {
+			return fmt.Errorf("task %s: %w: %w", task.ID, err, apiframework.ErrBadRequest)
+		}
+	}
+	return nil
+}
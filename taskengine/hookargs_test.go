@@ -0,0 +1,120 @@
+package taskengine_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// searchHook declares the args of a vector search hook: top_k must be positive
+// and radius is only meaningful together with epsilon.
+type searchHook struct{}
+
+var searchArgs = []taskengine.HookArg{
+	{Name: "top_k", Type: "int", Check: taskengine.PositiveInt},
+	{Name: "epsilon", Type: "float", Check: taskengine.NonNegativeFloat},
+	{Name: "radius", Type: "float", Check: taskengine.NonNegativeFloat},
+}
+
+func (searchHook) Exec(context.Context, time.Time, any, taskengine.DataType, string, *taskengine.HookCall) (any, taskengine.DataType, string, error) {
+	return nil, taskengine.DataTypeAny, "", errors.New("not executed in validation tests")
+}
+
+func (searchHook) ValidateArgs(_ context.Context, call *taskengine.HookCall) error {
+	if err := taskengine.CheckHookArgs(call.Name, searchArgs, call.Args); err != nil {
+		return err
+	}
+	_, hasRadius := call.Args["radius"]
+	_, hasEpsilon := call.Args["epsilon"]
+	if hasRadius && !hasEpsilon {
+		return errors.New("radius requires epsilon")
+	}
+	return nil
+}
+
+func (searchHook) Supports(context.Context) ([]string, error) {
+	return []string{"vector_search"}, nil
+}
+
+func (searchHook) Schemas(context.Context) ([]taskengine.HookSchema, error) {
+	return []taskengine.HookSchema{{
+		Name:       "vector_search",
+		Args:       searchArgs,
+		InputType:  taskengine.DataTypeString,
+		OutputType: taskengine.DataTypeSearchResults,
+	}}, nil
+}
+
+func searchChain(args map[string]string) *taskengine.TaskChainDefinition {
+	return &taskengine.TaskChainDefinition{
+		ID: "rag",
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:      "search",
+				Handler: taskengine.HandleHook,
+				Hook:    &taskengine.HookCall{Name: "vector_search", Args: args},
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestUnit_ValidateChainHooks_AcceptsValidArgs(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, taskengine.ValidateChainHooks(ctx, searchHook{}, searchChain(nil)))
+	require.NoError(t, taskengine.ValidateChainHooks(ctx, searchHook{}, searchChain(map[string]string{
+		"top_k": "5", "epsilon": "0.1", "radius": "0.5",
+	})))
+}
+
+func TestUnit_ValidateChainHooks_RejectsBadArgs(t *testing.T) {
+	ctx := context.Background()
+	for name, tc := range map[string]struct {
+		args map[string]string
+		msg  string
+	}{
+		"negative top_k":         {map[string]string{"top_k": "-1"}, `arg "top_k"`},
+		"non-numeric top_k":      {map[string]string{"top_k": "five"}, `arg "top_k"`},
+		"negative epsilon":       {map[string]string{"epsilon": "-0.5"}, `arg "epsilon"`},
+		"radius without epsilon": {map[string]string{"radius": "0.5"}, "radius requires epsilon"},
+	} {
+		err := taskengine.ValidateChainHooks(ctx, searchHook{}, searchChain(tc.args))
+		require.ErrorIs(t, err, apiframework.ErrBadRequest, name)
+		require.ErrorContains(t, err, "task search", name)
+		require.ErrorContains(t, err, tc.msg, name)
+	}
+}
+
+func TestUnit_CheckHookArgs_Required(t *testing.T) {
+	declared := []taskengine.HookArg{{Name: "query", Required: true}}
+	require.ErrorContains(t, taskengine.CheckHookArgs("sql_query", declared, nil), `missing required arg "query"`)
+	require.NoError(t, taskengine.CheckHookArgs("sql_query", declared, map[string]string{"query": "q", "extra": "ignored"}))
+}
+
+func TestUnit_HookSchema_JSON(t *testing.T) {
+	schemas, err := searchHook{}.Schemas(context.Background())
+	require.NoError(t, err)
+	data, err := json.Marshal(schemas[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"name": "vector_search",
+		"description": "",
+		"args": [
+			{"name": "top_k", "type": "int", "required": false},
+			{"name": "epsilon", "type": "float", "required": false},
+			{"name": "radius", "type": "float", "required": false}
+		],
+		"inputType": "string",
+		"outputType": "search_results"
+	}`, string(data))
+}
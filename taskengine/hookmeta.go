@@ -0,0 +1,48 @@
+package taskengine
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+)
+
+// SetHookMetadata records a metadata value, like a classifier's confidence,
+// alongside the output of the running hook. Transition branches with Meta set
+// compare against it. Outside of a task execution it does nothing.
+func SetHookMetadata(ctx context.Context, key string, value any) {
+	sink, ok := ctx.Value(hookMetaKey{}).(*hookMetaSink)
+	if !ok {
+		return
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	sink.values[key] = value
+}
+
+type hookMetaKey struct{}
+
+type hookMetaSink struct {
+	mu     sync.Mutex
+	values map[string]any
+}
+
+// withHookMetadata returns a context hooks record their metadata in for a single task attempt.
+func withHookMetadata(ctx context.Context) (context.Context, *hookMetaSink) {
+	sink := &hookMetaSink{values: map[string]any{}}
+	return context.WithValue(ctx, hookMetaKey{}, sink), sink
+}
+
+func (s *hookMetaSink) snapshot() map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.values)
+}
+
+// metaString formats a metadata value for comparison with a branch's When.
+func metaString(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,79 @@
+package taskengine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// classifierHook labels its input and reports how confident it is as metadata.
+type classifierHook struct {
+	confidence float64
+}
+
+func (h classifierHook) Exec(ctx context.Context, _ time.Time, _ any, _ taskengine.DataType, _ string, _ *taskengine.HookCall) (any, taskengine.DataType, string, error) {
+	taskengine.SetHookMetadata(ctx, "confidence", h.confidence)
+	return "billing", taskengine.DataTypeString, "billing", nil
+}
+
+func (classifierHook) ValidateArgs(context.Context, *taskengine.HookCall) error { return nil }
+
+func (classifierHook) Supports(context.Context) ([]string, error) {
+	return []string{"classify"}, nil
+}
+
+func (classifierHook) Schemas(context.Context) ([]taskengine.HookSchema, error) { return nil, nil }
+
+func TestUnit_SimpleEnv_ExecEnv_BranchOnHookMetadata(t *testing.T) {
+	end := taskengine.TaskTransition{
+		Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd}},
+	}
+	chain := &taskengine.TaskChainDefinition{
+		ID: "classify",
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:      "classify",
+				Handler: taskengine.HandleHook,
+				Hook:    &taskengine.HookCall{Name: "classify"},
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpGreaterThan, Meta: "confidence", When: "0.8", Goto: "route"},
+						{Operator: taskengine.OpEquals, Meta: "missing", When: "billing", Goto: "route"},
+						{Operator: taskengine.OpDefault, Goto: "escalate"},
+					},
+				},
+			},
+			{ID: "route", Handler: taskengine.HandleNoop, PromptTemplate: "routed", Transition: end},
+			{ID: "escalate", Handler: taskengine.HandleNoop, PromptTemplate: "escalated", Transition: end},
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		confidence float64
+		want       string
+	}{
+		"confident":   {confidence: 0.93, want: "routed"},
+		"unconfident": {confidence: 0.41, want: "escalated"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			exec, err := taskengine.NewExec(t.Context(), &fakeRuntime{}, classifierHook{confidence: tc.confidence}, libtracker.NoopTracker{})
+			require.NoError(t, err)
+			env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector())
+			require.NoError(t, err)
+
+			out, _, _, err := env.ExecEnv(t.Context(), chain, "my invoice is wrong", taskengine.DataTypeString)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, out)
+		})
+	}
+}
+
+func TestUnit_SetHookMetadata_OutsideExecution(t *testing.T) {
+	require.NotPanics(t, func() {
+		taskengine.SetHookMetadata(context.Background(), "confidence", 0.5)
+	})
+}
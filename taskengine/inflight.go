@@ -0,0 +1,160 @@
+package taskengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/google/uuid"
+)
+
+// ErrExecutionCancelled is the cause of executions cancelled through the
+// InFlightRegistry.
+var ErrExecutionCancelled = errors.New("execution cancelled by operator")
+
+// InFlightExecution describes a running chain execution.
+type InFlightExecution struct {
+	RequestID   string    `json:"requestId" example:"b6f1c0de-2f4a-4a8e-9a51-6f2d1e0c9b11"`
+	ChainID     string    `json:"chainId" example:"openai-compatible-chain"`
+	StartedAt   time.Time `json:"startedAt" example:"2025-01-01T12:00:00Z"`
+	CurrentTask string    `json:"currentTask" example:"generate_reply"`
+}
+
+// InFlightRegistry keeps track of the running executions of the environments
+// it is passed to with WithInFlight, and cancels them on request.
+type InFlightRegistry struct {
+	mu         sync.Mutex
+	executions map[*inFlightEntry]struct{}
+}
+
+// NewInFlightRegistry creates an empty registry.
+func NewInFlightRegistry() *InFlightRegistry {
+	return &InFlightRegistry{executions: map[*inFlightEntry]struct{}{}}
+}
+
+// WithInFlight registers every execution with registry while it runs.
+func WithInFlight(registry *InFlightRegistry) EnvOption {
+	return func(e *SimpleEnv) {
+		e.inFlight = registry
+	}
+}
+
+type inFlightEntry struct {
+	mu   sync.Mutex
+	info InFlightExecution
+	// detached is cancelled by Cancel only; tasks that run detached from the
+	// caller's context derive from it.
+	detached context.Context
+	cancel   context.CancelCauseFunc
+}
+
+func (e *inFlightEntry) setTask(taskID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.info.CurrentTask = taskID
+}
+
+func (e *inFlightEntry) snapshot() InFlightExecution {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.info
+}
+
+type inFlightKey struct{}
+
+// track registers an execution of chain. The returned context is cancelled
+// when ctx is or when the execution is cancelled through the registry; stop
+// must be called once the execution ended.
+func (r *InFlightRegistry) track(ctx context.Context, chain *TaskChainDefinition) (context.Context, func()) {
+	requestID, _ := ctx.Value(libtracker.ContextKeyRequestID).(string)
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	detached, cancelDetached := context.WithCancelCause(context.Background())
+	ctx, cancel := context.WithCancelCause(ctx)
+	stopPropagation := context.AfterFunc(detached, func() {
+		cancel(context.Cause(detached))
+	})
+	entry := &inFlightEntry{
+		info: InFlightExecution{
+			RequestID: requestID,
+			ChainID:   chain.ID,
+			StartedAt: time.Now().UTC(),
+		},
+		detached: detached,
+		cancel:   cancelDetached,
+	}
+
+	r.mu.Lock()
+	r.executions[entry] = struct{}{}
+	r.mu.Unlock()
+
+	stop := func() {
+		r.mu.Lock()
+		delete(r.executions, entry)
+		r.mu.Unlock()
+		stopPropagation()
+		cancelDetached(nil)
+		cancel(nil)
+	}
+	return context.WithValue(ctx, inFlightKey{}, entry), stop
+}
+
+// List returns the running executions, oldest first.
+func (r *InFlightRegistry) List() []InFlightExecution {
+	r.mu.Lock()
+	entries := make([]*inFlightEntry, 0, len(r.executions))
+	for entry := range r.executions {
+		entries = append(entries, entry)
+	}
+	r.mu.Unlock()
+
+	executions := make([]InFlightExecution, len(entries))
+	for i, entry := range entries {
+		executions[i] = entry.snapshot()
+	}
+	slices.SortFunc(executions, func(a, b InFlightExecution) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	return executions
+}
+
+// Cancel cancels the running executions of requestID, including their model
+// and hook calls in progress.
+func (r *InFlightRegistry) Cancel(requestID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found := false
+	for entry := range r.executions {
+		if entry.info.RequestID == requestID {
+			entry.cancel(ErrExecutionCancelled)
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("no execution in flight for request %s %w", requestID, apiframework.ErrNotFound)
+	}
+	return nil
+}
+
+// inFlightFrom returns the registry entry of the execution running in ctx, if any.
+func inFlightFrom(ctx context.Context) *inFlightEntry {
+	entry, _ := ctx.Value(inFlightKey{}).(*inFlightEntry)
+	return entry
+}
+
+// detachedContext returns the context tasks run in when they are not bound to
+// the caller's deadline: it carries the tracking values of ctx and is only
+// cancelled when the execution is cancelled through the InFlightRegistry.
+func detachedContext(ctx context.Context) context.Context {
+	base := context.Background()
+	if entry := inFlightFrom(ctx); entry != nil {
+		base = entry.detached
+	}
+	return libtracker.CopyTrackingValues(ctx, base)
+}
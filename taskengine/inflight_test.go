@@ -0,0 +1,66 @@
+package taskengine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingExec stands in for a slow model call: it blocks until its context ends.
+type blockingExec struct {
+	started chan struct{}
+}
+
+func (b blockingExec) TaskExec(ctx context.Context, _ time.Time, _ int, _ *taskengine.TaskDefinition, _ any, _ taskengine.DataType) (any, taskengine.DataType, string, error) {
+	close(b.started)
+	<-ctx.Done()
+	return nil, taskengine.DataTypeAny, "", context.Cause(ctx)
+}
+
+func TestUnit_InFlightRegistry_ListAndCancel(t *testing.T) {
+	registry := taskengine.NewInFlightRegistry()
+	exec := blockingExec{started: make(chan struct{})}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector(), taskengine.WithInFlight(registry))
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		ID: "slow",
+		Tasks: []taskengine.TaskDefinition{{
+			ID:      "generate",
+			Handler: taskengine.HandleNoop,
+			Transition: taskengine.TaskTransition{
+				Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd}},
+			},
+		}},
+	}
+	ctx := context.WithValue(t.Context(), libtracker.ContextKeyRequestID, "req-1")
+	done := make(chan error, 1)
+	go func() {
+		_, _, _, err := env.ExecEnv(ctx, chain, "in", taskengine.DataTypeString)
+		done <- err
+	}()
+	<-exec.started
+
+	running := registry.List()
+	require.Len(t, running, 1)
+	require.Equal(t, "req-1", running[0].RequestID)
+	require.Equal(t, "slow", running[0].ChainID)
+	require.Equal(t, "generate", running[0].CurrentTask)
+	require.False(t, running[0].StartedAt.IsZero())
+
+	require.ErrorIs(t, registry.Cancel("req-2"), apiframework.ErrNotFound)
+	require.NoError(t, registry.Cancel("req-1"))
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, taskengine.ErrExecutionCancelled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled execution did not stop")
+	}
+	require.Empty(t, registry.List())
+}
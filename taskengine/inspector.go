@@ -41,10 +41,15 @@ type CapturedStateUnit struct {
 	InputType   DataType      `json:"inputType" example:"string" openapi_include_type:"string"`
 	OutputType  DataType      `json:"outputType" example:"string" openapi_include_type:"string"`
 	Transition  string        `json:"transition" example:"valid_input"`
+	Skipped     bool          `json:"skipped,omitempty" example:"false"`
 	Duration    time.Duration `json:"duration" example:"452000000"` // in nanoseconds
 	Error       ErrorResponse `json:"error" openapi_include_type:"taskengine.ErrorResponse"`
 	Input       string        `json:"input" example:"This is a test input that needs validation"`
 	Output      string        `json:"output" example:"valid"`
+
+	// InputTokens and OutputTokens are the tokens consumed by the model calls of the task.
+	InputTokens  int `json:"inputTokens,omitempty" example:"120"`
+	OutputTokens int `json:"outputTokens,omitempty" example:"48"`
 }
 
 type ErrorResponse struct {
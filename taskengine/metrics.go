@@ -0,0 +1,125 @@
+package taskengine
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics receives execution measurements from SimpleEnv and the hook layer.
+// Use NoopMetrics when metrics are disabled.
+type Metrics interface {
+	// ChainStarted is called once per ExecEnv invocation.
+	ChainStarted()
+	// ChainFinished is called when the chain returns; err is nil on success.
+	ChainFinished(duration time.Duration, err error)
+	// TaskFinished is called after every task attempt.
+	TaskFinished(taskType TaskHandler, duration time.Duration, err error)
+	// TaskRetried is called before each retry of a failed task.
+	TaskRetried(taskType TaskHandler)
+	// HookFailed is called when a hook returns an error.
+	HookFailed(hook string)
+}
+
+// NoopMetrics discards all measurements.
+type NoopMetrics struct{}
+
+func (NoopMetrics) ChainStarted()                                  {}
+func (NoopMetrics) ChainFinished(time.Duration, error)             {}
+func (NoopMetrics) TaskFinished(TaskHandler, time.Duration, error) {}
+func (NoopMetrics) TaskRetried(TaskHandler)                        {}
+func (NoopMetrics) HookFailed(string)                              {}
+
+// PrometheusMetrics implements Metrics with Prometheus collectors.
+type PrometheusMetrics struct {
+	chainsStarted   prometheus.Counter
+	chainsCompleted prometheus.Counter
+	chainsFailed    prometheus.Counter
+	chainDuration   prometheus.Histogram
+	taskDuration    *prometheus.HistogramVec
+	taskErrors      *prometheus.CounterVec
+	taskRetries     *prometheus.CounterVec
+	hookErrors      *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics creates the task engine collectors and registers them with reg.
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	m := &PrometheusMetrics{
+		chainsStarted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "taskengine_chains_started_total",
+			Help: "Number of task chains started.",
+		}),
+		chainsCompleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "taskengine_chains_completed_total",
+			Help: "Number of task chains that completed successfully.",
+		}),
+		chainsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "taskengine_chains_failed_total",
+			Help: "Number of task chains that failed.",
+		}),
+		chainDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "taskengine_chain_duration_seconds",
+			Help:    "Duration of task chain executions.",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		}),
+		taskDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "taskengine_task_duration_seconds",
+			Help:    "Duration of individual task attempts.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+		}, []string{"task_type"}),
+		taskErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "taskengine_task_errors_total",
+			Help: "Number of failed task attempts.",
+		}, []string{"task_type"}),
+		taskRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "taskengine_task_retries_total",
+			Help: "Number of task retries.",
+		}, []string{"task_type"}),
+		hookErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "taskengine_hook_errors_total",
+			Help: "Number of failed hook executions.",
+		}, []string{"hook"}),
+	}
+	for _, c := range []prometheus.Collector{
+		m.chainsStarted, m.chainsCompleted, m.chainsFailed, m.chainDuration,
+		m.taskDuration, m.taskErrors, m.taskRetries, m.hookErrors,
+	} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *PrometheusMetrics) ChainStarted() {
+	m.chainsStarted.Inc()
+}
+
+func (m *PrometheusMetrics) ChainFinished(duration time.Duration, err error) {
+	m.chainDuration.Observe(duration.Seconds())
+	if err != nil {
+		m.chainsFailed.Inc()
+		return
+	}
+	m.chainsCompleted.Inc()
+}
+
+func (m *PrometheusMetrics) TaskFinished(taskType TaskHandler, duration time.Duration, err error) {
+	m.taskDuration.WithLabelValues(taskType.String()).Observe(duration.Seconds())
+	if err != nil {
+		m.taskErrors.WithLabelValues(taskType.String()).Inc()
+	}
+}
+
+func (m *PrometheusMetrics) TaskRetried(taskType TaskHandler) {
+	m.taskRetries.WithLabelValues(taskType.String()).Inc()
+}
+
+func (m *PrometheusMetrics) HookFailed(hook string) {
+	m.hookErrors.WithLabelValues(hook).Inc()
+}
+
+var (
+	_ Metrics = NoopMetrics{}
+	_ Metrics = (*PrometheusMetrics)(nil)
+)
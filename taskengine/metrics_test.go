@@ -0,0 +1,105 @@
+package taskengine_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_SimpleEnv_Metrics_CountsRetries(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics, err := taskengine.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	mockExec := &taskengine.MockTaskExecutor{
+		MockOutput:    "done",
+		ErrorSequence: []error{errors.New("transient"), nil},
+	}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector(), taskengine.WithMetrics(metrics))
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:             "answer",
+				Handler:        taskengine.HandleRawString,
+				RetryOnFailure: 1,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+
+	_, _, _, err = env.ExecEnv(context.Background(), chain, "hello", taskengine.DataTypeString)
+	require.NoError(t, err)
+
+	expected := `
+# HELP taskengine_chains_completed_total Number of task chains that completed successfully.
+# TYPE taskengine_chains_completed_total counter
+taskengine_chains_completed_total 1
+# HELP taskengine_chains_failed_total Number of task chains that failed.
+# TYPE taskengine_chains_failed_total counter
+taskengine_chains_failed_total 0
+# HELP taskengine_chains_started_total Number of task chains started.
+# TYPE taskengine_chains_started_total counter
+taskengine_chains_started_total 1
+# HELP taskengine_task_errors_total Number of failed task attempts.
+# TYPE taskengine_task_errors_total counter
+taskengine_task_errors_total{task_type="raw_string"} 1
+# HELP taskengine_task_retries_total Number of task retries.
+# TYPE taskengine_task_retries_total counter
+taskengine_task_retries_total{task_type="raw_string"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"taskengine_chains_started_total",
+		"taskengine_chains_completed_total",
+		"taskengine_chains_failed_total",
+		"taskengine_task_errors_total",
+		"taskengine_task_retries_total",
+	))
+	require.Equal(t, 1, testutil.CollectAndCount(reg, "taskengine_task_duration_seconds"))
+}
+
+func TestUnit_SimpleEnv_Metrics_CountsFailedChains(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics, err := taskengine.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	mockExec := &taskengine.MockTaskExecutor{MockError: errors.New("boom")}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector(), taskengine.WithMetrics(metrics))
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{ID: "answer", Handler: taskengine.HandleRawString, RetryOnFailure: 2},
+		},
+	}
+
+	_, _, _, err = env.ExecEnv(context.Background(), chain, "hello", taskengine.DataTypeString)
+	require.Error(t, err)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			if m.GetCounter() != nil {
+				values[mf.GetName()] = m.GetCounter().GetValue()
+			}
+		}
+	}
+	require.Equal(t, 1.0, values["taskengine_chains_failed_total"])
+	require.Equal(t, 0.0, values["taskengine_chains_completed_total"])
+	require.Equal(t, 2.0, values["taskengine_task_retries_total"])
+	require.Equal(t, 3.0, values["taskengine_task_errors_total"])
+}
@@ -0,0 +1,164 @@
+package taskengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+)
+
+// Response formats for LLMExecutionConfig.ResponseFormat.
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+// DefaultMaxJSONRepairs is the number of repair attempts made when
+// ResponseFormat.MaxRepairs is not set.
+const DefaultMaxJSONRepairs = 2
+
+// ErrInvalidJSONResponse is returned when the model does not produce valid JSON
+// for a JSON response format within the allowed repair attempts.
+var ErrInvalidJSONResponse = errors.New("model did not return valid JSON")
+
+// ResponseFormat requests structured output from the model.
+// The model is instructed to reply with JSON, the reply is validated and, if it
+// does not parse or does not match Schema, the model is asked to repair it.
+type ResponseFormat struct {
+	// Type is "text" (default), "json_object" or "json_schema".
+	Type string `yaml:"type" json:"type" example:"json_schema"`
+	// Schema is the JSON schema the reply must conform to; required for "json_schema".
+	Schema map[string]any `yaml:"schema,omitempty" json:"schema,omitempty" openapi_include_type:"object"`
+	// MaxRepairs limits how often the model is asked to fix an invalid reply.
+	// Default: DefaultMaxJSONRepairs.
+	MaxRepairs int `yaml:"max_repairs,omitempty" json:"max_repairs,omitempty" example:"2"`
+}
+
+func (f *ResponseFormat) validate() error {
+	switch f.Type {
+	case "", ResponseFormatText, ResponseFormatJSONObject:
+	case ResponseFormatJSONSchema:
+		if f.Schema == nil {
+			return fmt.Errorf("response_format json_schema requires a schema: %w", apiframework.ErrBadRequest)
+		}
+		if _, err := loadSchema(f.Schema); err != nil {
+			return fmt.Errorf("invalid response_format schema: %v: %w", err, apiframework.ErrBadRequest)
+		}
+	default:
+		return fmt.Errorf("unsupported response_format type %q: %w", f.Type, apiframework.ErrBadRequest)
+	}
+	if f.MaxRepairs < 0 {
+		return fmt.Errorf("response_format max_repairs must not be negative: %w", apiframework.ErrBadRequest)
+	}
+	return nil
+}
+
+// wantsJSON reports whether f requests JSON output.
+func (f *ResponseFormat) wantsJSON() bool {
+	return f != nil && (f.Type == ResponseFormatJSONObject || f.Type == ResponseFormatJSONSchema)
+}
+
+func (f *ResponseFormat) maxRepairs() int {
+	if f.MaxRepairs == 0 {
+		return DefaultMaxJSONRepairs
+	}
+	return f.MaxRepairs
+}
+
+// instruction tells the model how to format its reply.
+func (f *ResponseFormat) instruction() string {
+	instruction := "Reply only with valid JSON, without any surrounding text or code fences."
+	if f.Schema != nil {
+		schema, _ := json.Marshal(f.Schema)
+		instruction += "\nThe JSON must conform to this JSON schema:\n" + string(schema)
+	}
+	return instruction
+}
+
+// parse decodes the model reply and validates it against the schema, if any.
+func (f *ResponseFormat) parse(content string) (any, error) {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimPrefix(content, "```json")
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(strings.TrimSpace(content), "```")
+	}
+	var doc any
+	if err := json.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, fmt.Errorf("reply is not valid JSON: %v", err)
+	}
+	if f.Schema != nil {
+		schema, err := loadSchema(f.Schema)
+		if err != nil {
+			return nil, err
+		}
+		if err := schema.VisitJSON(doc); err != nil {
+			return nil, fmt.Errorf("reply does not match the schema: %v", err)
+		}
+	}
+	return doc, nil
+}
+
+func repairPrompt(err error) string {
+	return fmt.Sprintf("Your previous reply was invalid: %v. Reply again with only the corrected JSON.", err)
+}
+
+// executeJSON runs a chat model task whose config requests JSON output and returns
+// the validated reply as a DataTypeJSON document. The format instruction and repair
+// turns are only sent to the model.
+func (exe *SimpleExec) executeJSON(ctx context.Context, input ChatHistory, ctxLength int, llmCall *LLMExecutionConfig) (any, DataType, string, error) {
+	format := llmCall.ResponseFormat
+	working := input
+	working.Messages = append([]Message{{Role: "system", Content: format.instruction(), Timestamp: time.Now().UTC()}}, input.Messages...)
+	working.InputTokens = 0
+
+	var lastErr error
+	for attempt := 0; attempt <= format.maxRepairs(); attempt++ {
+		out, _, _, err := exe.executeLLM(ctx, working, ctxLength, llmCall)
+		if err != nil {
+			return nil, DataTypeAny, "", err
+		}
+		result := out.(ChatHistory)
+		reply := result.Messages[len(result.Messages)-1]
+		doc, err := format.parse(reply.Content)
+		if err == nil {
+			return doc, DataTypeJSON, "executed", nil
+		}
+		lastErr = err
+		working.Messages = append(result.Messages, Message{Role: "user", Content: repairPrompt(err), Timestamp: time.Now().UTC()})
+		working.InputTokens = 0
+	}
+	return nil, DataTypeAny, "", fmt.Errorf("%w after %d repair attempts: %v", ErrInvalidJSONResponse, format.maxRepairs(), lastErr)
+}
+
+// promptJSON runs a prompt task whose config requests JSON output and returns the
+// validated document together with its JSON text.
+func (exe *SimpleExec) promptJSON(ctx context.Context, systemInstruction string, llmCall LLMExecutionConfig, prompt string) (any, string, error) {
+	format := llmCall.ResponseFormat
+	if systemInstruction != "" {
+		systemInstruction += "\n\n"
+	}
+	systemInstruction += format.instruction()
+
+	var lastErr error
+	current := prompt
+	for attempt := 0; attempt <= format.maxRepairs(); attempt++ {
+		reply, err := exe.Prompt(ctx, systemInstruction, llmCall, current)
+		if err != nil {
+			return nil, "", err
+		}
+		doc, err := format.parse(reply)
+		if err == nil {
+			normalized, _ := json.Marshal(doc)
+			return doc, string(normalized), nil
+		}
+		lastErr = err
+		current = fmt.Sprintf("%s\n\nPrevious reply:\n%s\n\n%s", prompt, reply, repairPrompt(err))
+	}
+	return nil, "", fmt.Errorf("%w after %d repair attempts: %v", ErrInvalidJSONResponse, format.maxRepairs(), lastErr)
+}
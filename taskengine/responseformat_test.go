@@ -0,0 +1,162 @@
+package taskengine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/internal/llmrepo"
+	libmodelprovider "github.com/contenox/runtime/internal/modelrepo"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedRuntime answers chat and prompt requests with the scripted replies in order
+// and records what it was sent.
+type scriptedRuntime struct {
+	llmrepo.ModelRepo
+	replies []string
+	chats   [][]libmodelprovider.Message
+	prompts []string
+}
+
+func (r *scriptedRuntime) next() string {
+	reply := r.replies[0]
+	r.replies = r.replies[1:]
+	return reply
+}
+
+func (r *scriptedRuntime) CountTokens(ctx context.Context, modelName string, prompt string) (int, error) {
+	return len(prompt), nil
+}
+
+func (r *scriptedRuntime) Chat(ctx context.Context, req llmrepo.Request, messages []libmodelprovider.Message, opts ...libmodelprovider.ChatOption) (libmodelprovider.Message, llmrepo.Meta, error) {
+	r.chats = append(r.chats, messages)
+	return libmodelprovider.Message{Role: "assistant", Content: r.next()}, llmrepo.Meta{ModelName: "small"}, nil
+}
+
+func (r *scriptedRuntime) PromptExecute(ctx context.Context, req llmrepo.Request, systeminstruction string, temperature *float32, prompt string) (string, llmrepo.Meta, error) {
+	r.prompts = append(r.prompts, prompt)
+	return r.next(), llmrepo.Meta{ModelName: "small"}, nil
+}
+
+var weatherSchema = map[string]any{
+	"type":     "object",
+	"required": []any{"city", "celsius"},
+	"properties": map[string]any{
+		"city":    map[string]any{"type": "string"},
+		"celsius": map[string]any{"type": "number"},
+	},
+}
+
+func TestUnit_ModelExecution_RepairsInvalidJSON(t *testing.T) {
+	runtime := &scriptedRuntime{replies: []string{
+		`Sure! {"city": "Berlin"`,
+		`{"city": "Berlin"}`,
+		"```json\n{\"city\": \"Berlin\", \"celsius\": 21}\n```",
+	}}
+	exec, err := taskengine.NewExec(t.Context(), runtime, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	req := taskengine.OpenAIChatRequest{
+		Model:    "small",
+		Messages: []taskengine.OpenAIChatRequestMessage{{Role: "user", Content: "Weather in Berlin?"}},
+		ResponseFormat: &taskengine.OpenAIResponseFormat{
+			Type:       taskengine.ResponseFormatJSONSchema,
+			JSONSchema: &taskengine.OpenAIJSONSchema{Name: "weather", Schema: weatherSchema},
+		},
+	}
+
+	out, dataType, _, err := exec.TaskExec(context.Background(), time.Now(), 0, &taskengine.TaskDefinition{
+		ID:      "chat",
+		Handler: taskengine.HandleModelExecution,
+	}, req, taskengine.DataTypeOpenAIChat)
+	require.NoError(t, err)
+	require.Equal(t, taskengine.DataTypeJSON, dataType)
+	require.Equal(t, map[string]any{"city": "Berlin", "celsius": float64(21)}, out)
+
+	require.Len(t, runtime.chats, 3)
+	require.Equal(t, "system", runtime.chats[0][0].Role)
+	require.Contains(t, runtime.chats[0][0].Content, `"celsius"`)
+	repair := runtime.chats[1][len(runtime.chats[1])-1]
+	require.Equal(t, "user", repair.Role)
+	require.Contains(t, repair.Content, "not valid JSON")
+	repair = runtime.chats[2][len(runtime.chats[2])-1]
+	require.Contains(t, repair.Content, "does not match the schema")
+}
+
+func TestUnit_ConvertToOpenAIChatResponse_AcceptsJSON(t *testing.T) {
+	exec, err := taskengine.NewExec(t.Context(), &scriptedRuntime{}, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+
+	out, dataType, _, err := exec.TaskExec(context.Background(), time.Now(), 0, &taskengine.TaskDefinition{
+		ID:            "respond",
+		Handler:       taskengine.HandleConvertToOpenAIChatResponse,
+		ExecuteConfig: &taskengine.LLMExecutionConfig{Model: "small"},
+	}, map[string]any{"city": "Berlin", "celsius": 21}, taskengine.DataTypeJSON)
+	require.NoError(t, err)
+	require.Equal(t, taskengine.DataTypeOpenAIChatResponse, dataType)
+
+	resp := out.(taskengine.OpenAIChatResponse)
+	require.Equal(t, "small", resp.Model)
+	require.Len(t, resp.Choices, 1)
+	require.Equal(t, "assistant", resp.Choices[0].Message.Role)
+	require.JSONEq(t, `{"city": "Berlin", "celsius": 21}`, resp.Choices[0].Message.Content)
+}
+
+func TestUnit_ModelExecution_FailsWhenJSONRepairsExhausted(t *testing.T) {
+	runtime := &scriptedRuntime{replies: []string{"nope", "still nope"}}
+	out, err := execScripted(t, runtime, &taskengine.LLMExecutionConfig{
+		Model:          "small",
+		ResponseFormat: &taskengine.ResponseFormat{Type: taskengine.ResponseFormatJSONObject, MaxRepairs: 1},
+	})
+	require.ErrorIs(t, err, taskengine.ErrInvalidJSONResponse)
+	require.Nil(t, out)
+	require.Len(t, runtime.chats, 2)
+}
+
+func TestUnit_ModelExecution_RejectsInvalidResponseFormat(t *testing.T) {
+	runtime := &scriptedRuntime{}
+	_, err := execScripted(t, runtime, &taskengine.LLMExecutionConfig{
+		Model:          "small",
+		ResponseFormat: &taskengine.ResponseFormat{Type: taskengine.ResponseFormatJSONSchema},
+	})
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	require.Empty(t, runtime.chats)
+}
+
+func TestUnit_RawString_ReturnsValidatedJSON(t *testing.T) {
+	runtime := &scriptedRuntime{replies: []string{`{"city": 1}`, `{"city": "Paris", "celsius": 18.5}`}}
+	exec, err := taskengine.NewExec(t.Context(), runtime, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+
+	out, dataType, eval, err := exec.TaskExec(context.Background(), time.Now(), 0, &taskengine.TaskDefinition{
+		ID:      "extract",
+		Handler: taskengine.HandleRawString,
+		ExecuteConfig: &taskengine.LLMExecutionConfig{
+			Model:          "small",
+			ResponseFormat: &taskengine.ResponseFormat{Type: taskengine.ResponseFormatJSONSchema, Schema: weatherSchema},
+		},
+	}, "It is 18.5 degrees in Paris.", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, taskengine.DataTypeJSON, dataType)
+	require.Equal(t, map[string]any{"city": "Paris", "celsius": 18.5}, out)
+	require.JSONEq(t, `{"city": "Paris", "celsius": 18.5}`, eval)
+	require.Len(t, runtime.prompts, 2)
+	require.Contains(t, runtime.prompts[1], `{"city": 1}`)
+	require.Contains(t, runtime.prompts[1], "does not match the schema")
+}
+
+func execScripted(t *testing.T, runtime *scriptedRuntime, cfg *taskengine.LLMExecutionConfig) (any, error) {
+	t.Helper()
+	exec, err := taskengine.NewExec(t.Context(), runtime, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{{Role: "user", Content: "hello"}}}
+	out, _, _, err := exec.TaskExec(context.Background(), time.Now(), 0, &taskengine.TaskDefinition{
+		ID:            "chat",
+		Handler:       taskengine.HandleModelExecution,
+		ExecuteConfig: cfg,
+	}, history, taskengine.DataTypeChatHistory)
+	return out, err
+}
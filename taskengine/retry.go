@@ -0,0 +1,59 @@
+package taskengine
+
+import (
+	"context"
+	"errors"
+
+	"github.com/contenox/runtime/internal/apiframework"
+)
+
+// retryableError marks whether the wrapped error is worth retrying.
+type retryableError struct {
+	err       error
+	retryable bool
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// Permanent marks err as not retryable. Executors and hooks use it for failures
+// that will not go away on a new attempt, such as invalid arguments.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{err: err, retryable: false}
+}
+
+// Transient marks err as retryable, overriding the default classification of
+// the errors it wraps.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{err: err, retryable: true}
+}
+
+// IsRetryable reports whether a task failing with err should be attempted again.
+// An explicit Permanent or Transient mark wins, the outermost one if nested.
+// Otherwise cancellation and validation errors are not retryable and every other
+// error is.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var marked *retryableError
+	if errors.As(err, &marked) {
+		return marked.retryable
+	}
+	switch {
+	case errors.Is(err, context.Canceled),
+		errors.Is(err, ErrSchemaValidation),
+		errors.Is(err, ErrTypeMismatch),
+		errors.Is(err, ErrUnsupportedTaskType),
+		errors.Is(err, apiframework.ErrBadRequest),
+		errors.Is(err, apiframework.ErrUnprocessableEntity):
+		return false
+	}
+	return true
+}
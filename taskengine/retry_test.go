@@ -0,0 +1,200 @@
+package taskengine_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_IsRetryable(t *testing.T) {
+	transient := errors.New("connection reset")
+	require.True(t, taskengine.IsRetryable(transient))
+	require.False(t, taskengine.IsRetryable(nil))
+	require.False(t, taskengine.IsRetryable(fmt.Errorf("call: %w", context.Canceled)))
+	require.False(t, taskengine.IsRetryable(fmt.Errorf("bad args: %w", apiframework.ErrBadRequest)))
+	require.False(t, taskengine.IsRetryable(fmt.Errorf("hook: %w", taskengine.Permanent(transient))))
+	require.True(t, taskengine.IsRetryable(taskengine.Transient(fmt.Errorf("busy: %w", apiframework.ErrUnprocessableEntity))))
+	require.ErrorIs(t, taskengine.Permanent(transient), transient)
+}
+
+func retryChain(retries int) *taskengine.TaskChainDefinition {
+	return &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:             "task1",
+				Handler:        taskengine.HandleRawString,
+				PromptTemplate: `Flaky task`,
+				RetryOnFailure: retries,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd}},
+				},
+			},
+		},
+	}
+}
+
+func TestUnit_SimpleEnv_ExecEnv_SkipsRetriesForPermanentErrors(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{
+		MockError: taskengine.Permanent(errors.New("invalid arguments")),
+	}
+	env, err := taskengine.NewEnv(context.Background(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	_, _, state, err := env.ExecEnv(context.Background(), retryChain(3), "", taskengine.DataTypeString)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed after 0 retries")
+	require.Equal(t, 1, mockExec.CallCount())
+	require.Len(t, state, 1)
+}
+
+func TestUnit_SimpleEnv_ExecEnv_RetriesTransientErrors(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{
+		ErrorSequence:      []error{errors.New("connection reset"), errors.New("connection reset"), nil},
+		MockOutputSequence: []any{nil, nil, "done"},
+	}
+	env, err := taskengine.NewEnv(context.Background(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	out, _, _, err := env.ExecEnv(context.Background(), retryChain(3), "", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "done", out)
+	require.Equal(t, 3, mockExec.CallCount())
+}
+
+func budgetChain(budget int) *taskengine.TaskChainDefinition {
+	task := func(id, next string) taskengine.TaskDefinition {
+		return taskengine.TaskDefinition{
+			ID:             id,
+			Handler:        taskengine.HandleRawString,
+			PromptTemplate: `Flaky task`,
+			RetryOnFailure: 3,
+			Transition: taskengine.TaskTransition{
+				Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: next}},
+			},
+		}
+	}
+	return &taskengine.TaskChainDefinition{
+		ID:          "budgeted",
+		RetryBudget: budget,
+		Tasks:       []taskengine.TaskDefinition{task("task1", "task2"), task("task2", taskengine.TermEnd)},
+	}
+}
+
+// budgetExec lets task1 succeed on its third attempt and fails every call after that.
+func budgetExec() *taskengine.MockTaskExecutor {
+	reset := errors.New("connection reset")
+	return &taskengine.MockTaskExecutor{
+		ErrorSequence:      []error{reset, reset, nil, reset},
+		MockOutputSequence: []any{nil, nil, "ok", nil},
+	}
+}
+
+func TestUnit_SimpleEnv_ExecEnv_RetryBudget(t *testing.T) {
+	mockExec := budgetExec()
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	_, _, state, err := env.ExecEnv(t.Context(), budgetChain(3), "", taskengine.DataTypeString)
+	require.ErrorIs(t, err, taskengine.ErrRetryBudgetExhausted)
+	require.Contains(t, err.Error(), "after 3 retries")
+	// task1 used two retries, task2 the last one although it had three of its own.
+	require.Equal(t, 5, mockExec.CallCount())
+	require.Len(t, state, 5)
+}
+
+func TestUnit_SimpleEnv_ExecEnv_RetryBudgetUnlimitedByDefault(t *testing.T) {
+	mockExec := budgetExec()
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	_, _, _, err = env.ExecEnv(t.Context(), budgetChain(0), "", taskengine.DataTypeString)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, taskengine.ErrRetryBudgetExhausted)
+	require.Contains(t, err.Error(), "task task2 failed after 3 retries")
+	require.Equal(t, 7, mockExec.CallCount())
+}
+
+// sleepyHook ignores cancellation and blocks for delay on every call.
+type sleepyHook struct {
+	delay time.Duration
+	calls atomic.Int32
+}
+
+func (h *sleepyHook) Exec(_ context.Context, _ time.Time, input any, dataType taskengine.DataType, transition string, _ *taskengine.HookCall) (any, taskengine.DataType, string, error) {
+	h.calls.Add(1)
+	time.Sleep(h.delay)
+	return input, dataType, transition, nil
+}
+
+func (h *sleepyHook) ValidateArgs(context.Context, *taskengine.HookCall) error { return nil }
+
+func (h *sleepyHook) Supports(context.Context) ([]string, error) { return []string{"sleepy"}, nil }
+
+func (h *sleepyHook) Schemas(context.Context) ([]taskengine.HookSchema, error) { return nil, nil }
+
+func TestUnit_SimpleEnv_ExecEnv_HookTimeoutIsRetried(t *testing.T) {
+	hook := &sleepyHook{delay: time.Second}
+	exec, err := taskengine.NewExec(t.Context(), &fakeRuntime{}, hook, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:             "lookup",
+				Handler:        taskengine.HandleHook,
+				Hook:           &taskengine.HookCall{Name: "sleepy"},
+				Timeout:        "20ms",
+				RetryOnFailure: 2,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd}},
+				},
+			},
+		},
+	}
+
+	started := time.Now()
+	_, _, state, err := env.ExecEnv(t.Context(), chain, "query", taskengine.DataTypeString)
+	require.ErrorIs(t, err, taskengine.ErrHookTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Contains(t, err.Error(), "failed after 2 retries")
+	require.Less(t, time.Since(started), hook.delay, "timed out hooks are abandoned, not awaited")
+	require.Equal(t, int32(3), hook.calls.Load())
+	require.Len(t, state, 3)
+}
+
+func TestUnit_SimpleEnv_ExecEnv_HookWithinTimeout(t *testing.T) {
+	hook := &sleepyHook{delay: time.Millisecond}
+	exec, err := taskengine.NewExec(t.Context(), &fakeRuntime{}, hook, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:      "lookup",
+				Handler: taskengine.HandleHook,
+				Hook:    &taskengine.HookCall{Name: "sleepy"},
+				Timeout: "1s",
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd}},
+				},
+			},
+		},
+	}
+
+	out, _, _, err := env.ExecEnv(t.Context(), chain, "query", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "query", out)
+	require.Equal(t, int32(1), hook.calls.Load())
+}
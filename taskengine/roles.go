@@ -0,0 +1,54 @@
+package taskengine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/contenox/runtime/internal/apiframework"
+)
+
+// Message roles understood by the engine and the model providers.
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// NormalizeRole returns the canonical form of role, ignoring case and
+// surrounding whitespace. Roles other than system, user, assistant and tool
+// are rejected with ErrBadRequest.
+func NormalizeRole(role string) (string, error) {
+	switch canonical := strings.ToLower(strings.TrimSpace(role)); canonical {
+	case RoleSystem, RoleUser, RoleAssistant, RoleTool:
+		return canonical, nil
+	default:
+		return "", fmt.Errorf("unknown message role %q, expected system, user, assistant or tool %w", role, apiframework.ErrBadRequest)
+	}
+}
+
+// NormalizeRoles canonicalizes the roles of the history's messages in place,
+// see NormalizeRole.
+func (h *ChatHistory) NormalizeRoles() error {
+	for i := range h.Messages {
+		role, err := NormalizeRole(h.Messages[i].Role)
+		if err != nil {
+			return fmt.Errorf("message %d: %w", i, err)
+		}
+		h.Messages[i].Role = role
+	}
+	return nil
+}
+
+// NormalizeRoles canonicalizes the roles of the request's messages in place,
+// see NormalizeRole.
+func (r *OpenAIChatRequest) NormalizeRoles() error {
+	for i := range r.Messages {
+		role, err := NormalizeRole(r.Messages[i].Role)
+		if err != nil {
+			return fmt.Errorf("message %d: %w", i, err)
+		}
+		r.Messages[i].Role = role
+	}
+	return nil
+}
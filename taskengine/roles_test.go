@@ -0,0 +1,53 @@
+package taskengine_test
+
+import (
+	"testing"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_NormalizeRole(t *testing.T) {
+	for in, want := range map[string]string{
+		"system":      taskengine.RoleSystem,
+		"User":        taskengine.RoleUser,
+		" Assistant ": taskengine.RoleAssistant,
+		"TOOL":        taskengine.RoleTool,
+		"assistant\n": taskengine.RoleAssistant,
+	} {
+		got, err := taskengine.NormalizeRole(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"ai", "bot", "", "function"} {
+		_, err := taskengine.NormalizeRole(in)
+		require.ErrorIs(t, err, apiframework.ErrBadRequest, in)
+	}
+}
+
+func TestUnit_NormalizeRoles_Messages(t *testing.T) {
+	req := taskengine.OpenAIChatRequest{Messages: []taskengine.OpenAIChatRequestMessage{
+		{Role: "System", Content: "be brief"},
+		{Role: "USER", Content: "hi"},
+	}}
+	require.NoError(t, req.NormalizeRoles())
+	require.Equal(t, "system", req.Messages[0].Role)
+	require.Equal(t, "user", req.Messages[1].Role)
+
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{
+		{Role: "user", Content: "hi"},
+		{Role: "ai", Content: "hello"},
+	}}
+	err := history.NormalizeRoles()
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	require.ErrorContains(t, err, `message 1: unknown message role "ai"`)
+}
+
+func TestUnit_ConvertOpenAIToChatHistory_NormalizesRoles(t *testing.T) {
+	history, _, _ := taskengine.ConvertOpenAIToChatHistory(taskengine.OpenAIChatRequest{
+		Messages: []taskengine.OpenAIChatRequestMessage{{Role: "Assistant", Content: "hello"}},
+	})
+	require.Equal(t, taskengine.RoleAssistant, history.Messages[0].Role)
+}
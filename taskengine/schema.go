@@ -0,0 +1,120 @@
+package taskengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ErrSchemaValidation indicates that a chain input or output does not match the declared schema.
+var ErrSchemaValidation = errors.New("schema validation failed")
+
+// Schema validation stages.
+const (
+	SchemaStageInput  = "input"
+	SchemaStageOutput = "output"
+)
+
+// SchemaValidationError reports a value that violates a chain's input or output schema.
+// Input violations are bad requests; output violations are unprocessable entities.
+type SchemaValidationError struct {
+	ChainID string
+	// Stage is SchemaStageInput or SchemaStageOutput.
+	Stage string
+	// Field is the dotted path of the offending value; empty for the root.
+	Field  string
+	Reason string
+}
+
+func (e *SchemaValidationError) Error() string {
+	field := e.Field
+	if field == "" {
+		field = "(root)"
+	}
+	return fmt.Sprintf("chain %s: %s %s: field %s: %s", e.ChainID, e.Stage, ErrSchemaValidation, field, e.Reason)
+}
+
+func (e *SchemaValidationError) Unwrap() []error {
+	if e.Stage == SchemaStageInput {
+		return []error{ErrSchemaValidation, apiframework.ErrBadRequest}
+	}
+	return []error{ErrSchemaValidation, apiframework.ErrUnprocessableEntity}
+}
+
+// ValidateSchemas checks that the input and output schemas declared on chain are well-formed
+// and valid JSON schemas, e.g. use known types and compilable patterns.
+func ValidateSchemas(ctx context.Context, chain *TaskChainDefinition) error {
+	for stage, raw := range map[string]map[string]any{
+		SchemaStageInput:  chain.InputSchema,
+		SchemaStageOutput: chain.OutputSchema,
+	} {
+		if raw == nil {
+			continue
+		}
+		schema, err := loadSchema(raw)
+		if err == nil {
+			err = schema.Validate(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s schema: %v %w", stage, err, apiframework.ErrBadRequest)
+		}
+	}
+	return nil
+}
+
+// validateAgainstSchema checks value against the schema declared for stage.
+// A nil schema accepts any value.
+func validateAgainstSchema(chain *TaskChainDefinition, stage string, raw map[string]any, value any) error {
+	if raw == nil {
+		return nil
+	}
+	schema, err := loadSchema(raw)
+	if err != nil {
+		return fmt.Errorf("chain %s: invalid %s schema: %v %w", chain.ID, stage, err, apiframework.ErrBadRequest)
+	}
+	doc, err := toJSONValue(value)
+	if err != nil {
+		return &SchemaValidationError{ChainID: chain.ID, Stage: stage, Reason: err.Error()}
+	}
+	if err := schema.VisitJSON(doc); err != nil {
+		verr := &SchemaValidationError{ChainID: chain.ID, Stage: stage, Reason: err.Error()}
+		var schemaErr *openapi3.SchemaError
+		if errors.As(err, &schemaErr) {
+			verr.Field = strings.Join(schemaErr.JSONPointer(), ".")
+			verr.Reason = schemaErr.Reason
+		}
+		return verr
+	}
+	return nil
+}
+
+func loadSchema(raw map[string]any) (*openapi3.Schema, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var schema openapi3.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// toJSONValue converts value to its generic JSON representation
+// (maps, slices, strings, float64, bool and nil).
+func toJSONValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("value is not JSON serializable: %w", err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
@@ -0,0 +1,113 @@
+package taskengine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+func schemaChain() *taskengine.TaskChainDefinition {
+	return &taskengine.TaskChainDefinition{
+		ID: "lookup",
+		InputSchema: map[string]any{
+			"type":     "object",
+			"required": []any{"customer"},
+			"properties": map[string]any{
+				"customer": map[string]any{
+					"type":     "object",
+					"required": []any{"id"},
+					"properties": map[string]any{
+						"id": map[string]any{"type": "integer"},
+					},
+				},
+			},
+		},
+		OutputSchema: map[string]any{"type": "string"},
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:      "answer",
+				Handler: taskengine.HandleRawString,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestUnit_SimpleEnv_Schema_ValidInput(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{MockOutput: "found", MockTransitionValue: "ok"}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	input := map[string]any{"customer": map[string]any{"id": 42}}
+	out, _, _, err := env.ExecEnv(context.Background(), schemaChain(), input, taskengine.DataTypeJSON)
+	require.NoError(t, err)
+	require.Equal(t, "found", out)
+	require.Equal(t, 1, mockExec.CallCount())
+}
+
+func TestUnit_SimpleEnv_Schema_InvalidInputRejected(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{MockOutput: "found", MockTransitionValue: "ok"}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	input := map[string]any{"customer": map[string]any{"id": "not-a-number"}}
+	_, _, _, err = env.ExecEnv(context.Background(), schemaChain(), input, taskengine.DataTypeJSON)
+	require.Error(t, err)
+	require.Equal(t, 0, mockExec.CallCount(), "invalid input must be rejected before execution")
+
+	var verr *taskengine.SchemaValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, taskengine.SchemaStageInput, verr.Stage)
+	require.Equal(t, "customer.id", verr.Field)
+	require.ErrorIs(t, err, taskengine.ErrSchemaValidation)
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+}
+
+func TestUnit_SimpleEnv_Schema_OutputMismatch(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{MockOutput: 7, MockTransitionValue: "ok"}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	input := map[string]any{"customer": map[string]any{"id": 42}}
+	_, _, history, err := env.ExecEnv(context.Background(), schemaChain(), input, taskengine.DataTypeJSON)
+	require.Error(t, err)
+	require.Len(t, history, 1)
+
+	var verr *taskengine.SchemaValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, taskengine.SchemaStageOutput, verr.Stage)
+	require.Empty(t, verr.Field)
+	require.ErrorIs(t, err, apiframework.ErrUnprocessableEntity)
+}
+
+func TestUnit_ValidateSchemas_RejectsMalformedSchema(t *testing.T) {
+	chain := schemaChain()
+	chain.InputSchema = map[string]any{"type": 12}
+	require.ErrorIs(t, taskengine.ValidateSchemas(context.Background(), chain), apiframework.ErrBadRequest)
+	require.NoError(t, taskengine.ValidateSchemas(context.Background(), schemaChain()))
+}
+
+func TestUnit_ValidateSchemas_RejectsInvalidSchema(t *testing.T) {
+	for name, schema := range map[string]map[string]any{
+		"unknown type":    {"type": "strnig"},
+		"invalid pattern": {"type": "string", "pattern": "["},
+		"nested property": {"type": "object", "properties": map[string]any{"id": map[string]any{"type": "integr"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			chain := schemaChain()
+			chain.OutputSchema = schema
+			err := taskengine.ValidateSchemas(context.Background(), chain)
+			require.ErrorIs(t, err, apiframework.ErrBadRequest)
+			require.Contains(t, err.Error(), "invalid output schema")
+		})
+	}
+}
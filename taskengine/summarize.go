@@ -0,0 +1,73 @@
+package taskengine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultSummaryKeepMessages is the number of recent messages kept verbatim
+// when SummarizeConfig.KeepMessages is not set.
+const DefaultSummaryKeepMessages = 4
+
+// summaryPrefix marks the system message holding the summary of earlier messages.
+const summaryPrefix = "Summary of the earlier conversation:\n"
+
+const summaryInstruction = `You condense conversations. Summarize the conversation below so it can be continued without the original messages.
+Keep every fact needed to continue coherently: names, numbers, dates, decisions, preferences, open questions and tasks in progress.
+If the conversation starts with an earlier summary, merge it into the new summary. Reply with the summary only.`
+
+// summarizeHistory replaces the oldest messages of history with a summary once the
+// history holds more than cfg.ThresholdTokens tokens. Leading system messages and the
+// most recent cfg.KeepMessages messages are kept verbatim. An earlier summary is part
+// of the summarized messages, so it is extended instead of recomputed.
+func (exe *SimpleExec) summarizeHistory(ctx context.Context, history ChatHistory, llmCall *LLMExecutionConfig) (ChatHistory, error) {
+	cfg := llmCall.Summarize
+	total, err := countPromptTokens(ctx, exe.repo, llmCall.Model, history.Messages)
+	if err != nil {
+		return history, err
+	}
+	if total <= cfg.ThresholdTokens {
+		return history, nil
+	}
+
+	keep := cfg.KeepMessages
+	if keep == 0 {
+		keep = DefaultSummaryKeepMessages
+	}
+	pinned := 0
+	for pinned < len(history.Messages) && history.Messages[pinned].Role == "system" && !isSummary(history.Messages[pinned]) {
+		pinned++
+	}
+	boundary := max(len(history.Messages)-keep, pinned)
+	// Never separate tool results from the call that requested them.
+	for boundary > pinned && history.Messages[boundary].Role == "tool" {
+		boundary--
+	}
+	older := history.Messages[pinned:boundary]
+	if len(older) == 0 || (len(older) == 1 && isSummary(older[0])) {
+		return history, nil
+	}
+
+	var transcript strings.Builder
+	for _, m := range older {
+		fmt.Fprintf(&transcript, "%s: %s\n", m.Role, m.Content)
+	}
+	summary, err := exe.Prompt(ctx, summaryInstruction, *llmCall, transcript.String())
+	if err != nil {
+		return history, fmt.Errorf("summarization failed: %w", err)
+	}
+
+	messages := make([]Message, 0, pinned+1+len(history.Messages)-boundary)
+	messages = append(messages, history.Messages[:pinned]...)
+	messages = append(messages, Message{Role: "system", Content: summaryPrefix + summary, Timestamp: time.Now().UTC()})
+	messages = append(messages, history.Messages[boundary:]...)
+	history.Messages = messages
+	history.InputTokens = 0
+	return history, nil
+}
+
+func isSummary(m Message) bool {
+	return m.Role == "system" && strings.HasPrefix(m.Content, summaryPrefix)
+}
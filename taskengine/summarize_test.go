@@ -0,0 +1,133 @@
+package taskengine_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/internal/llmrepo"
+	libmodelprovider "github.com/contenox/runtime/internal/modelrepo"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// summarizingRuntime counts one token per word, summarizes with a fixed text
+// and records what it was asked to summarize and to chat about.
+type summarizingRuntime struct {
+	llmrepo.ModelRepo
+	prompts []string
+	chats   [][]libmodelprovider.Message
+}
+
+func (r *summarizingRuntime) CountTokens(ctx context.Context, modelName string, prompt string) (int, error) {
+	return len(strings.Fields(prompt)), nil
+}
+
+func (r *summarizingRuntime) PromptExecute(ctx context.Context, req llmrepo.Request, systeminstruction string, temperature *float32, prompt string) (string, llmrepo.Meta, error) {
+	r.prompts = append(r.prompts, prompt)
+	return "User ordered tea, order 42.", llmrepo.Meta{ModelName: "small"}, nil
+}
+
+func (r *summarizingRuntime) Chat(ctx context.Context, req llmrepo.Request, messages []libmodelprovider.Message, opts ...libmodelprovider.ChatOption) (libmodelprovider.Message, llmrepo.Meta, error) {
+	r.chats = append(r.chats, messages)
+	return libmodelprovider.Message{Role: "assistant", Content: "ok"}, llmrepo.Meta{ModelName: "small"}, nil
+}
+
+func (r *summarizingRuntime) lastPromptTokens() int {
+	total := 0
+	for _, m := range r.chats[len(r.chats)-1] {
+		total += len(strings.Fields(m.Content))
+	}
+	return total
+}
+
+func longHistory() taskengine.ChatHistory {
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{{Role: "system", Content: "You are helpful."}}}
+	history.Messages = append(history.Messages, taskengine.Message{Role: "user", Content: "I would like to order tea, order 42."})
+	for i := 1; i < 20; i++ {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		history.Messages = append(history.Messages, taskengine.Message{Role: role, Content: fmt.Sprintf("message %d with a few more filler words", i)})
+	}
+	return history
+}
+
+func TestUnit_ModelExecution_SummarizesLongHistory(t *testing.T) {
+	runtime := &summarizingRuntime{}
+	exec, err := taskengine.NewExec(t.Context(), runtime, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	task := &taskengine.TaskDefinition{
+		ID:      "chat",
+		Handler: taskengine.HandleModelExecution,
+		ExecuteConfig: &taskengine.LLMExecutionConfig{
+			Model:     "small",
+			Summarize: &taskengine.SummarizeConfig{ThresholdTokens: 60, KeepMessages: 4},
+		},
+	}
+
+	out, _, _, err := exec.TaskExec(context.Background(), time.Now(), 0, task, longHistory(), taskengine.DataTypeChatHistory)
+	require.NoError(t, err)
+	require.Len(t, runtime.prompts, 1)
+	require.Contains(t, runtime.prompts[0], "order tea, order 42")
+	require.NotContains(t, runtime.prompts[0], "You are helpful.")
+	require.Less(t, runtime.lastPromptTokens(), 60)
+
+	sent := runtime.chats[0]
+	require.Len(t, sent, 6)
+	require.Equal(t, "You are helpful.", sent[0].Content)
+	require.Equal(t, "system", sent[1].Role)
+	require.Contains(t, sent[1].Content, "User ordered tea, order 42.")
+	require.Equal(t, "message 16 with a few more filler words", sent[2].Content)
+	require.Equal(t, "message 19 with a few more filler words", sent[5].Content)
+
+	// The next turn reuses the stored summary instead of summarizing again.
+	history := out.(taskengine.ChatHistory)
+	history.Messages = append(history.Messages, taskengine.Message{Role: "user", Content: "Where is my order?"})
+	history.InputTokens = 0
+	_, _, _, err = exec.TaskExec(context.Background(), time.Now(), 0, task, history, taskengine.DataTypeChatHistory)
+	require.NoError(t, err)
+	require.Len(t, runtime.prompts, 1)
+	require.Contains(t, runtime.chats[1][1].Content, "User ordered tea, order 42.")
+	require.Less(t, runtime.lastPromptTokens(), 60)
+}
+
+func TestUnit_ModelExecution_SummaryIsExtendedWhenHistoryGrows(t *testing.T) {
+	runtime := &summarizingRuntime{}
+	exec, err := taskengine.NewExec(t.Context(), runtime, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	task := &taskengine.TaskDefinition{
+		ID:      "chat",
+		Handler: taskengine.HandleModelExecution,
+		ExecuteConfig: &taskengine.LLMExecutionConfig{
+			Model:     "small",
+			Summarize: &taskengine.SummarizeConfig{ThresholdTokens: 60, KeepMessages: 4},
+		},
+	}
+
+	out, _, _, err := exec.TaskExec(context.Background(), time.Now(), 0, task, longHistory(), taskengine.DataTypeChatHistory)
+	require.NoError(t, err)
+	history := out.(taskengine.ChatHistory)
+	for i := range 6 {
+		history.Messages = append(history.Messages, taskengine.Message{Role: "user", Content: fmt.Sprintf("follow up %d with a few more filler words", i)})
+	}
+	history.InputTokens = 0
+
+	_, _, _, err = exec.TaskExec(context.Background(), time.Now(), 0, task, history, taskengine.DataTypeChatHistory)
+	require.NoError(t, err)
+	require.Len(t, runtime.prompts, 2)
+	require.True(t, strings.HasPrefix(runtime.prompts[1], "system: Summary of the earlier conversation"), "earlier summary must be folded into the new one")
+	require.Less(t, runtime.lastPromptTokens(), 60)
+}
+
+func TestUnit_ModelExecution_RejectsInvalidSummarizeConfig(t *testing.T) {
+	runtime := &fakeRuntime{available: []string{"small"}}
+	_, err := execModel(t, runtime, &taskengine.LLMExecutionConfig{Model: "small", Summarize: &taskengine.SummarizeConfig{}})
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	require.Empty(t, runtime.calls)
+}
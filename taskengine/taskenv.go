@@ -3,9 +3,13 @@ package taskengine
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"log"
+	"math"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"text/template"
@@ -14,6 +18,8 @@ import (
 	"dario.cat/mergo"
 	"github.com/contenox/runtime/internal/apiframework"
 	"github.com/contenox/runtime/libtracker"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // DataType represents the type of data passed between tasks.
@@ -99,22 +105,42 @@ type EnvExecutor interface {
 	// ExecEnv executes a task chain with the given input and data type.
 	// Returns final output, output type, execution history, and error.
 	ExecEnv(ctx context.Context, chain *TaskChainDefinition, input any, dataType DataType) (any, DataType, []CapturedStateUnit, error)
+	// ResumeChain continues a failed execution from its checkpoint, see ResumableError.
+	ResumeChain(ctx context.Context, checkpointID string) (any, DataType, []CapturedStateUnit, error)
 }
 
 // ErrUnsupportedTaskType indicates unrecognized task type
 var ErrUnsupportedTaskType = errors.New("executor does not support the task type")
 
+// ErrTypeMismatch indicates a task received an input type it does not accept.
+var ErrTypeMismatch = errors.New("input type mismatch")
+
+// ErrRetryBudgetExhausted indicates a chain used up its RetryBudget.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// ErrChainStepLimitExceeded indicates a chain ran more tasks than its step limit
+// allows, typically because its transitions loop.
+var ErrChainStepLimitExceeded = errors.New("chain step limit exceeded")
+
+// DefaultMaxSteps is the number of tasks a chain may run per execution unless
+// configured otherwise, see WithMaxSteps and TaskChainDefinition.MaxSteps.
+const DefaultMaxSteps = 1000
+
 // HookRepo defines interface for external system integrations and side effects.
 type HookRepo interface {
 	// Exec executes a hook with the given input and arguments.
 	// Returns output, output type, transition value, and error.
 	Exec(ctx context.Context, startingTime time.Time, input any, dataType DataType, transition string, args *HookCall) (any, DataType, string, error)
+	// ValidateArgs checks the arguments of a hook call without executing it.
+	ValidateArgs(ctx context.Context, args *HookCall) error
 	// HookRegistry provides hook discovery functionality.
 	HookRegistry
 }
 
 type HookRegistry interface {
 	Supports(ctx context.Context) ([]string, error)
+	// Schemas describes every supported hook.
+	Schemas(ctx context.Context) ([]HookSchema, error)
 }
 
 // SimpleEnv is the default implementation of EnvExecutor.
@@ -122,9 +148,36 @@ type HookRegistry interface {
 // It executes tasks in order, using retry and timeout policies, and tracks execution
 // progress using an ActivityTracker.
 type SimpleEnv struct {
-	exec      TaskExecutor
-	tracker   libtracker.ActivityTracker
-	inspector Inspector
+	exec        TaskExecutor
+	tracker     libtracker.ActivityTracker
+	inspector   Inspector
+	metrics     Metrics
+	tracer      trace.Tracer
+	checkpoints CheckpointStore
+	inFlight    *InFlightRegistry
+	maxSteps    int
+}
+
+// EnvOption configures optional SimpleEnv behavior.
+type EnvOption func(*SimpleEnv)
+
+// WithMetrics reports chain and task measurements to m.
+func WithMetrics(m Metrics) EnvOption {
+	return func(e *SimpleEnv) {
+		if m != nil {
+			e.metrics = m
+		}
+	}
+}
+
+// WithMaxSteps limits the number of tasks a chain may run per execution,
+// chains can only lower it with MaxSteps. Values below one keep DefaultMaxSteps.
+func WithMaxSteps(n int) EnvOption {
+	return func(e *SimpleEnv) {
+		if n > 0 {
+			e.maxSteps = n
+		}
+	}
 }
 
 // NewEnv creates a new SimpleEnv with the given tracker and task executor.
@@ -133,15 +186,23 @@ func NewEnv(
 	tracker libtracker.ActivityTracker,
 	exec TaskExecutor,
 	inspector Inspector,
+	opts ...EnvOption,
 ) (EnvExecutor, error) {
 	if tracker == nil {
 		tracker = libtracker.NoopTracker{}
 	}
-	return &SimpleEnv{
+	env := &SimpleEnv{
 		exec:      exec,
 		tracker:   tracker,
 		inspector: inspector,
-	}, nil
+		metrics:   NoopMetrics{},
+		tracer:    defaultTracer(),
+		maxSteps:  DefaultMaxSteps,
+	}
+	for _, opt := range opts {
+		opt(env)
+	}
+	return env, nil
 }
 
 // ExecEnv executes the given chain with the provided input.
@@ -149,6 +210,59 @@ func NewEnv(
 // It manages the full lifecycle of task execution: rendering prompts, calling the
 // TaskExecutor, handling timeouts, retries, transitions, and collecting final output.
 func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, input any, dataType DataType) (any, DataType, []CapturedStateUnit, error) {
+	return exe.run(ctx, chain, input, dataType, nil)
+}
+
+// run executes chain from its first task, or from a checkpoint if from is set.
+func (exe SimpleEnv) run(ctx context.Context, chain *TaskChainDefinition, input any, dataType DataType, from *resumePoint) (any, DataType, []CapturedStateUnit, error) {
+	if exe.inFlight != nil {
+		var stop func()
+		ctx, stop = exe.inFlight.track(ctx, chain)
+		defer stop()
+	}
+	startedAt := time.Now().UTC()
+	exe.metrics.ChainStarted()
+	ctx, span := exe.tracer.Start(ctx, "taskengine.chain", trace.WithAttributes(
+		attribute.String(attrChainID, chain.ID),
+	))
+	checkpoint := exe.checkpointFor(ctx, chain, from)
+	output, outputType, history, err := exe.execValidated(ctx, chain, input, dataType, from, checkpoint)
+	if err != nil && errors.Is(context.Cause(ctx), ErrExecutionCancelled) {
+		err = fmt.Errorf("chain %s: %w: %w", chain.ID, ErrExecutionCancelled, err)
+		reportCancel, _, endCancel := exe.tracker.Start(ctx, "cancel", "chain", "chain_id", chain.ID)
+		reportCancel(err)
+		endCancel()
+	}
+	endSpan(span, err)
+	exe.metrics.ChainFinished(time.Since(startedAt), err)
+	if chain.OnComplete != nil && !chain.DryRun {
+		exe.notifyCompletion(ctx, chain, output, outputType, err, time.Since(startedAt))
+	}
+	if err != nil && checkpoint.saved {
+		err = &ResumableError{CheckpointID: checkpoint.id, Err: err}
+	}
+	return output, outputType, history, err
+}
+
+// execValidated runs the chain, checking the input and final output against
+// the chain's declared schemas. Resumed executions were checked on input already.
+func (exe SimpleEnv) execValidated(ctx context.Context, chain *TaskChainDefinition, input any, dataType DataType, from *resumePoint, checkpoint *executionCheckpoint) (any, DataType, []CapturedStateUnit, error) {
+	if from == nil {
+		if err := validateAgainstSchema(chain, SchemaStageInput, chain.InputSchema, input); err != nil {
+			return nil, DataTypeAny, nil, err
+		}
+	}
+	output, outputType, history, err := exe.execChain(ctx, chain, input, dataType, from, checkpoint)
+	if err != nil || chain.DryRun {
+		return output, outputType, history, err
+	}
+	if err := validateAgainstSchema(chain, SchemaStageOutput, chain.OutputSchema, output); err != nil {
+		return nil, DataTypeAny, history, err
+	}
+	return output, outputType, history, nil
+}
+
+func (exe SimpleEnv) execChain(ctx context.Context, chain *TaskChainDefinition, input any, dataType DataType, from *resumePoint, checkpoint *executionCheckpoint) (any, DataType, []CapturedStateUnit, error) {
 	stack := exe.inspector.Start(ctx)
 
 	vars := map[string]any{
@@ -156,26 +270,58 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 	}
 	varTypes := map[string]DataType{"input": dataType}
 	startingTime := time.Now().UTC()
-	var err error
-
-	if err := validateChain(chain.Tasks); err != nil {
-		return nil, DataTypeAny, stack.GetExecutionHistory(), err
+	captureContent := libtracker.CaptureContentEnabled(ctx)
+	exec := exe.exec
+	if chain.DryRun {
+		exec = dryRunExecutor{}
 	}
+	var err error
 
-	currentTask, err := findTaskByID(chain.Tasks, chain.Tasks[0].ID)
+	// The executor may fill in defaults on the task it runs; work on a copy so
+	// the caller's (possibly shared) chain definition is left untouched.
+	tasks := slices.Clone(chain.Tasks)
+	index, err := validateChain(tasks, chain.RequireDefaultBranch)
 	if err != nil {
 		return nil, DataTypeAny, stack.GetExecutionHistory(), err
 	}
 
+	currentTask := &tasks[0]
+
 	var finalOutput any
 	var transitionEval string
 	var output any = input
 	var outputType DataType = dataType
 	var taskErr error
+	var taskMeta map[string]any
+
+	var completed []string
+	if from != nil {
+		output, outputType, completed, err = from.restore(index, vars, varTypes)
+		if err != nil {
+			return nil, DataTypeAny, stack.GetExecutionHistory(), err
+		}
+		currentTask, err = index.find(from.checkpoint.NextTaskID)
+		if err != nil {
+			return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("checkpoint %s: resuming: %w", checkpoint.id, err)
+		}
+	}
 
+	inFlight := inFlightFrom(ctx)
+	chainRetries := 0
+	maxSteps := exe.maxSteps
+	if chain.MaxSteps > 0 {
+		maxSteps = min(chain.MaxSteps, exe.maxSteps)
+	}
+	steps := 0
 	for {
+		if steps++; maxSteps > 0 && steps > maxSteps {
+			return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("chain %s: %w: %d steps, next task %s", chain.ID, ErrChainStepLimitExceeded, maxSteps, currentTask.ID)
+		}
+		if inFlight != nil {
+			inFlight.setTask(currentTask.ID)
+		}
 		if ctx.Err() != nil {
-			return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("task %s: context canceled", currentTask.ID)
+			return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("task %s: %w", currentTask.ID, context.Cause(ctx))
 		}
 
 		// Determine task input
@@ -202,18 +348,35 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 			taskInput = rendered
 			taskInputType = DataTypeString
 		}
+		skip := skipsInput(currentTask, taskInputType)
+		if skip {
+			_, _, endSkip := exe.tracker.Start(ctx, "skip_task", currentTask.ID,
+				"task_type", currentTask.Handler,
+				"input_type", taskInputType.String(),
+			)
+			endSkip()
+		} else {
+			taskInput, taskInputType, err = coerceInput(currentTask, taskInput, taskInputType)
+			if err != nil {
+				return nil, DataTypeAny, stack.GetExecutionHistory(), err
+			}
+		}
 		maxRetries := max(currentTask.RetryOnFailure, 0)
+		retries := 0
 
 	retryLoop:
 		for retry := 0; retry <= maxRetries; retry++ {
+			retries = retry
 			// Note: Return on breakpoint for now
 			if stack.HasBreakpoint(currentTask.ID) {
 				return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("task %s: breakpoint set", currentTask.ID)
 			}
+			if retry > 0 {
+				exe.metrics.TaskRetried(currentTask.Handler)
+			}
 
 			// Track task attempt start
-			taskCtx := context.Background()
-			taskCtx = libtracker.CopyTrackingValues(ctx, taskCtx)
+			taskCtx := detachedContext(ctx)
 			var cancel context.CancelFunc
 			if currentTask.Timeout != "" {
 				timeout, err := time.ParseDuration(currentTask.Timeout)
@@ -222,6 +385,15 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 				}
 				taskCtx, cancel = context.WithTimeout(ctx, timeout)
 			}
+			taskCtx, taskSpan := exe.tracer.Start(
+				trace.ContextWithSpan(taskCtx, trace.SpanFromContext(ctx)),
+				"taskengine.task",
+				trace.WithAttributes(
+					attribute.String(attrTaskID, currentTask.ID),
+					attribute.String(attrTaskType, currentTask.Handler.String()),
+					attribute.Int(attrRetry, retry),
+				),
+			)
 			reportErrAttempt, reportChangeAttempt, endAttempt := exe.tracker.Start(
 				taskCtx,
 				"task_attempt",
@@ -230,18 +402,29 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 				"task_type", currentTask.Handler,
 			)
 
+			taskCtx, metaSink := withHookMetadata(taskCtx)
+			taskCtx, usageSink := withTokenUsage(taskCtx)
 			startTime := time.Now().UTC()
 
-			output, outputType, transitionEval, taskErr = exe.exec.TaskExec(taskCtx, startingTime, int(chain.TokenLimit), currentTask, taskInput, taskInputType)
+			taskExec := exec
+			if skip {
+				taskExec = skipExecutor{}
+			}
+			output, outputType, transitionEval, taskErr = taskExec.TaskExec(taskCtx, startingTime, int(chain.TokenLimit), currentTask, taskInput, taskInputType)
 			if taskErr != nil {
 				taskErr = fmt.Errorf("task %s: %w", currentTask.ID, taskErr)
 				reportErrAttempt(taskErr)
 			}
 			endAttempt()
+			taskMeta = metaSink.snapshot()
+			usage := usageSink.total()
+			taskSpan.SetAttributes(attribute.String(attrTransition, transitionEval))
+			endSpan(taskSpan, taskErr)
 			if cancel != nil {
 				cancel()
 			}
 			duration := time.Since(startTime)
+			exe.metrics.TaskFinished(currentTask.Handler, duration, taskErr)
 			errState := ErrorResponse{
 				ErrorInternal: taskErr,
 			}
@@ -255,17 +438,26 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 				InputType:   taskInputType,
 				OutputType:  outputType,
 				Transition:  transitionEval,
+				Skipped:     skip,
 				Duration:    duration,
 				Error:       errState,
 			}
-			if chain.Debug {
-				step.Input = fmt.Sprintf("%v", taskInput)
-				step.Output = fmt.Sprintf("%v", output)
+			step.InputTokens, step.OutputTokens = usage.InputTokens, usage.OutputTokens
+			if (chain.Debug || chain.DryRun) && captureContent {
+				step.Input = capturedString(currentTask.Capture, taskInput)
+				step.Output = capturedString(currentTask.Capture, output)
 			}
 			stack.RecordStep(step)
 
 			if taskErr != nil {
 				reportErrAttempt(taskErr)
+				if !IsRetryable(taskErr) || ctx.Err() != nil || retry == maxRetries {
+					break retryLoop
+				}
+				if chain.RetryBudget > 0 && chainRetries >= chain.RetryBudget {
+					return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("chain %s: %w after %d retries: %w", chain.ID, ErrRetryBudgetExhausted, chainRetries, taskErr)
+				}
+				chainRetries++
 				continue retryLoop
 			}
 
@@ -403,14 +595,14 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 			}
 
 			// Report successful attempt
-			reportChangeAttempt(currentTask.ID, output)
+			reportChangeAttempt(currentTask.ID, trackedTaskContent(currentTask.Capture, captureContent, output, outputType))
 			break retryLoop
 		}
 
 		if taskErr != nil {
 			if currentTask.Transition.OnFailure != "" {
 				previousTaskID := currentTask.ID
-				currentTask, err = findTaskByID(chain.Tasks, currentTask.Transition.OnFailure)
+				currentTask, err = index.find(currentTask.Transition.OnFailure)
 				if err != nil {
 					return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("error transition target not found: %v", err)
 				}
@@ -426,7 +618,7 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 				reportChangeErrTransition(currentTask.ID, taskErr)
 				continue
 			}
-			return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("task %s failed after %d retries: %v", currentTask.ID, maxRetries, taskErr)
+			return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("task %s failed after %d retries: %w", currentTask.ID, retries, taskErr)
 		}
 
 		// Update execution variables
@@ -436,7 +628,7 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 		varTypes[currentTask.ID] = outputType
 
 		// Handle print statement
-		if currentTask.Print != "" {
+		if currentTask.Print != "" && !chain.DryRun {
 			printMsg, err := renderTemplate(currentTask.Print, vars)
 			if err != nil {
 				return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("task %s: print template error: %v", currentTask.ID, err)
@@ -445,12 +637,23 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 		}
 
 		// Evaluate transitions
-		nextTaskID, err := exe.evaluateTransitions(ctx, currentTask.ID, currentTask.Transition, transitionEval)
+		nextTaskID, err := exe.evaluateTransitions(ctx, currentTask.ID, currentTask.Transition, transitionEval, taskMeta, output, outputType, chain.TransitionTolerance)
 		if err != nil {
 			return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("task %s: transition error: %v", currentTask.ID, err)
 		}
+		trace.SpanFromContext(ctx).AddEvent("taskengine.transition", trace.WithAttributes(
+			attribute.String(attrTaskID, currentTask.ID),
+			attribute.String(attrTransition, transitionEval),
+			attribute.String(attrNextTask, nextTaskID),
+		))
 
 		if nextTaskID == "" || nextTaskID == TermEnd {
+			if checkpoint.saved {
+				if err := exe.checkpoints.Delete(ctx, checkpoint.id); err != nil {
+					log.Printf("taskengine: removing checkpoint %s: %v", checkpoint.id, err)
+				}
+				checkpoint.saved = false
+			}
 			finalOutput = output
 			// Track final output
 			_, reportChangeFinal, endFinal := exe.tracker.Start(
@@ -458,10 +661,15 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 				"chain_complete",
 				"chain")
 			defer endFinal()
-			reportChangeFinal("chain", finalOutput)
+			reportChangeFinal("chain", trackedTaskContent(currentTask.Capture, captureContent, finalOutput, outputType))
 			break
 		}
 
+		if checkpoint.save {
+			completed = markCompleted(completed, currentTask.ID)
+			exe.saveCheckpoint(ctx, checkpoint, chain, input, dataType, completed, currentTask.ID, output, outputType, nextTaskID)
+		}
+
 		// Track normal transition to next task
 		_, reportChangeTransition, endTransition := exe.tracker.Start(
 			ctx,
@@ -473,7 +681,7 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 		reportChangeTransition(nextTaskID, transitionEval)
 
 		// Find next task
-		currentTask, err = findTaskByID(chain.Tasks, nextTaskID)
+		currentTask, err = index.find(nextTaskID)
 		if err != nil {
 			return nil, DataTypeAny, stack.GetExecutionHistory(), fmt.Errorf("next task %s not found: %v", nextTaskID, err)
 		}
@@ -482,8 +690,106 @@ func (exe SimpleEnv) ExecEnv(ctx context.Context, chain *TaskChainDefinition, in
 	return finalOutput, outputType, stack.GetExecutionHistory(), nil
 }
 
+// skipsInput reports whether task is skipped for inputs of type actual.
+func skipsInput(task *TaskDefinition, actual DataType) bool {
+	for _, skipped := range task.SkipInputTypes {
+		if skipped == actual {
+			return true
+		}
+	}
+	return false
+}
+
+// coerceInput verifies that the task accepts the given input type.
+// If it does not, the input is converted to the first declared type that has a
+// registered conversion; otherwise ErrTypeMismatch is returned.
+func coerceInput(task *TaskDefinition, input any, actual DataType) (any, DataType, error) {
+	if len(task.InputTypes) == 0 || actual == DataTypeAny {
+		return input, actual, nil
+	}
+	for _, expected := range task.InputTypes {
+		if expected == DataTypeAny || expected == actual {
+			return input, actual, nil
+		}
+	}
+	for _, expected := range task.InputTypes {
+		if !hasConversion(actual, expected) {
+			continue
+		}
+		converted, err := Convert(input, actual, expected)
+		if err != nil {
+			return nil, DataTypeAny, fmt.Errorf("task %s: converting %s to %s: %w", task.ID, actual.String(), expected.String(), err)
+		}
+		return converted, expected, nil
+	}
+	expected := make([]string, len(task.InputTypes))
+	for i, dt := range task.InputTypes {
+		expected[i] = dt.String()
+	}
+	return nil, DataTypeAny, fmt.Errorf("task %s: %w: expected %s, got %s",
+		task.ID, ErrTypeMismatch, strings.Join(expected, " or "), actual.String())
+}
+
+// trackedContent returns the value to hand to the activity tracker.
+// When content capture is disabled only metadata about the value is reported.
+func trackedContent(capture bool, value any, dataType DataType) any {
+	if capture {
+		return value
+	}
+	meta := map[string]any{
+		"redacted": true,
+		"type":     dataType.String(),
+	}
+	if history, ok := value.(ChatHistory); ok {
+		meta["messages"] = len(history.Messages)
+		meta["input_tokens"] = history.InputTokens
+		meta["output_tokens"] = history.OutputTokens
+	}
+	return meta
+}
+
+// captureSummaryLength is the number of characters kept by CaptureSummary.
+const captureSummaryLength = 256
+
+// trackedTaskContent applies the task's capture mode on top of trackedContent.
+func trackedTaskContent(mode CaptureMode, capture bool, value any, dataType DataType) any {
+	switch {
+	case mode == CaptureNone:
+		return trackedContent(false, value, dataType)
+	case mode == CaptureSummary && capture:
+		return capturedString(mode, value)
+	default:
+		return trackedContent(capture, value, dataType)
+	}
+}
+
+// capturedString formats value for an execution trace according to mode.
+func capturedString(mode CaptureMode, value any) string {
+	content := fmt.Sprintf("%v", value)
+	switch mode {
+	case CaptureNone:
+		return ""
+	case CaptureSummary:
+		runes := []rune(content)
+		if len(runes) <= captureSummaryLength {
+			return content
+		}
+		sum := sha256.Sum256([]byte(content))
+		return fmt.Sprintf("%s... (%d bytes, sha256:%x)", string(runes[:captureSummaryLength]), len(content), sum)
+	default:
+		return content
+	}
+}
+
+// RenderTemplate renders tmplStr the same way task prompt templates are rendered,
+// with the helpers from templateFuncs available.
+func RenderTemplate(tmplStr string, vars map[string]any) (string, error) {
+	return renderTemplate(tmplStr, vars)
+}
+
+// renderTemplate renders tmplStr with the given variables and the helpers from templateFuncs.
 func renderTemplate(tmplStr string, vars map[string]any) (string, error) {
-	tmpl, err := template.New("prompt").Parse(tmplStr)
+	tmpl, err := template.New("prompt").Funcs(templateFuncs).Parse(tmplStr)
 	if err != nil {
 		return "", err
 	}
@@ -494,17 +800,45 @@ func renderTemplate(tmplStr string, vars map[string]any) (string, error) {
 	return buf.String(), nil
 }
 
-func (exe SimpleEnv) evaluateTransitions(ctx context.Context, taskID string, transition TaskTransition, eval string) (string, error) {
+// evaluateTransitions selects the branch to follow. Branches are matched against
+// the task's typed output where possible (see compareTyped) and against the
+// transition string eval otherwise. Numbers are compared with the branch's
+// tolerance, or with tolerance if the branch sets none.
+func (exe SimpleEnv) evaluateTransitions(ctx context.Context, taskID string, transition TaskTransition, eval string, meta map[string]any, output any, outputType DataType, tolerance float64) (string, error) {
 	// First check explicit matches
 	for _, ct := range transition.Branches {
 		if ct.Operator == OpDefault {
 			continue
 		}
 
-		match, err := compare(ct.Operator, eval, ct.When)
+		tol := tolerance
+		if ct.Tolerance > 0 {
+			tol = ct.Tolerance
+		}
+		if ct.Meta != "" {
+			value, ok := meta[ct.Meta]
+			if !ok {
+				continue
+			}
+			match, err := compare(ct.Operator, metaString(value), ct.When, tol)
+			if err != nil {
+				return "", err
+			}
+			if match {
+				return ct.Goto, nil
+			}
+			continue
+		}
+		match, ok, err := compareTyped(ct.Operator, output, outputType, ct.When, tol)
 		if err != nil {
 			return "", err
 		}
+		if !ok {
+			match, err = compare(ct.Operator, eval, ct.When, tol)
+			if err != nil {
+				return "", err
+			}
+		}
 		if match {
 			return ct.Goto, nil
 		}
@@ -551,13 +885,134 @@ func parseNumber(s string) (float64, error) {
 	return num, nil
 }
 
+// Branch values that match search results by their count.
+const (
+	WhenEmpty      = "empty"
+	WhenHasResults = "has_results"
+)
+
+// compareTyped applies operator to a bool, numeric or search results output
+// without going through its string form: bools are compared with equals, numbers
+// with equals, the ordering operators and in_range, and search results by their
+// count, where equals also accepts WhenEmpty and WhenHasResults.
+//
+// ok is false when the output is of another type or when does not parse as a
+// value of the output's type; the caller then falls back to compare.
+func compareTyped(operator OperatorTerm, output any, outputType DataType, when string, tolerance float64) (match bool, ok bool, err error) {
+	when = strings.TrimSpace(when)
+	var value float64
+	switch outputType {
+	case DataTypeBool:
+		b, isBool := output.(bool)
+		target, err := strconv.ParseBool(when)
+		if !isBool || err != nil || operator != OpEquals {
+			return false, false, nil
+		}
+		return b == target, true, nil
+	case DataTypeInt, DataTypeFloat:
+		n, isNumber := toFloat(output)
+		if !isNumber {
+			return false, false, nil
+		}
+		value = n
+	case DataTypeSearchResults:
+		results, isResults := output.([]SearchResult)
+		if !isResults {
+			return false, false, nil
+		}
+		if operator == OpEquals {
+			switch when {
+			case WhenEmpty:
+				return len(results) == 0, true, nil
+			case WhenHasResults:
+				return len(results) > 0, true, nil
+			}
+		}
+		value = float64(len(results))
+	default:
+		return false, false, nil
+	}
+
+	switch operator {
+	case OpEquals, OpGreaterThan, OpGt, OpLessThan, OpLt:
+		target, err := strconv.ParseFloat(when, 64)
+		if err != nil {
+			return false, false, nil
+		}
+		return compareNumbers(operator, value, target, tolerance), true, nil
+	case OpInRange:
+		lower, upper, found := strings.Cut(when, "-")
+		if !found {
+			return false, false, nil
+		}
+		lo, errLo := strconv.ParseFloat(strings.TrimSpace(lower), 64)
+		hi, errHi := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+		if errLo != nil || errHi != nil {
+			return false, false, nil
+		}
+		if lo > hi {
+			return false, true, fmt.Errorf("invalid range: lower bound %f > upper bound %f", lo, hi)
+		}
+		return inRange(value, lo, hi, tolerance), true, nil
+	default:
+		return false, false, nil
+	}
+}
+
+// compareNumbers applies equals, > or < to value and target. Values within
+// tolerance of target count as equal to it, so they match equals but neither
+// ordering operator.
+func compareNumbers(operator OperatorTerm, value, target, tolerance float64) bool {
+	switch operator {
+	case OpEquals:
+		return math.Abs(value-target) <= tolerance
+	case OpGreaterThan, OpGt:
+		return value > target+tolerance
+	case OpLessThan, OpLt:
+		return value < target-tolerance
+	default:
+		return false
+	}
+}
+
+// inRange reports whether value lies within [lower, upper], widened by tolerance
+// on both ends.
+func inRange(value, lower, upper, tolerance float64) bool {
+	return value >= lower-tolerance && value <= upper+tolerance
+}
+
+// toFloat converts the numeric outputs of tasks to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
 // compare applies a logical operator to a model response and a target value.
 //
 // Supported operators include equality, string containment, numeric comparisons,
-// and range checks using "parse_range".
-func compare(operator OperatorTerm, response, when string) (bool, error) {
+// and range checks using "parse_range". Numeric comparisons apply tolerance as
+// compareNumbers does; with a tolerance set, equals compares numeric responses
+// and targets as numbers rather than as strings.
+func compare(operator OperatorTerm, response, when string, tolerance float64) (bool, error) {
 	switch operator {
 	case OpEquals:
+		if tolerance > 0 {
+			resNum, errRes := strconv.ParseFloat(strings.TrimSpace(response), 64)
+			targetNum, errTarget := strconv.ParseFloat(strings.TrimSpace(when), 64)
+			if errRes == nil && errTarget == nil {
+				return compareNumbers(OpEquals, resNum, targetNum, tolerance), nil
+			}
+		}
 		return response == when, nil
 	case OpContains:
 		return strings.Contains(response, when), nil
@@ -574,7 +1029,7 @@ func compare(operator OperatorTerm, response, when string) (bool, error) {
 		if err != nil {
 			return false, err
 		}
-		return resNum > targetNum, nil
+		return compareNumbers(OpGreaterThan, resNum, targetNum, tolerance), nil
 	case OpLessThan, OpLt:
 		resNum, err := parseNumber(response)
 		if err != nil {
@@ -584,7 +1039,7 @@ func compare(operator OperatorTerm, response, when string) (bool, error) {
 		if err != nil {
 			return false, err
 		}
-		return resNum < targetNum, nil
+		return compareNumbers(OpLessThan, resNum, targetNum, tolerance), nil
 	case OpInRange:
 		parts := strings.Split(when, "-")
 		if len(parts) != 2 {
@@ -610,35 +1065,112 @@ func compare(operator OperatorTerm, response, when string) (bool, error) {
 			return false, fmt.Errorf("failed to parse response as number: %q: %w", response, err)
 		}
 
-		return resNum >= lower && resNum <= upper, nil
+		return inRange(resNum, lower, upper, tolerance), nil
 	default:
 		return false, fmt.Errorf("unsupported operator: %s", operator)
 	}
 }
 
-// findTaskByID returns the task with the given ID from the task list.
-func findTaskByID(tasks []TaskDefinition, id string) (*TaskDefinition, error) {
-	for _, task := range tasks {
-		if task.ID == id {
-			return &task, nil
+// missingDefaultBranch reports whether task has conditional branches but no
+// default branch. Outputs matching none of the conditions fail the chain at
+// runtime with "no matching transition found".
+func missingDefaultBranch(task *TaskDefinition) bool {
+	if len(task.Transition.Branches) == 0 {
+		return false
+	}
+	for _, branch := range task.Transition.Branches {
+		if branch.Operator == OpDefault {
+			return false
+		}
+	}
+	return true
+}
+
+// ChainWarnings reports likely authoring mistakes that do not make the chain
+// invalid, such as tasks without a default branch when the chain does not set
+// RequireDefaultBranch, or transition cycles without a way out that only end
+// when the chain's step limit is reached.
+func ChainWarnings(chain *TaskChainDefinition) []string {
+	var warnings []string
+	for i := range chain.Tasks {
+		task := &chain.Tasks[i]
+		if missingDefaultBranch(task) {
+			warnings = append(warnings, fmt.Sprintf("task %s: conditional branches without a default branch, unmatched outputs fail the chain", task.ID))
 		}
 	}
-	return nil, fmt.Errorf("task not found: %s", id)
+	for _, cycle := range endlessCycles(chain.Tasks) {
+		warnings = append(warnings, cycleWarning(cycle))
+	}
+	return warnings
+}
+
+// taskIndex maps the IDs of a chain's tasks to the tasks.
+type taskIndex map[string]*TaskDefinition
+
+// find returns the task with the given ID.
+func (idx taskIndex) find(id string) (*TaskDefinition, error) {
+	task, ok := idx[id]
+	if !ok {
+		return nil, fmt.Errorf("task not found: %s", id)
+	}
+	return task, nil
+}
+
+// hasVar reports whether name is a variable the chain can set: the chain
+// input, the previous output, a task's output or a task's composed output.
+func (idx taskIndex) hasVar(name string) bool {
+	if name == "input" || name == "previous_output" {
+		return true
+	}
+	if _, ok := idx[name]; ok {
+		return true
+	}
+	id, ok := strings.CutSuffix(name, "_composed")
+	if !ok {
+		return false
+	}
+	task, ok := idx[id]
+	return ok && task.Compose != nil
 }
 
-func validateChain(tasks []TaskDefinition) error {
+// validateChain checks the tasks and returns an index of them. The index points
+// into tasks. With requireDefault, tasks that lack a default branch (see
+// missingDefaultBranch) are rejected.
+func validateChain(tasks []TaskDefinition, requireDefault bool) (taskIndex, error) {
 	if len(tasks) == 0 {
-		return fmt.Errorf("chain has no tasks %w", apiframework.ErrBadRequest)
+		return nil, fmt.Errorf("chain has no tasks %w", apiframework.ErrBadRequest)
 	}
-	for _, ct := range tasks {
+	index := make(taskIndex, len(tasks))
+	for i := range tasks {
+		ct := &tasks[i]
+		switch ct.Capture {
+		case "", CaptureFull, CaptureSummary, CaptureNone:
+		default:
+			return nil, fmt.Errorf("task %s: invalid capture mode %q %w", ct.ID, ct.Capture, apiframework.ErrBadRequest)
+		}
+		if len(ct.SkipInputTypes) > 0 && ct.Handler != HandleHook {
+			return nil, fmt.Errorf("task %s: skip_input_types is only supported for hook tasks %w", ct.ID, apiframework.ErrBadRequest)
+		}
+		if requireDefault && missingDefaultBranch(ct) {
+			return nil, fmt.Errorf("task %s: conditional branches without a default branch %w", ct.ID, apiframework.ErrBadRequest)
+		}
 		if ct.ID == "" || ct.ID == TermEnd {
 			if ct.ID == "" {
-				return fmt.Errorf("task ID cannot be empty %w", apiframework.ErrBadRequest)
+				return nil, fmt.Errorf("task ID cannot be empty %w", apiframework.ErrBadRequest)
 			}
 			if ct.ID == TermEnd {
-				return fmt.Errorf("task ID cannot be '%s' %w", TermEnd, apiframework.ErrBadRequest)
+				return nil, fmt.Errorf("task ID cannot be '%s' %w", TermEnd, apiframework.ErrBadRequest)
 			}
 		}
+		if _, exists := index[ct.ID]; exists {
+			return nil, fmt.Errorf("duplicate task ID %q %w", ct.ID, apiframework.ErrBadRequest)
+		}
+		index[ct.ID] = ct
+	}
+	for i := range tasks {
+		if ref := tasks[i].InputVar; ref != "" && !index.hasVar(ref) {
+			return nil, fmt.Errorf("task %s: input variable %q is neither a task ID nor a chain variable %w", tasks[i].ID, ref, apiframework.ErrBadRequest)
+		}
 	}
-	return nil
+	return index, nil
 }
@@ -3,8 +3,11 @@ package taskengine_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/contenox/runtime/internal/apiframework"
 	"github.com/contenox/runtime/libtracker"
 	"github.com/contenox/runtime/taskengine"
 	"github.com/stretchr/testify/require"
@@ -335,6 +338,36 @@ func TestUnit_SimpleEnv_ExecEnv_InputVar_InvalidVariable(t *testing.T) {
 	require.Contains(t, err.Error(), "input variable")
 }
 
+func TestUnit_SimpleEnv_ExecEnv_InputVar_NonAdjacentTask(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{
+		MockOutputSequence: []any{"search results", "summary", "merged"},
+	}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	next := func(id string) taskengine.TaskTransition {
+		return taskengine.TaskTransition{Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: id}}}
+	}
+	chain := &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{ID: "search", Handler: taskengine.HandleHook, Hook: &taskengine.HookCall{Name: "search"}, Transition: next("summarize")},
+			{ID: "summarize", Handler: taskengine.HandleNoop, Transition: next("merge")},
+			{ID: "merge", Handler: taskengine.HandleHook, Hook: &taskengine.HookCall{Name: "merge"}, InputVar: "search", Transition: next(taskengine.TermEnd)},
+		},
+	}
+
+	_, _, _, err = env.ExecEnv(t.Context(), chain, "query", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, 3, mockExec.CallCount())
+	require.Equal(t, "search results", mockExec.CalledWithInput, "merge reads the output of search, not of summarize")
+
+	chain.Tasks[2].InputVar = "serach"
+	_, _, _, err = env.ExecEnv(t.Context(), chain, "query", taskengine.DataTypeString)
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	require.ErrorContains(t, err, `task merge: input variable "serach"`)
+	require.Equal(t, 3, mockExec.CallCount(), "rejected before any task runs")
+}
+
 func TestUnit_SimpleEnv_ExecEnv_InputVar_DefaultBehavior(t *testing.T) {
 	mockExec := &taskengine.MockTaskExecutor{
 		MockOutputSequence:          []any{"first", "second"},
@@ -375,3 +408,591 @@ func TestUnit_SimpleEnv_ExecEnv_InputVar_DefaultBehavior(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "second", result)
 }
+
+// recordingTracker keeps every value reported through reportChange and reportErr.
+type recordingTracker struct {
+	operations []string
+	changes    []any
+	errs       []error
+}
+
+func (r *recordingTracker) Start(ctx context.Context, operation string, subject string, kvArgs ...any) (func(error), func(string, any), func()) {
+	r.operations = append(r.operations, operation+":"+subject)
+	return func(err error) { r.errs = append(r.errs, err) },
+		func(_ string, data any) { r.changes = append(r.changes, data) },
+		func() {}
+}
+
+func captureChain() *taskengine.TaskChainDefinition {
+	return &taskengine.TaskChainDefinition{
+		Debug: true,
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:             "task1",
+				Handler:        taskengine.HandleRawString,
+				PromptTemplate: `Secret: {{.input}}`,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: "default", Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestUnit_SimpleEnv_ExecEnv_CaptureContentDisabled(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{
+		MockOutput:          "classified answer",
+		MockTransitionValue: "ok",
+	}
+	tracker := &recordingTracker{}
+	env, err := taskengine.NewEnv(t.Context(), tracker, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	ctx := libtracker.WithCaptureContent(context.Background(), false)
+	result, _, state, err := env.ExecEnv(ctx, captureChain(), "top secret", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "classified answer", result)
+
+	require.Len(t, state, 1)
+	require.Equal(t, "task1", state[0].TaskID)
+	require.Equal(t, taskengine.DataTypeString, state[0].InputType)
+	require.Equal(t, "ok", state[0].Transition)
+	require.Empty(t, state[0].Input)
+	require.Empty(t, state[0].Output)
+
+	require.NotEmpty(t, tracker.changes)
+	for _, change := range tracker.changes {
+		require.NotContains(t, fmt.Sprintf("%v", change), "classified answer")
+		require.NotContains(t, fmt.Sprintf("%v", change), "top secret")
+	}
+}
+
+func TestUnit_SimpleEnv_ExecEnv_CaptureContentEnabledByDefault(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{
+		MockOutput:          "classified answer",
+		MockTransitionValue: "ok",
+	}
+	tracker := &recordingTracker{}
+	env, err := taskengine.NewEnv(t.Context(), tracker, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	_, _, state, err := env.ExecEnv(context.Background(), captureChain(), "top secret", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Len(t, state, 1)
+	require.Equal(t, "Secret: top secret", state[0].Input)
+	require.Equal(t, "classified answer", state[0].Output)
+	require.Contains(t, tracker.changes, any("classified answer"))
+}
+
+func TestUnit_SimpleEnv_ExecEnv_TaskCaptureModes(t *testing.T) {
+	large := strings.Repeat("x", 1000)
+	mockExec := &taskengine.MockTaskExecutor{
+		MockOutput:          large,
+		MockTransitionValue: "ok",
+	}
+
+	t.Run("none", func(t *testing.T) {
+		tracker := &recordingTracker{}
+		env, err := taskengine.NewEnv(t.Context(), tracker, mockExec, taskengine.NewSimpleInspector())
+		require.NoError(t, err)
+		chain := captureChain()
+		chain.Tasks[0].Capture = taskengine.CaptureNone
+
+		result, _, state, err := env.ExecEnv(context.Background(), chain, "top secret", taskengine.DataTypeString)
+		require.NoError(t, err)
+		require.Equal(t, large, result)
+		require.Len(t, state, 1)
+		require.Empty(t, state[0].Input)
+		require.Empty(t, state[0].Output)
+		require.NotEmpty(t, tracker.changes)
+		for _, change := range tracker.changes {
+			require.NotContains(t, fmt.Sprintf("%v", change), "xxxx")
+		}
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		tracker := &recordingTracker{}
+		env, err := taskengine.NewEnv(t.Context(), tracker, mockExec, taskengine.NewSimpleInspector())
+		require.NoError(t, err)
+		chain := captureChain()
+		chain.Tasks[0].Capture = taskengine.CaptureSummary
+
+		result, _, state, err := env.ExecEnv(context.Background(), chain, "top secret", taskengine.DataTypeString)
+		require.NoError(t, err)
+		require.Equal(t, large, result)
+		require.Len(t, state, 1)
+		require.Equal(t, "Secret: top secret", state[0].Input)
+		require.Less(t, len(state[0].Output), len(large))
+		require.True(t, strings.HasPrefix(state[0].Output, strings.Repeat("x", 256)+"... (1000 bytes, sha256:"))
+		for _, change := range tracker.changes {
+			require.NotContains(t, fmt.Sprintf("%v", change), large)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		env, err := taskengine.NewEnv(t.Context(), &recordingTracker{}, mockExec, taskengine.NewSimpleInspector())
+		require.NoError(t, err)
+		chain := captureChain()
+		chain.Tasks[0].Capture = "partial"
+
+		_, _, _, err = env.ExecEnv(context.Background(), chain, "top secret", taskengine.DataTypeString)
+		require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	})
+}
+
+func typedChain(inputTypes ...taskengine.DataType) *taskengine.TaskChainDefinition {
+	return &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:         "summarize",
+				Handler:    taskengine.HandleNoop,
+				InputTypes: inputTypes,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestUnit_SimpleEnv_ExecEnv_InputTypeMismatch(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{MockOutput: "ok"}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{{Role: "user", Content: "hi"}}}
+	_, _, _, err = env.ExecEnv(context.Background(), typedChain(taskengine.DataTypeInt), history, taskengine.DataTypeChatHistory)
+	require.ErrorIs(t, err, taskengine.ErrTypeMismatch)
+	require.Contains(t, err.Error(), "summarize")
+	require.Contains(t, err.Error(), "expected int, got chat_history")
+	require.Equal(t, 0, mockExec.CallCount())
+}
+
+func TestUnit_SimpleEnv_ExecEnv_InputTypeCompatible(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{MockOutput: "ok"}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	result, _, _, err := env.ExecEnv(context.Background(), typedChain(taskengine.DataTypeChatHistory, taskengine.DataTypeString), "hello", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "ok", result)
+
+	result, _, _, err = env.ExecEnv(context.Background(), typedChain(taskengine.DataTypeAny), 42, taskengine.DataTypeInt)
+	require.NoError(t, err)
+	require.Equal(t, "ok", result)
+}
+
+func conversionChain() *taskengine.TaskChainDefinition {
+	return &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:             "to_history",
+				Handler:        taskengine.HandleHook,
+				Hook:           &taskengine.HookCall{Name: "convert_openai_to_history"},
+				InputTypes:     []taskengine.DataType{taskengine.DataTypeOpenAIChat},
+				SkipInputTypes: []taskengine.DataType{taskengine.DataTypeChatHistory},
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd}},
+				},
+			},
+		},
+	}
+}
+
+func TestUnit_SimpleEnv_ExecEnv_SkipInputTypes(t *testing.T) {
+	converted := taskengine.ChatHistory{Messages: []taskengine.Message{{Role: "user", Content: "converted"}}}
+	mockExec := &taskengine.MockTaskExecutor{
+		MockOutputSequence:   []any{converted},
+		MockTaskTypeSequence: []taskengine.DataType{taskengine.DataTypeChatHistory},
+	}
+	tracker := &recordingTracker{}
+	env, err := taskengine.NewEnv(t.Context(), tracker, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	openAI := taskengine.OpenAIChatRequest{Messages: []taskengine.OpenAIChatRequestMessage{{Role: "user", Content: "hi"}}}
+	out, outType, state, err := env.ExecEnv(t.Context(), conversionChain(), openAI, taskengine.DataTypeOpenAIChat)
+	require.NoError(t, err)
+	require.Equal(t, converted, out)
+	require.Equal(t, taskengine.DataTypeChatHistory, outType)
+	require.Equal(t, 1, mockExec.CallCount())
+	require.False(t, state[0].Skipped)
+	require.NotContains(t, tracker.operations, "skip_task:to_history")
+
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{{Role: "user", Content: "already internal"}}}
+	out, outType, state, err = env.ExecEnv(t.Context(), conversionChain(), history, taskengine.DataTypeChatHistory)
+	require.NoError(t, err)
+	require.Equal(t, history, out, "input passes through unchanged")
+	require.Equal(t, taskengine.DataTypeChatHistory, outType)
+	require.Equal(t, 1, mockExec.CallCount(), "skipped hooks are not executed")
+	require.Len(t, state, 1)
+	require.True(t, state[0].Skipped)
+	require.Equal(t, taskengine.SkippedTransition, state[0].Transition)
+	require.Contains(t, tracker.operations, "skip_task:to_history")
+}
+
+func TestUnit_SimpleEnv_ExecEnv_SkipInputTypesOnlyForHooks(t *testing.T) {
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, &taskengine.MockTaskExecutor{}, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	chain := conversionChain()
+	chain.Tasks[0].Handler = taskengine.HandleRawString
+	_, _, _, err = env.ExecEnv(t.Context(), chain, "hi", taskengine.DataTypeString)
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+}
+
+// branchChain routes a single task to the "matched" or "fallback" task.
+func branchChain(branches ...taskengine.TransitionBranch) *taskengine.TaskChainDefinition {
+	end := taskengine.TaskTransition{
+		Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd}},
+	}
+	branches = append(branches, taskengine.TransitionBranch{Operator: taskengine.OpDefault, Goto: "fallback"})
+	return &taskengine.TaskChainDefinition{
+		ID: "branch",
+		Tasks: []taskengine.TaskDefinition{
+			{ID: "check", Handler: taskengine.HandleNoop, Transition: taskengine.TaskTransition{Branches: branches}},
+			{ID: "matched", Handler: taskengine.HandleNoop, Transition: end},
+			{ID: "fallback", Handler: taskengine.HandleNoop, Transition: end},
+		},
+	}
+}
+
+func runBranch(t *testing.T, output any, outputType taskengine.DataType, transition string, branches ...taskengine.TransitionBranch) string {
+	t.Helper()
+	mockExec := &taskengine.MockTaskExecutor{
+		MockOutputSequence:          []any{output, "done"},
+		MockTaskTypeSequence:        []taskengine.DataType{outputType, taskengine.DataTypeString},
+		MockTransitionValueSequence: []string{transition, "done"},
+	}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+	_, _, state, err := env.ExecEnv(t.Context(), branchChain(branches...), "in", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Len(t, state, 2)
+	return state[1].TaskID
+}
+
+func TestUnit_SimpleEnv_ExecEnv_BoolTransitions(t *testing.T) {
+	isTrue := taskengine.TransitionBranch{Operator: taskengine.OpEquals, When: "true", Goto: "matched"}
+	require.Equal(t, "matched", runBranch(t, true, taskengine.DataTypeBool, "true", isTrue))
+	require.Equal(t, "fallback", runBranch(t, false, taskengine.DataTypeBool, "false", isTrue))
+
+	// Bools are compared by value, not by their spelling.
+	isTrue.When = "TRUE"
+	require.Equal(t, "matched", runBranch(t, true, taskengine.DataTypeBool, "true", isTrue))
+
+	// Non-bool branch values still match the transition string of the task.
+	approved := taskengine.TransitionBranch{Operator: taskengine.OpEquals, When: "approved", Goto: "matched"}
+	require.Equal(t, "matched", runBranch(t, true, taskengine.DataTypeBool, "approved", approved))
+}
+
+func TestUnit_SimpleEnv_ExecEnv_NumericTransitions(t *testing.T) {
+	over := taskengine.TransitionBranch{Operator: taskengine.OpGreaterThan, When: "5", Goto: "matched"}
+	require.Equal(t, "matched", runBranch(t, 7, taskengine.DataTypeInt, "", over))
+	require.Equal(t, "fallback", runBranch(t, 5, taskengine.DataTypeInt, "", over))
+
+	equals := taskengine.TransitionBranch{Operator: taskengine.OpEquals, When: "3.0", Goto: "matched"}
+	require.Equal(t, "matched", runBranch(t, 3, taskengine.DataTypeInt, "3", equals))
+
+	// The native score is compared, not its rounded transition string.
+	inRange := taskengine.TransitionBranch{Operator: taskengine.OpInRange, When: "0.5-0.7", Goto: "matched"}
+	require.Equal(t, "matched", runBranch(t, 0.6999, taskengine.DataTypeFloat, "0.70", inRange))
+	require.Equal(t, "fallback", runBranch(t, 0.7001, taskengine.DataTypeFloat, "0.70", inRange))
+}
+
+func TestUnit_SimpleEnv_ExecEnv_SearchResultTransitions(t *testing.T) {
+	empty := taskengine.TransitionBranch{Operator: taskengine.OpEquals, When: taskengine.WhenEmpty, Goto: "matched"}
+	results := []taskengine.SearchResult{{ID: "a"}, {ID: "b"}}
+	require.Equal(t, "matched", runBranch(t, []taskengine.SearchResult{}, taskengine.DataTypeSearchResults, "", empty))
+	require.Equal(t, "fallback", runBranch(t, results, taskengine.DataTypeSearchResults, "", empty))
+
+	hasResults := taskengine.TransitionBranch{Operator: taskengine.OpEquals, When: taskengine.WhenHasResults, Goto: "matched"}
+	require.Equal(t, "matched", runBranch(t, results, taskengine.DataTypeSearchResults, "", hasResults))
+
+	atLeastTwo := taskengine.TransitionBranch{Operator: taskengine.OpGt, When: "1", Goto: "matched"}
+	require.Equal(t, "matched", runBranch(t, results, taskengine.DataTypeSearchResults, "", atLeastTwo))
+	require.Equal(t, "fallback", runBranch(t, results[:1], taskengine.DataTypeSearchResults, "", atLeastTwo))
+}
+
+func TestUnit_SimpleEnv_ExecEnv_TransitionTolerance(t *testing.T) {
+	const score = 0.7000001
+	equals := taskengine.TransitionBranch{Operator: taskengine.OpEquals, When: "0.7", Goto: "matched"}
+	above := taskengine.TransitionBranch{Operator: taskengine.OpGt, When: "0.7", Goto: "matched"}
+	inRange := taskengine.TransitionBranch{Operator: taskengine.OpInRange, When: "0.5-0.7", Goto: "matched"}
+
+	// Without a tolerance the comparisons are exact.
+	require.Equal(t, "fallback", runBranch(t, score, taskengine.DataTypeFloat, "0.70", equals))
+	require.Equal(t, "matched", runBranch(t, score, taskengine.DataTypeFloat, "0.70", above))
+	require.Equal(t, "fallback", runBranch(t, score, taskengine.DataTypeFloat, "0.70", inRange))
+
+	for _, branch := range []*taskengine.TransitionBranch{&equals, &above, &inRange} {
+		branch.Tolerance = 0.001
+	}
+	require.Equal(t, "matched", runBranch(t, score, taskengine.DataTypeFloat, "0.70", equals))
+	require.Equal(t, "fallback", runBranch(t, score, taskengine.DataTypeFloat, "0.70", above), "within tolerance is not greater")
+	require.Equal(t, "matched", runBranch(t, score, taskengine.DataTypeFloat, "0.70", inRange))
+	require.Equal(t, "fallback", runBranch(t, 0.702, taskengine.DataTypeFloat, "0.70", inRange))
+}
+
+func TestUnit_SimpleEnv_ExecEnv_ChainTransitionTolerance(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{MockOutput: "0.7000001", MockTransitionValue: "0.7000001"}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	chain := branchChain(taskengine.TransitionBranch{Operator: taskengine.OpEquals, When: "0.7", Goto: "matched"})
+	_, _, state, err := env.ExecEnv(t.Context(), chain, "in", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "fallback", state[1].TaskID, "string outputs match exactly by default")
+
+	chain.TransitionTolerance = 0.001
+	_, _, state, err = env.ExecEnv(t.Context(), chain, "in", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "matched", state[1].TaskID)
+}
+
+func TestUnit_SimpleEnv_ExecEnv_RequireDefaultBranch(t *testing.T) {
+	mockExec := &taskengine.MockTaskExecutor{MockOutput: "ok", MockTransitionValue: "ok"}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		ID: "no_default",
+		Tasks: []taskengine.TaskDefinition{{
+			ID:      "classify",
+			Handler: taskengine.HandleNoop,
+			Transition: taskengine.TaskTransition{
+				Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpEquals, When: "ok", Goto: taskengine.TermEnd}},
+			},
+		}},
+	}
+	require.Equal(t, []string{
+		"task classify: conditional branches without a default branch, unmatched outputs fail the chain",
+	}, taskengine.ChainWarnings(chain))
+
+	// Without the flag a missing default is only a warning.
+	_, _, _, err = env.ExecEnv(t.Context(), chain, "in", taskengine.DataTypeString)
+	require.NoError(t, err)
+
+	chain.RequireDefaultBranch = true
+	_, _, _, err = env.ExecEnv(t.Context(), chain, "in", taskengine.DataTypeString)
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	require.ErrorContains(t, err, "task classify: conditional branches without a default branch")
+	require.Equal(t, 1, mockExec.CallCount(), "rejected before any task runs")
+
+	chain.Tasks[0].Transition.Branches = append(chain.Tasks[0].Transition.Branches,
+		taskengine.TransitionBranch{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd})
+	require.Empty(t, taskengine.ChainWarnings(chain))
+	_, _, _, err = env.ExecEnv(t.Context(), chain, "in", taskengine.DataTypeString)
+	require.NoError(t, err)
+}
+
+// longChain returns n tasks visited as task0, task1, ..., task<n-1>. After the
+// first task they are declared in reverse, so no hop goes to the next slice element.
+func longChain(n int) *taskengine.TaskChainDefinition {
+	tasks := make([]taskengine.TaskDefinition, 0, n)
+	for i := range n {
+		id := 0
+		if i > 0 {
+			id = n - i
+		}
+		next := taskengine.TermEnd
+		if id < n-1 {
+			next = fmt.Sprintf("task%d", id+1)
+		}
+		tasks = append(tasks, taskengine.TaskDefinition{
+			ID:      fmt.Sprintf("task%d", id),
+			Handler: taskengine.HandleNoop,
+			Transition: taskengine.TaskTransition{
+				Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: next}},
+			},
+		})
+	}
+	return &taskengine.TaskChainDefinition{ID: "long", Tasks: tasks}
+}
+
+func TestUnit_SimpleEnv_ExecEnv_LongChain(t *testing.T) {
+	const n = 500
+	mockExec := &taskengine.MockTaskExecutor{MockOutput: "ok"}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	chain := longChain(n)
+	_, _, state, err := env.ExecEnv(t.Context(), chain, "in", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Len(t, state, n)
+	for i, step := range state {
+		require.Equal(t, fmt.Sprintf("task%d", i), step.TaskID)
+	}
+
+	chain.Tasks = append(chain.Tasks, chain.Tasks[1])
+	_, _, _, err = env.ExecEnv(t.Context(), chain, "in", taskengine.DataTypeString)
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	require.ErrorContains(t, err, "duplicate task ID")
+}
+
+func BenchmarkSimpleEnv_ExecEnv_LongChain(b *testing.B) {
+	env, err := taskengine.NewEnv(context.Background(), libtracker.NoopTracker{}, &taskengine.MockTaskExecutor{MockOutput: "ok"}, taskengine.NewSimpleInspector())
+	require.NoError(b, err)
+	chain := longChain(1000)
+	for b.Loop() {
+		if _, _, _, err := env.ExecEnv(context.Background(), chain, "in", taskengine.DataTypeString); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestUnit_SimpleEnv_ExecEnv_LeavesChainUntouched(t *testing.T) {
+	hooks := &recordingHooks{output: "done"}
+	exec, err := taskengine.NewExec(t.Context(), &fakeRuntime{}, hooks, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:      "lookup",
+				Handler: taskengine.HandleHook,
+				Hook:    &taskengine.HookCall{Name: "get_weather"},
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd}},
+				},
+			},
+		},
+	}
+	_, _, _, err = env.ExecEnv(t.Context(), chain, "in", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Len(t, hooks.calls, 1)
+	require.NotNil(t, hooks.calls[0].Args, "hooks always receive args")
+	require.Nil(t, chain.Tasks[0].Hook.Args, "the caller's chain is not modified")
+}
+
+// cyclicChain bounces between two tasks forever.
+func cyclicChain(maxSteps int) *taskengine.TaskChainDefinition {
+	task := func(id, next string) taskengine.TaskDefinition {
+		return taskengine.TaskDefinition{
+			ID:      id,
+			Handler: taskengine.HandleRawString,
+			Transition: taskengine.TaskTransition{
+				Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: next}},
+			},
+		}
+	}
+	return &taskengine.TaskChainDefinition{
+		ID:       "cyclic",
+		MaxSteps: maxSteps,
+		Tasks:    []taskengine.TaskDefinition{task("a", "b"), task("b", "a")},
+	}
+}
+
+func TestUnit_SimpleEnv_ExecEnv_StepLimit(t *testing.T) {
+	for name, tc := range map[string]struct {
+		envLimit   int
+		chainLimit int
+		want       int
+	}{
+		"default":      {want: taskengine.DefaultMaxSteps},
+		"env limit":    {envLimit: 10, want: 10},
+		"chain lowers": {envLimit: 10, chainLimit: 4, want: 4},
+		"chain raises": {envLimit: 10, chainLimit: 25, want: 10},
+		"default caps": {chainLimit: taskengine.DefaultMaxSteps + 5, want: taskengine.DefaultMaxSteps},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockExec := &taskengine.MockTaskExecutor{MockOutput: "again", MockTransitionValue: "again"}
+			env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector(), taskengine.WithMaxSteps(tc.envLimit))
+			require.NoError(t, err)
+
+			_, _, state, err := env.ExecEnv(t.Context(), cyclicChain(tc.chainLimit), "start", taskengine.DataTypeString)
+			require.ErrorIs(t, err, taskengine.ErrChainStepLimitExceeded)
+			require.Equal(t, tc.want, mockExec.CallCount())
+			require.Len(t, state, tc.want)
+		})
+	}
+}
+
+func TestUnit_SimpleEnv_ExecEnv_LoopsWithinStepLimit(t *testing.T) {
+	chain := &taskengine.TaskChainDefinition{
+		ID:       "revise",
+		MaxSteps: 3,
+		Tasks: []taskengine.TaskDefinition{{
+			ID:      "revise",
+			Handler: taskengine.HandleRawString,
+			Transition: taskengine.TaskTransition{
+				Branches: []taskengine.TransitionBranch{
+					{Operator: taskengine.OpEquals, When: "done", Goto: taskengine.TermEnd},
+					{Operator: taskengine.OpDefault, Goto: "revise"},
+				},
+			},
+		}},
+	}
+	newExec := func() *taskengine.MockTaskExecutor {
+		return &taskengine.MockTaskExecutor{
+			MockOutputSequence:          []any{"draft", "draft", "final"},
+			MockTransitionValueSequence: []string{"again", "again", "done"},
+		}
+	}
+
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, newExec(), taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+	out, _, _, err := env.ExecEnv(t.Context(), chain, "start", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "final", out)
+
+	chain.MaxSteps = 2
+	env, err = taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, newExec(), taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+	_, _, _, err = env.ExecEnv(t.Context(), chain, "start", taskengine.DataTypeString)
+	require.ErrorIs(t, err, taskengine.ErrChainStepLimitExceeded)
+}
+
+func TestUnit_ChainWarnings_TransitionCycles(t *testing.T) {
+	branch := func(goTo string) taskengine.TaskTransition {
+		return taskengine.TaskTransition{Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: goTo}}}
+	}
+	task := func(id string, transition taskengine.TaskTransition) taskengine.TaskDefinition {
+		return taskengine.TaskDefinition{ID: id, Handler: taskengine.HandleNoop, Transition: transition}
+	}
+
+	t.Run("linear chain", func(t *testing.T) {
+		chain := &taskengine.TaskChainDefinition{Tasks: []taskengine.TaskDefinition{
+			task("a", branch("b")), task("b", branch("c")), task("c", branch(taskengine.TermEnd)),
+		}}
+		require.Empty(t, taskengine.ChainWarnings(chain))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		chain := cyclicChain(0)
+		require.Equal(t, []string{
+			"transition cycle a -> b -> a never reaches the end of the chain, mark intended loops with loop: true",
+		}, taskengine.ChainWarnings(chain))
+	})
+
+	t.Run("cycle after a linear start", func(t *testing.T) {
+		chain := &taskengine.TaskChainDefinition{Tasks: []taskengine.TaskDefinition{
+			task("start", branch("poll")), task("poll", branch("wait")), task("wait", branch("poll")),
+		}}
+		require.Equal(t, []string{
+			"transition cycle poll -> wait -> poll never reaches the end of the chain, mark intended loops with loop: true",
+		}, taskengine.ChainWarnings(chain))
+	})
+
+	t.Run("cycle with an exit", func(t *testing.T) {
+		chain := &taskengine.TaskChainDefinition{Tasks: []taskengine.TaskDefinition{
+			task("draft", branch("review")),
+			task("review", taskengine.TaskTransition{Branches: []taskengine.TransitionBranch{
+				{Operator: taskengine.OpEquals, When: "approved", Goto: taskengine.TermEnd},
+				{Operator: taskengine.OpDefault, Goto: "draft"},
+			}}),
+		}}
+		require.Empty(t, taskengine.ChainWarnings(chain))
+	})
+
+	t.Run("intended loop", func(t *testing.T) {
+		chain := cyclicChain(0)
+		chain.Tasks[1].Transition.Branches[0].Loop = true
+		require.Empty(t, taskengine.ChainWarnings(chain))
+	})
+}
@@ -2,17 +2,22 @@ package taskengine
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"dario.cat/mergo"
 	"github.com/contenox/runtime/internal/llmrepo"
+	"github.com/contenox/runtime/internal/llmresolver"
 	libmodelprovider "github.com/contenox/runtime/internal/modelrepo"
 	"github.com/contenox/runtime/libtracker"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TaskExecutor executes individual tasks within a workflow.
@@ -94,20 +99,50 @@ func (exe *SimpleExec) Prompt(ctx context.Context, systemInstruction string, llm
 	if llmCall.Models != nil {
 		modelNames = append(modelNames, llmCall.Models...)
 	}
-	response, _, err := exe.repo.PromptExecute(ctx, llmrepo.Request{
+	response, meta, err := exe.repo.PromptExecute(ctx, llmrepo.Request{
 		ProviderTypes: providerNames,
 		ModelNames:    modelNames,
 		Tracker:       exe.tracker,
-	}, systemInstruction, float32(llmCall.Temperature), prompt)
+	}, systemInstruction, llmCall.Temperature, prompt)
 	if err != nil {
 		err = fmt.Errorf("prompt execution failed: %w", err)
 		reportErr(err)
 		return "", err
 	}
 
+	inputTokens, err := exe.countPromptTokens(ctx, meta.ModelName, systemInstruction, prompt)
+	if err != nil {
+		err = fmt.Errorf("tokenizer failed: %w", err)
+		reportErr(err)
+		return "", err
+	}
+	outputTokens, err := exe.repo.CountTokens(ctx, meta.ModelName, response)
+	if err != nil {
+		err = fmt.Errorf("tokenizer failed: %w", err)
+		reportErr(err)
+		return "", err
+	}
+	ReportTokenUsage(ctx, inputTokens, outputTokens)
+
 	return strings.TrimSpace(response), nil
 }
 
+// countPromptTokens counts the tokens of the system instruction and prompt of a prompt call.
+func (exe *SimpleExec) countPromptTokens(ctx context.Context, modelName string, systemInstruction string, prompt string) (int, error) {
+	total := 0
+	for _, text := range []string{systemInstruction, prompt} {
+		if text == "" {
+			continue
+		}
+		count, err := exe.repo.CountTokens(ctx, modelName, text)
+		if err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
 // Prompt resolves a model client and sends the prompt
 // to be executed. Returns the trimmed response string or an error.
 func (exe *SimpleExec) Embed(ctx context.Context, llmCall LLMExecutionConfig, prompt string) ([]float64, error) {
@@ -311,6 +346,16 @@ func (exe *SimpleExec) TaskExec(taskCtx context.Context, startingTime time.Time,
 
 		switch currentTask.Handler {
 		case HandleRawString:
+			if format := currentTask.ExecuteConfig.ResponseFormat; format != nil {
+				if taskErr = format.validate(); taskErr != nil {
+					break
+				}
+				if format.wantsJSON() {
+					output, transitionEval, taskErr = exe.promptJSON(taskCtx, currentTask.SystemInstruction, *currentTask.ExecuteConfig, prompt)
+					outputType = DataTypeJSON
+					break
+				}
+			}
 			transitionEval, taskErr = exe.Prompt(taskCtx, currentTask.SystemInstruction, *currentTask.ExecuteConfig, prompt)
 			output = transitionEval
 			outputType = DataTypeString
@@ -356,12 +401,22 @@ func (exe *SimpleExec) TaskExec(taskCtx context.Context, startingTime time.Time,
 			return nil, DataTypeAny, "", errors.New(message)
 		}
 	case HandleConvertToOpenAIChatResponse:
-		if dataType != DataTypeChatHistory {
-			return nil, DataTypeAny, "", fmt.Errorf("handler '%s' requires input of type 'chat_history', but got '%s'", currentTask.Handler, dataType.String())
-		}
-		chatHistory, ok := input.(ChatHistory)
-		if !ok {
-			return nil, DataTypeAny, "", fmt.Errorf("input data is not of type ChatHistory")
+		var chatHistory ChatHistory
+		switch dataType {
+		case DataTypeChatHistory:
+			var ok bool
+			if chatHistory, ok = input.(ChatHistory); !ok {
+				return nil, DataTypeAny, "", fmt.Errorf("input data is not of type ChatHistory")
+			}
+		case DataTypeJSON:
+			// The validated reply of a task with a JSON response format.
+			content, err := json.Marshal(input)
+			if err != nil {
+				return nil, DataTypeAny, "", fmt.Errorf("failed to encode JSON input: %w", err)
+			}
+			chatHistory.Messages = []Message{{Role: "assistant", Content: string(content), Timestamp: time.Now().UTC()}}
+		default:
+			return nil, DataTypeAny, "", fmt.Errorf("handler '%s' requires input of type 'chat_history' or 'json', but got '%s'", currentTask.Handler, dataType.String())
 		}
 
 		id := fmt.Sprintf("chatcmpl-%d-%s", time.Now().UnixNano(), uuid.NewString()[:4])
@@ -371,51 +426,26 @@ func (exe *SimpleExec) TaskExec(taskCtx context.Context, startingTime time.Time,
 		transitionEval = "converted"
 		taskErr = nil
 
-	case HandleModelExecution:
-		if currentTask.ExecuteConfig == nil {
-			currentTask.ExecuteConfig = &LLMExecutionConfig{}
+	case HandleModelExecution, HandleToolCalling:
+		chatHistory, finalExecConfig, err := prepareChatInput(currentTask, input, dataType)
+		if err != nil {
+			return nil, DataTypeAny, "", err
 		}
 
-		var chatHistory ChatHistory
-		var finalExecConfig *LLMExecutionConfig = currentTask.ExecuteConfig
-
-		switch dataType {
-		case DataTypeOpenAIChat:
-			openAIRequest, ok := input.(OpenAIChatRequest)
-			if !ok {
-				return nil, DataTypeAny, "", fmt.Errorf("input data for handler %s claimed to be %s but was %T", currentTask.Handler, dataType.String(), input)
-			}
-
-			var requestConfig LLMExecutionConfig
-			chatHistory, _, requestConfig = ConvertOpenAIToChatHistory(openAIRequest)
-
-			finalExecConfig = &requestConfig
-			if err := mergo.Merge(finalExecConfig, currentTask.ExecuteConfig, mergo.WithOverride); err != nil {
-				return nil, DataTypeAny, "", fmt.Errorf("failed to merge execution configs: %w", err)
-			}
-
-		case DataTypeChatHistory:
-			var ok bool
-			chatHistory, ok = input.(ChatHistory)
-			if !ok {
-				return nil, DataTypeAny, "", fmt.Errorf("input data for handler %s claimed to be %s but was %T", currentTask.Handler, dataType.String(), input)
-			}
-
-		default:
-			return nil, DataTypeAny, "", fmt.Errorf("handler '%s' requires input of type 'openai_chat' or 'chat_history', but got '%s'", currentTask.Handler, dataType.String())
+		if currentTask.Handler == HandleToolCalling {
+			output, outputType, transitionEval, taskErr = exe.executeWithTools(
+				taskCtx,
+				startingTime,
+				chatHistory,
+				ctxLength,
+				finalExecConfig,
+			)
+			break
 		}
-		if currentTask.SystemInstruction != "" {
-			alreadyPresent := false
-			for _, msg := range chatHistory.Messages {
-				if msg.Role == "system" && msg.Content == currentTask.SystemInstruction {
-					alreadyPresent = true
-					break
-				}
-			}
-			if !alreadyPresent {
-				messages := []Message{{Role: "system", Content: currentTask.SystemInstruction, Timestamp: time.Now().UTC()}}
-				chatHistory.Messages = append(messages, chatHistory.Messages...)
-			}
+
+		if finalExecConfig.ResponseFormat.wantsJSON() {
+			output, outputType, transitionEval, taskErr = exe.executeJSON(taskCtx, chatHistory, ctxLength, finalExecConfig)
+			break
 		}
 
 		// Call the final execution function with the prepared data
@@ -430,8 +460,10 @@ func (exe *SimpleExec) TaskExec(taskCtx context.Context, startingTime time.Time,
 		if currentTask.Hook == nil {
 			taskErr = fmt.Errorf("hook task missing hook definition")
 		} else {
-			if currentTask.Hook.Args == nil {
-				currentTask.Hook.Args = make(map[string]string)
+			// The hook definition is shared with the caller's chain; never modify it.
+			hook := *currentTask.Hook
+			if hook.Args == nil {
+				hook.Args = make(map[string]string)
 			}
 			output, outputType, transitionEval, taskErr = exe.hookengine(
 				taskCtx,
@@ -439,7 +471,7 @@ func (exe *SimpleExec) TaskExec(taskCtx context.Context, startingTime time.Time,
 				output,
 				outputType,
 				transitionEval,
-				currentTask.Hook,
+				&hook,
 			)
 		}
 
@@ -450,6 +482,63 @@ func (exe *SimpleExec) TaskExec(taskCtx context.Context, startingTime time.Time,
 	return output, outputType, transitionEval, taskErr
 }
 
+// prepareChatInput builds the chat history and execution config a chat model task runs with.
+// OpenAI chat requests are converted and their sampling parameters merged with the task's
+// ExecuteConfig, and the task's SystemInstruction is prepended unless already present.
+func prepareChatInput(currentTask *TaskDefinition, input any, dataType DataType) (ChatHistory, *LLMExecutionConfig, error) {
+	if currentTask.ExecuteConfig == nil {
+		currentTask.ExecuteConfig = &LLMExecutionConfig{}
+	}
+
+	var chatHistory ChatHistory
+	var finalExecConfig *LLMExecutionConfig = currentTask.ExecuteConfig
+
+	switch dataType {
+	case DataTypeOpenAIChat:
+		openAIRequest, ok := input.(OpenAIChatRequest)
+		if !ok {
+			return ChatHistory{}, nil, fmt.Errorf("input data for handler %s claimed to be %s but was %T", currentTask.Handler, dataType.String(), input)
+		}
+
+		var requestConfig LLMExecutionConfig
+		chatHistory, _, requestConfig = ConvertOpenAIToChatHistory(openAIRequest)
+
+		finalExecConfig = &requestConfig
+		if err := mergo.Merge(finalExecConfig, currentTask.ExecuteConfig, mergo.WithOverride); err != nil {
+			return ChatHistory{}, nil, fmt.Errorf("failed to merge execution configs: %w", err)
+		}
+
+	case DataTypeChatHistory:
+		var ok bool
+		chatHistory, ok = input.(ChatHistory)
+		if !ok {
+			return ChatHistory{}, nil, fmt.Errorf("input data for handler %s claimed to be %s but was %T", currentTask.Handler, dataType.String(), input)
+		}
+
+	default:
+		return ChatHistory{}, nil, fmt.Errorf("handler '%s' requires input of type 'openai_chat' or 'chat_history', but got '%s'", currentTask.Handler, dataType.String())
+	}
+	// Normalize a copy, the input may still be referenced by chain variables.
+	chatHistory.Messages = slices.Clone(chatHistory.Messages)
+	if err := chatHistory.NormalizeRoles(); err != nil {
+		return ChatHistory{}, nil, fmt.Errorf("task %s: %w", currentTask.ID, err)
+	}
+	if currentTask.SystemInstruction != "" {
+		alreadyPresent := false
+		for _, msg := range chatHistory.Messages {
+			if msg.Role == "system" && msg.Content == currentTask.SystemInstruction {
+				alreadyPresent = true
+				break
+			}
+		}
+		if !alreadyPresent {
+			messages := []Message{{Role: "system", Content: currentTask.SystemInstruction, Timestamp: time.Now().UTC()}}
+			chatHistory.Messages = append(messages, chatHistory.Messages...)
+		}
+	}
+	return chatHistory, finalExecConfig, nil
+}
+
 func (exe *SimpleExec) parseTransition(inputStr string) (string, error) {
 	if inputStr == "" {
 		return "", nil
@@ -469,13 +558,24 @@ func (exe *SimpleExec) parseTransition(inputStr string) (string, error) {
 	return command, nil
 }
 
-func (exe *SimpleExec) executeLLM(ctx context.Context, input ChatHistory, ctxLength int, llmCall *LLMExecutionConfig) (any, DataType, string, error) {
-	reportErr, _, end := exe.tracker.Start(ctx, "SimpleExec", "prompt_model",
+func (exe *SimpleExec) executeLLM(ctx context.Context, input ChatHistory, ctxLength int, llmCall *LLMExecutionConfig, opts ...libmodelprovider.ChatOption) (any, DataType, string, error) {
+	reportErr, reportChange, end := exe.tracker.Start(ctx, "SimpleExec", "prompt_model",
 		"model_name", llmCall.Model,
 		"model_names", llmCall.Models,
 		"provider_types", llmCall.Providers,
 		"provider_type", llmCall.Provider)
 	defer end()
+	if err := llmCall.Validate(); err != nil {
+		reportErr(err)
+		return nil, DataTypeAny, "", err
+	}
+	if llmCall.Summarize != nil {
+		var err error
+		if input, err = exe.summarizeHistory(ctx, input, llmCall); err != nil {
+			reportErr(err)
+			return nil, DataTypeAny, "", err
+		}
+	}
 	providerNames := []string{}
 	if llmCall.Provider != "" {
 		providerNames = append(providerNames, llmCall.Provider)
@@ -484,14 +584,12 @@ func (exe *SimpleExec) executeLLM(ctx context.Context, input ChatHistory, ctxLen
 		providerNames = append(providerNames, llmCall.Providers...)
 	}
 	if input.InputTokens <= 0 {
-		for _, m := range input.Messages {
-			InputCount, err := exe.repo.CountTokens(ctx, llmCall.Model, m.Content)
-			if err != nil {
-				reportErr(fmt.Errorf("token count failed: %w", err))
-				return nil, DataTypeAny, "", fmt.Errorf("token count failed: %w", err)
-			}
-			input.InputTokens += InputCount
+		count, err := countPromptTokens(ctx, exe.repo, llmCall.Model, input.Messages)
+		if err != nil {
+			reportErr(err)
+			return nil, DataTypeAny, "", err
 		}
+		input.InputTokens = count
 	}
 	if ctxLength > 0 && input.InputTokens > ctxLength {
 		reportErr(fmt.Errorf("input token count %d exceeds context length %d", input.InputTokens, ctxLength))
@@ -509,23 +607,32 @@ func (exe *SimpleExec) executeLLM(ctx context.Context, input ChatHistory, ctxLen
 	messagesC := []libmodelprovider.Message{}
 	for _, m := range input.Messages {
 		messagesC = append(messagesC, libmodelprovider.Message{
-			Role:    m.Role,
-			Content: m.Content,
+			Role:       m.Role,
+			Content:    m.Content,
+			ToolCalls:  toProviderToolCalls(m.ToolCalls),
+			ToolCallID: m.ToolCallID,
 		})
 	}
-	resp, meta, err := exe.repo.Chat(ctx, llmrepo.Request{
+	resp, meta, attempted, err := exe.chatWithFallback(ctx, llmrepo.Request{
 		ProviderTypes: providerNames,
-		ModelNames:    modelNames,
 		ContextLength: input.InputTokens,
 		Tracker:       exe.tracker,
-	}, messagesC)
+	}, modelNames, llmCall.FallbackOnError, messagesC, append(chatOptions(llmCall), opts...)...)
 	if err != nil {
+		reportErr(err)
 		return nil, DataTypeAny, "", fmt.Errorf("chat failed: %w", err)
 	}
+	reportChange(meta.ModelName, map[string]any{
+		"model_name": meta.ModelName,
+		"backend_id": meta.BackendID,
+		"attempted":  attempted,
+	})
+	input.Model = meta.ModelName
 	input.Messages = append(input.Messages, Message{
 		Role:      resp.Role,
 		Content:   resp.Content,
 		Timestamp: time.Now().UTC(),
+		ToolCalls: fromProviderToolCalls(resp.ToolCalls),
 	})
 
 	outputTokensCount, err := exe.repo.CountTokens(ctx, meta.ModelName, resp.Content)
@@ -535,13 +642,112 @@ func (exe *SimpleExec) executeLLM(ctx context.Context, input ChatHistory, ctxLen
 		return nil, DataTypeAny, "", err
 	}
 	input.OutputTokens = outputTokensCount
+	ReportTokenUsage(ctx, input.InputTokens, outputTokensCount)
+	input.FinishReason = resp.FinishReason
+	if input.FinishReason == "" {
+		input.FinishReason = libmodelprovider.FinishReasonStop
+		if llmCall.MaxTokens > 0 && outputTokensCount >= llmCall.MaxTokens {
+			input.FinishReason = libmodelprovider.FinishReasonLength
+		}
+	}
 
 	return input, DataTypeChatHistory, "executed", nil
 }
 
+// chatOptions returns the chat options for the sampling parameters that are set.
+func chatOptions(c *LLMExecutionConfig) []libmodelprovider.ChatOption {
+	var opts []libmodelprovider.ChatOption
+	if c.Temperature != nil {
+		opts = append(opts, libmodelprovider.WithTemperature(float64(*c.Temperature)))
+	}
+	if c.TopP > 0 {
+		opts = append(opts, libmodelprovider.WithTopP(c.TopP))
+	}
+	if c.MaxTokens > 0 {
+		opts = append(opts, libmodelprovider.WithMaxTokens(c.MaxTokens))
+	}
+	if len(c.Stop) > 0 {
+		opts = append(opts, libmodelprovider.WithStop(c.Stop))
+	}
+	if c.KeepAlive != "" {
+		// Invalid values are rejected by Validate before the task runs.
+		if keepAlive, err := libmodelprovider.ParseKeepAlive(string(c.KeepAlive)); err == nil {
+			opts = append(opts, libmodelprovider.WithKeepAlive(keepAlive))
+		}
+	}
+	return opts
+}
+
+// chatWithFallback tries modelNames in order until one of them serves the request.
+// Models that are not available in the runtime are always skipped; errors returned
+// by an available model only move on to the next model if fallbackOnError is set.
+// With no model names the repository's default model is used.
+// It returns the response, the serving model's metadata and the models attempted.
+func (exe *SimpleExec) chatWithFallback(ctx context.Context, req llmrepo.Request, modelNames []string, fallbackOnError bool, messages []libmodelprovider.Message, opts ...libmodelprovider.ChatOption) (libmodelprovider.Message, llmrepo.Meta, []string, error) {
+	if len(modelNames) == 0 {
+		resp, meta, err := exe.repo.Chat(ctx, req, messages, opts...)
+		return resp, meta, nil, err
+	}
+	var errs []error
+	attempted := make([]string, 0, len(modelNames))
+	for _, model := range modelNames {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		attempted = append(attempted, model)
+		req.ModelNames = []string{model}
+		resp, meta, err := exe.repo.Chat(ctx, req, messages, opts...)
+		if err == nil {
+			return resp, meta, attempted, nil
+		}
+		errs = append(errs, fmt.Errorf("model %s: %w", model, err))
+		if !isModelUnavailable(err) && !fallbackOnError {
+			break
+		}
+	}
+	return libmodelprovider.Message{}, llmrepo.Meta{}, attempted, errors.Join(errs...)
+}
+
+// isModelUnavailable reports whether err means the requested model could not be resolved,
+// as opposed to the model failing while serving the request.
+func isModelUnavailable(err error) bool {
+	return errors.Is(err, llmresolver.ErrNoSatisfactoryModel) || errors.Is(err, llmresolver.ErrNoAvailableModels)
+}
+
+// ErrHookTimeout indicates a hook did not finish within the task's timeout.
+// It is retryable.
+var ErrHookTimeout = errors.New("hook timed out")
+
+// hookengine runs the hook under ctx and returns as soon as ctx is done, so a hook
+// that ignores cancellation cannot stall the chain past the task's timeout.
 func (exe *SimpleExec) hookengine(ctx context.Context, startingTime time.Time, input any, dataType DataType, transition string, hook *HookCall) (any, DataType, string, error) {
-	res, dataType, transition, err := exe.hookProvider.Exec(ctx, startingTime, input, dataType, transition, hook)
-	return res, dataType, transition, err
+	ctx, span := childTracer(trace.SpanFromContext(ctx)).Start(ctx, "taskengine.hook", trace.WithAttributes(
+		attribute.String(attrHookName, hook.Name),
+	))
+	type hookResult struct {
+		output     any
+		dataType   DataType
+		transition string
+		err        error
+	}
+	done := make(chan hookResult, 1)
+	go func() {
+		output, outputType, next, err := exe.hookProvider.Exec(ctx, startingTime, input, dataType, transition, hook)
+		done <- hookResult{output: output, dataType: outputType, transition: next, err: err}
+	}()
+
+	var res hookResult
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res = hookResult{dataType: DataTypeAny, transition: transition, err: ctx.Err()}
+	}
+	if res.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		res.err = Transient(fmt.Errorf("hook %s: %w: %w", hook.Name, ErrHookTimeout, res.err))
+	}
+	endSpan(span, res.err)
+	return res.output, res.dataType, res.transition, res.err
 }
 
 // condition executes a prompt and evaluates its result against a provided condition mapping.
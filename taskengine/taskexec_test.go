@@ -0,0 +1,292 @@
+package taskengine_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/internal/llmrepo"
+	"github.com/contenox/runtime/internal/llmresolver"
+	libmodelprovider "github.com/contenox/runtime/internal/modelrepo"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRuntime serves chat requests for the models in available.
+// Models listed in failing are available but return the given error.
+type fakeRuntime struct {
+	llmrepo.ModelRepo
+	available []string
+	failing   map[string]error
+	calls     []string
+	sampling  samplingArgs
+}
+
+// samplingArgs records the chat options applied to a request.
+type samplingArgs struct {
+	temperature *float64
+	maxTokens   int
+	topP        float64
+	stop        []string
+	keepAlive   *time.Duration
+}
+
+func (a *samplingArgs) SetTemperature(v float64)         { a.temperature = &v }
+func (a *samplingArgs) SetMaxTokens(v int)               { a.maxTokens = v }
+func (a *samplingArgs) SetTopP(v float64)                { a.topP = v }
+func (a *samplingArgs) SetStop(v []string)               { a.stop = v }
+func (a *samplingArgs) SetTools([]libmodelprovider.Tool) {}
+func (a *samplingArgs) SetKeepAlive(v time.Duration)     { a.keepAlive = &v }
+
+func (f *fakeRuntime) CountTokens(ctx context.Context, modelName string, prompt string) (int, error) {
+	return len(prompt), nil
+}
+
+func (f *fakeRuntime) Chat(ctx context.Context, req llmrepo.Request, messages []libmodelprovider.Message, opts ...libmodelprovider.ChatOption) (libmodelprovider.Message, llmrepo.Meta, error) {
+	model := req.ModelNames[0]
+	f.calls = append(f.calls, model)
+	f.sampling = samplingArgs{}
+	for _, opt := range opts {
+		opt.ApplyTo(&f.sampling)
+	}
+	if !slices.Contains(f.available, model) {
+		return libmodelprovider.Message{}, llmrepo.Meta{}, fmt.Errorf("chat: client resolution failed: %w", llmresolver.ErrNoSatisfactoryModel)
+	}
+	if err, ok := f.failing[model]; ok {
+		return libmodelprovider.Message{}, llmrepo.Meta{}, fmt.Errorf("chat execution failed: %w", err)
+	}
+	resp := libmodelprovider.Message{Role: "assistant", Content: "hi from " + model, FinishReason: libmodelprovider.FinishReasonStop}
+	if max := f.sampling.maxTokens; max > 0 && len(resp.Content) > max {
+		resp.Content = resp.Content[:max]
+		resp.FinishReason = libmodelprovider.FinishReasonLength
+	}
+	return resp, llmrepo.Meta{ModelName: model, BackendID: "b1"}, nil
+}
+
+type noHooks struct{}
+
+func (noHooks) Exec(context.Context, time.Time, any, taskengine.DataType, string, *taskengine.HookCall) (any, taskengine.DataType, string, error) {
+	return nil, taskengine.DataTypeAny, "", errors.New("no hooks")
+}
+
+func (noHooks) ValidateArgs(context.Context, *taskengine.HookCall) error { return nil }
+
+func (noHooks) Supports(context.Context) ([]string, error) { return nil, nil }
+
+func (noHooks) Schemas(context.Context) ([]taskengine.HookSchema, error) { return nil, nil }
+
+func execModel(t *testing.T, runtime *fakeRuntime, cfg *taskengine.LLMExecutionConfig) (any, error) {
+	t.Helper()
+	exec, err := taskengine.NewExec(t.Context(), runtime, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{{Role: "user", Content: "hello"}}}
+	out, _, _, err := exec.TaskExec(context.Background(), time.Now(), 0, &taskengine.TaskDefinition{
+		ID:            "chat",
+		Handler:       taskengine.HandleModelExecution,
+		ExecuteConfig: cfg,
+	}, history, taskengine.DataTypeChatHistory)
+	return out, err
+}
+
+func TestUnit_ModelExecution_FallsBackWhenModelUnavailable(t *testing.T) {
+	runtime := &fakeRuntime{available: []string{"small"}}
+
+	out, err := execModel(t, runtime, &taskengine.LLMExecutionConfig{Models: []string{"large", "small"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"large", "small"}, runtime.calls)
+
+	history, ok := out.(taskengine.ChatHistory)
+	require.True(t, ok)
+	require.Equal(t, "small", history.Model)
+	require.Equal(t, "hi from small", history.Messages[len(history.Messages)-1].Content)
+}
+
+func TestUnit_ModelExecution_ModelErrorStopsWithoutFallbackOnError(t *testing.T) {
+	runtime := &fakeRuntime{
+		available: []string{"large", "small"},
+		failing:   map[string]error{"large": errors.New("500 internal error")},
+	}
+
+	_, err := execModel(t, runtime, &taskengine.LLMExecutionConfig{Models: []string{"large", "small"}})
+	require.ErrorContains(t, err, "500 internal error")
+	require.Equal(t, []string{"large"}, runtime.calls)
+
+	runtime.calls = nil
+	out, err := execModel(t, runtime, &taskengine.LLMExecutionConfig{Models: []string{"large", "small"}, FallbackOnError: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"large", "small"}, runtime.calls)
+	require.Equal(t, "small", out.(taskengine.ChatHistory).Model)
+}
+
+func TestUnit_ModelExecution_FailsWhenAllModelsExhausted(t *testing.T) {
+	runtime := &fakeRuntime{}
+
+	_, err := execModel(t, runtime, &taskengine.LLMExecutionConfig{Model: "large", Models: []string{"small"}})
+	require.ErrorIs(t, err, llmresolver.ErrNoSatisfactoryModel)
+	require.ErrorContains(t, err, "model large")
+	require.ErrorContains(t, err, "model small")
+	require.Equal(t, []string{"large", "small"}, runtime.calls)
+}
+
+func TestUnit_ModelExecution_ForwardsSamplingParameters(t *testing.T) {
+	runtime := &fakeRuntime{available: []string{"small"}}
+
+	_, err := execModel(t, runtime, &taskengine.LLMExecutionConfig{
+		Model:       "small",
+		Temperature: ptr[float32](0.25),
+		TopP:        0.9,
+		MaxTokens:   64,
+		Stop:        []string{"###"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, samplingArgs{temperature: ptr(0.25), maxTokens: 64, topP: 0.9, stop: []string{"###"}}, runtime.sampling)
+
+	_, err = execModel(t, runtime, &taskengine.LLMExecutionConfig{Model: "small"})
+	require.NoError(t, err)
+	require.Equal(t, samplingArgs{}, runtime.sampling, "unset parameters must not be forwarded")
+
+	_, err = execModel(t, runtime, &taskengine.LLMExecutionConfig{Model: "small", Temperature: ptr[float32](0)})
+	require.NoError(t, err)
+	require.Equal(t, samplingArgs{temperature: ptr(0.0)}, runtime.sampling, "an explicit zero temperature must be forwarded")
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestUnit_ModelExecution_ForwardsOpenAIRequestParameters(t *testing.T) {
+	runtime := &fakeRuntime{available: []string{"small"}}
+	exec, err := taskengine.NewExec(t.Context(), runtime, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+
+	req := taskengine.OpenAIChatRequest{
+		Model:       "small",
+		Messages:    []taskengine.OpenAIChatRequestMessage{{Role: "user", Content: "hello"}},
+		Temperature: ptr(1.5),
+		TopP:        0.5,
+		MaxTokens:   32,
+		Stop:        []string{"\n"},
+	}
+	_, _, _, err = exec.TaskExec(context.Background(), time.Now(), 0, &taskengine.TaskDefinition{
+		ID:      "chat",
+		Handler: taskengine.HandleModelExecution,
+	}, req, taskengine.DataTypeOpenAIChat)
+	require.NoError(t, err)
+	require.Equal(t, samplingArgs{temperature: ptr(1.5), maxTokens: 32, topP: 0.5, stop: []string{"\n"}}, runtime.sampling)
+
+	req = taskengine.OpenAIChatRequest{}
+	require.NoError(t, json.Unmarshal([]byte(`{"model":"small","messages":[{"role":"user","content":"hello"}],"temperature":0}`), &req))
+	_, _, _, err = exec.TaskExec(context.Background(), time.Now(), 0, &taskengine.TaskDefinition{
+		ID:      "chat",
+		Handler: taskengine.HandleModelExecution,
+	}, req, taskengine.DataTypeOpenAIChat)
+	require.NoError(t, err)
+	require.Equal(t, samplingArgs{temperature: ptr(0.0)}, runtime.sampling, "an explicit zero temperature must be forwarded")
+}
+
+func TestUnit_ModelExecution_ForwardsKeepAlive(t *testing.T) {
+	runtime := &fakeRuntime{available: []string{"small"}}
+
+	for keepAlive, want := range map[taskengine.KeepAlive]time.Duration{
+		"10m": 10 * time.Minute,
+		"300": 5 * time.Minute,
+		"-1":  -1,
+		"0":   0,
+	} {
+		_, err := execModel(t, runtime, &taskengine.LLMExecutionConfig{Model: "small", KeepAlive: keepAlive})
+		require.NoError(t, err)
+		require.NotNil(t, runtime.sampling.keepAlive, keepAlive)
+		require.Equal(t, want, *runtime.sampling.keepAlive, keepAlive)
+	}
+
+	// Ollama clients commonly send keep_alive as a number of seconds.
+	for raw, want := range map[string]time.Duration{
+		`-1`:    -1,
+		`0`:     0,
+		`300`:   5 * time.Minute,
+		`"10m"`: 10 * time.Minute,
+	} {
+		var cfg taskengine.LLMExecutionConfig
+		require.NoError(t, json.Unmarshal([]byte(`{"model":"small","keep_alive":`+raw+`}`), &cfg), raw)
+		_, err := execModel(t, runtime, &cfg)
+		require.NoError(t, err, raw)
+		require.NotNil(t, runtime.sampling.keepAlive, raw)
+		require.Equal(t, want, *runtime.sampling.keepAlive, raw)
+	}
+
+	var req taskengine.OpenAIChatRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"model":"small","messages":[],"keep_alive":-1}`), &req))
+	require.Equal(t, taskengine.KeepAlive("-1"), req.KeepAlive)
+	require.Error(t, json.Unmarshal([]byte(`{"model":"small","messages":[],"keep_alive":true}`), &req))
+}
+
+func TestUnit_ModelExecution_RejectsOutOfRangeSampling(t *testing.T) {
+	runtime := &fakeRuntime{available: []string{"small"}}
+
+	for _, cfg := range []taskengine.LLMExecutionConfig{
+		{Model: "small", Temperature: ptr[float32](2.5)},
+		{Model: "small", TopP: 1.1},
+		{Model: "small", MaxTokens: -1},
+		{Model: "small", Stop: []string{"a", "b", "c", "d", "e"}},
+		{Model: "small", KeepAlive: "until tomorrow"},
+	} {
+		_, err := execModel(t, runtime, &cfg)
+		require.ErrorIs(t, err, apiframework.ErrBadRequest)
+	}
+	require.Empty(t, runtime.calls)
+}
+
+func TestUnit_OpenAIChain_ReportsFinishReasonAndUsage(t *testing.T) {
+	runtime := &fakeRuntime{available: []string{"small"}}
+	exec, err := taskengine.NewExec(t.Context(), runtime, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		ID: "openai",
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:      "chat",
+				Handler: taskengine.HandleModelExecution,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: "respond"}},
+				},
+			},
+			{
+				ID:      "respond",
+				Handler: taskengine.HandleConvertToOpenAIChatResponse,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd}},
+				},
+			},
+		},
+	}
+	req := taskengine.OpenAIChatRequest{
+		Model:    "small",
+		Messages: []taskengine.OpenAIChatRequestMessage{{Role: "user", Content: "hello"}},
+	}
+
+	out, _, _, err := env.ExecEnv(context.Background(), chain, req, taskengine.DataTypeOpenAIChat)
+	require.NoError(t, err)
+	resp := out.(taskengine.OpenAIChatResponse)
+	require.Equal(t, "stop", resp.Choices[0].FinishReason)
+	require.Equal(t, "hi from small", resp.Choices[0].Message.Content)
+	require.Equal(t, len("hello"), resp.Usage.PromptTokens)
+	require.Equal(t, len("hi from small"), resp.Usage.CompletionTokens)
+	require.Equal(t, resp.Usage.PromptTokens+resp.Usage.CompletionTokens, resp.Usage.TotalTokens)
+
+	req.MaxTokens = 5
+	req.Stop = []string{"###"}
+	out, _, _, err = env.ExecEnv(context.Background(), chain, req, taskengine.DataTypeOpenAIChat)
+	require.NoError(t, err)
+	resp = out.(taskengine.OpenAIChatResponse)
+	require.Equal(t, samplingArgs{maxTokens: 5, stop: []string{"###"}}, runtime.sampling)
+	require.Equal(t, "length", resp.Choices[0].FinishReason)
+	require.Equal(t, "hi fr", resp.Choices[0].Message.Content)
+	require.Equal(t, 5, resp.Usage.CompletionTokens)
+}
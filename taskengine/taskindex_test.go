@@ -0,0 +1,36 @@
+package taskengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_TaskIndex_RetainedPointers(t *testing.T) {
+	tasks := []TaskDefinition{
+		{ID: "first", Handler: HandleNoop},
+		{ID: "second", Handler: HandleNoop},
+		{ID: "third", Handler: HandleNoop},
+	}
+	index, err := validateChain(tasks, false)
+	require.NoError(t, err)
+
+	current, err := index.find("first")
+	require.NoError(t, err)
+	retained := current
+
+	// Transition twice while holding on to the first pointer.
+	current, err = index.find("second")
+	require.NoError(t, err)
+	require.Equal(t, "second", current.ID)
+	current, err = index.find("third")
+	require.NoError(t, err)
+	require.Equal(t, "third", current.ID)
+
+	require.Equal(t, "first", retained.ID)
+	require.Same(t, &tasks[0], retained, "lookups return the slice element, not a copy")
+	require.Same(t, &tasks[2], current)
+
+	_, err = index.find("missing")
+	require.ErrorContains(t, err, "task not found: missing")
+}
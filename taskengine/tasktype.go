@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/contenox/runtime/internal/apiframework"
+	libmodelprovider "github.com/contenox/runtime/internal/modelrepo"
 	"gopkg.in/yaml.v3"
 )
 
@@ -44,12 +46,19 @@ const (
 	// Requires DataTypeChatHistory input and ExecuteConfig configuration.
 	HandleModelExecution TaskHandler = "model_execution"
 
+	// HandleToolCalling executes a model like HandleModelExecution and offers it the
+	// tools declared in ExecuteConfig.Tools. Each tool call is routed to the hook with
+	// the tool's name, its result is appended to the chat history and the model is
+	// invoked again until it answers without tool calls.
+	HandleToolCalling TaskHandler = "tool_calling"
+
 	// HandleParseTransition attempts to parse transition commands (e.g., "/command").
 	// Strips transition prefix if present in input.
 	HandleParseTransition TaskHandler = "parse_transition"
 
 	// HandleConvertToOpenAIChatResponse converts a chat history input to OpenAI Chat format.
-	// Requires DataTypeChatHistory input and ExecuteConfig configuration.
+	// Requires DataTypeChatHistory input, or the DataTypeJSON reply of a task with a JSON
+	// response format, and ExecuteConfig configuration.
 	HandleConvertToOpenAIChatResponse TaskHandler = "convert_to_openai_chat_response"
 
 	// HandleNoop performs no operation, passing input through unchanged.
@@ -98,6 +107,10 @@ func (dt *DataType) UnmarshalJSON(data []byte) error {
 		*dt = DataTypeJSON
 	case "chat_history":
 		*dt = DataTypeChatHistory
+	case "openai_chat":
+		*dt = DataTypeOpenAIChat
+	case "openai_chat_response":
+		*dt = DataTypeOpenAIChatResponse
 	default:
 		return fmt.Errorf("unknown data type: %q", s)
 	}
@@ -152,14 +165,33 @@ type TransitionBranch struct {
 	Operator OperatorTerm `yaml:"operator,omitempty" json:"operator,omitempty" example:"equals" openapi_include_type:"string"`
 
 	// When specifies the condition that must be met to follow this branch.
-	// Format depends on the task type:
-	// - For condition_key: exact string match
-	// - For parse_number: numeric comparison (using Operator)
+	// Format depends on the task's output type:
+	// - For bool outputs (condition_key): "true" or "false" with equals
+	// - For int and float outputs (parse_number, parse_score): a number, compared numerically
+	// - For search_results outputs: "empty" or "has_results" with equals, or a
+	//   number compared against the result count
+	// - Otherwise, or when When does not parse as the output's type: a string
+	//   compared against the task's transition value
 	When string `yaml:"when" json:"when" example:"yes"`
 
+	// Meta names a metadata value the task's hook recorded with SetHookMetadata,
+	// e.g. "confidence". When set, that value is compared to When instead of the
+	// task's output, and the branch is skipped if the hook did not record it.
+	Meta string `yaml:"meta,omitempty" json:"meta,omitempty" example:"confidence"`
+
+	// Tolerance is the margin for numeric comparisons: values within Tolerance of
+	// When count as equal to it, and in_range bounds are widened by it.
+	// Zero falls back to the chain's TransitionTolerance.
+	Tolerance float64 `yaml:"tolerance,omitempty" json:"tolerance,omitempty" example:"0.001"`
+
 	// Goto specifies the target task ID if this branch is taken.
 	// Leave empty or use taskengine.TermEnd to end the chain.
 	Goto string `yaml:"goto" json:"goto" example:"positive_response"`
+
+	// Loop marks a branch that intentionally returns to an earlier task, e.g. to
+	// poll a hook until it reports completion. Chain validation does not warn
+	// about cycles through such branches; the chain's step limit still applies.
+	Loop bool `yaml:"loop,omitempty" json:"loop,omitempty" example:"false"`
 }
 
 // OperatorTerm represents logical operators used for task transition evaluation
@@ -230,7 +262,102 @@ type LLMExecutionConfig struct {
 	Models      []string `yaml:"models,omitempty" json:"models,omitempty" example:"[\"gpt-4\", \"gpt-3.5-turbo\"]"`
 	Provider    string   `yaml:"provider,omitempty" json:"provider,omitempty" example:"ollama"`
 	Providers   []string `yaml:"providers,omitempty" json:"providers,omitempty" example:"[\"ollama\", \"openai\"]"`
-	Temperature float32  `yaml:"temperature,omitempty" json:"temperature,omitempty" example:"0.7"`
+	Temperature *float32 `yaml:"temperature,omitempty" json:"temperature,omitempty" example:"0.7"`
+	// TopP sets nucleus sampling for chat model execution (0-1). Zero leaves the model default.
+	TopP float64 `yaml:"top_p,omitempty" json:"top_p,omitempty" example:"0.9"`
+	// MaxTokens limits the number of generated tokens for chat model execution. Zero leaves the model default.
+	MaxTokens int `yaml:"max_tokens,omitempty" json:"max_tokens,omitempty" example:"512"`
+	// Stop lists sequences at which chat model generation stops.
+	Stop []string `yaml:"stop,omitempty" json:"stop,omitempty" example:"[\"\\n\\n\"]"`
+	// KeepAlive sets how long Ollama backends keep the model loaded after the
+	// request: a duration like "10m", or seconds, where -1 keeps it loaded
+	// indefinitely and 0 unloads it right away. Other backends ignore it.
+	KeepAlive KeepAlive `yaml:"keep_alive,omitempty" json:"keep_alive,omitempty" example:"10m" openapi_include_type:"string"`
+	// FallbackOnError makes model execution try the next model in Model/Models when a model
+	// returns an error. Models that are unavailable are always skipped.
+	FallbackOnError bool `yaml:"fallback_on_error,omitempty" json:"fallback_on_error,omitempty" example:"false"`
+	// Tools declares the tools offered to the model by tool_calling tasks.
+	Tools []ToolDefinition `yaml:"tools,omitempty" json:"tools,omitempty" openapi_include_type:"taskengine.ToolDefinition"`
+	// MaxToolIterations limits how often a tool_calling task invokes the model.
+	// Default: DefaultMaxToolIterations.
+	MaxToolIterations int `yaml:"max_tool_iterations,omitempty" json:"max_tool_iterations,omitempty" example:"5"`
+	// Summarize optionally condenses long chat histories before chat model execution.
+	Summarize *SummarizeConfig `yaml:"summarize,omitempty" json:"summarize,omitempty" openapi_include_type:"taskengine.SummarizeConfig"`
+	// ResponseFormat requests validated JSON output from model_execution and raw_string tasks.
+	ResponseFormat *ResponseFormat `yaml:"response_format,omitempty" json:"response_format,omitempty" openapi_include_type:"taskengine.ResponseFormat"`
+}
+
+// SummarizeConfig controls summarization of chat histories that grow too long.
+// When the history exceeds ThresholdTokens, the oldest messages are replaced by a
+// single system message holding their summary. The summary stays in the returned
+// history, so later turns reuse it and only summarize what was added since.
+type SummarizeConfig struct {
+	// ThresholdTokens triggers summarization when the history holds more tokens.
+	ThresholdTokens int `yaml:"threshold_tokens" json:"threshold_tokens" example:"3000"`
+	// KeepMessages is the number of most recent messages kept verbatim.
+	// Default: DefaultSummaryKeepMessages.
+	KeepMessages int `yaml:"keep_messages,omitempty" json:"keep_messages,omitempty" example:"4"`
+}
+
+// ToolDefinition declares a tool in the OpenAI function format.
+// Calls to the tool are executed by the registered hook of the same name.
+type ToolDefinition struct {
+	// Name of the tool and of the hook that executes it.
+	Name string `yaml:"name" json:"name" example:"get_weather"`
+	// Description tells the model when to use the tool.
+	Description string `yaml:"description,omitempty" json:"description,omitempty" example:"Returns the current weather for a city"`
+	// Parameters is the JSON schema of the tool arguments.
+	Parameters map[string]any `yaml:"parameters,omitempty" json:"parameters,omitempty" openapi_include_type:"object"`
+}
+
+// KeepAlive is an Ollama keep_alive value. In JSON it is accepted both as a
+// string ("10m", "-1") and as a number of seconds (-1, 0, 300), like Ollama does.
+type KeepAlive string
+
+func (k *KeepAlive) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*k = KeepAlive(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("keep_alive must be a duration string like \"10m\" or a number of seconds: %w", err)
+	}
+	*k = KeepAlive(n.String())
+	return nil
+}
+
+// maxStopSequences is the number of stop sequences accepted by OpenAI-compatible backends.
+const maxStopSequences = 4
+
+// Validate checks that the sampling parameters are within their accepted ranges.
+// Unset (zero) parameters are always valid.
+func (c *LLMExecutionConfig) Validate() error {
+	if t := c.Temperature; t != nil && (*t < 0 || *t > 2) {
+		return fmt.Errorf("temperature %v out of range [0, 2]: %w", *t, apiframework.ErrBadRequest)
+	}
+	if c.TopP < 0 || c.TopP > 1 {
+		return fmt.Errorf("top_p %v out of range [0, 1]: %w", c.TopP, apiframework.ErrBadRequest)
+	}
+	if c.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must not be negative: %w", apiframework.ErrBadRequest)
+	}
+	if c.KeepAlive != "" {
+		if _, err := libmodelprovider.ParseKeepAlive(string(c.KeepAlive)); err != nil {
+			return fmt.Errorf("%w: %w", err, apiframework.ErrBadRequest)
+		}
+	}
+	if len(c.Stop) > maxStopSequences {
+		return fmt.Errorf("at most %d stop sequences are allowed: %w", maxStopSequences, apiframework.ErrBadRequest)
+	}
+	if c.Summarize != nil && (c.Summarize.ThresholdTokens <= 0 || c.Summarize.KeepMessages < 0) {
+		return fmt.Errorf("summarize requires a positive threshold_tokens and a non-negative keep_messages: %w", apiframework.ErrBadRequest)
+	}
+	if c.ResponseFormat != nil {
+		return c.ResponseFormat.validate()
+	}
+	return nil
 }
 
 // HookCall represents an external integration or side-effect triggered during a task.
@@ -256,6 +383,8 @@ type HookCall struct {
 // | Print               | Optional     | Optional    | Optional   | Optional   | Optional  | Opt   | Opt   |
 // | ExecuteConfig       | Optional     | Optional    | Optional   | Optional   | Optional  | -     | -     |
 // | InputVar            | Optional     | Optional    | Optional   | Optional   | Optional  | Opt   | Opt   |
+// | InputTypes          | Optional     | Optional    | Optional   | Optional   | Optional  | Opt   | Opt   |
+// | SkipInputTypes      | -            | -           | -          | -          | -         | Opt   | -     |
 // | SystemInstruction   | Optional     | Optional    | Optional   | Optional   | Optional  | Opt   | Opt   |
 // | Compose             | Optional     | Optional    | Optional   | Optional   | Optional  | Opt   | Opt   |
 // | Transition          | Required     | Required    | Required   | Required   | Required  | Req   | Req   |
@@ -289,19 +418,37 @@ type TaskDefinition struct {
 	// Supports template variables from previous task outputs.
 	// Optional for all task types except Hook where it's rarely used.
 	// Example: "The score is: {{.previous_output}}"
+	// Helper functions such as upper, lower, trim, default, toJson and now are available.
 	Print string `yaml:"print,omitempty" json:"print,omitempty" example:"Validation result: {{.validate_input}}"`
 
 	// PromptTemplate is the text prompt sent to the LLM.
 	// It's Required and only applicable for the raw_string type.
 	// Supports template variables from previous task outputs.
 	// Example: "Rate the quality from 1-10: {{.input}}"
+	// Helper functions such as upper, lower, trim, default, toJson and now are available.
 	PromptTemplate string `yaml:"prompt_template" json:"prompt_template" example:"Is this input valid? {{.input}}"`
 
 	// InputVar is the name of the variable to use as input for the task.
 	// Example: "input" for the original input.
-	// Each task stores its output in a variable named with it's task id.
+	// Each task stores its output in a variable named with it's task id, so
+	// naming an earlier, non-adjacent task feeds its output to this task instead
+	// of the previous output, e.g. for a task merging two branches.
+	// The name must be "input", "previous_output", a task ID or "<task ID>_composed"
+	// of a task with Compose; other names are rejected when the chain is validated.
 	InputVar string `yaml:"input_var,omitempty" json:"input_var,omitempty" example:"input"`
 
+	// InputTypes optionally declares which data types the task accepts as input.
+	// Other types are converted when a conversion is registered (see RegisterConversion),
+	// otherwise the engine fails with ErrTypeMismatch before executing the task.
+	// Empty or containing "any" accepts every type.
+	InputTypes []DataType `yaml:"input_types,omitempty" json:"input_types,omitempty" example:"[\"string\"]" openapi_include_type:"string"`
+
+	// SkipInputTypes lists input data types for which a hook task is skipped.
+	// A skipped task passes its input through unchanged and transitions with "skipped",
+	// e.g. a conversion hook skips inputs that already have the target type.
+	// Only valid for Hook tasks.
+	SkipInputTypes []DataType `yaml:"skip_input_types,omitempty" json:"skip_input_types,omitempty" example:"[\"chat_history\"]" openapi_include_type:"string"`
+
 	// Compose merges the specified the output with the withVar side.
 	// Optional. compose is applied before the input reaches the task execution,
 	Compose *ComposeTask `yaml:"compose,omitempty" json:"compose,omitempty" openapi_include_type:"taskengine.ComposeTask"`
@@ -318,8 +465,26 @@ type TaskDefinition struct {
 	// Applies to all task types including Hooks.
 	// Default: 0 (no retries)
 	RetryOnFailure int `yaml:"retry_on_failure,omitempty" json:"retry_on_failure,omitempty" example:"2"`
+
+	// Capture controls how much of this task's input and output is recorded
+	// in execution traces and activity reports.
+	// Use "summary" or "none" for tasks producing large or sensitive intermediates.
+	// Default: "full"
+	Capture CaptureMode `yaml:"capture,omitempty" json:"capture,omitempty" example:"summary" openapi_include_type:"string"`
 }
 
+// CaptureMode selects how much of a task's content is captured for tracing.
+type CaptureMode string
+
+const (
+	// CaptureFull records task input and output as they are.
+	CaptureFull CaptureMode = "full"
+	// CaptureSummary records a truncated prefix with the length and SHA-256 of the content.
+	CaptureSummary CaptureMode = "summary"
+	// CaptureNone records only metadata about the content.
+	CaptureNone CaptureMode = "none"
+)
+
 // ComposeTask is a task that composes multiple variables into a single output.
 // the composed output is stored in a variable named after the task ID with "_composed" suffix.
 // and is also directly mutating the task's output.
@@ -357,6 +522,10 @@ type TaskChainDefinition struct {
 	// Enables capturing user input and output.
 	Debug bool `yaml:"debug" json:"debug"`
 
+	// DryRun renders templates and walks the transitions without calling any
+	// model or hook. Each step records its rendered input and stubbed output.
+	DryRun bool `yaml:"dry_run,omitempty" json:"dry_run,omitempty"`
+
 	// Description provides a human-readable summary of the chain's purpose.
 	Description string `yaml:"description" json:"description"`
 
@@ -365,6 +534,53 @@ type TaskChainDefinition struct {
 
 	// TokenLimit is the token limit for the context window (used during execution).
 	TokenLimit int64 `yaml:"token_limit" json:"token_limit"`
+
+	// RetryBudget caps the retries of all tasks of an execution together. Once
+	// it is used up the next retry fails the chain with ErrRetryBudgetExhausted,
+	// even if the task has RetryOnFailure left. Zero means unlimited.
+	RetryBudget int `yaml:"retry_budget,omitempty" json:"retry_budget,omitempty" example:"5"`
+
+	// MaxSteps caps the number of tasks an execution may run, counting every
+	// revisit of a task. Chains whose transitions loop fail with
+	// ErrChainStepLimitExceeded once it is reached. The limit of the
+	// environment, see WithMaxSteps, still applies: values above it have no
+	// effect. Zero uses the limit of the environment.
+	MaxSteps int `yaml:"max_steps,omitempty" json:"max_steps,omitempty" example:"50"`
+
+	// TransitionTolerance is the default Tolerance of numeric transition branches,
+	// e.g. to keep model scores like 0.7000001 from missing a 0.7 threshold.
+	TransitionTolerance float64 `yaml:"transition_tolerance,omitempty" json:"transition_tolerance,omitempty" example:"0.001"`
+
+	// RequireDefaultBranch rejects chains with tasks that have conditional
+	// branches but no default branch. When unset such tasks are only reported
+	// as warnings by chain validation.
+	RequireDefaultBranch bool `yaml:"require_default_branch,omitempty" json:"require_default_branch,omitempty"`
+
+	// OnComplete optionally posts the chain result to a webhook once execution finishes.
+	OnComplete *WebhookConfig `yaml:"on_complete,omitempty" json:"on_complete,omitempty" openapi_include_type:"taskengine.WebhookConfig"`
+
+	// InputSchema optionally declares the JSON schema the chain input must match.
+	// Inputs that do not match are rejected before any task runs.
+	InputSchema map[string]any `yaml:"input_schema,omitempty" json:"input_schema,omitempty" openapi_include_type:"object"`
+
+	// OutputSchema optionally declares the JSON schema the final output must match.
+	OutputSchema map[string]any `yaml:"output_schema,omitempty" json:"output_schema,omitempty" openapi_include_type:"object"`
+}
+
+// WebhookConfig describes an HTTP endpoint notified when a chain finishes.
+// Delivery failures are reported to the activity tracker and never fail the chain.
+type WebhookConfig struct {
+	// URL receives a POST with the chain ID, status, duration and result.
+	URL string `yaml:"url" json:"url" example:"https://hooks.example.com/chain-done"`
+
+	// Headers are added to the request, e.g. for authentication.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+
+	// Timeout limits each delivery attempt. Format: "10s". Default: 10s.
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty" example:"10s"`
+
+	// Retries sets how many times a failed delivery is retried, at most 5. Default: 0.
+	Retries int `yaml:"retries,omitempty" json:"retries,omitempty" example:"2"`
 }
 
 type SearchResult struct {
@@ -383,6 +599,9 @@ type ChatHistory struct {
 	InputTokens int `json:"inputTokens" example:"15"`
 	// OutputTokens will be filled by the engine and will hold the number of tokens used for the output.
 	OutputTokens int `json:"outputTokens" example:"10"`
+	// FinishReason is filled by the engine and tells why the model stopped generating
+	// the last message: "stop" for a natural end, "length" when the token limit was hit.
+	FinishReason string `json:"finishReason,omitempty" example:"stop"`
 }
 
 // Message represents a single message in a chat conversation.
@@ -397,6 +616,19 @@ type Message struct {
 	Content string `json:"content" example:"What is the capital of France?"`
 	// Timestamp is the time the message was sent.
 	Timestamp time.Time `json:"timestamp" example:"2023-11-15T14:30:45Z"`
+	// ToolCalls lists the tools an assistant message asked to call.
+	ToolCalls []ToolCall `json:"toolCalls,omitempty" openapi_include_type:"taskengine.ToolCall"`
+	// ToolCallID is set on "tool" messages and links the result to its call.
+	ToolCallID string `json:"toolCallId,omitempty" example:"call_123"`
+}
+
+// ToolCall is a tool invocation requested by the model.
+type ToolCall struct {
+	ID string `json:"id" example:"call_123"`
+	// Name is the name of the requested tool.
+	Name string `json:"name" example:"get_weather"`
+	// Arguments holds the JSON encoded tool arguments.
+	Arguments string `json:"arguments" example:"{\"city\": \"Paris\"}"`
 }
 
 // OpenAIChatRequest represents a request compatible with OpenAI's chat API.
@@ -404,7 +636,7 @@ type OpenAIChatRequest struct {
 	Model            string                     `json:"model" example:"mistral:instruct"`
 	Messages         []OpenAIChatRequestMessage `json:"messages" openapi_include_type:"taskengine.OpenAIChatRequestMessage"`
 	MaxTokens        int                        `json:"max_tokens,omitempty" example:"512"`
-	Temperature      float64                    `json:"temperature,omitempty" example:"0.7"`
+	Temperature      *float64                   `json:"temperature,omitempty" example:"0.7"`
 	TopP             float64                    `json:"top_p,omitempty" example:"1.0"`
 	Stop             []string                   `json:"stop,omitempty" example:"[\"\\n\", \"###\"]"`
 	N                int                        `json:"n,omitempty" example:"1"`
@@ -412,6 +644,21 @@ type OpenAIChatRequest struct {
 	PresencePenalty  float64                    `json:"presence_penalty,omitempty" example:"0.0"`
 	FrequencyPenalty float64                    `json:"frequency_penalty,omitempty" example:"0.0"`
 	User             string                     `json:"user,omitempty" example:"user_123"`
+	ResponseFormat   *OpenAIResponseFormat      `json:"response_format,omitempty" openapi_include_type:"taskengine.OpenAIResponseFormat"`
+	// KeepAlive is passed to Ollama backends, see LLMExecutionConfig.KeepAlive.
+	KeepAlive KeepAlive `json:"keep_alive,omitempty" example:"10m" openapi_include_type:"string"`
+}
+
+// OpenAIResponseFormat is the response_format of an OpenAI chat request.
+type OpenAIResponseFormat struct {
+	Type       string            `json:"type" example:"json_schema"`
+	JSONSchema *OpenAIJSONSchema `json:"json_schema,omitempty" openapi_include_type:"taskengine.OpenAIJSONSchema"`
+}
+
+type OpenAIJSONSchema struct {
+	Name   string         `json:"name" example:"weather_report"`
+	Schema map[string]any `json:"schema" openapi_include_type:"object"`
+	Strict bool           `json:"strict,omitempty" example:"true"`
 }
 
 type OpenAIChatRequestMessage struct {
@@ -0,0 +1,58 @@
+package taskengine
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateFuncs is the set of helper functions available inside PromptTemplate and Print.
+//
+// The set is intentionally small and free of side effects; it offers no access to the
+// environment, the filesystem or the network.
+//
+//	upper  "hello" | upper                      -> "HELLO"
+//	lower  "HELLO" | lower                      -> "hello"
+//	trim   "  hi  " | trim                      -> "hi"
+//	default "n/a" .value                        -> .value, or "n/a" if .value is empty
+//	toJson .value                               -> JSON encoding of .value
+//	now                                         -> current time in UTC
+//	date   "2006-01-02" now                     -> time formatted with a Go layout
+var templateFuncs = template.FuncMap{
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"trim":    strings.TrimSpace,
+	"default": defaultValue,
+	"toJson":  toJSON,
+	"now":     func() time.Time { return time.Now().UTC() },
+	"date":    func(layout string, t time.Time) string { return t.Format(layout) },
+}
+
+// defaultValue returns fallback when value is nil or the zero value of its type.
+func defaultValue(fallback any, value any) any {
+	if value == nil {
+		return fallback
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+		if v.Len() == 0 {
+			return fallback
+		}
+	default:
+		if v.IsZero() {
+			return fallback
+		}
+	}
+	return value
+}
+
+func toJSON(value any) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,73 @@
+package taskengine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+func renderViaChain(t *testing.T, tmpl string, input any) (any, error) {
+	t.Helper()
+	mockExec := &taskengine.MockTaskExecutor{
+		MockOutput:          "done",
+		MockTransitionValue: "done",
+	}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:             "render",
+				Handler:        taskengine.HandleRawString,
+				PromptTemplate: tmpl,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+	_, _, _, err = env.ExecEnv(context.Background(), chain, input, taskengine.DataTypeAny)
+	return mockExec.CalledWithInput, err
+}
+
+func TestUnit_TemplateFuncs(t *testing.T) {
+	tests := []struct {
+		name  string
+		tmpl  string
+		input any
+		want  string
+	}{
+		{name: "upper", tmpl: `{{ .input | upper }}`, input: "hello", want: "HELLO"},
+		{name: "lower", tmpl: `{{ lower .input }}`, input: "HeLLo", want: "hello"},
+		{name: "trim", tmpl: `[{{ trim .input }}]`, input: "  padded  ", want: "[padded]"},
+		{name: "default on empty", tmpl: `{{ default "n/a" .input }}`, input: "", want: "n/a"},
+		{name: "default on value", tmpl: `{{ default "n/a" .input }}`, input: "set", want: "set"},
+		{name: "toJson", tmpl: `{{ toJson .input }}`, input: map[string]any{"a": 1}, want: `{"a":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderViaChain(t, tt.tmpl, tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnit_TemplateFuncs_Now(t *testing.T) {
+	got, err := renderViaChain(t, `{{ date "2006" now }}`, "")
+	require.NoError(t, err)
+	require.Equal(t, time.Now().UTC().Format("2006"), got)
+}
+
+func TestUnit_TemplateFuncs_UnknownFunction(t *testing.T) {
+	_, err := renderViaChain(t, `{{ env "HOME" }}`, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `function "env" not defined`)
+}
@@ -0,0 +1,102 @@
+package taskengine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// TokenCounter counts the tokens of a text for a model.
+type TokenCounter interface {
+	CountTokens(ctx context.Context, modelName string, prompt string) (int, error)
+}
+
+// ChatTokenCount is the number of prompt tokens a chat request will consume.
+type ChatTokenCount struct {
+	// Model is the model the tokens were counted for; empty means the default tokenizer.
+	Model        string `json:"model" example:"mistral:instruct"`
+	PromptTokens int    `json:"prompt_tokens" example:"42"`
+}
+
+// CountChatTokens returns the prompt tokens a chat model task accounts for when it runs
+// on req, without invoking a model. The chat history is assembled exactly as during
+// execution, including the task's system instruction and model overrides.
+// A nil task counts the request messages as they are.
+func CountChatTokens(ctx context.Context, counter TokenCounter, task *TaskDefinition, req OpenAIChatRequest) (ChatTokenCount, error) {
+	if task == nil {
+		task = &TaskDefinition{Handler: HandleModelExecution}
+	}
+	// Work on a copy, prepareChatInput defaults the task's ExecuteConfig.
+	taskCopy := *task
+	history, llmCall, err := prepareChatInput(&taskCopy, req, DataTypeOpenAIChat)
+	if err != nil {
+		return ChatTokenCount{}, err
+	}
+	count, err := countPromptTokens(ctx, counter, llmCall.Model, history.Messages)
+	if err != nil {
+		return ChatTokenCount{}, err
+	}
+	return ChatTokenCount{Model: llmCall.Model, PromptTokens: count}, nil
+}
+
+// countPromptTokens sums the tokens of all message contents for model.
+func countPromptTokens(ctx context.Context, counter TokenCounter, model string, messages []Message) (int, error) {
+	total := 0
+	for _, m := range messages {
+		count, err := counter.CountTokens(ctx, model, m.Content)
+		if err != nil {
+			return 0, fmt.Errorf("token count failed: %w", err)
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// TokenUsage is the number of tokens consumed by model calls.
+type TokenUsage struct {
+	InputTokens  int `json:"inputTokens" example:"120"`
+	OutputTokens int `json:"outputTokens" example:"48"`
+}
+
+// TotalTokenUsage sums the token usage recorded for each step of an execution.
+func TotalTokenUsage(history []CapturedStateUnit) TokenUsage {
+	var total TokenUsage
+	for _, step := range history {
+		total.InputTokens += step.InputTokens
+		total.OutputTokens += step.OutputTokens
+	}
+	return total
+}
+
+// ReportTokenUsage adds the tokens of a model call to the usage of the running
+// task, it is recorded in the task's CapturedStateUnit. Executors and hooks that
+// call a model report through it; outside of a task execution it does nothing.
+func ReportTokenUsage(ctx context.Context, inputTokens, outputTokens int) {
+	sink, ok := ctx.Value(tokenUsageKey{}).(*tokenUsageSink)
+	if !ok {
+		return
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	sink.usage.InputTokens += inputTokens
+	sink.usage.OutputTokens += outputTokens
+}
+
+type tokenUsageKey struct{}
+
+type tokenUsageSink struct {
+	mu    sync.Mutex
+	usage TokenUsage
+}
+
+// withTokenUsage returns a context model calls report their token usage in for a single task attempt.
+func withTokenUsage(ctx context.Context) (context.Context, *tokenUsageSink) {
+	sink := &tokenUsageSink{}
+	return context.WithValue(ctx, tokenUsageKey{}, sink), sink
+}
+
+func (s *tokenUsageSink) total() TokenUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.usage
+}
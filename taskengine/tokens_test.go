@@ -0,0 +1,161 @@
+package taskengine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_CountChatTokens_MatchesAssembledPrompt(t *testing.T) {
+	runtime := &fakeRuntime{available: []string{"small"}}
+	exec, err := taskengine.NewExec(t.Context(), runtime, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+
+	task := &taskengine.TaskDefinition{
+		ID:                "chat",
+		Handler:           taskengine.HandleModelExecution,
+		SystemInstruction: "You are a terse assistant.",
+	}
+	req := taskengine.OpenAIChatRequest{
+		Model: "small",
+		Messages: []taskengine.OpenAIChatRequestMessage{
+			{Role: "user", Content: "hello"},
+			{Role: "assistant", Content: "hi"},
+			{Role: "user", Content: "what time is it?"},
+		},
+	}
+
+	count, err := taskengine.CountChatTokens(context.Background(), runtime, task, req)
+	require.NoError(t, err)
+	require.Equal(t, "small", count.Model)
+
+	want := 0
+	for _, text := range []string{task.SystemInstruction, "hello", "hi", "what time is it?"} {
+		n, err := runtime.CountTokens(context.Background(), "small", text)
+		require.NoError(t, err)
+		want += n
+	}
+	require.Equal(t, want, count.PromptTokens)
+	require.Empty(t, runtime.calls, "counting must not invoke the model")
+	require.Nil(t, task.ExecuteConfig, "counting must not modify the task")
+
+	out, _, _, err := exec.TaskExec(context.Background(), time.Now(), 0, task, req, taskengine.DataTypeOpenAIChat)
+	require.NoError(t, err)
+	require.Equal(t, count.PromptTokens, out.(taskengine.ChatHistory).InputTokens)
+}
+
+func TestUnit_CountChatTokens_WithoutTaskCountsRequestMessages(t *testing.T) {
+	runtime := &fakeRuntime{}
+	req := taskengine.OpenAIChatRequest{
+		Model:    "small",
+		Messages: []taskengine.OpenAIChatRequestMessage{{Role: "user", Content: "hello"}},
+	}
+
+	count, err := taskengine.CountChatTokens(context.Background(), runtime, nil, req)
+	require.NoError(t, err)
+	require.Equal(t, taskengine.ChatTokenCount{Model: "small", PromptTokens: len("hello")}, count)
+}
+
+// meteredExec reports a fixed token usage for each task it runs.
+type meteredExec struct {
+	usage map[string]taskengine.TokenUsage
+}
+
+func (m meteredExec) TaskExec(ctx context.Context, _ time.Time, _ int, task *taskengine.TaskDefinition, input any, dataType taskengine.DataType) (any, taskengine.DataType, string, error) {
+	if usage, ok := m.usage[task.ID]; ok {
+		taskengine.ReportTokenUsage(ctx, usage.InputTokens, usage.OutputTokens)
+	}
+	return input, dataType, "ok", nil
+}
+
+func TestUnit_SimpleEnv_ExecEnv_CapturesTokenUsagePerTask(t *testing.T) {
+	next := func(goTo string) taskengine.TaskTransition {
+		return taskengine.TaskTransition{Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: goTo}}}
+	}
+	chain := &taskengine.TaskChainDefinition{
+		ID: "metered",
+		Tasks: []taskengine.TaskDefinition{
+			{ID: "draft", Handler: taskengine.HandleRawString, Transition: next("review")},
+			{ID: "review", Handler: taskengine.HandleRawString, Transition: next("format")},
+			{ID: "format", Handler: taskengine.HandleNoop, Transition: next(taskengine.TermEnd)},
+		},
+	}
+	exec := meteredExec{usage: map[string]taskengine.TokenUsage{
+		"draft":  {InputTokens: 120, OutputTokens: 48},
+		"review": {InputTokens: 70, OutputTokens: 12},
+	}}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	_, _, history, err := env.ExecEnv(t.Context(), chain, "write a haiku", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, 120, history[0].InputTokens)
+	require.Equal(t, 48, history[0].OutputTokens)
+	require.Equal(t, 70, history[1].InputTokens)
+	require.Equal(t, 12, history[1].OutputTokens)
+	require.Zero(t, history[2].InputTokens, "tasks that report no usage contribute zero")
+	require.Zero(t, history[2].OutputTokens)
+
+	require.Equal(t, taskengine.TokenUsage{InputTokens: 190, OutputTokens: 60}, taskengine.TotalTokenUsage(history))
+}
+
+func TestUnit_SimpleEnv_ExecEnv_CapturesModelTokenUsage(t *testing.T) {
+	runtime := &fakeRuntime{available: []string{"small"}}
+	exec, err := taskengine.NewExec(t.Context(), runtime, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+	chain := &taskengine.TaskChainDefinition{
+		ID: "chat",
+		Tasks: []taskengine.TaskDefinition{{
+			ID:            "chat",
+			Handler:       taskengine.HandleModelExecution,
+			ExecuteConfig: &taskengine.LLMExecutionConfig{Model: "small"},
+			Transition:    taskengine.TaskTransition{Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd}}},
+		}},
+	}
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{{Role: "user", Content: "hello"}}}
+
+	_, _, steps, err := env.ExecEnv(t.Context(), chain, history, taskengine.DataTypeChatHistory)
+	require.NoError(t, err)
+	require.Len(t, steps, 1)
+	// The fake tokenizer counts one token per byte.
+	require.Equal(t, len("hello"), steps[0].InputTokens)
+	require.Equal(t, len("hi from small"), steps[0].OutputTokens)
+}
+
+func TestUnit_SimpleEnv_ExecEnv_CapturesPromptTokenUsage(t *testing.T) {
+	runtime := &scriptedRuntime{replies: []string{"a short poem"}}
+	exec, err := taskengine.NewExec(t.Context(), runtime, noHooks{}, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, exec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+	chain := &taskengine.TaskChainDefinition{
+		ID: "prompt",
+		Tasks: []taskengine.TaskDefinition{{
+			ID:                "draft",
+			Handler:           taskengine.HandleRawString,
+			SystemInstruction: "Be brief.",
+			ExecuteConfig:     &taskengine.LLMExecutionConfig{Model: "small"},
+			Transition:        taskengine.TaskTransition{Branches: []taskengine.TransitionBranch{{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd}}},
+		}},
+	}
+
+	_, _, steps, err := env.ExecEnv(t.Context(), chain, "write a haiku", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Len(t, steps, 1)
+	// The fake tokenizer counts one token per byte.
+	require.Equal(t, len("Be brief.")+len(runtime.prompts[0]), steps[0].InputTokens)
+	require.Equal(t, len("a short poem"), steps[0].OutputTokens)
+}
+
+func TestUnit_ReportTokenUsage_OutsideExecution(t *testing.T) {
+	require.NotPanics(t, func() {
+		taskengine.ReportTokenUsage(context.Background(), 10, 5)
+	})
+}
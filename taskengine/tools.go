@@ -0,0 +1,150 @@
+package taskengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	libmodelprovider "github.com/contenox/runtime/internal/modelrepo"
+)
+
+// DefaultMaxToolIterations is the number of model invocations a tool_calling task
+// performs when ExecuteConfig.MaxToolIterations is not set.
+const DefaultMaxToolIterations = 5
+
+// ErrToolIterationsExceeded indicates the model kept requesting tools after the
+// maximum number of iterations.
+var ErrToolIterationsExceeded = errors.New("tool iterations exceeded")
+
+// executeWithTools runs the tool calling loop: the model is invoked with the declared
+// tools, every requested tool call is executed by the hook of the same name and its
+// result is appended to the history as a "tool" message. The loop ends when the model
+// answers without tool calls.
+func (exe *SimpleExec) executeWithTools(ctx context.Context, startingTime time.Time, history ChatHistory, ctxLength int, llmCall *LLMExecutionConfig) (any, DataType, string, error) {
+	if len(llmCall.Tools) == 0 {
+		return nil, DataTypeAny, "", fmt.Errorf("tool_calling requires at least one tool in execute_config.tools: %w", apiframework.ErrBadRequest)
+	}
+	tools := make([]libmodelprovider.Tool, 0, len(llmCall.Tools))
+	declared := make(map[string]bool, len(llmCall.Tools))
+	for _, tool := range llmCall.Tools {
+		if tool.Name == "" {
+			return nil, DataTypeAny, "", fmt.Errorf("tool name is required: %w", apiframework.ErrBadRequest)
+		}
+		declared[tool.Name] = true
+		tools = append(tools, libmodelprovider.Tool{
+			Type: "function",
+			Function: libmodelprovider.FunctionTool{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+	maxIterations := llmCall.MaxToolIterations
+	if maxIterations <= 0 {
+		maxIterations = DefaultMaxToolIterations
+	}
+
+	outputTokens := 0
+	for range maxIterations {
+		// Recount the input on every iteration, it grows with each tool result.
+		history.InputTokens = 0
+		out, _, _, err := exe.executeLLM(ctx, history, ctxLength, llmCall, libmodelprovider.WithTools(tools))
+		if err != nil {
+			return nil, DataTypeAny, "", err
+		}
+		history = out.(ChatHistory)
+		outputTokens += history.OutputTokens
+		history.OutputTokens = outputTokens
+
+		calls := history.Messages[len(history.Messages)-1].ToolCalls
+		if len(calls) == 0 {
+			return history, DataTypeChatHistory, "executed", nil
+		}
+		for _, call := range calls {
+			if !declared[call.Name] {
+				return nil, DataTypeAny, "", fmt.Errorf("model requested undeclared tool %q", call.Name)
+			}
+			result, err := exe.callTool(ctx, startingTime, call)
+			if err != nil {
+				return nil, DataTypeAny, "", fmt.Errorf("tool %s failed: %w", call.Name, err)
+			}
+			history.Messages = append(history.Messages, Message{
+				Role:       "tool",
+				Content:    result,
+				ToolCallID: call.ID,
+				Timestamp:  time.Now().UTC(),
+			})
+		}
+	}
+	return nil, DataTypeAny, "", fmt.Errorf("%w: model still requested tools after %d iterations", ErrToolIterationsExceeded, maxIterations)
+}
+
+// callTool executes a tool call through the hook named after the tool.
+// The decoded arguments are passed as JSON input and, as strings, as hook args.
+// The hook output is returned as text for the model.
+func (exe *SimpleExec) callTool(ctx context.Context, startingTime time.Time, call ToolCall) (string, error) {
+	args := map[string]any{}
+	if call.Arguments != "" {
+		if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+			return "", fmt.Errorf("invalid arguments %q: %w", call.Arguments, err)
+		}
+	}
+	hookArgs := make(map[string]string, len(args))
+	for k, v := range args {
+		if s, ok := v.(string); ok {
+			hookArgs[k] = s
+			continue
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		hookArgs[k] = string(encoded)
+	}
+	output, _, _, err := exe.hookengine(ctx, startingTime, args, DataTypeJSON, "", &HookCall{Name: call.Name, Args: hookArgs})
+	if err != nil {
+		return "", err
+	}
+	if s, ok := output.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(output)
+	if err != nil {
+		return "", fmt.Errorf("tool output is not JSON serializable: %w", err)
+	}
+	return string(encoded), nil
+}
+
+func toProviderToolCalls(calls []ToolCall) []libmodelprovider.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	converted := make([]libmodelprovider.ToolCall, 0, len(calls))
+	for _, call := range calls {
+		converted = append(converted, libmodelprovider.ToolCall{
+			ID:       call.ID,
+			Type:     "function",
+			Function: libmodelprovider.FunctionCall{Name: call.Name, Arguments: call.Arguments},
+		})
+	}
+	return converted
+}
+
+func fromProviderToolCalls(calls []libmodelprovider.ToolCall) []ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	converted := make([]ToolCall, 0, len(calls))
+	for _, call := range calls {
+		converted = append(converted, ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+	return converted
+}
@@ -0,0 +1,163 @@
+package taskengine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/internal/llmrepo"
+	libmodelprovider "github.com/contenox/runtime/internal/modelrepo"
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+// toolArgs records the tools offered on a chat request.
+type toolArgs struct {
+	samplingArgs
+	tools []libmodelprovider.Tool
+}
+
+func (a *toolArgs) SetTools(tools []libmodelprovider.Tool) { a.tools = tools }
+
+// toolRuntime requests a get_weather call until it sees a tool result, then answers with it.
+// With alwaysCall set it never stops requesting the tool.
+type toolRuntime struct {
+	llmrepo.ModelRepo
+	alwaysCall bool
+	offered    [][]libmodelprovider.Tool
+	requests   [][]libmodelprovider.Message
+}
+
+func (r *toolRuntime) CountTokens(ctx context.Context, modelName string, prompt string) (int, error) {
+	return len(prompt), nil
+}
+
+func (r *toolRuntime) Chat(ctx context.Context, req llmrepo.Request, messages []libmodelprovider.Message, opts ...libmodelprovider.ChatOption) (libmodelprovider.Message, llmrepo.Meta, error) {
+	var args toolArgs
+	for _, opt := range opts {
+		opt.ApplyTo(&args)
+	}
+	r.offered = append(r.offered, args.tools)
+	r.requests = append(r.requests, messages)
+	meta := llmrepo.Meta{ModelName: "small", BackendID: "b1"}
+
+	last := messages[len(messages)-1]
+	if r.alwaysCall || last.Role != "tool" {
+		return libmodelprovider.Message{
+			Role: "assistant",
+			ToolCalls: []libmodelprovider.ToolCall{{
+				ID:       "call_1",
+				Type:     "function",
+				Function: libmodelprovider.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris","days":2}`},
+			}},
+			FinishReason: libmodelprovider.FinishReasonToolCalls,
+		}, meta, nil
+	}
+	return libmodelprovider.Message{Role: "assistant", Content: "Forecast: " + last.Content, FinishReason: libmodelprovider.FinishReasonStop}, meta, nil
+}
+
+// recordingHooks answers every hook call with output and records the calls.
+type recordingHooks struct {
+	output any
+	calls  []taskengine.HookCall
+	inputs []any
+}
+
+func (h *recordingHooks) Exec(_ context.Context, _ time.Time, input any, _ taskengine.DataType, _ string, call *taskengine.HookCall) (any, taskengine.DataType, string, error) {
+	h.calls = append(h.calls, *call)
+	h.inputs = append(h.inputs, input)
+	return h.output, taskengine.DataTypeJSON, "ok", nil
+}
+
+func (h *recordingHooks) ValidateArgs(context.Context, *taskengine.HookCall) error { return nil }
+
+func (h *recordingHooks) Schemas(context.Context) ([]taskengine.HookSchema, error) { return nil, nil }
+
+func (h *recordingHooks) Supports(context.Context) ([]string, error) {
+	return []string{"get_weather"}, nil
+}
+
+func weatherTool() *taskengine.LLMExecutionConfig {
+	return &taskengine.LLMExecutionConfig{
+		Model: "small",
+		Tools: []taskengine.ToolDefinition{{
+			Name:        "get_weather",
+			Description: "Returns the weather forecast for a city",
+			Parameters: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"city": map[string]any{"type": "string"}},
+			},
+		}},
+	}
+}
+
+func execTools(t *testing.T, runtime *toolRuntime, hooks taskengine.HookRepo, cfg *taskengine.LLMExecutionConfig) (any, error) {
+	t.Helper()
+	exec, err := taskengine.NewExec(t.Context(), runtime, hooks, libtracker.NoopTracker{})
+	require.NoError(t, err)
+	history := taskengine.ChatHistory{Messages: []taskengine.Message{{Role: "user", Content: "Weather in Paris?"}}}
+	out, _, _, err := exec.TaskExec(context.Background(), time.Now(), 0, &taskengine.TaskDefinition{
+		ID:            "assistant",
+		Handler:       taskengine.HandleToolCalling,
+		ExecuteConfig: cfg,
+	}, history, taskengine.DataTypeChatHistory)
+	return out, err
+}
+
+func TestUnit_ToolCalling_RoutesCallToHookAndAnswers(t *testing.T) {
+	runtime := &toolRuntime{}
+	hooks := &recordingHooks{output: map[string]any{"forecast": "sunny"}}
+
+	out, err := execTools(t, runtime, hooks, weatherTool())
+	require.NoError(t, err)
+
+	require.Len(t, hooks.calls, 1)
+	require.Equal(t, "get_weather", hooks.calls[0].Name)
+	require.Equal(t, map[string]string{"city": "Paris", "days": "2"}, hooks.calls[0].Args)
+	require.Equal(t, map[string]any{"city": "Paris", "days": float64(2)}, hooks.inputs[0])
+
+	require.Len(t, runtime.offered, 2)
+	for _, offered := range runtime.offered {
+		require.Len(t, offered, 1)
+		require.Equal(t, "get_weather", offered[0].Function.Name)
+	}
+	// The second request carries the tool call and its result.
+	second := runtime.requests[1]
+	require.Equal(t, "call_1", second[1].ToolCalls[0].ID)
+	require.Equal(t, "tool", second[2].Role)
+	require.Equal(t, "call_1", second[2].ToolCallID)
+
+	history := out.(taskengine.ChatHistory)
+	require.Len(t, history.Messages, 4)
+	require.Equal(t, "get_weather", history.Messages[1].ToolCalls[0].Name)
+	require.Equal(t, `{"forecast":"sunny"}`, history.Messages[2].Content)
+	require.Equal(t, `Forecast: {"forecast":"sunny"}`, history.Messages[3].Content)
+	require.Equal(t, libmodelprovider.FinishReasonStop, history.FinishReason)
+}
+
+func TestUnit_ToolCalling_StopsAfterMaxIterations(t *testing.T) {
+	runtime := &toolRuntime{alwaysCall: true}
+	hooks := &recordingHooks{output: "sunny"}
+	cfg := weatherTool()
+	cfg.MaxToolIterations = 3
+
+	_, err := execTools(t, runtime, hooks, cfg)
+	require.ErrorIs(t, err, taskengine.ErrToolIterationsExceeded)
+	require.Len(t, runtime.requests, 3)
+	require.Len(t, hooks.calls, 3)
+}
+
+func TestUnit_ToolCalling_RejectsMissingOrUndeclaredTools(t *testing.T) {
+	hooks := &recordingHooks{output: "sunny"}
+
+	_, err := execTools(t, &toolRuntime{}, hooks, &taskengine.LLMExecutionConfig{Model: "small"})
+	require.ErrorIs(t, err, apiframework.ErrBadRequest)
+
+	cfg := weatherTool()
+	cfg.Tools[0].Name = "get_time"
+	_, err = execTools(t, &toolRuntime{}, hooks, cfg)
+	require.ErrorContains(t, err, `undeclared tool "get_weather"`)
+	require.Empty(t, hooks.calls)
+}
@@ -0,0 +1,50 @@
+package taskengine
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans emitted by the task engine.
+const tracerName = "github.com/contenox/runtime/taskengine"
+
+// Span attribute keys used by the task engine.
+const (
+	attrChainID    = "taskengine.chain.id"
+	attrTaskID     = "taskengine.task.id"
+	attrTaskType   = "taskengine.task.type"
+	attrRetry      = "taskengine.task.retry"
+	attrTransition = "taskengine.task.transition"
+	attrNextTask   = "taskengine.task.next"
+	attrHookName   = "taskengine.hook.name"
+)
+
+// WithTracerProvider emits chain, task and hook spans using tp
+// instead of the global OpenTelemetry tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) EnvOption {
+	return func(e *SimpleEnv) {
+		if tp != nil {
+			e.tracer = tp.Tracer(tracerName)
+		}
+	}
+}
+
+func defaultTracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// childTracer returns a tracer from the provider that created the span in ctx,
+// so nested spans end up in the same trace pipeline as their parent.
+func childTracer(span trace.Span) trace.Tracer {
+	return span.TracerProvider().Tracer(tracerName)
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,87 @@
+package taskengine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/contenox/runtime/libtracker"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestUnit_SimpleEnv_Tracing_SpanTree(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	mockExec := &taskengine.MockTaskExecutor{
+		MockOutputSequence:          []any{"yes", "done"},
+		MockTransitionValueSequence: []string{"yes", "ok"},
+	}
+	env, err := taskengine.NewEnv(t.Context(), libtracker.NoopTracker{}, mockExec, taskengine.NewSimpleInspector(), taskengine.WithTracerProvider(tp))
+	require.NoError(t, err)
+
+	chain := &taskengine.TaskChainDefinition{
+		ID: "two-step",
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:      "classify",
+				Handler: taskengine.HandleConditionKey,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpEquals, When: "yes", Goto: "answer"},
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+			{
+				ID:      "answer",
+				Handler: taskengine.HandleRawString,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "http.request")
+	_, _, _, err = env.ExecEnv(ctx, chain, "hello", taskengine.DataTypeString)
+	require.NoError(t, err)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 4)
+	byName := map[string][]tracetest.SpanStub{}
+	for _, s := range spans {
+		byName[s.Name] = append(byName[s.Name], s)
+	}
+
+	require.Len(t, byName["http.request"], 1)
+	require.Len(t, byName["taskengine.chain"], 1)
+	require.Len(t, byName["taskengine.task"], 2)
+	request := byName["http.request"][0]
+	chainSpan := byName["taskengine.chain"][0]
+
+	require.Equal(t, request.SpanContext.TraceID(), chainSpan.SpanContext.TraceID())
+	require.Equal(t, request.SpanContext.SpanID(), chainSpan.Parent.SpanID())
+	require.Contains(t, chainSpan.Attributes, attribute.String("taskengine.chain.id", "two-step"))
+
+	tasks := byName["taskengine.task"]
+	require.Equal(t, chainSpan.SpanContext.SpanID(), tasks[0].Parent.SpanID())
+	require.Equal(t, chainSpan.SpanContext.SpanID(), tasks[1].Parent.SpanID())
+	require.Contains(t, tasks[0].Attributes, attribute.String("taskengine.task.id", "classify"))
+	require.Contains(t, tasks[0].Attributes, attribute.String("taskengine.task.type", "condition_key"))
+	require.Contains(t, tasks[0].Attributes, attribute.Int("taskengine.task.retry", 0))
+	require.Contains(t, tasks[0].Attributes, attribute.String("taskengine.task.transition", "yes"))
+	require.Contains(t, tasks[1].Attributes, attribute.String("taskengine.task.id", "answer"))
+	require.Contains(t, tasks[1].Attributes, attribute.String("taskengine.task.transition", "ok"))
+
+	require.Len(t, chainSpan.Events, 2)
+	require.Equal(t, "taskengine.transition", chainSpan.Events[0].Name)
+	require.Contains(t, chainSpan.Events[0].Attributes, attribute.String("taskengine.task.next", "answer"))
+}
@@ -0,0 +1,145 @@
+package taskengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/libtracker"
+)
+
+const (
+	defaultWebhookTimeout = 10 * time.Second
+	// maxWebhookRetries caps WebhookConfig.Retries, so one delivery cannot keep
+	// retrying for hours.
+	maxWebhookRetries = 5
+)
+
+// webhookRetryDelay is the pause between delivery attempts, multiplied by the attempt number.
+var webhookRetryDelay = 500 * time.Millisecond
+
+// CompletionEvent is the payload posted to a chain's OnComplete webhook.
+type CompletionEvent struct {
+	ChainID    string `json:"chainId"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	Output     any    `json:"output,omitempty"`
+	OutputType string `json:"outputType"`
+}
+
+// notifyCompletion delivers the chain result to the OnComplete webhook in the
+// background, so a slow or unreachable webhook does not delay the caller.
+// Errors are reported to the tracker only; they never affect the chain result.
+func (exe SimpleEnv) notifyCompletion(ctx context.Context, chain *TaskChainDefinition, output any, outputType DataType, chainErr error, duration time.Duration) {
+	event := CompletionEvent{
+		ChainID:    chain.ID,
+		Status:     "success",
+		DurationMs: duration.Milliseconds(),
+		OutputType: outputType.String(),
+	}
+	if chainErr != nil {
+		event.Status = "error"
+		event.Error = chainErr.Error()
+	} else if libtracker.CaptureContentEnabled(ctx) {
+		event.Output = output
+	}
+	// Marshal before returning, the caller owns output afterwards.
+	body, err := json.Marshal(event)
+
+	// The delivery outlives the request; it is bounded by its own deadline instead.
+	go exe.deliverWebhook(context.WithoutCancel(ctx), chain.ID, chain.OnComplete, event.Status, body, err)
+}
+
+func (exe SimpleEnv) deliverWebhook(ctx context.Context, chainID string, hook *WebhookConfig, status string, body []byte, err error) {
+	reportErr, reportChange, end := exe.tracker.Start(ctx, "notify", "chain_webhook",
+		"chain_id", chainID,
+		"url", hook.URL,
+	)
+	defer end()
+	if err != nil {
+		reportErr(fmt.Errorf("failed to marshal webhook payload: %w", err))
+		return
+	}
+
+	timeout := defaultWebhookTimeout
+	if hook.Timeout != "" {
+		if timeout, err = time.ParseDuration(hook.Timeout); err != nil {
+			reportErr(fmt.Errorf("invalid webhook timeout: %w", err))
+			return
+		}
+	}
+
+	attempts := min(max(hook.Retries, 0), maxWebhookRetries) + 1
+	ctx, cancel := context.WithTimeout(ctx, webhookDeadline(attempts, timeout))
+	defer cancel()
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(time.Duration(attempt) * webhookRetryDelay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				reportErr(fmt.Errorf("webhook delivery stopped after %d attempts: %w: %w", attempt, context.Cause(ctx), err))
+				return
+			}
+		}
+		if err = postWebhook(ctx, hook, body, timeout); err == nil {
+			reportChange(chainID, map[string]any{"status": status, "attempts": attempt + 1})
+			return
+		}
+	}
+	reportErr(fmt.Errorf("webhook delivery failed after %d attempts: %w", attempts, err))
+}
+
+// Validate checks the timeout format and that Retries is within 0 and the
+// supported maximum.
+func (w *WebhookConfig) Validate() error {
+	if w.URL == "" {
+		return fmt.Errorf("on_complete: url is required %w", apiframework.ErrBadRequest)
+	}
+	if w.Timeout != "" {
+		if d, err := time.ParseDuration(w.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("on_complete: invalid timeout %q %w", w.Timeout, apiframework.ErrBadRequest)
+		}
+	}
+	if w.Retries < 0 || w.Retries > maxWebhookRetries {
+		return fmt.Errorf("on_complete: retries must be between 0 and %d, got %d %w", maxWebhookRetries, w.Retries, apiframework.ErrBadRequest)
+	}
+	return nil
+}
+
+// webhookDeadline bounds a whole delivery: every attempt may take timeout, plus
+// the pauses between them.
+func webhookDeadline(attempts int, timeout time.Duration) time.Duration {
+	pauses := attempts * (attempts - 1) / 2
+	return time.Duration(attempts)*timeout + time.Duration(pauses)*webhookRetryDelay
+}
+
+func postWebhook(ctx context.Context, hook *WebhookConfig, body []byte, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package taskengine_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/contenox/runtime/internal/apiframework"
+	"github.com/contenox/runtime/taskengine"
+	"github.com/stretchr/testify/require"
+)
+
+func webhookChain(url string, retries int) *taskengine.TaskChainDefinition {
+	return &taskengine.TaskChainDefinition{
+		ID: "notify-chain",
+		OnComplete: &taskengine.WebhookConfig{
+			URL:     url,
+			Headers: map[string]string{"X-Token": "secret"},
+			Timeout: "2s",
+			Retries: retries,
+		},
+		Tasks: []taskengine.TaskDefinition{
+			{
+				ID:      "answer",
+				Handler: taskengine.HandleNoop,
+				Transition: taskengine.TaskTransition{
+					Branches: []taskengine.TransitionBranch{
+						{Operator: taskengine.OpDefault, Goto: taskengine.TermEnd},
+					},
+				},
+			},
+		},
+	}
+}
+
+// webhookTracker records the errors of webhook deliveries and signals when one finished.
+type webhookTracker struct {
+	mu   sync.Mutex
+	errs []error
+	done chan struct{}
+}
+
+func newWebhookTracker() *webhookTracker {
+	return &webhookTracker{done: make(chan struct{}, 1)}
+}
+
+func (w *webhookTracker) Start(_ context.Context, operation string, _ string, _ ...any) (func(error), func(string, any), func()) {
+	if operation != "notify" {
+		return func(error) {}, func(string, any) {}, func() {}
+	}
+	return func(err error) {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			w.errs = append(w.errs, err)
+		}, func(string, any) {}, func() {
+			w.done <- struct{}{}
+		}
+}
+
+// wait blocks until a delivery finished and returns the errors reported so far.
+func (w *webhookTracker) wait(t *testing.T) []error {
+	t.Helper()
+	select {
+	case <-w.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook delivery did not finish")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.errs
+}
+
+func TestUnit_SimpleEnv_ExecEnv_OnCompleteWebhook(t *testing.T) {
+	var received taskengine.CompletionEvent
+	var token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("X-Token")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	mockExec := &taskengine.MockTaskExecutor{MockOutput: "42"}
+	tracker := newWebhookTracker()
+	env, err := taskengine.NewEnv(t.Context(), tracker, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	result, _, _, err := env.ExecEnv(context.Background(), webhookChain(server.URL, 0), "question", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "42", result)
+
+	require.Empty(t, tracker.wait(t))
+	require.Equal(t, "secret", token)
+	require.Equal(t, "notify-chain", received.ChainID)
+	require.Equal(t, "success", received.Status)
+	require.Equal(t, "42", received.Output)
+	require.Equal(t, "string", received.OutputType)
+}
+
+func TestUnit_SimpleEnv_ExecEnv_FailedWebhookDoesNotFailChain(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	mockExec := &taskengine.MockTaskExecutor{MockOutput: "42"}
+	tracker := newWebhookTracker()
+	env, err := taskengine.NewEnv(t.Context(), tracker, mockExec, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	result, _, _, err := env.ExecEnv(context.Background(), webhookChain(server.URL, 1), "question", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Equal(t, "42", result)
+
+	errs := tracker.wait(t)
+	require.Equal(t, int32(2), calls.Load(), "delivery should be retried once")
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "webhook delivery failed after 2 attempts")
+}
+
+func TestUnit_SimpleEnv_ExecEnv_SlowWebhookDoesNotDelayChain(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	tracker := newWebhookTracker()
+	env, err := taskengine.NewEnv(t.Context(), tracker, &taskengine.MockTaskExecutor{MockOutput: "42"}, taskengine.NewSimpleInspector())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := time.Now()
+	_, _, _, err = env.ExecEnv(ctx, webhookChain(server.URL, 3), "question", taskengine.DataTypeString)
+	require.NoError(t, err)
+	require.Less(t, time.Since(started), time.Second, "the chain returns before the webhook answers")
+
+	// Canceling the request does not abort the delivery.
+	cancel()
+	release <- struct{}{}
+	require.Empty(t, tracker.wait(t))
+}
+
+func TestUnit_WebhookConfig_Validate(t *testing.T) {
+	require.NoError(t, webhookChain("https://hooks.example.com", 5).OnComplete.Validate())
+
+	for name, hook := range map[string]*taskengine.WebhookConfig{
+		"missing url":      {Retries: 1},
+		"too many retries": {URL: "https://hooks.example.com", Retries: 6},
+		"negative retries": {URL: "https://hooks.example.com", Retries: -1},
+		"invalid timeout":  {URL: "https://hooks.example.com", Timeout: "10"},
+	} {
+		err := hook.Validate()
+		require.ErrorIs(t, err, apiframework.ErrBadRequest, name)
+	}
+
+	err := taskengine.ValidateChainHooks(context.Background(), nil, webhookChain("https://hooks.example.com", 100))
+	require.ErrorContains(t, err, "retries must be between 0 and 5, got 100")
+}
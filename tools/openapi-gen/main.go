@@ -224,13 +224,18 @@ func extractRoute(fset *token.FileSet, file *ast.File, call *ast.CallExpr, swagg
 	var handler *ast.FuncDecl
 	var handlerDocs string
 	if len(call.Args) > 1 {
-		if funcLit, ok := call.Args[1].(*ast.FuncLit); ok {
+		handlerArg := call.Args[1]
+		// Unwrap per-route middleware such as WithMaxBodySize(limit, h.handler).
+		if wrapped, ok := handlerArg.(*ast.CallExpr); ok && len(wrapped.Args) > 0 {
+			handlerArg = wrapped.Args[len(wrapped.Args)-1]
+		}
+		if funcLit, ok := handlerArg.(*ast.FuncLit); ok {
 			handler = &ast.FuncDecl{
 				Name: ast.NewIdent("handler"),
 				Type: funcLit.Type,
 				Body: funcLit.Body,
 			}
-		} else if sel, ok := call.Args[1].(*ast.SelectorExpr); ok {
+		} else if sel, ok := handlerArg.(*ast.SelectorExpr); ok {
 			ast.Inspect(file, func(n ast.Node) bool {
 				if fn, ok := n.(*ast.FuncDecl); ok && fn.Name.Name == sel.Sel.Name {
 					handler = fn
@@ -1324,8 +1329,11 @@ func findParamDescriptions(handler *ast.FuncDecl) map[string]string {
 
 // findQueryParamDescriptions inspects the handler's AST for calls to GetQueryParam
 // and extracts the parameter name, default value, and description from its arguments.
-func findQueryParamDescriptions(handler *ast.FuncDecl) map[string]*openapi3.Parameter {
-	queryParams := make(map[string]*openapi3.Parameter)
+// Parameters are returned in the order the handler reads them, so the generated
+// spec is stable between runs.
+func findQueryParamDescriptions(handler *ast.FuncDecl) []*openapi3.Parameter {
+	var queryParams []*openapi3.Parameter
+	seen := map[string]bool{}
 	if handler == nil {
 		return queryParams
 	}
@@ -1353,7 +1361,10 @@ func findQueryParamDescriptions(handler *ast.FuncDecl) map[string]*openapi3.Para
 							param.Schema.Value.Default = defaultValue
 						}
 
-						queryParams[paramName] = param
+						if !seen[paramName] {
+							seen[paramName] = true
+							queryParams = append(queryParams, param)
+						}
 					}
 				}
 			}